@@ -0,0 +1,199 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"sync"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// Future holds the result of a command run by AsyncClient.
+// Its methods block until the command completes.
+type Future struct {
+	done chan struct{}
+
+	record  *Record
+	existed bool
+	err     error
+}
+
+func newFuture() *Future {
+	return &Future{done: make(chan struct{})}
+}
+
+func (f *Future) complete(record *Record, existed bool, err error) {
+	f.record, f.existed, f.err = record, existed, err
+	close(f.done)
+}
+
+// Done returns a channel which is closed when the command completes.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the command completes and returns its error.
+func (f *Future) Wait() error {
+	<-f.done
+	return f.err
+}
+
+// Record blocks until the command completes and returns the record it read.
+// The record is nil for commands which do not read one.
+func (f *Future) Record() (*Record, error) {
+	<-f.done
+	return f.record, f.err
+}
+
+// Existed blocks until the command completes and returns whether the record
+// existed, for DeleteAsync and ExistsAsync.
+func (f *Future) Existed() (bool, error) {
+	<-f.done
+	return f.existed, f.err
+}
+
+// AsyncClient runs the commands of a Client on a bounded pool of workers and
+// returns futures of their results, so that callers do not need to spawn a
+// goroutine per command. When all the workers are busy, the commands are
+// queued; when the queue is full, the async methods block until there is room.
+type AsyncClient struct {
+	client *Client
+
+	tasks chan func()
+	wg    sync.WaitGroup
+
+	mutex  sync.RWMutex
+	closed bool
+}
+
+// NewAsyncClient generates an AsyncClient running the commands of the client
+// on the given number of workers, with up to queueSize commands waiting.
+// Closing the AsyncClient does not close the client.
+func NewAsyncClient(client *Client, workers int, queueSize int) *AsyncClient {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	ac := &AsyncClient{
+		client: client,
+		tasks:  make(chan func(), queueSize),
+	}
+
+	ac.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer ac.wg.Done()
+			for task := range ac.tasks {
+				task()
+			}
+		}()
+	}
+
+	return ac
+}
+
+// Close stops the workers after the queued commands complete.
+// The commands submitted afterwards fail with COMMAND_REJECTED.
+func (ac *AsyncClient) Close() {
+	ac.mutex.Lock()
+	if ac.closed {
+		ac.mutex.Unlock()
+		return
+	}
+	ac.closed = true
+	close(ac.tasks)
+	ac.mutex.Unlock()
+
+	ac.wg.Wait()
+}
+
+// submit queues the command, completing the future with an error if the
+// async client is closed.
+func (ac *AsyncClient) submit(f *Future, task func()) *Future {
+	ac.mutex.RLock()
+	defer ac.mutex.RUnlock()
+
+	if ac.closed {
+		f.complete(nil, false, NewAerospikeError(COMMAND_REJECTED, "Async client is closed"))
+		return f
+	}
+
+	ac.tasks <- task
+	return f
+}
+
+// GetAsync reads a record like Client.Get.
+func (ac *AsyncClient) GetAsync(policy *BasePolicy, key *Key, binNames ...string) *Future {
+	f := newFuture()
+	return ac.submit(f, func() {
+		rec, err := ac.client.Get(policy, key, binNames...)
+		f.complete(rec, false, err)
+	})
+}
+
+// GetHeaderAsync reads a record's generation and expiration like Client.GetHeader.
+func (ac *AsyncClient) GetHeaderAsync(policy *BasePolicy, key *Key) *Future {
+	f := newFuture()
+	return ac.submit(f, func() {
+		rec, err := ac.client.GetHeader(policy, key)
+		f.complete(rec, false, err)
+	})
+}
+
+// ExistsAsync checks if a record exists like Client.Exists.
+func (ac *AsyncClient) ExistsAsync(policy *BasePolicy, key *Key) *Future {
+	f := newFuture()
+	return ac.submit(f, func() {
+		existed, err := ac.client.Exists(policy, key)
+		f.complete(nil, existed, err)
+	})
+}
+
+// PutAsync writes the bins like Client.Put.
+func (ac *AsyncClient) PutAsync(policy *WritePolicy, key *Key, binMap BinMap) *Future {
+	f := newFuture()
+	return ac.submit(f, func() {
+		f.complete(nil, false, ac.client.Put(policy, key, binMap))
+	})
+}
+
+// PutBinsAsync writes the bins like Client.PutBins.
+func (ac *AsyncClient) PutBinsAsync(policy *WritePolicy, key *Key, bins ...*Bin) *Future {
+	f := newFuture()
+	return ac.submit(f, func() {
+		f.complete(nil, false, ac.client.PutBins(policy, key, bins...))
+	})
+}
+
+// DeleteAsync deletes a record like Client.Delete.
+func (ac *AsyncClient) DeleteAsync(policy *WritePolicy, key *Key) *Future {
+	f := newFuture()
+	return ac.submit(f, func() {
+		existed, err := ac.client.Delete(policy, key)
+		f.complete(nil, existed, err)
+	})
+}
+
+// OperateAsync performs the operations like Client.Operate.
+func (ac *AsyncClient) OperateAsync(policy *WritePolicy, key *Key, operations ...*Operation) *Future {
+	f := newFuture()
+	return ac.submit(f, func() {
+		rec, err := ac.client.Operate(policy, key, operations...)
+		f.complete(rec, false, err)
+	})
+}
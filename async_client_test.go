@@ -0,0 +1,88 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+var _ = Describe("Async Client Test", func() {
+	initTestVars()
+
+	var ns = "test"
+	var set = randString(50)
+	var client *Client
+	var err error
+
+	BeforeEach(func() {
+		client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("must run the commands on the workers", func() {
+		async := NewAsyncClient(client, 4, 16)
+		defer async.Close()
+
+		keys := make([]*Key, 100)
+		futures := make([]*Future, len(keys))
+		for i := range keys {
+			keys[i], err = NewKey(ns, set, i)
+			Expect(err).ToNot(HaveOccurred())
+			futures[i] = async.PutBinsAsync(nil, keys[i], NewBin("bin", i))
+		}
+		for _, f := range futures {
+			Expect(f.Wait()).ToNot(HaveOccurred())
+		}
+
+		for i := range keys {
+			futures[i] = async.GetAsync(nil, keys[i])
+		}
+		for i, f := range futures {
+			<-f.Done()
+			rec, err := f.Record()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins["bin"]).To(Equal(i))
+		}
+
+		existed, err := async.DeleteAsync(nil, keys[0]).Existed()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(existed).To(BeTrue())
+
+		existed, err = async.ExistsAsync(nil, keys[0]).Existed()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(existed).To(BeFalse())
+	})
+
+	It("must reject the commands after Close", func() {
+		async := NewAsyncClient(client, 1, 0)
+		async.Close()
+
+		key, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = async.PutBinsAsync(nil, key, NewBin("bin", 1)).Wait()
+		Expect(err).To(HaveOccurred())
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(COMMAND_REJECTED))
+	})
+
+})
@@ -0,0 +1,234 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backup reads and writes logical backups in the text format of the
+// asbackup and asrestore tools (.asb files), and takes and restores them with
+// parallel scans and writes, without installing the C tools.
+//
+//	f, err := os.Create("test.asb")
+//	...
+//	n, err := backup.Backup(f, client, nil, "test", "", 8)
+//
+// User keys are only backed up for records stored with their key; other records
+// are restored by digest.
+package backup
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/types"
+)
+
+// version is the version of the backup format written.
+const version = "3.1"
+
+// Backup scans the set of the namespace, or the whole namespace if setName is
+// empty, and writes its records to w in the backup format.
+// The partitions are split in parallel ranges, each read by its own scan.
+// The backup is aborted on the first error returned by a node or by w.
+// It returns the number of records written.
+// If the policy is nil, the default relevant policy will be used.
+func Backup(w io.Writer, client ClientIfc, policy *ScanPolicy, namespace string, setName string, parallel int) (int, error) {
+	filters, err := SplitPartitionFilters(parallel)
+	if err != nil {
+		return 0, err
+	}
+
+	if policy == nil {
+		policy = NewScanPolicy()
+	}
+
+	writer := NewWriter(w, namespace)
+
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		count    int
+		firstErr error
+		abort    = make(chan struct{})
+	)
+
+	fail := func(err error) {
+		mutex.Lock()
+		if firstErr == nil {
+			firstErr = err
+			close(abort)
+		}
+		mutex.Unlock()
+	}
+
+	for _, filter := range filters {
+		// do not modify the caller's policy
+		p := *policy
+		mp := *NewMultiPolicy()
+		if policy.MultiPolicy != nil {
+			mp = *policy.MultiPolicy
+		}
+		mp.PartitionFilter = filter
+		p.MultiPolicy = &mp
+
+		recordset, err := client.ScanAll(&p, namespace, setName)
+		if err != nil {
+			fail(err)
+			break
+		}
+
+		wg.Add(1)
+		go func(recordset *Recordset) {
+			defer wg.Done()
+
+			results := recordset.Results()
+			defer closeRecordset(recordset, results)
+
+			for res := range results {
+				select {
+				case <-abort:
+					return
+				default:
+				}
+
+				if res.Err != nil {
+					fail(res.Err)
+					return
+				}
+
+				mutex.Lock()
+				err := writer.Write(NewEntry(res.Record))
+				if err == nil {
+					count++
+				}
+				mutex.Unlock()
+
+				if err != nil {
+					fail(err)
+					return
+				}
+			}
+		}(recordset)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return count, firstErr
+	}
+	return count, writer.Flush()
+}
+
+// Restore reads the backup from r and writes its records with parallel workers.
+// Records which expired since the backup are skipped; the others keep their
+// expiration time. User keys in the backup are stored with the records.
+// The restore is aborted on the first error returned by a node or by r.
+// It returns the number of records written.
+// If the policy is nil, the default relevant policy will be used.
+func Restore(client ClientIfc, policy *WritePolicy, r io.Reader, parallel int) (int, error) {
+	if parallel <= 0 {
+		return 0, types.NewAerospikeError(types.PARAMETER_ERROR, "Restore requires at least one worker")
+	}
+
+	reader, err := NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+
+	if policy == nil {
+		policy = NewWritePolicy(0, 0)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		count    int64
+		firstErr error
+		abort    = make(chan struct{})
+		entries  = make(chan *Entry, parallel)
+	)
+
+	fail := func(err error) {
+		mutex.Lock()
+		if firstErr == nil {
+			firstErr = err
+			close(abort)
+		}
+		mutex.Unlock()
+	}
+
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for entry := range entries {
+				select {
+				case <-abort:
+					return
+				default:
+				}
+
+				// do not modify the caller's policy
+				p := *policy
+				if entry.VoidTime == 0 {
+					p.Expiration = -1
+				} else {
+					ttl := entry.VoidTime + types.CITRUSLEAF_EPOCH - time.Now().Unix()
+					if ttl <= 0 {
+						continue
+					}
+					p.Expiration = int32(ttl)
+				}
+				p.SendKey = userKey(entry.Key) != nil
+
+				if err := client.Put(&p, entry.Key, entry.Bins); err != nil {
+					fail(err)
+					return
+				}
+				atomic.AddInt64(&count, 1)
+			}
+		}()
+	}
+
+read:
+	for {
+		entry, err := reader.Read()
+		if err != nil {
+			if err != io.EOF {
+				fail(err)
+			}
+			break
+		}
+
+		select {
+		case entries <- entry:
+		case <-abort:
+			break read
+		}
+	}
+	close(entries)
+
+	wg.Wait()
+	return int(atomic.LoadInt64(&count)), firstErr
+}
+
+// closeRecordset closes the recordset, and discards the results left.
+func closeRecordset(recordset *Recordset, results <-chan *Result) {
+	go func() {
+		for range results {
+		}
+	}()
+	recordset.Close()
+}
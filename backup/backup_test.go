@@ -0,0 +1,244 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/types"
+)
+
+func TestBackup(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Aerospike Client Library Backup Suite")
+}
+
+var _ = Describe("Backup Test", func() {
+
+	roundTrip := func(entries ...*Entry) []*Entry {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, "test")
+		for _, entry := range entries {
+			Expect(w.Write(entry)).ToNot(HaveOccurred())
+		}
+		Expect(w.Flush()).ToNot(HaveOccurred())
+
+		r, err := NewReader(&buf)
+		Expect(err).ToNot(HaveOccurred())
+
+		var read []*Entry
+		for {
+			entry, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).ToNot(HaveOccurred())
+			read = append(read, entry)
+		}
+		return read
+	}
+
+	It("should write the header and records in the asbackup format", func() {
+		key, err := NewKey("test", "my set", "k 1")
+		Expect(err).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		w := NewWriter(&buf, "test")
+		Expect(w.Write(&Entry{Key: key, Generation: 2, VoidTime: 300, Bins: BinMap{"b": true, "a": 1}})).ToNot(HaveOccurred())
+		Expect(w.Flush()).ToNot(HaveOccurred())
+
+		lines := strings.Split(buf.String(), "\n")
+		Expect(lines[:5]).To(Equal([]string{"Version 3.1", "# namespace test", "# first-file", "+ k S 3 k 1", "+ n test"}))
+		Expect(lines[6:]).To(Equal([]string{"+ s my\\ set", "+ g 2", "+ t 300", "+ b 2", "- I a 1", "- Z b T", ""}))
+	})
+
+	It("should read back all the bin types", func() {
+		key, err := NewKey("test", "set", 7)
+		Expect(err).ToNot(HaveOccurred())
+
+		bins := BinMap{
+			"nil":    nil,
+			"bool":   false,
+			"int":    -42,
+			"float":  1.25,
+			"str":    "multi\nline string",
+			"geo":    NewGeoJSONValue(`{"type":"Point","coordinates":[1,2]}`),
+			"blob":   []byte{0, 1, 2, 255},
+			"hll":    NewHLLValue([]byte{3, 4}),
+			"list":   []interface{}{"a", "b"},
+			"map":    map[interface{}]interface{}{"k": "v"},
+			"a name": "escaped",
+		}
+
+		entries := roundTrip(&Entry{Key: key, Generation: 3, VoidTime: 1000, Bins: bins})
+		Expect(len(entries)).To(Equal(1))
+
+		entry := entries[0]
+		Expect(entry.Key.Namespace()).To(Equal("test"))
+		Expect(entry.Key.SetName()).To(Equal("set"))
+		Expect(entry.Key.Digest()).To(Equal(key.Digest()))
+		Expect(entry.Key.Value().GetObject()).To(Equal(7))
+		Expect(entry.Generation).To(Equal(3))
+		Expect(entry.VoidTime).To(Equal(int64(1000)))
+
+		Expect(entry.Bins).To(Equal(bins))
+	})
+
+	It("should read back records without user key and set", func() {
+		key, err := NewKeyWithDigest("test", "", nil, make([]byte, 20))
+		Expect(err).ToNot(HaveOccurred())
+
+		entries := roundTrip(&Entry{Key: key, Bins: BinMap{"bin": "v"}})
+		Expect(len(entries)).To(Equal(1))
+		Expect(entries[0].Key.SetName()).To(Equal(""))
+		Expect(entries[0].Key.Value().GetObject()).To(BeNil())
+		Expect(entries[0].Key.Digest()).To(Equal(make([]byte, 20)))
+		Expect(entries[0].Bins).To(Equal(BinMap{"bin": "v"}))
+	})
+
+	It("should skip metadata, indexes and UDFs, and read compact blobs", func() {
+		data := "Version 3.0\n" +
+			"# namespace test\n" +
+			"* i test set idx 1 bin N\n" +
+			"* u L my.lua 12 line1\nline2\n\n" +
+			"+ k B! 2 \x01\n\n" +
+			"+ n test\n" +
+			"+ d AAAAAAAAAAAAAAAAAAAAAAAAAAA=\n" +
+			"+ g 1\n+ t 0\n+ b 1\n" +
+			"- B! bin 3 a\nb\n"
+
+		r, err := NewReader(strings.NewReader(data))
+		Expect(err).ToNot(HaveOccurred())
+
+		entry, err := r.Read()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entry.Key.Value().GetObject()).To(Equal([]byte{1, '\n'}))
+		Expect(entry.Bins).To(Equal(BinMap{"bin": []byte("a\nb")}))
+
+		_, err = r.Read()
+		Expect(err).To(Equal(io.EOF))
+	})
+
+	It("should reject invalid files", func() {
+		_, err := NewReader(strings.NewReader("Version 9.9\n"))
+		Expect(err).To(HaveOccurred())
+
+		r, err := NewReader(strings.NewReader("Version 3.1\n+ n test\n+ d AAAA"))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = r.Read()
+		Expect(err).To(Equal(io.ErrUnexpectedEOF))
+
+		r, err = NewReader(strings.NewReader("Version 3.1\n+ n test\n+ d AAAAAAAAAAAAAAAAAAAAAAAAAAA=\n+ g x\n"))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = r.Read()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not write unsupported bin values", func() {
+		key, err := NewKey("test", "set", 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		w := NewWriter(&buf, "test")
+		Expect(w.Write(&Entry{Key: key, Bins: BinMap{"bin": struct{}{}}})).To(HaveOccurred())
+		Expect(w.Flush()).ToNot(HaveOccurred())
+		Expect(buf.String()).To(Equal("Version 3.1\n# namespace test\n# first-file\n"))
+	})
+
+	It("should write unsigned integers which fit in an int64", func() {
+		key, err := NewKey("test", "set", 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		entries := roundTrip(&Entry{Key: key, Bins: BinMap{"uint": uint(7), "uint64": uint64(math.MaxInt64)}})
+		Expect(entries[0].Bins).To(Equal(BinMap{"uint": 7, "uint64": math.MaxInt64}))
+
+		var buf bytes.Buffer
+		w := NewWriter(&buf, "test")
+		Expect(w.Write(&Entry{Key: key, Bins: BinMap{"bin": uint64(math.MaxInt64) + 1}})).To(HaveOccurred())
+	})
+
+	It("should back up with a policy without MultiPolicy", func() {
+		source := NewFakeClient()
+		key, err := NewKey("test", "set", 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(source.Put(nil, key, BinMap{"i": 1})).ToNot(HaveOccurred())
+
+		var buf bytes.Buffer
+		n, err := Backup(&buf, source, &ScanPolicy{}, "test", "", 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(Equal(1))
+	})
+
+	It("should back up and restore namespaces in parallel", func() {
+		source := NewFakeClient()
+		policy := NewWritePolicy(0, 0)
+		policy.SendKey = true
+		for i := 0; i < 100; i++ {
+			key, err := NewKey("test", "set", i)
+			Expect(err).ToNot(HaveOccurred())
+			if i%10 == 0 {
+				policy.Expiration = 3600
+			} else {
+				policy.Expiration = -1
+			}
+			Expect(source.Put(policy, key, BinMap{"i": i, "s": "str"})).ToNot(HaveOccurred())
+		}
+
+		var buf bytes.Buffer
+		n, err := Backup(&buf, source, nil, "test", "", 4)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(Equal(100))
+
+		target := NewFakeClient()
+		n, err = Restore(target, nil, &buf, 4)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(Equal(100))
+		Expect(target.Len()).To(Equal(100))
+
+		key, err := NewKey("test", "set", 10)
+		Expect(err).ToNot(HaveOccurred())
+		rec, err := target.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(BinMap{"i": 10, "s": "str"}))
+		Expect(rec.Expiration > 3590 && rec.Expiration <= 3600).To(BeTrue())
+
+		err = target.ScanAllFunc(nil, "test", "set", func(rec *Record) error {
+			Expect(rec.Key.Value().GetObject()).To(Equal(rec.Bins["i"]))
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should compute the void time of records", func() {
+		key, err := NewKey("test", "set", 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		never := NewEntry(&Record{Key: key, Expiration: types.TTL(0)})
+		Expect(never.VoidTime).To(Equal(int64(0)))
+
+		entry := NewEntry(&Record{Key: key, Expiration: 100})
+		expected := time.Now().Unix() - types.CITRUSLEAF_EPOCH + 100
+		Expect(entry.VoidTime >= expected-1 && entry.VoidTime <= expected).To(BeTrue())
+	})
+})
@@ -0,0 +1,470 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+
+	. "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/types"
+)
+
+// Reader reads entries from a backup file.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader returns a reader of the backup file from r, after reading the
+// file header.
+func NewReader(r io.Reader) (*Reader, error) {
+	rd := &Reader{r: bufio.NewReader(r)}
+
+	line, err := rd.readField(' ')
+	if err != nil {
+		return nil, err
+	}
+	if line != "Version" {
+		return nil, parseError("missing version header")
+	}
+	if line, err = rd.readField('\n'); err != nil {
+		return nil, err
+	}
+	if line != "3.0" && line != version {
+		return nil, parseError("unsupported version %s", line)
+	}
+	return rd, nil
+}
+
+// Read returns the next entry of the backup file, or io.EOF at the end of the file.
+// Metadata, secondary index and UDF definitions are skipped.
+// The language specific blobs of older clients (Java, C#, Python, Ruby, PHP and
+// Erlang) are read as plain []byte blobs, since the client has no value types
+// for them; they are restored as BLOB bins.
+func (rd *Reader) Read() (*Entry, error) {
+	for {
+		b, err := rd.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		switch b {
+		case '#':
+			if err := rd.skipLine(); err != nil {
+				return nil, err
+			}
+		case '*':
+			if err := rd.skipGlobal(); err != nil {
+				return nil, err
+			}
+		case '+':
+			return rd.readRecord()
+		default:
+			return nil, parseError("unexpected line starting with %q", b)
+		}
+	}
+}
+
+// skipGlobal skips a secondary index or UDF definition.
+func (rd *Reader) skipGlobal() error {
+	if err := rd.expect(' '); err != nil {
+		return err
+	}
+	kind, err := rd.readByte()
+	if err != nil {
+		return err
+	}
+
+	if kind != 'u' {
+		return rd.skipLine()
+	}
+
+	// UDF: * u <type> <name> <length> <content>
+	if err := rd.expect(' '); err != nil {
+		return err
+	}
+	if _, err := rd.readField(' '); err != nil {
+		return err
+	}
+	if _, err := rd.readField(' '); err != nil {
+		return err
+	}
+	_, err = rd.readData()
+	return err
+}
+
+func (rd *Reader) readRecord() (*Entry, error) {
+	var key interface{}
+
+	// the leading '+' of the first line was already read
+	field, err := rd.readLine(true)
+	if err != nil {
+		return nil, err
+	}
+
+	if field == 'k' {
+		if key, err = rd.readKey(); err != nil {
+			return nil, err
+		}
+		if field, err = rd.readLine(false); err != nil {
+			return nil, err
+		}
+	}
+
+	if field != 'n' {
+		return nil, parseError("missing namespace")
+	}
+	namespace, err := rd.readField('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rd.expectLine('d'); err != nil {
+		return nil, err
+	}
+	encoded, err := rd.readField('\n')
+	if err != nil {
+		return nil, err
+	}
+	digest, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, parseError("invalid digest: %s", err)
+	}
+
+	setName := ""
+	if field, err = rd.readLine(false); err != nil {
+		return nil, err
+	}
+	if field == 's' {
+		if setName, err = rd.readField('\n'); err != nil {
+			return nil, err
+		}
+		if field, err = rd.readLine(false); err != nil {
+			return nil, err
+		}
+	}
+
+	if field != 'g' {
+		return nil, parseError("missing generation")
+	}
+	generation, err := rd.readInt('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rd.expectLine('t'); err != nil {
+		return nil, err
+	}
+	voidTime, err := rd.readInt('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rd.expectLine('b'); err != nil {
+		return nil, err
+	}
+	count, err := rd.readInt('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := NewKeyWithDigest(namespace, setName, key, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{Key: k, Bins: make(BinMap, count), Generation: int(generation), VoidTime: voidTime}
+	for i := int64(0); i < count; i++ {
+		name, value, err := rd.readBin()
+		if err != nil {
+			return nil, err
+		}
+		entry.Bins[name] = value
+	}
+	return entry, nil
+}
+
+// readKey reads the user key of a record: + k <type> <value>
+func (rd *Reader) readKey() (interface{}, error) {
+	kind, compact, err := rd.readType()
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case 'I':
+		n, err := rd.readInt('\n')
+		return clientInt(n), err
+	case 'D':
+		return rd.readFloat()
+	case 'S':
+		data, err := rd.readData()
+		return string(data), err
+	case 'B':
+		return rd.readBlob(compact)
+	}
+	return nil, parseError("unsupported key type %q", kind)
+}
+
+// readBin reads a bin: - <type> <name> <value>
+func (rd *Reader) readBin() (string, interface{}, error) {
+	if err := rd.expect('-'); err != nil {
+		return "", nil, err
+	}
+	if err := rd.expect(' '); err != nil {
+		return "", nil, err
+	}
+	kind, compact, err := rd.readType()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if kind == 'N' {
+		name, err := rd.readField('\n')
+		return name, nil, err
+	}
+
+	name, err := rd.readField(' ')
+	if err != nil {
+		return "", nil, err
+	}
+
+	var value interface{}
+	switch kind {
+	case 'Z':
+		var b string
+		if b, err = rd.readField('\n'); err == nil {
+			if b != "T" && b != "F" {
+				return "", nil, parseError("invalid boolean %s in bin `%s`", b, name)
+			}
+			value = b == "T"
+		}
+	case 'I':
+		var n int64
+		n, err = rd.readInt('\n')
+		value = clientInt(n)
+	case 'D':
+		value, err = rd.readFloat()
+	case 'S', 'G':
+		var data []byte
+		if data, err = rd.readData(); err == nil {
+			if kind == 'S' {
+				value = string(data)
+			} else {
+				value = NewGeoJSONValue(string(data))
+			}
+		}
+	case 'B', 'J', 'C', 'P', 'R', 'H', 'E':
+		// language specific blobs lose their particle type
+		value, err = rd.readBlob(compact)
+	case 'Y':
+		var data []byte
+		if data, err = rd.readBlob(compact); err == nil {
+			value = NewHLLValue(data)
+		}
+	case 'L', 'M':
+		var data []byte
+		if data, err = rd.readBlob(compact); err == nil {
+			value, err = UnmarshalMsgPack(data)
+		}
+	default:
+		return "", nil, parseError("unsupported type %q of bin `%s`", kind, name)
+	}
+
+	if err != nil {
+		return "", nil, err
+	}
+	return name, value, nil
+}
+
+// readLine reads the start of a record line: + <field> <space>
+func (rd *Reader) readLine(first bool) (byte, error) {
+	if !first {
+		if err := rd.expect('+'); err != nil {
+			return 0, err
+		}
+	}
+	if err := rd.expect(' '); err != nil {
+		return 0, err
+	}
+	field, err := rd.readByte()
+	if err != nil {
+		return 0, err
+	}
+	return field, rd.expect(' ')
+}
+
+func (rd *Reader) expectLine(field byte) error {
+	f, err := rd.readLine(false)
+	if err != nil {
+		return err
+	}
+	if f != field {
+		return parseError("expected field %q, found %q", field, f)
+	}
+	return nil
+}
+
+// readType reads a type, followed by '!' if its data is not base64 encoded, and a space.
+func (rd *Reader) readType() (kind byte, compact bool, err error) {
+	if kind, err = rd.readByte(); err != nil {
+		return 0, false, err
+	}
+	b, err := rd.readByte()
+	if err != nil {
+		return 0, false, err
+	}
+	if b == '!' {
+		compact = true
+		b, err = rd.readByte()
+		if err != nil {
+			return 0, false, err
+		}
+	}
+	if b != ' ' {
+		return 0, false, parseError("expected ' ', found %q", b)
+	}
+	return kind, compact, nil
+}
+
+// readField reads an escaped field ending with delim.
+func (rd *Reader) readField(delim byte) (string, error) {
+	var field []byte
+	for {
+		b, err := rd.readByte()
+		if err != nil {
+			return "", err
+		}
+
+		switch b {
+		case '\\':
+			if b, err = rd.readByte(); err != nil {
+				return "", err
+			}
+		case ' ', '\n':
+			if b != delim {
+				return "", parseError("expected %q, found %q after %q", delim, b, field)
+			}
+			return string(field), nil
+		}
+		field = append(field, b)
+	}
+}
+
+func (rd *Reader) readInt(delim byte) (int64, error) {
+	field, err := rd.readField(delim)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(field, 10, 64)
+	if err != nil {
+		return 0, parseError("invalid integer %s", field)
+	}
+	return n, nil
+}
+
+func (rd *Reader) readFloat() (float64, error) {
+	field, err := rd.readField('\n')
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, parseError("invalid float %s", field)
+	}
+	return f, nil
+}
+
+// readData reads data prefixed by its length: <length> <data>\n
+func (rd *Reader) readData() ([]byte, error) {
+	length, err := rd.readInt(' ')
+	if err != nil {
+		return nil, err
+	}
+	if length < 0 {
+		return nil, parseError("invalid length %d", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(rd.r, data); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return data, rd.expect('\n')
+}
+
+// readBlob reads data which is base64 encoded unless compact.
+func (rd *Reader) readBlob(compact bool) ([]byte, error) {
+	data, err := rd.readData()
+	if err != nil || compact {
+		return data, err
+	}
+
+	decoded := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+	n, err := base64.StdEncoding.Decode(decoded, data)
+	if err != nil {
+		return nil, parseError("invalid base64 data: %s", err)
+	}
+	return decoded[:n], nil
+}
+
+func (rd *Reader) expect(c byte) error {
+	b, err := rd.readByte()
+	if err != nil {
+		return err
+	}
+	if b != c {
+		return parseError("expected %q, found %q", c, b)
+	}
+	return nil
+}
+
+// skipLine skips the rest of the line.
+func (rd *Reader) skipLine() error {
+	for {
+		_, err := rd.r.ReadSlice('\n')
+		if err != bufio.ErrBufferFull {
+			return unexpectedEOF(err)
+		}
+	}
+}
+
+// readByte reads a byte which is expected to exist.
+func (rd *Reader) readByte() (byte, error) {
+	b, err := rd.r.ReadByte()
+	return b, unexpectedEOF(err)
+}
+
+// clientInt returns the integer as the client decodes the integers received
+// from a server: an int, unless it does not fit.
+func clientInt(n int64) interface{} {
+	if int64(int(n)) == n {
+		return int(n)
+	}
+	return n
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func parseError(format string, args ...interface{}) error {
+	return types.NewAerospikeError(types.PARSE_ERROR, "Invalid backup file: "+fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,202 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/types"
+)
+
+// Entry is a record of a backup file.
+type Entry struct {
+	// Key is the key of the record. Its user key is only available if it was
+	// stored with the record.
+	Key *Key
+
+	// Bins are the bins of the record.
+	Bins BinMap
+
+	// Generation is the generation of the record.
+	Generation int
+
+	// VoidTime is the time the record expires, in seconds since the citrusleaf
+	// epoch (2010-01-01 UTC), or 0 if it never expires.
+	VoidTime int64
+}
+
+// NewEntry returns the backup entry of a record read from the server.
+func NewEntry(rec *Record) *Entry {
+	// records that never expire have a void time of 0, give or take the second
+	// their TTL was computed in
+	voidTime := time.Now().Unix() - types.CITRUSLEAF_EPOCH + int64(rec.Expiration)
+	if voidTime <= 1 {
+		voidTime = 0
+	}
+
+	return &Entry{
+		Key:        rec.Key,
+		Bins:       rec.Bins,
+		Generation: rec.Generation,
+		VoidTime:   voidTime,
+	}
+}
+
+// Writer writes entries to a backup file.
+type Writer struct {
+	w   *bufio.Writer
+	buf bytes.Buffer
+}
+
+// NewWriter returns a writer of a backup file of the namespace to w, and writes
+// the file header. Call Flush after the last entry.
+func NewWriter(w io.Writer, namespace string) *Writer {
+	wr := &Writer{w: bufio.NewWriter(w)}
+	fmt.Fprintf(wr.w, "Version %s\n# namespace %s\n# first-file\n", version, escape(namespace))
+	return wr
+}
+
+// Write writes an entry. Its bins are written sorted by name.
+// Nothing is written if the entry holds a value that can't be backed up.
+func (wr *Writer) Write(entry *Entry) error {
+	wr.buf.Reset()
+	buf := &wr.buf
+	key := entry.Key
+
+	switch v := userKey(key).(type) {
+	case nil:
+	case int, int64:
+		fmt.Fprintf(buf, "+ k I %d\n", v)
+	case string:
+		fmt.Fprintf(buf, "+ k S %d %s\n", len(v), v)
+	case []byte:
+		data := base64.StdEncoding.EncodeToString(v)
+		fmt.Fprintf(buf, "+ k B %d %s\n", len(data), data)
+	default:
+		return types.NewAerospikeError(types.PARAMETER_ERROR, fmt.Sprintf("Unsupported user key type: %T", v))
+	}
+
+	fmt.Fprintf(buf, "+ n %s\n", escape(key.Namespace()))
+	fmt.Fprintf(buf, "+ d %s\n", base64.StdEncoding.EncodeToString(key.Digest()))
+	if key.SetName() != "" {
+		fmt.Fprintf(buf, "+ s %s\n", escape(key.SetName()))
+	}
+	fmt.Fprintf(buf, "+ g %d\n+ t %d\n+ b %d\n", entry.Generation, entry.VoidTime, len(entry.Bins))
+
+	names := make([]string, 0, len(entry.Bins))
+	for name := range entry.Bins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := writeBin(buf, escape(name), entry.Bins[name]); err != nil {
+			return err
+		}
+	}
+
+	_, err := wr.w.Write(buf.Bytes())
+	return err
+}
+
+// Flush writes the buffered data to the underlying writer.
+func (wr *Writer) Flush() error {
+	return wr.w.Flush()
+}
+
+func writeBin(buf *bytes.Buffer, name string, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		fmt.Fprintf(buf, "- N %s\n", name)
+	case bool:
+		if v {
+			fmt.Fprintf(buf, "- Z %s T\n", name)
+		} else {
+			fmt.Fprintf(buf, "- Z %s F\n", name)
+		}
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		fmt.Fprintf(buf, "- I %s %d\n", name, v)
+	case uint:
+		return writeBin(buf, name, uint64(v))
+	case uint64:
+		// like NewValue, only the values which fit in an int64 are supported
+		if v > math.MaxInt64 {
+			return types.NewAerospikeError(types.PARAMETER_ERROR, fmt.Sprintf("Value of bin `%s` does not fit in an int64: %d", name, v))
+		}
+		fmt.Fprintf(buf, "- I %s %d\n", name, v)
+	case float32:
+		fmt.Fprintf(buf, "- D %s %s\n", name, strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case float64:
+		fmt.Fprintf(buf, "- D %s %s\n", name, strconv.FormatFloat(v, 'g', -1, 64))
+	case string:
+		fmt.Fprintf(buf, "- S %s %d %s\n", name, len(v), v)
+	case GeoJSONValue:
+		fmt.Fprintf(buf, "- G %s %d %s\n", name, len(v), string(v))
+	case HLLValue:
+		writeBlob(buf, 'Y', name, v)
+	case []byte:
+		writeBlob(buf, 'B', name, v)
+	case Value:
+		return writeBin(buf, name, v.GetObject())
+	default:
+		var particle byte
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Slice, reflect.Array:
+			particle = 'L'
+		case reflect.Map:
+			particle = 'M'
+		default:
+			return types.NewAerospikeError(types.PARAMETER_ERROR, fmt.Sprintf("Unsupported type for bin `%s`: %T", name, value))
+		}
+
+		data, err := MarshalMsgPack(value)
+		if err != nil {
+			return err
+		}
+		writeBlob(buf, particle, name, data)
+	}
+	return nil
+}
+
+func writeBlob(buf *bytes.Buffer, particle byte, name string, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	fmt.Fprintf(buf, "- %c %s %d %s\n", particle, name, len(encoded), encoded)
+}
+
+// userKey returns the user key of the key, if it is available.
+func userKey(key *Key) interface{} {
+	if key.Value() == nil {
+		return nil
+	}
+	return key.Value().GetObject()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, " ", `\ `, "\n", "\\\n")
+
+// escape escapes the separators of the backup format in names.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
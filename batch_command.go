@@ -15,7 +15,9 @@
 package aerospike
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 
 	. "github.com/aerospike/aerospike-client-go/types"
 	// . "github.com/aerospike/aerospike-client-go/types/atomic"
@@ -34,20 +36,51 @@ type baseMultiCommand struct {
 	*baseCommand
 
 	recordset *Recordset
+
+	// partitions of a partition scan or query assigned to the node
+	partitions *nodePartitions
+
+	// secondary index value of the last record of a partition query
+	bval int64
+
+	// decompressed message the records are parsed from, if the response was compressed
+	inflated *bytes.Reader
 }
 
 func newMultiCommand(node *Node, recordset *Recordset) *baseMultiCommand {
-	return &baseMultiCommand{
+	cmd := &baseMultiCommand{
 		baseCommand: &baseCommand{node: node},
 		recordset:   recordset,
 	}
+
+	// closing the recordset aborts the connection being opened
+	if recordset != nil {
+		cmd.ctx = recordset.ctx
+	}
+	return cmd
 }
 
 func (cmd *baseMultiCommand) getNode(ifc command) (*Node, error) {
 	return cmd.node, nil
 }
 
-func (cmd *baseMultiCommand) parseResult(ifc command, conn *Connection) error {
+func (cmd *baseMultiCommand) parseResult(ifc command, conn *Connection) (err error) {
+	if cmd.recordset != nil {
+		// unblock pending reads as soon as the recordset is closed
+		stop := conn.interruptOn(cmd.recordset.cancelled)
+		defer func() {
+			// an interrupted connection must not be put back in the pool
+			if stop() && err == nil {
+				err = NewAerospikeError(SCAN_TERMINATED)
+			}
+		}()
+	}
+
+	// decompress the messages of scans and queries on the inflate pool, if any
+	if pool := cmd.node.cluster.inflatePool; pool != nil && cmd.recordset != nil && ifc.getPolicy(ifc).GetBasePolicy().UseCompression {
+		return cmd.parseInflatedResults(ifc, conn, pool)
+	}
+
 	// Read socket into receive buffer one record at a time.  Do not read entire receive size
 	// because the receive buffer would be too big.
 	status := true
@@ -61,9 +94,24 @@ func (cmd *baseMultiCommand) parseResult(ifc command, conn *Connection) error {
 		size := Buffer.BytesToInt64(cmd.dataBuffer, 0)
 		receiveSize := int(size & 0xFFFFFFFFFFFF)
 
+		if receiveSize > 0 && isCompressed(cmd.dataBuffer) {
+			// parse the records from the decompressed message instead of the connection
+			if err := cmd.readBytes(receiveSize); err != nil {
+				return err
+			}
+			msg, err := inflate(cmd.dataBuffer[:receiveSize])
+			if err != nil {
+				return err
+			}
+			cmd.inflated = bytes.NewReader(msg[8:])
+			receiveSize = len(msg) - 8
+		}
+
 		if receiveSize > 0 {
 			var err error
-			if status, err = ifc.parseRecordResults(ifc, receiveSize); err != nil {
+			status, err = ifc.parseRecordResults(ifc, receiveSize)
+			cmd.inflated = nil
+			if err != nil {
 				return err
 			}
 		} else {
@@ -73,12 +121,26 @@ func (cmd *baseMultiCommand) parseResult(ifc command, conn *Connection) error {
 	return nil
 }
 
+// partitionDone handles the partition completion messages of partition scans and queries.
+// Returns true if the message was a partition completion message.
+func (cmd *baseMultiCommand) partitionDone(info3 int, resultCode ResultCode) bool {
+	if cmd.partitions == nil || (info3&_INFO3_PARTITION_DONE) == 0 {
+		return false
+	}
+
+	// generation field is overloaded as the partition id
+	partitionId := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 6)))
+	cmd.partitions.partitionDone(partitionId, resultCode)
+	return true
+}
+
 func (cmd *baseMultiCommand) parseKey(fieldCount int) (*Key, error) {
 	var digest []byte
 	var namespace, setName string
 	var userKey Value
 	var err error
 
+	cmd.bval = 0
 	for i := 0; i < fieldCount; i++ {
 		if err = cmd.readBytes(4); err != nil {
 			return nil, err
@@ -104,12 +166,49 @@ func (cmd *baseMultiCommand) parseKey(fieldCount int) (*Key, error) {
 			if userKey, err = bytesToKeyValue(int(cmd.dataBuffer[1]), cmd.dataBuffer, 2, size-1); err != nil {
 				return nil, err
 			}
+		case BVAL_ARRAY:
+			cmd.bval = Buffer.LittleBytesToInt64(cmd.dataBuffer, 1)
 		}
 	}
 
 	return &Key{namespace: namespace, setName: setName, digest: digest, userKey: userKey}, nil
 }
 
+// readRawBins reads the bins of a record without decoding them.
+// Their particles are copied into a buffer kept with the record.
+func (cmd *baseMultiCommand) readRawBins(opCount int) ([]rawBin, []byte, error) {
+	if opCount == 0 {
+		return nil, nil, nil
+	}
+
+	rawBins := make([]rawBin, opCount)
+	var rawData []byte
+	for i := 0; i < opCount; i++ {
+		if err := cmd.readBytes(8); err != nil {
+			return nil, nil, err
+		}
+
+		opSize := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 0)))
+		particleType := int(cmd.dataBuffer[5])
+		nameSize := int(cmd.dataBuffer[7])
+
+		if err := cmd.readBytes(nameSize); err != nil {
+			return nil, nil, err
+		}
+		name := string(cmd.dataBuffer[:nameSize])
+
+		particleBytesSize := int(opSize - (4 + nameSize))
+		if err := cmd.readBytes(particleBytesSize); err != nil {
+			return nil, nil, err
+		}
+
+		rawBins[i] = rawBin{name: name, particleType: particleType, offset: len(rawData), size: particleBytesSize}
+		rawData = append(rawData, cmd.dataBuffer[:particleBytesSize]...)
+	}
+
+	return rawBins, rawData, nil
+}
+
 func (cmd *baseMultiCommand) readBytes(length int) error {
 	if length > len(cmd.dataBuffer) {
 		// Corrupted data streams can result in a huge length.
@@ -120,7 +219,12 @@ func (cmd *baseMultiCommand) readBytes(length int) error {
 		cmd.dataBuffer = make([]byte, length)
 	}
 
-	_, err := cmd.conn.Read(cmd.dataBuffer, length)
+	var err error
+	if cmd.inflated != nil {
+		_, err = io.ReadFull(cmd.inflated, cmd.dataBuffer[:length])
+	} else {
+		_, err = cmd.conn.Read(cmd.dataBuffer, length)
+	}
 	if err != nil {
 		return err
 	}
@@ -30,6 +30,9 @@ type batchCommandExists struct {
 	keys           []*Key
 	existsArray    []bool
 	index          int
+
+	// if set, record and node errors are reported per key instead of failing the batch
+	errs []error
 }
 
 func newBatchCommandExists(
@@ -68,15 +71,15 @@ func (cmd *batchCommandExists) parseRecordResults(ifc command, receiveSize int)
 		}
 
 		resultCode := ResultCode(cmd.dataBuffer[5] & 0xFF)
+		info3 := cmd.dataBuffer[3]
 
 		// The only valid server return codes are "ok" and "not found".
-		// If other return codes are received, then abort the batch.
-		if resultCode != 0 && resultCode != KEY_NOT_FOUND_ERROR {
+		// If other return codes are received, then abort the batch,
+		// unless the errors are reported per key.
+		if resultCode != 0 && resultCode != KEY_NOT_FOUND_ERROR && (cmd.errs == nil || (int(info3)&_INFO3_LAST) == _INFO3_LAST) {
 			return false, NewAerospikeError(resultCode)
 		}
 
-		info3 := cmd.dataBuffer[3]
-
 		// If cmd is the end marker of the response, do not proceed further
 		if (int(info3) & _INFO3_LAST) == _INFO3_LAST {
 			return false, nil
@@ -101,14 +104,24 @@ func (cmd *batchCommandExists) parseRecordResults(ifc command, receiveSize int)
 			// only set the results to true; as a result, no synchronization is needed
 			if resultCode == 0 {
 				cmd.existsArray[offset] = true
+			} else if resultCode != KEY_NOT_FOUND_ERROR {
+				cmd.errs[offset] = NewAerospikeError(resultCode)
 			}
-		} else {
-			Logger.Debug("Unexpected batch key returned: " + key.namespace + "," + Buffer.BytesToHexString(key.digest))
+		} else if cmd.node.cluster.logEnabled(DEBUG) {
+			cmd.node.cluster.logEvent(DEBUG, SUBSYSTEM_COMMAND, cmd.node.GetName(), nil, "Unexpected batch key returned: %s,%s", key.namespace, Buffer.BytesToHexString(key.digest))
 		}
 	}
 	return true, nil
 }
 
 func (cmd *batchCommandExists) Execute() error {
-	return cmd.execute(cmd)
+	err := cmd.execute(cmd)
+	if err != nil && cmd.errs != nil {
+		// report the error for the keys the node did not respond to
+		for _, offset := range cmd.batchNamespace.offsets[cmd.index:cmd.batchNamespace.offsetSize] {
+			cmd.errs[offset] = err
+		}
+		return nil
+	}
+	return err
 }
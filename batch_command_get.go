@@ -32,6 +32,16 @@ type batchCommandGet struct {
 	records        []*Record
 	readAttr       int
 	index          int
+
+	// if set, record and node errors are reported per key instead of failing the batch
+	errs []error
+
+	// if set, each record is read with the bins of its batch read
+	batchReads []*BatchRead
+
+	// if set, records are passed to the callback as they are received instead
+	// of being set in records; a nil record means the key was not found
+	callback func(offset int, record *Record) error
 }
 
 func newBatchCommandGet(
@@ -59,6 +69,9 @@ func (cmd *batchCommandGet) getPolicy(ifc command) Policy {
 }
 
 func (cmd *batchCommandGet) writeBuffer(ifc command) error {
+	if cmd.batchReads != nil {
+		cmd.binNames, cmd.readAttr = batchReadProjection(cmd.batchReads, cmd.batchNamespace.offsets[:cmd.batchNamespace.offsetSize])
+	}
 	return cmd.setBatchGet(cmd.policy, cmd.keys, cmd.batchNamespace, cmd.binNames, cmd.readAttr)
 }
 
@@ -73,15 +86,15 @@ func (cmd *batchCommandGet) parseRecordResults(ifc command, receiveSize int) (bo
 			return false, err
 		}
 		resultCode := ResultCode(cmd.dataBuffer[5] & 0xFF)
+		info3 := int(cmd.dataBuffer[3])
 
 		// The only valid server return codes are "ok" and "not found".
-		// If other return codes are received, then abort the batch.
-		if resultCode != 0 && resultCode != KEY_NOT_FOUND_ERROR {
+		// If other return codes are received, then abort the batch,
+		// unless the errors are reported per key.
+		if resultCode != 0 && resultCode != KEY_NOT_FOUND_ERROR && (cmd.errs == nil || (info3&_INFO3_LAST) == _INFO3_LAST) {
 			return false, NewAerospikeError(resultCode)
 		}
 
-		info3 := int(cmd.dataBuffer[3])
-
 		// If cmd is the end marker of the response, do not proceed further
 		if (info3 & _INFO3_LAST) == _INFO3_LAST {
 			return false, nil
@@ -100,13 +113,28 @@ func (cmd *batchCommandGet) parseRecordResults(ifc command, receiveSize int) (bo
 		cmd.index++
 
 		if bytes.Equal(key.digest, cmd.keys[offset].digest) {
-			if resultCode == 0 {
+			if cmd.callback != nil {
+				var record *Record
+				if resultCode == 0 {
+					if record, err = cmd.parseRecord(key, opCount, generation, expiration); err != nil {
+						return false, err
+					}
+				}
+				if err = cmd.callback(offset, record); err != nil {
+					return false, err
+				}
+			} else if resultCode == 0 {
 				if cmd.records[offset], err = cmd.parseRecord(key, opCount, generation, expiration); err != nil {
 					return false, err
 				}
+				if cmd.batchReads != nil {
+					cmd.batchReads[offset].Record = cmd.batchReads[offset].project(cmd.records[offset])
+				}
+			} else if resultCode != KEY_NOT_FOUND_ERROR {
+				cmd.errs[offset] = NewAerospikeError(resultCode)
 			}
-		} else {
-			Logger.Debug("Unexpected batch key returned: " + string(key.namespace) + "," + Buffer.BytesToHexString(key.digest))
+		} else if cmd.node.cluster.logEnabled(DEBUG) {
+			cmd.node.cluster.logEvent(DEBUG, SUBSYSTEM_COMMAND, cmd.node.GetName(), nil, "Unexpected batch key returned: %s,%s", key.namespace, Buffer.BytesToHexString(key.digest))
 		}
 	}
 	return true, nil
@@ -159,5 +187,13 @@ func (cmd *batchCommandGet) parseRecord(key *Key, opCount int, generation int, e
 }
 
 func (cmd *batchCommandGet) Execute() error {
-	return cmd.execute(cmd)
+	err := cmd.execute(cmd)
+	if err != nil && cmd.errs != nil {
+		// report the error for the keys the node did not respond to
+		for _, offset := range cmd.batchNamespace.offsets[cmd.index:cmd.batchNamespace.offsetSize] {
+			cmd.errs[offset] = err
+		}
+		return nil
+	}
+	return err
 }
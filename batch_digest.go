@@ -0,0 +1,131 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"fmt"
+	"sync"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// PartitionDigest identifies a record by its partition and digest, for tools such as
+// replication and repair which operate on records below the user key level, and never
+// had the user keys of the records.
+type PartitionDigest struct {
+	// PartitionId is the partition of the record. It must match the digest.
+	PartitionId int
+
+	// Digest is the 20 byte digest of the record.
+	Digest []byte
+}
+
+// NewPartitionDigest creates a PartitionDigest from the record digest,
+// determining its partition from the digest.
+func NewPartitionDigest(digest []byte) PartitionDigest {
+	return PartitionDigest{
+		PartitionId: NewPartitionByKey(&Key{digest: digest}).PartitionId,
+		Digest:      digest,
+	}
+}
+
+// digestKeys converts the partition digests to keys of the namespace and set.
+// It returns an error if a digest is invalid, or does not belong to its partition.
+func digestKeys(namespace, setName string, digests []PartitionDigest) ([]*Key, error) {
+	keys := make([]*Key, len(digests))
+	for i, pd := range digests {
+		key := &Key{namespace: namespace, setName: setName}
+		if err := key.SetDigest(pd.Digest); err != nil {
+			return nil, err
+		}
+
+		if partitionId := NewPartitionByKey(key).PartitionId; partitionId != pd.PartitionId {
+			return nil, NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Digest %s belongs to partition %d, not %d.", key, partitionId, pd.PartitionId))
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+// BatchGetByDigest reads multiple records of the namespace by partition and digest in one batch request.
+// The returned records are in positional order with the original digest array order.
+// If a record is not found, the positional record will be nil.
+// The keys of the returned records only have the namespace and digest set.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) BatchGetByDigest(policy *BasePolicy, namespace string, digests []PartitionDigest, binNames ...string) ([]*Record, error) {
+	keys, err := digestKeys(namespace, "", digests)
+	if err != nil {
+		return nil, err
+	}
+	return clnt.BatchGet(policy, keys, binNames...)
+}
+
+// BatchPutByDigest writes the bins of multiple records of the namespace and set by partition and digest.
+// The bins are in positional order with the digests.
+// The user keys of the records are never sent, regardless of the SendKey policy.
+// Digests are grouped by node, and the records on each node are written
+// concurrently with the other nodes.
+// The returned error array is in positional order with the original digest array;
+// a nil entry means the record was written successfully.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) BatchPutByDigest(policy *WritePolicy, namespace, setName string, digests []PartitionDigest, bins [][]*Bin) ([]error, error) {
+	policy = clnt.getUsableWritePolicy(policy)
+
+	// do not modify the caller's policy; there are no user keys to send
+	wp := *policy
+	wp.SendKey = false
+
+	if len(digests) != len(bins) {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "BatchPutByDigest requires the bins of each digest.")
+	}
+
+	keys, err := digestKeys(namespace, setName, digests)
+	if err != nil {
+		return nil, err
+	}
+
+	batchNodes, err := newBatchNodeList(clnt.cluster, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// same array can be used without synchronization;
+	// each goroutine only sets the errors of its own keys
+	errs := make([]error, len(keys))
+
+	var wg sync.WaitGroup
+	for _, batchNode := range batchNodes {
+		for _, bns := range batchNode.BatchNamespaces {
+			wg.Add(1)
+			go func(bns *batchNamespace) {
+				defer wg.Done()
+				for _, offset := range bns.offsets[:bns.offsetSize] {
+					if errs[offset] = clnt.validateWrite(keys[offset], binOperations(WRITE, bins[offset])); errs[offset] != nil {
+						continue
+					}
+					command, err := newWriteCommand(clnt.cluster, &wp, keys[offset], bins[offset], WRITE)
+					if err != nil {
+						errs[offset] = err
+						continue
+					}
+					errs[offset] = command.Execute()
+				}
+			}(bns)
+		}
+	}
+	wg.Wait()
+
+	return errs, nil
+}
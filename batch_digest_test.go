@@ -0,0 +1,53 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batch Digest Test", func() {
+
+	It("should convert partition digests to keys", func() {
+		key, err := NewKey("test", "set", "user-key")
+		Expect(err).ToNot(HaveOccurred())
+
+		pd := NewPartitionDigest(key.Digest())
+		Expect(pd.PartitionId).To(Equal(NewPartitionByKey(key).PartitionId))
+
+		keys, err := digestKeys("test", "other", []PartitionDigest{pd})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(keys)).To(Equal(1))
+		Expect(keys[0].Namespace()).To(Equal("test"))
+		Expect(keys[0].SetName()).To(Equal("other"))
+		Expect(keys[0].Digest()).To(Equal(key.Digest()))
+		Expect(keys[0].Value()).To(BeNil())
+	})
+
+	It("should reject invalid digests and mismatched partitions", func() {
+		key, err := NewKey("test", "set", 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		pd := NewPartitionDigest(key.Digest())
+		pd.PartitionId = (pd.PartitionId + 1) % _PARTITIONS
+		_, err = digestKeys("test", "", []PartitionDigest{pd})
+		Expect(err).To(HaveOccurred())
+
+		_, err = digestKeys("test", "", []PartitionDigest{{Digest: []byte{1, 2, 3}}})
+		Expect(err).To(HaveOccurred())
+	})
+
+})
@@ -0,0 +1,95 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// BatchRead specifies the key of a record to read in a batch with
+// Client.BatchGetComplex, and which bins of it to read, so that a single batch
+// can read different bins from different records.
+// The read record is set in Record.
+type BatchRead struct {
+	// Key of the record to read.
+	Key *Key
+
+	// BinNames are the bins to read. They are ignored if ReadAllBins is set.
+	// If ReadAllBins is not set and BinNames is empty, only the record header
+	// (generation and expiration) is read.
+	BinNames []string
+
+	// ReadAllBins determines if all the bins of the record are read.
+	ReadAllBins bool
+
+	// Record is the record read, or nil if it does not exist.
+	// It is set by Client.BatchGetComplex.
+	Record *Record
+}
+
+// NewBatchRead creates a BatchRead of the bins of the record with the key.
+// All bins are read if no bin names are specified.
+func NewBatchRead(key *Key, binNames ...string) *BatchRead {
+	return &BatchRead{
+		Key:         key,
+		BinNames:    binNames,
+		ReadAllBins: len(binNames) == 0,
+	}
+}
+
+// NewBatchReadHeader creates a BatchRead of the header of the record with the key.
+func NewBatchReadHeader(key *Key) *BatchRead {
+	return &BatchRead{Key: key}
+}
+
+// batchReadProjection returns the bins and the read attributes of a batch command
+// which returns the bins requested by all the batch reads of the offsets.
+// The batch protocol applies the same bins to all the records of a command,
+// so the bins of each record are selected when its response is parsed.
+func batchReadProjection(batchReads []*BatchRead, offsets []int) (map[string]struct{}, int) {
+	binNames := map[string]struct{}{}
+	headersOnly := true
+	for _, offset := range offsets {
+		br := batchReads[offset]
+		if br.ReadAllBins {
+			// all bins are returned when no bin is requested
+			return nil, _INFO1_READ
+		}
+		for _, binName := range br.BinNames {
+			binNames[binName] = struct{}{}
+			headersOnly = false
+		}
+	}
+
+	if headersOnly {
+		return nil, _INFO1_READ | _INFO1_NOBINDATA
+	}
+	return binNames, _INFO1_READ
+}
+
+// project removes the bins which were not requested by the batch read from the record.
+func (br *BatchRead) project(rec *Record) *Record {
+	if br.ReadAllBins || rec == nil {
+		return rec
+	}
+
+	var bins BinMap
+	for _, binName := range br.BinNames {
+		if value, exists := rec.Bins[binName]; exists {
+			if bins == nil {
+				bins = BinMap{}
+			}
+			bins[binName] = value
+		}
+	}
+	rec.Bins = bins
+	return rec
+}
@@ -0,0 +1,54 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batch Read Test", func() {
+
+	It("should request the bins of all the batch reads of a command", func() {
+		reads := []*BatchRead{
+			{BinNames: []string{"a"}},
+			{BinNames: []string{"b", "a"}},
+			{},
+			{ReadAllBins: true},
+		}
+
+		binNames, readAttr := batchReadProjection(reads, []int{0, 1, 2})
+		Expect(binNames).To(Equal(map[string]struct{}{"a": {}, "b": {}}))
+		Expect(readAttr).To(Equal(_INFO1_READ))
+
+		binNames, readAttr = batchReadProjection(reads, []int{0, 3})
+		Expect(binNames).To(BeNil())
+		Expect(readAttr).To(Equal(_INFO1_READ))
+
+		binNames, readAttr = batchReadProjection(reads, []int{2})
+		Expect(binNames).To(BeNil())
+		Expect(readAttr).To(Equal(_INFO1_READ | _INFO1_NOBINDATA))
+	})
+
+	It("should keep only the bins of the batch read in the record", func() {
+		rec := &Record{Bins: BinMap{"a": 1, "b": 2}}
+		Expect(NewBatchRead(nil, "b", "c").project(rec).Bins).To(Equal(BinMap{"b": 2}))
+
+		rec = &Record{Bins: BinMap{"a": 1, "b": 2}}
+		Expect(NewBatchRead(nil).project(rec).Bins).To(Equal(BinMap{"a": 1, "b": 2}))
+		Expect(NewBatchReadHeader(nil).project(rec).Bins).To(BeNil())
+		Expect(NewBatchReadHeader(nil).project(nil)).To(BeNil())
+	})
+})
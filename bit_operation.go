@@ -0,0 +1,317 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// Bit operation commands.
+const (
+	_BIT_RESIZE   = 0
+	_BIT_INSERT   = 1
+	_BIT_REMOVE   = 2
+	_BIT_SET      = 3
+	_BIT_OR       = 4
+	_BIT_XOR      = 5
+	_BIT_AND      = 6
+	_BIT_NOT      = 7
+	_BIT_LSHIFT   = 8
+	_BIT_RSHIFT   = 9
+	_BIT_ADD      = 10
+	_BIT_SUBTRACT = 11
+	_BIT_SET_INT  = 12
+	_BIT_GET      = 50
+	_BIT_COUNT    = 51
+	_BIT_LSCAN    = 52
+	_BIT_RSCAN    = 53
+	_BIT_GET_INT  = 54
+
+	_BIT_INT_FLAGS_SIGNED = 1
+)
+
+// newBitOperation creates a bit operation on a blob bin.
+// Bit modify operations require the server to return a result for each operation.
+func newBitOperation(opType OperationType, binName string, command int, args ...interface{}) *Operation {
+	op := &Operation{OpType: opType, BinName: binName, BinValue: newCDTOpValue(command, nil, args...)}
+	op.respondAllOps = opType == BIT_MODIFY
+	return op
+}
+
+// Bit operations. Create bit operations used by the client's Operate command.
+// Offset orientation is left-to-right. Negative offsets are supported.
+// If the offset is negative, the offset starts backwards from end of the bitmap.
+// If an offset is out of bounds, a parameter error will be returned.
+//
+// Bit operations on bitmap items nested in lists/maps are not currently
+// supported by the server.
+
+// BitResizeOp creates byte "resize" operation.
+// Server resizes byte[] to byteSize according to resizeFlags.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010]
+//	byteSize = 4
+//	resizeFlags = 0
+//	bin result = [0b00000001, 0b01000010, 0b00000000, 0b00000000]
+func BitResizeOp(policy *BitPolicy, binName string, byteSize int, resizeFlags BitResizeFlags) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_RESIZE, byteSize, int(policy.flags), int(resizeFlags))
+}
+
+// BitInsertOp creates byte "insert" operation.
+// Server inserts value bytes into byte[] bin at byteOffset.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	byteOffset = 1
+//	value = [0b11111111, 0b11000111]
+//	bin result = [0b00000001, 0b11111111, 0b11000111, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+func BitInsertOp(policy *BitPolicy, binName string, byteOffset int, value []byte) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_INSERT, byteOffset, value, int(policy.flags))
+}
+
+// BitRemoveOp creates byte "remove" operation.
+// Server removes bytes from byte[] bin at byteOffset for byteSize.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	byteOffset = 2
+//	byteSize = 3
+//	bin result = [0b00000001, 0b01000010]
+func BitRemoveOp(policy *BitPolicy, binName string, byteOffset int, byteSize int) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_REMOVE, byteOffset, byteSize, int(policy.flags))
+}
+
+// BitSetOp creates bit "set" operation.
+// Server sets value on byte[] bin at bitOffset for bitSize.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 13
+//	bitSize = 3
+//	value = [0b11100000]
+//	bin result = [0b00000001, 0b01000111, 0b00000011, 0b00000100, 0b00000101]
+func BitSetOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value []byte) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_SET, bitOffset, bitSize, value, int(policy.flags))
+}
+
+// BitOrOp creates bit "or" operation.
+// Server performs bitwise "or" on value and byte[] bin at bitOffset for bitSize.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 17
+//	bitSize = 6
+//	value = [0b10101000]
+//	bin result = [0b00000001, 0b01000010, 0b01010111, 0b00000100, 0b00000101]
+func BitOrOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value []byte) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_OR, bitOffset, bitSize, value, int(policy.flags))
+}
+
+// BitXorOp creates bit "exclusive or" operation.
+// Server performs bitwise "xor" on value and byte[] bin at bitOffset for bitSize.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 17
+//	bitSize = 6
+//	value = [0b10101100]
+//	bin result = [0b00000001, 0b01000010, 0b01010101, 0b00000100, 0b00000101]
+func BitXorOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value []byte) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_XOR, bitOffset, bitSize, value, int(policy.flags))
+}
+
+// BitAndOp creates bit "and" operation.
+// Server performs bitwise "and" on value and byte[] bin at bitOffset for bitSize.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 23
+//	bitSize = 9
+//	value = [0b00111100, 0b10000000]
+//	bin result = [0b00000001, 0b01000010, 0b00000010, 0b00000000, 0b00000101]
+func BitAndOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value []byte) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_AND, bitOffset, bitSize, value, int(policy.flags))
+}
+
+// BitNotOp creates bit "not" operation.
+// Server negates byte[] bin starting at bitOffset for bitSize.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 25
+//	bitSize = 6
+//	bin result = [0b00000001, 0b01000010, 0b00000011, 0b01111010, 0b00000101]
+func BitNotOp(policy *BitPolicy, binName string, bitOffset int, bitSize int) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_NOT, bitOffset, bitSize, int(policy.flags))
+}
+
+// BitLShiftOp creates bit "left shift" operation.
+// Server shifts left byte[] bin starting at bitOffset for bitSize.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 32
+//	bitSize = 8
+//	shift = 3
+//	bin result = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00101000]
+func BitLShiftOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, shift int) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_LSHIFT, bitOffset, bitSize, shift, int(policy.flags))
+}
+
+// BitRShiftOp creates bit "right shift" operation.
+// Server shifts right byte[] bin starting at bitOffset for bitSize.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 0
+//	bitSize = 9
+//	shift = 1
+//	bin result = [0b00000000, 0b11000010, 0b00000011, 0b00000100, 0b00000101]
+func BitRShiftOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, shift int) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_RSHIFT, bitOffset, bitSize, shift, int(policy.flags))
+}
+
+// BitAddOp creates bit "add" operation.
+// Server adds value to byte[] bin starting at bitOffset for bitSize. BitSize must be <= 64.
+// Signed indicates if bits should be treated as a signed number.
+// If add overflows/underflows, BitOverflowAction is used.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 24
+//	bitSize = 16
+//	value = 128
+//	signed = false
+//	bin result = [0b00000001, 0b01000010, 0b00000011, 0b10000100, 0b00000101]
+func BitAddOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value int64, signed bool, action BitOverflowAction) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_ADD, bitOffset, bitSize, value, int(policy.flags), bitMathFlags(signed, action))
+}
+
+// BitSubtractOp creates bit "subtract" operation.
+// Server subtracts value from byte[] bin starting at bitOffset for bitSize. BitSize must be <= 64.
+// Signed indicates if bits should be treated as a signed number.
+// If add overflows/underflows, BitOverflowAction is used.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 24
+//	bitSize = 16
+//	value = 128
+//	signed = false
+//	bin result = [0b00000001, 0b01000010, 0b00000011, 0b0000011, 0b10000101]
+func BitSubtractOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value int64, signed bool, action BitOverflowAction) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_SUBTRACT, bitOffset, bitSize, value, int(policy.flags), bitMathFlags(signed, action))
+}
+
+// BitSetIntOp creates bit "setInt" operation.
+// Server sets value to byte[] bin starting at bitOffset for bitSize. Size must be <= 64.
+// Server does not return a value.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 1
+//	bitSize = 8
+//	value = 127
+//	bin result = [0b00111111, 0b11000010, 0b00000011, 0b0000100, 0b00000101]
+func BitSetIntOp(policy *BitPolicy, binName string, bitOffset int, bitSize int, value int64) *Operation {
+	return newBitOperation(BIT_MODIFY, binName, _BIT_SET_INT, bitOffset, bitSize, value, int(policy.flags))
+}
+
+// BitGetOp creates bit "get" operation.
+// Server returns bits from byte[] bin starting at bitOffset for bitSize.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 9
+//	bitSize = 5
+//	returns [0b1000000]
+func BitGetOp(binName string, bitOffset int, bitSize int) *Operation {
+	return newBitOperation(BIT_READ, binName, _BIT_GET, bitOffset, bitSize)
+}
+
+// BitCountOp creates bit "count" operation.
+// Server returns integer count of set bits from byte[] bin starting at bitOffset for bitSize.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 20
+//	bitSize = 4
+//	returns 2
+func BitCountOp(binName string, bitOffset int, bitSize int) *Operation {
+	return newBitOperation(BIT_READ, binName, _BIT_COUNT, bitOffset, bitSize)
+}
+
+// BitLScanOp creates bit "left scan" operation.
+// Server returns integer bit offset of the first specified value bit in byte[] bin
+// starting at bitOffset for bitSize.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 24
+//	bitSize = 8
+//	value = true
+//	returns 5
+func BitLScanOp(binName string, bitOffset int, bitSize int, value bool) *Operation {
+	return newBitOperation(BIT_READ, binName, _BIT_LSCAN, bitOffset, bitSize, value)
+}
+
+// BitRScanOp creates bit "right scan" operation.
+// Server returns integer bit offset of the last specified value bit in byte[] bin
+// starting at bitOffset for bitSize.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 32
+//	bitSize = 8
+//	value = true
+//	returns 7
+func BitRScanOp(binName string, bitOffset int, bitSize int, value bool) *Operation {
+	return newBitOperation(BIT_READ, binName, _BIT_RSCAN, bitOffset, bitSize, value)
+}
+
+// BitGetIntOp creates bit "get integer" operation.
+// Server returns integer from byte[] bin starting at bitOffset for bitSize.
+// Signed indicates if bits should be treated as a signed number.
+// Example:
+//
+//	bin = [0b00000001, 0b01000010, 0b00000011, 0b00000100, 0b00000101]
+//	bitOffset = 8
+//	bitSize = 16
+//	signed = false
+//	returns 16899
+func BitGetIntOp(binName string, bitOffset int, bitSize int, signed bool) *Operation {
+	if signed {
+		return newBitOperation(BIT_READ, binName, _BIT_GET_INT, bitOffset, bitSize, _BIT_INT_FLAGS_SIGNED)
+	}
+	return newBitOperation(BIT_READ, binName, _BIT_GET_INT, bitOffset, bitSize)
+}
+
+// bitMathFlags packs the signed flag and the overflow action of add/subtract operations.
+func bitMathFlags(signed bool, action BitOverflowAction) int {
+	flags := int(action)
+	if signed {
+		flags |= _BIT_INT_FLAGS_SIGNED
+	}
+	return flags
+}
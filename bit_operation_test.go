@@ -0,0 +1,135 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("Bit Operations Test", func() {
+	initTestVars()
+
+	// connection data
+	var client *Client
+	var err error
+	var ns = "test"
+	var set = randString(50)
+	var key *Key
+	var wpolicy = NewWritePolicy(0, 0)
+	var bpolicy = DefaultBitPolicy()
+	var binName string
+	var initial = []byte{0x01, 0x42, 0x03, 0x04, 0x05}
+
+	BeforeEach(func() {
+		client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+		key, err = NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		binName = randString(10)
+		err = client.PutBins(wpolicy, key, NewBin(binName, initial))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should read bits", func() {
+		rec, err := client.Operate(wpolicy, key,
+			BitGetOp(binName, 9, 5),
+			BitCountOp(binName, 20, 4),
+			BitLScanOp(binName, 24, 8, true),
+			BitRScanOp(binName, 32, 8, true),
+			BitGetIntOp(binName, 8, 16, false),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[binName]).To(Equal([]interface{}{[]byte{0x80}, 2, 5, 7, 16899}))
+	})
+
+	It("should set, negate and shift bits", func() {
+		rec, err := client.Operate(wpolicy, key,
+			BitSetOp(bpolicy, binName, 13, 3, []byte{0xE0}),
+			BitNotOp(bpolicy, binName, 25, 6),
+			BitLShiftOp(bpolicy, binName, 32, 8, 3),
+			BitGetOp(binName, 0, 40),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[binName]).To(Equal([]interface{}{nil, nil, nil, []byte{0x01, 0x47, 0x03, 0x7A, 0x28}}))
+	})
+
+	It("should apply bitwise or, xor and and", func() {
+		rec, err := client.Operate(wpolicy, key,
+			BitOrOp(bpolicy, binName, 17, 6, []byte{0xA8}),
+			BitGetOp(binName, 16, 8),
+			BitXorOp(bpolicy, binName, 17, 6, []byte{0xAC}),
+			BitGetOp(binName, 16, 8),
+			BitAndOp(bpolicy, binName, 0, 8, []byte{0x00}),
+			BitGetOp(binName, 0, 8),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[binName]).To(Equal([]interface{}{nil, []byte{0x57}, nil, []byte{0x01}, nil, []byte{0x00}}))
+	})
+
+	It("should resize, insert and remove bytes", func() {
+		rec, err := client.Operate(wpolicy, key,
+			BitResizeOp(bpolicy, binName, 8, BitResizeFlagsDefault),
+			BitGetOp(binName, 0, 64),
+			BitInsertOp(bpolicy, binName, 1, []byte{0xFF, 0xC7}),
+			BitRemoveOp(bpolicy, binName, 1, 2),
+			BitResizeOp(bpolicy, binName, 5, BitResizeFlagsShrinkOnly),
+			BitGetOp(binName, 0, 40),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[binName]).To(Equal([]interface{}{
+			nil,
+			[]byte{0x01, 0x42, 0x03, 0x04, 0x05, 0x00, 0x00, 0x00},
+			nil,
+			nil,
+			nil,
+			initial,
+		}))
+	})
+
+	It("should do integer math on bits", func() {
+		rec, err := client.Operate(wpolicy, key,
+			BitAddOp(bpolicy, binName, 24, 16, 128, false, BitOverflowActionFail),
+			BitGetIntOp(binName, 24, 16, false),
+			BitSubtractOp(bpolicy, binName, 24, 16, 128, false, BitOverflowActionFail),
+			BitGetIntOp(binName, 24, 16, false),
+			BitSetIntOp(bpolicy, binName, 0, 8, -1),
+			BitGetIntOp(binName, 0, 8, true),
+			BitAddOp(bpolicy, binName, 0, 8, 1, false, BitOverflowActionWrap),
+			BitGetIntOp(binName, 0, 8, false),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[binName]).To(Equal([]interface{}{nil, 0x0485, nil, 0x0405, nil, -1, nil, 0}))
+
+		_, err = client.Operate(wpolicy, key, BitAddOp(bpolicy, binName, 0, 8, 256, false, BitOverflowActionFail))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should honor bit write flags", func() {
+		_, err := client.Operate(wpolicy, key, BitResizeOp(NewBitPolicy(BitWriteFlagsCreateOnly), binName, 10, BitResizeFlagsDefault))
+		Expect(err).To(HaveOccurred())
+
+		rec, err := client.Operate(wpolicy, key,
+			BitResizeOp(NewBitPolicy(BitWriteFlagsCreateOnly|BitWriteFlagsNoFail), binName, 10, BitResizeFlagsDefault),
+			BitGetOp(binName, 0, 40),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[binName]).To(Equal([]interface{}{nil, initial}))
+	})
+
+})
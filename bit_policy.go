@@ -0,0 +1,87 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// BitWriteFlags determines the behavior of bit write operations.
+// Flags can be combined using bitwise OR.
+type BitWriteFlags int
+
+const (
+	// BitWriteFlagsDefault allows create or update.
+	BitWriteFlagsDefault BitWriteFlags = 0
+
+	// BitWriteFlagsCreateOnly means: If the bin already exists, the operation will be denied.
+	// If the bin does not exist, a new bin will be created.
+	BitWriteFlagsCreateOnly BitWriteFlags = 1
+
+	// BitWriteFlagsUpdateOnly means: If the bin already exists, the bin will be overwritten.
+	// If the bin does not exist, the operation will be denied.
+	BitWriteFlagsUpdateOnly BitWriteFlags = 2
+
+	// BitWriteFlagsNoFail means: Do not raise error if operation is denied.
+	BitWriteFlagsNoFail BitWriteFlags = 4
+
+	// BitWriteFlagsPartial means: Allow other valid operations to be committed if this operations is
+	// denied due to flag constraints.
+	BitWriteFlagsPartial BitWriteFlags = 8
+)
+
+// BitResizeFlags determines the behavior of bit resize operations.
+// Flags can be combined using bitwise OR.
+type BitResizeFlags int
+
+const (
+	// BitResizeFlagsDefault adds/removes bytes from the end of the blob.
+	BitResizeFlagsDefault BitResizeFlags = 0
+
+	// BitResizeFlagsFromFront adds/removes bytes from the beginning of the blob.
+	BitResizeFlagsFromFront BitResizeFlags = 1
+
+	// BitResizeFlagsGrowOnly only allows the blob to grow.
+	BitResizeFlagsGrowOnly BitResizeFlags = 2
+
+	// BitResizeFlagsShrinkOnly only allows the blob to shrink.
+	BitResizeFlagsShrinkOnly BitResizeFlags = 4
+)
+
+// BitOverflowAction specifies the action to take when a bitwise add/subtract results in overflow/underflow.
+type BitOverflowAction int
+
+const (
+	// BitOverflowActionFail fails the operation with an error.
+	BitOverflowActionFail BitOverflowAction = 0
+
+	// BitOverflowActionSaturate sets the result to the maximum value on overflow
+	// and to the minimum value on underflow.
+	BitOverflowActionSaturate BitOverflowAction = 2
+
+	// BitOverflowActionWrap wraps the value on overflow and underflow.
+	BitOverflowActionWrap BitOverflowAction = 4
+)
+
+// BitPolicy determines the write flags used in bit write operations.
+type BitPolicy struct {
+	flags BitWriteFlags
+}
+
+// NewBitPolicy creates a bit policy with the specified write flags.
+func NewBitPolicy(flags BitWriteFlags) *BitPolicy {
+	return &BitPolicy{flags: flags}
+}
+
+// DefaultBitPolicy returns the default bit policy.
+func DefaultBitPolicy() *BitPolicy {
+	return NewBitPolicy(BitWriteFlagsDefault)
+}
@@ -0,0 +1,48 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"runtime"
+)
+
+// ClientVersion is the version of this client library.
+const ClientVersion = "1.5.0"
+
+// BuildInfo describes the client library and the Go runtime it runs on,
+// so that fleet-wide dashboards can spot outdated clients.
+type BuildInfo struct {
+	// ClientVersion is the version of this client library.
+	ClientVersion string `json:"client_version"`
+
+	// GoVersion, OS and Arch describe the Go runtime.
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+
+	// ProtocolVersion is the version of the wire protocol of database commands.
+	ProtocolVersion int `json:"protocol_version"`
+}
+
+// GetBuildInfo returns the build metadata of the client library.
+func GetBuildInfo() BuildInfo {
+	return BuildInfo{
+		ClientVersion:   ClientVersion,
+		GoVersion:       runtime.Version(),
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		ProtocolVersion: int(_CL_MSG_VERSION),
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Build Info Test", func() {
+
+	It("should describe the client and the Go runtime", func() {
+		info := GetBuildInfo()
+		Expect(info.ClientVersion).To(Equal(ClientVersion))
+		Expect(info.GoVersion).To(Equal(runtime.Version()))
+		Expect(info.OS).To(Equal(runtime.GOOS))
+		Expect(info.Arch).To(Equal(runtime.GOARCH))
+		Expect(info.ProtocolVersion).To(Equal(2))
+	})
+
+	It("should list the protocol features used with a node", func() {
+		node := &Node{}
+		Expect(node.features()).To(BeEmpty())
+
+		node.useNewInfo = true
+		node.supportsBackgroundOps = true
+		Expect(node.features()).To(Equal([]string{"new-info", "background-ops"}))
+	})
+
+	It("should query partitions only if all nodes support it", func() {
+		old, pquery := &Node{}, &Node{supportsPartitionQuery: true}
+		Expect(supportsPartitionQuery([]*Node{pquery, pquery})).To(BeTrue())
+		Expect(supportsPartitionQuery([]*Node{pquery, old})).To(BeFalse())
+		Expect(pquery.features()).To(Equal([]string{"partition-query"}))
+	})
+
+	It("should keep the build metadata in diffs", func() {
+		prev := &ClusterStats{Build: GetBuildInfo(), Nodes: map[string]NodeStats{"A": {Name: "A", Build: "3.5.4"}}}
+		cur := &ClusterStats{Build: GetBuildInfo(), Nodes: map[string]NodeStats{"A": {Name: "A", Build: "3.5.4", Features: []string{"new-info"}}}}
+
+		diff := cur.Diff(prev)
+		Expect(diff.Build).To(Equal(GetBuildInfo()))
+		Expect(diff.Nodes["A"].Build).To(Equal("3.5.4"))
+		Expect(diff.Nodes["A"].Features).To(Equal([]string{"new-info"}))
+	})
+
+})
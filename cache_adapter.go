@@ -0,0 +1,152 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"math"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/logger"
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// DefaultCacheBinName is the default name of the bin holding the values stored by CacheAdapter.
+const DefaultCacheBinName = "value"
+
+// CacheAdapter exposes a set as a key/value cache with the Get/Set/Delete
+// method shapes of common Go in-memory caches, so that code written against
+// such a cache can be backed by Aerospike with minimal changes.
+// Keys and values may be of any type accepted by NewKey and NewValue.
+// Values are read back as decoded by the client, e.g. integers as int.
+type CacheAdapter struct {
+	client *Client
+
+	// Namespace and SetName locate the records of the cache.
+	Namespace string
+	SetName   string
+
+	// BinName is the name of the bin holding the cached values.
+	BinName string //= DefaultCacheBinName
+
+	// ReadPolicy is used for Get and GetWithTTL; the default policy is used if nil.
+	ReadPolicy *BasePolicy
+
+	// WritePolicy is used for Set and Delete; the default policy is used if nil.
+	// Its Expiration is overridden by the TTL passed to Set.
+	WritePolicy *WritePolicy
+}
+
+// NewCacheAdapter generates a CacheAdapter storing its entries in the set of the namespace.
+func NewCacheAdapter(client *Client, namespace string, setName string) *CacheAdapter {
+	return &CacheAdapter{
+		client:    client,
+		Namespace: namespace,
+		SetName:   setName,
+		BinName:   DefaultCacheBinName,
+	}
+}
+
+// Get returns the value cached for the key, and whether it was found.
+// As with in-memory caches, errors are reported as misses; they are logged
+// at debug level. Use GetWithTTL to tell them apart.
+func (ca *CacheAdapter) Get(key interface{}) (interface{}, bool) {
+	value, _, err := ca.GetWithTTL(key)
+	if err != nil {
+		if err != ErrKeyNotFound && ca.client.cluster.logEnabled(DEBUG) {
+			ca.client.cluster.logEvent(DEBUG, SUBSYSTEM_CLIENT, "", map[string]interface{}{"namespace": ca.Namespace, "set": ca.SetName, "error": err}, "Cache read failed: %s", err)
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+// GetWithTTL returns the value cached for the key and the time left until it expires.
+// A zero TTL means the entry never expires.
+// ErrKeyNotFound is returned if the key is not cached.
+func (ca *CacheAdapter) GetWithTTL(key interface{}) (interface{}, time.Duration, error) {
+	k, err := NewKey(ca.Namespace, ca.SetName, key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	policy := ca.client.getUsablePolicy(ca.ReadPolicy)
+
+	// do not modify the caller's policy
+	bp := *policy
+	bp.KeyNotFoundAsError = true
+
+	rec, err := ca.client.Get(&bp, k, ca.BinName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value, exists := rec.Bins[ca.BinName]
+	if !exists {
+		// the record was not written by the adapter
+		return nil, 0, ErrKeyNotFound
+	}
+
+	// records which never expire have a void time of 0, which is in the past
+	if rec.Expiration <= 0 {
+		return value, 0, nil
+	}
+	return value, time.Duration(rec.Expiration) * time.Second, nil
+}
+
+// Set caches the value for the key, replacing any previous value.
+// The entry expires after ttl, rounded up to the second; a zero ttl uses the
+// namespace's default TTL, and a negative ttl never expires.
+func (ca *CacheAdapter) Set(key interface{}, value interface{}, ttl time.Duration) error {
+	k, err := NewKey(ca.Namespace, ca.SetName, key)
+	if err != nil {
+		return err
+	}
+
+	policy := ca.client.getUsableWritePolicy(ca.WritePolicy)
+
+	// do not modify the caller's policy
+	wp := *policy
+	wp.RecordExistsAction = REPLACE
+	wp.Expiration = cacheExpiration(ttl)
+
+	return ca.client.PutBins(&wp, k, NewBin(ca.BinName, value))
+}
+
+// Delete removes the key from the cache. Deleting a missing key is not an error.
+func (ca *CacheAdapter) Delete(key interface{}) error {
+	k, err := NewKey(ca.Namespace, ca.SetName, key)
+	if err != nil {
+		return err
+	}
+
+	_, err = ca.client.Delete(ca.WritePolicy, k)
+	return err
+}
+
+// cacheExpiration converts a cache TTL to a record expiration.
+func cacheExpiration(ttl time.Duration) int32 {
+	switch {
+	case ttl < 0:
+		return -1
+	case ttl == 0:
+		return 0
+	}
+
+	secs := (ttl + time.Second - 1) / time.Second
+	if secs > math.MaxInt32 {
+		secs = math.MaxInt32
+	}
+	return int32(secs)
+}
@@ -0,0 +1,99 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+var _ = Describe("Cache Adapter Test", func() {
+	initTestVars()
+
+	var ns = "test"
+	var set = randString(50)
+	var client *Client
+	var cache *CacheAdapter
+	var err error
+
+	BeforeEach(func() {
+		client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+		cache = NewCacheAdapter(client, ns, set)
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("must set, get and delete values", func() {
+		key := randString(50)
+
+		_, found := cache.Get(key)
+		Expect(found).To(BeFalse())
+
+		err = cache.Set(key, "value", time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+
+		value, found := cache.Get(key)
+		Expect(found).To(BeTrue())
+		Expect(value).To(Equal("value"))
+
+		value, ttl, err := cache.GetWithTTL(key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal("value"))
+		Expect(ttl > 0 && ttl <= time.Hour).To(BeTrue())
+
+		err = cache.Delete(key)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, _, err = cache.GetWithTTL(key)
+		Expect(err).To(Equal(ErrKeyNotFound))
+
+		// deleting a missing key is not an error
+		err = cache.Delete(key)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("must report a zero TTL for values which never expire", func() {
+		key := randString(50)
+
+		err = cache.Set(key, "value", -1)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, ttl, err := cache.GetWithTTL(key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ttl).To(Equal(time.Duration(0)))
+	})
+
+	It("must replace previous values", func() {
+		key := 42
+
+		err = cache.Set(key, map[interface{}]interface{}{"a": 1}, 0)
+		Expect(err).ToNot(HaveOccurred())
+		err = cache.Set(key, 7, 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		value, found := cache.Get(key)
+		Expect(found).To(BeTrue())
+		Expect(value).To(Equal(7))
+	})
+
+})
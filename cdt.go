@@ -0,0 +1,130 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
+)
+
+// cdtOpValue encapsulates the arguments of a collection data type (CDT)
+// operation. The command and its arguments are packed as a MessagePack
+// array and sent to the server as a blob.
+//
+// If a context path is specified, the operation is applied to the nested
+// list or map identified by the path.
+type cdtOpValue struct {
+	command int
+	ctx     []*CDTContext
+	args    []interface{}
+	bytes   []byte
+
+	// set if the arguments could not be packed; returned when the operation is written
+	err error
+}
+
+func newCDTOpValue(command int, ctx []*CDTContext, args ...interface{}) *cdtOpValue {
+	res := &cdtOpValue{
+		command: command,
+		ctx:     ctx,
+		args:    args,
+	}
+
+	packer := newPacker()
+	if len(ctx) > 0 {
+		packer.PackArrayBegin(3)
+		packer.PackAInt(0xff)
+		if err := packCDTContext(packer, ctx); err != nil {
+			res.err = newCDTPackError(err)
+			return res
+		}
+	}
+
+	packer.PackArrayBegin(len(args) + 1)
+	packer.PackAInt(command)
+	for i := range args {
+		if err := packer.PackObject(args[i]); err != nil {
+			res.err = newCDTPackError(err)
+			return res
+		}
+	}
+	res.bytes = packer.buffer.Bytes()
+
+	return res
+}
+
+// newCDTPackError reports the packing errors of the operation arguments as PARAMETER_ERROR.
+func newCDTPackError(err error) error {
+	if _, ok := err.(AerospikeError); ok {
+		return err
+	}
+	return NewAerospikeError(PARAMETER_ERROR, "Failed to pack the CDT operation: "+err.Error())
+}
+
+func (vl *cdtOpValue) estimateSize() int {
+	return len(vl.bytes)
+}
+
+func (vl *cdtOpValue) write(buffer []byte, offset int) (int, error) {
+	if vl.err != nil {
+		return 0, vl.err
+	}
+	l := copy(buffer[offset:], vl.bytes)
+	return l, nil
+}
+
+func (vl *cdtOpValue) pack(packer *packer) error {
+	if vl.err != nil {
+		return vl.err
+	}
+	_, err := packer.buffer.Write(vl.bytes)
+	return err
+}
+
+// GetType returns wire protocol value type.
+func (vl *cdtOpValue) GetType() int {
+	return ParticleType.BLOB
+}
+
+// GetObject returns original value as an interface{}.
+func (vl *cdtOpValue) GetObject() interface{} {
+	return vl.args
+}
+
+func (vl *cdtOpValue) reader() io.Reader {
+	return bytes.NewReader(vl.bytes)
+}
+
+// String implements Stringer interface.
+func (vl *cdtOpValue) String() string {
+	return fmt.Sprintf("%d%v", vl.command, vl.args)
+}
+
+// newCDTOperation creates an operation of the specified type on a CDT bin.
+func newCDTOperation(opType OperationType, binName string, ctx []*CDTContext, command int, args ...interface{}) *Operation {
+	return &Operation{OpType: opType, BinName: binName, BinValue: newCDTOpValue(command, ctx, args...)}
+}
+
+// newCDTMapOperation creates an operation of the specified type on a map bin.
+// Map modify operations require the server to return a result for each operation.
+func newCDTMapOperation(opType OperationType, binName string, ctx []*CDTContext, command int, args ...interface{}) *Operation {
+	op := newCDTOperation(opType, binName, ctx, command, args...)
+	op.respondAllOps = opType == CDT_MODIFY
+	return op
+}
@@ -0,0 +1,125 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// CDT context types.
+const (
+	_CTX_LIST_INDEX = 0x10
+	_CTX_LIST_RANK  = 0x11
+	_CTX_LIST_VALUE = 0x13
+	_CTX_MAP_INDEX  = 0x20
+	_CTX_MAP_RANK   = 0x21
+	_CTX_MAP_KEY    = 0x22
+	_CTX_MAP_VALUE  = 0x23
+)
+
+// CDTContext identifies the location of a nested list or map to apply an operation to.
+// A context path is a list of contexts, starting at the top level of the bin,
+// each one selecting an element of the current level. For example:
+//
+//	// get the third item of the list stored under the "tokens" key of the map bin
+//	ListGetOp(binName, 2, CtxMapKey("tokens"))
+type CDTContext struct {
+	id    int
+	value interface{}
+}
+
+// String implements the Stringer interface.
+func (ctx *CDTContext) String() string {
+	return fmt.Sprintf("%#x:%v", ctx.id, ctx.value)
+}
+
+// CtxListIndex defines Lookup list by index offset.
+// If the index is negative, the resolved index starts backwards from end of list.
+// Examples:
+// 0: First item.
+// 4: Fifth item.
+// -1: Last item.
+// -3: Third to last item.
+func CtxListIndex(index int) *CDTContext {
+	return &CDTContext{id: _CTX_LIST_INDEX, value: index}
+}
+
+// CtxListRank defines Lookup list by rank.
+// 0 = smallest value
+// N = Nth smallest value
+// -1 = largest value
+func CtxListRank(rank int) *CDTContext {
+	return &CDTContext{id: _CTX_LIST_RANK, value: rank}
+}
+
+// CtxListValue defines Lookup list by value.
+func CtxListValue(value interface{}) *CDTContext {
+	return &CDTContext{id: _CTX_LIST_VALUE, value: value}
+}
+
+// CtxMapIndex defines Lookup map by index offset.
+// If the index is negative, the resolved index starts backwards from end of list.
+// Examples:
+// 0: First item.
+// 4: Fifth item.
+// -1: Last item.
+// -3: Third to last item.
+func CtxMapIndex(index int) *CDTContext {
+	return &CDTContext{id: _CTX_MAP_INDEX, value: index}
+}
+
+// CtxMapRank defines Lookup map by rank.
+// 0 = smallest value
+// N = Nth smallest value
+// -1 = largest value
+func CtxMapRank(rank int) *CDTContext {
+	return &CDTContext{id: _CTX_MAP_RANK, value: rank}
+}
+
+// CtxMapKey defines Lookup map by key.
+func CtxMapKey(key interface{}) *CDTContext {
+	return &CDTContext{id: _CTX_MAP_KEY, value: key}
+}
+
+// CtxMapValue defines Lookup map by value.
+func CtxMapValue(value interface{}) *CDTContext {
+	return &CDTContext{id: _CTX_MAP_VALUE, value: value}
+}
+
+// packCDTContextBytes packs the context path for use in secondary index commands.
+func packCDTContextBytes(ctx []*CDTContext) ([]byte, error) {
+	packer := newPacker()
+	if err := packCDTContext(packer, ctx); err != nil {
+		return nil, err
+	}
+	return packer.buffer.Bytes(), nil
+}
+
+// packCDTContext packs the context path as a flat list of id/value pairs.
+func packCDTContext(packer *packer, ctx []*CDTContext) error {
+	packer.PackArrayBegin(len(ctx) * 2)
+	for _, c := range ctx {
+		if c == nil {
+			return NewAerospikeError(PARAMETER_ERROR, "CDT context cannot be nil")
+		}
+		packer.PackAInt(c.id)
+		if err := packer.PackObject(c.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,473 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// List operation commands.
+const (
+	_CDT_LIST_SET_TYPE                       = 0
+	_CDT_LIST_APPEND                         = 1
+	_CDT_LIST_APPEND_ITEMS                   = 2
+	_CDT_LIST_INSERT                         = 3
+	_CDT_LIST_INSERT_ITEMS                   = 4
+	_CDT_LIST_POP                            = 5
+	_CDT_LIST_POP_RANGE                      = 6
+	_CDT_LIST_REMOVE                         = 7
+	_CDT_LIST_REMOVE_RANGE                   = 8
+	_CDT_LIST_SET                            = 9
+	_CDT_LIST_TRIM                           = 10
+	_CDT_LIST_CLEAR                          = 11
+	_CDT_LIST_INCREMENT                      = 12
+	_CDT_LIST_SORT                           = 13
+	_CDT_LIST_SIZE                           = 16
+	_CDT_LIST_GET                            = 17
+	_CDT_LIST_GET_RANGE                      = 18
+	_CDT_LIST_GET_BY_INDEX                   = 19
+	_CDT_LIST_GET_BY_RANK                    = 21
+	_CDT_LIST_GET_BY_VALUE                   = 22
+	_CDT_LIST_GET_BY_VALUE_LIST              = 23
+	_CDT_LIST_GET_BY_INDEX_RANGE             = 24
+	_CDT_LIST_GET_BY_VALUE_INTERVAL          = 25
+	_CDT_LIST_GET_BY_RANK_RANGE              = 26
+	_CDT_LIST_GET_BY_VALUE_REL_RANK_RANGE    = 27
+	_CDT_LIST_REMOVE_BY_INDEX                = 32
+	_CDT_LIST_REMOVE_BY_RANK                 = 34
+	_CDT_LIST_REMOVE_BY_VALUE                = 35
+	_CDT_LIST_REMOVE_BY_VALUE_LIST           = 36
+	_CDT_LIST_REMOVE_BY_INDEX_RANGE          = 37
+	_CDT_LIST_REMOVE_BY_VALUE_INTERVAL       = 38
+	_CDT_LIST_REMOVE_BY_RANK_RANGE           = 39
+	_CDT_LIST_REMOVE_BY_VALUE_REL_RANK_RANGE = 40
+)
+
+// ListReturnType determines the data type returned by
+// list get-by and remove-by operations.
+type ListReturnType int
+
+const (
+	// ListReturnTypeNone will not return a result.
+	ListReturnTypeNone ListReturnType = 0
+
+	// ListReturnTypeIndex will return index offset order.
+	// 0 = first key
+	// N = Nth key
+	// -1 = last key
+	ListReturnTypeIndex ListReturnType = 1
+
+	// ListReturnTypeReverseIndex will return reverse index offset order.
+	// 0 = last key
+	// -1 = first key
+	ListReturnTypeReverseIndex ListReturnType = 2
+
+	// ListReturnTypeRank will return value order.
+	// 0 = smallest value
+	// N = Nth smallest value
+	// -1 = largest value
+	ListReturnTypeRank ListReturnType = 3
+
+	// ListReturnTypeReverseRank will return reverse value order.
+	// 0 = largest value
+	// N = Nth largest value
+	// -1 = smallest value
+	ListReturnTypeReverseRank ListReturnType = 4
+
+	// ListReturnTypeCount will return count of items selected.
+	ListReturnTypeCount ListReturnType = 5
+
+	// ListReturnTypeValue will return value for single key read and value list for range read.
+	ListReturnTypeValue ListReturnType = 7
+
+	// ListReturnTypeInverted will invert meaning of list command and return values.
+	// For example:
+	//   ListRemoveByIndexRangeOp(binName, index, count, ListReturnTypeIndex|ListReturnTypeInverted)
+	// With the INVERTED flag enabled, the items outside of the specified index range will be removed and returned.
+	ListReturnTypeInverted ListReturnType = 0x10000
+)
+
+// ListSetOrderOp creates a set list order operation.
+// Server sets list order. Server returns nil.
+func ListSetOrderOp(binName string, listOrder ListOrderType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_SET_TYPE, int(listOrder))
+}
+
+// ListCreateOp creates a list create operation.
+// Server creates an empty list with the specified order if the bin does not exist,
+// or sets the order of the existing list. Server returns nil.
+// Use it as the first operation of an Operate call, so that the following
+// by-rank or by-value operations behave deterministically on new records.
+func ListCreateOp(binName string, listOrder ListOrderType, ctx ...*CDTContext) *Operation {
+	return ListSetOrderOp(binName, listOrder, ctx...)
+}
+
+// ListAppendOp creates a list append operation.
+// Server appends values to end of list bin.
+// Server returns list size on bin name.
+func ListAppendOp(binName string, values ...interface{}) *Operation {
+	if len(values) == 1 {
+		return newCDTOperation(CDT_MODIFY, binName, nil, _CDT_LIST_APPEND, values[0])
+	}
+	return newCDTOperation(CDT_MODIFY, binName, nil, _CDT_LIST_APPEND_ITEMS, values)
+}
+
+// ListAppendWithPolicyOp creates a list append operation.
+// Server appends values to end of list bin, honoring the list order and write flags.
+// Server returns list size on bin name.
+func ListAppendWithPolicyOp(policy *ListPolicy, binName string, values ...interface{}) *Operation {
+	return ListAppendWithPolicyContextOp(policy, binName, nil, values...)
+}
+
+// ListAppendWithPolicyContextOp creates a list append operation on the list
+// identified by the context path inside the bin.
+// Server appends values to end of the list, honoring the list order and write flags.
+// Server returns list size on bin name.
+func ListAppendWithPolicyContextOp(policy *ListPolicy, binName string, ctx []*CDTContext, values ...interface{}) *Operation {
+	if len(values) == 1 {
+		return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_APPEND, values[0], int(policy.attributes), int(policy.flags))
+	}
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_APPEND_ITEMS, values, int(policy.attributes), int(policy.flags))
+}
+
+// ListInsertOp creates a list insert operation.
+// Server inserts value to specified index of list bin.
+// Server returns list size on bin name.
+func ListInsertOp(binName string, index int, values ...interface{}) *Operation {
+	if len(values) == 1 {
+		return newCDTOperation(CDT_MODIFY, binName, nil, _CDT_LIST_INSERT, index, values[0])
+	}
+	return newCDTOperation(CDT_MODIFY, binName, nil, _CDT_LIST_INSERT_ITEMS, index, values)
+}
+
+// ListInsertWithPolicyOp creates a list insert operation.
+// Server inserts value to specified index of list bin, honoring the write flags.
+// Server returns list size on bin name.
+func ListInsertWithPolicyOp(policy *ListPolicy, binName string, index int, values ...interface{}) *Operation {
+	return ListInsertWithPolicyContextOp(policy, binName, index, nil, values...)
+}
+
+// ListInsertWithPolicyContextOp creates a list insert operation on the list
+// identified by the context path inside the bin.
+// Server inserts value to specified index of the list, honoring the write flags.
+// Server returns list size on bin name.
+func ListInsertWithPolicyContextOp(policy *ListPolicy, binName string, index int, ctx []*CDTContext, values ...interface{}) *Operation {
+	if len(values) == 1 {
+		return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_INSERT, index, values[0], int(policy.flags))
+	}
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_INSERT_ITEMS, index, values, int(policy.flags))
+}
+
+// ListIncrementOp creates a list increment operation.
+// Server increments list[index] by value.
+// Server returns the value of list[index] after the operation.
+func ListIncrementOp(binName string, index int, value interface{}, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_INCREMENT, index, value)
+}
+
+// ListIncrementWithPolicyOp creates a list increment operation.
+// Server increments list[index] by value, honoring the list order and write flags.
+// Server returns the value of list[index] after the operation.
+func ListIncrementWithPolicyOp(policy *ListPolicy, binName string, index int, value interface{}, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_INCREMENT, index, value, int(policy.attributes), int(policy.flags))
+}
+
+// ListPopOp creates list pop operation.
+// Server returns item at specified index and removes item from list bin.
+func ListPopOp(binName string, index int, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_POP, index)
+}
+
+// ListPopRangeOp creates a list pop range operation.
+// Server returns items starting at specified index and removes items from list bin.
+func ListPopRangeOp(binName string, index int, count int, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_POP_RANGE, index, count)
+}
+
+// ListPopRangeFromOp creates a list pop range operation.
+// Server returns items starting at specified index to the end of list and removes items from list bin.
+func ListPopRangeFromOp(binName string, index int, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_POP_RANGE, index)
+}
+
+// ListRemoveOp creates a list remove operation.
+// Server removes item at specified index from list bin.
+// Server returns number of items removed.
+func ListRemoveOp(binName string, index int, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE, index)
+}
+
+// ListRemoveRangeOp creates a list remove range operation.
+// Server removes "count" items starting at specified index from list bin.
+// Server returns number of items removed.
+func ListRemoveRangeOp(binName string, index int, count int, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_RANGE, index, count)
+}
+
+// ListRemoveRangeFromOp creates a list remove range operation.
+// Server removes all items starting at specified index to the end of list.
+// Server returns number of items removed.
+func ListRemoveRangeFromOp(binName string, index int, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_RANGE, index)
+}
+
+// ListSetOp creates a list set operation.
+// Server sets item value at specified index in list bin.
+// Server does not return a result by default.
+func ListSetOp(binName string, index int, value interface{}, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_SET, index, value)
+}
+
+// ListSetWithPolicyOp creates a list set operation.
+// Server sets item value at specified index in list bin, honoring the write flags.
+// Server does not return a result by default.
+func ListSetWithPolicyOp(policy *ListPolicy, binName string, index int, value interface{}, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_SET, index, value, int(policy.flags))
+}
+
+// ListTrimOp creates a list trim operation.
+// Server removes items in list bin that do not fall into range specified by index
+// and count range. If the range is out of bounds, then all items will be removed.
+// Server returns number of elements that were removed.
+func ListTrimOp(binName string, index int, count int, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_TRIM, index, count)
+}
+
+// ListClearOp creates a list clear operation.
+// Server removes all items in list bin.
+// Server does not return a result by default.
+func ListClearOp(binName string, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_CLEAR)
+}
+
+// ListSortOp creates a list sort operation.
+// Server sorts list according to sortFlags.
+// Server does not return a result by default.
+func ListSortOp(binName string, sortFlags ListSortFlags, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_SORT, int(sortFlags))
+}
+
+// ListSizeOp creates a list size operation.
+// Server returns size of list on bin name.
+func ListSizeOp(binName string, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_SIZE)
+}
+
+// ListGetOp creates a list get operation.
+// Server returns item at specified index in list bin.
+func ListGetOp(binName string, index int, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET, index)
+}
+
+// ListGetRangeOp creates a list get range operation.
+// Server returns "count" items starting at specified index in list bin.
+func ListGetRangeOp(binName string, index int, count int, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_RANGE, index, count)
+}
+
+// ListGetRangeFromOp creates a list get range operation.
+// Server returns items starting at specified index to the end of list.
+func ListGetRangeFromOp(binName string, index int, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_RANGE, index)
+}
+
+// ListRemoveByValueOp creates list remove by value operation.
+// Server removes the item identified by value and returns removed data specified by returnType.
+func ListRemoveByValueOp(binName string, value interface{}, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_VALUE, int(returnType), value)
+}
+
+// ListRemoveByValueListOp creates list remove by value list operation.
+// Server removes list items identified by values and returns removed data specified by returnType.
+func ListRemoveByValueListOp(binName string, values []interface{}, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_VALUE_LIST, int(returnType), values)
+}
+
+// ListRemoveByValueRangeOp creates a list remove operation.
+// Server removes list items identified by value range (valueBegin inclusive, valueEnd exclusive).
+// If valueBegin is nil, the range is less than valueEnd.
+// If valueEnd is nil, the range is greater than equal to valueBegin.
+// Server returns removed data specified by returnType.
+func ListRemoveByValueRangeOp(binName string, valueBegin, valueEnd interface{}, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	if valueEnd == nil {
+		return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_VALUE_INTERVAL, int(returnType), valueBegin)
+	}
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_VALUE_INTERVAL, int(returnType), valueBegin, valueEnd)
+}
+
+// ListRemoveByValueRelativeRankRangeOp creates a list remove by value relative to rank range operation.
+// Server removes list items nearest to value and greater by relative rank.
+// Server returns removed data specified by returnType.
+//
+// Examples for ordered list [0,4,5,9,11,15]:
+//
+//	(value,rank) = [removed items]
+//	(5,0) = [5,9,11,15]
+//	(5,1) = [9,11,15]
+//	(5,-1) = [4,5,9,11,15]
+//	(3,0) = [4,5,9,11,15]
+//	(3,3) = [11,15]
+//	(3,-3) = [0,4,5,9,11,15]
+func ListRemoveByValueRelativeRankRangeOp(binName string, value interface{}, rank int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_VALUE_REL_RANK_RANGE, int(returnType), value, rank)
+}
+
+// ListRemoveByValueRelativeRankRangeCountOp creates a list remove by value relative to rank range operation.
+// Server removes "count" list items nearest to value and greater by relative rank.
+// Server returns removed data specified by returnType.
+//
+// Examples for ordered list [0,4,5,9,11,15]:
+//
+//	(value,rank,count) = [removed items]
+//	(5,0,2) = [5,9]
+//	(5,1,1) = [9]
+//	(5,-1,2) = [4,5]
+//	(3,0,1) = [4]
+//	(3,3,7) = [11,15]
+//	(3,-3,2) = []
+func ListRemoveByValueRelativeRankRangeCountOp(binName string, value interface{}, rank, count int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_VALUE_REL_RANK_RANGE, int(returnType), value, rank, count)
+}
+
+// ListRemoveByIndexOp creates a list remove operation.
+// Server removes list item identified by index and returns removed data specified by returnType.
+func ListRemoveByIndexOp(binName string, index int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_INDEX, int(returnType), index)
+}
+
+// ListRemoveByIndexRangeOp creates a list remove operation.
+// Server removes list items starting at specified index to the end of list and returns removed
+// data specified by returnType.
+func ListRemoveByIndexRangeOp(binName string, index int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_INDEX_RANGE, int(returnType), index)
+}
+
+// ListRemoveByIndexRangeCountOp creates a list remove operation.
+// Server removes "count" list items starting at specified index and returns removed data specified by returnType.
+func ListRemoveByIndexRangeCountOp(binName string, index, count int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_INDEX_RANGE, int(returnType), index, count)
+}
+
+// ListRemoveByRankOp creates a list remove operation.
+// Server removes list item identified by rank and returns removed data specified by returnType.
+func ListRemoveByRankOp(binName string, rank int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_RANK, int(returnType), rank)
+}
+
+// ListRemoveByRankRangeOp creates a list remove operation.
+// Server removes list items starting at specified rank to the last ranked item and returns removed
+// data specified by returnType.
+func ListRemoveByRankRangeOp(binName string, rank int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_RANK_RANGE, int(returnType), rank)
+}
+
+// ListRemoveByRankRangeCountOp creates a list remove operation.
+// Server removes "count" list items starting at specified rank and returns removed data specified by returnType.
+func ListRemoveByRankRangeCountOp(binName string, rank, count int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_MODIFY, binName, ctx, _CDT_LIST_REMOVE_BY_RANK_RANGE, int(returnType), rank, count)
+}
+
+// ListGetByValueOp creates a list get by value operation.
+// Server selects list items identified by value and returns selected data specified by returnType.
+func ListGetByValueOp(binName string, value interface{}, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_VALUE, int(returnType), value)
+}
+
+// ListGetByValueListOp creates list get by value list operation.
+// Server selects list items identified by values and returns selected data specified by returnType.
+func ListGetByValueListOp(binName string, values []interface{}, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_VALUE_LIST, int(returnType), values)
+}
+
+// ListGetByValueRangeOp creates list get by value range operation.
+// Server selects list items identified by value range (valueBegin inclusive, valueEnd exclusive).
+// If valueBegin is nil, the range is less than valueEnd.
+// If valueEnd is nil, the range is greater than equal to valueBegin.
+// Server returns selected data specified by returnType.
+func ListGetByValueRangeOp(binName string, valueBegin, valueEnd interface{}, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	if valueEnd == nil {
+		return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_VALUE_INTERVAL, int(returnType), valueBegin)
+	}
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_VALUE_INTERVAL, int(returnType), valueBegin, valueEnd)
+}
+
+// ListGetByValueRelativeRankRangeOp creates a list get by value relative to rank range operation.
+// Server selects list items nearest to value and greater by relative rank.
+// Server returns selected data specified by returnType.
+//
+// Examples for ordered list [0,4,5,9,11,15]:
+//
+//	(value,rank) = [selected items]
+//	(5,0) = [5,9,11,15]
+//	(5,1) = [9,11,15]
+//	(5,-1) = [4,5,9,11,15]
+//	(3,0) = [4,5,9,11,15]
+//	(3,3) = [11,15]
+//	(3,-3) = [0,4,5,9,11,15]
+func ListGetByValueRelativeRankRangeOp(binName string, value interface{}, rank int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_VALUE_REL_RANK_RANGE, int(returnType), value, rank)
+}
+
+// ListGetByValueRelativeRankRangeCountOp creates a list get by value relative to rank range operation.
+// Server selects "count" list items nearest to value and greater by relative rank.
+// Server returns selected data specified by returnType.
+//
+// Examples for ordered list [0,4,5,9,11,15]:
+//
+//	(value,rank,count) = [selected items]
+//	(5,0,2) = [5,9]
+//	(5,1,1) = [9]
+//	(5,-1,2) = [4,5]
+//	(3,0,1) = [4]
+//	(3,3,7) = [11,15]
+//	(3,-3,2) = []
+func ListGetByValueRelativeRankRangeCountOp(binName string, value interface{}, rank, count int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_VALUE_REL_RANK_RANGE, int(returnType), value, rank, count)
+}
+
+// ListGetByIndexOp creates list get by index operation.
+// Server selects list item identified by index and returns selected data specified by returnType.
+func ListGetByIndexOp(binName string, index int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_INDEX, int(returnType), index)
+}
+
+// ListGetByIndexRangeOp creates list get by index range operation.
+// Server selects list items starting at specified index to the end of list and returns selected
+// data specified by returnType.
+func ListGetByIndexRangeOp(binName string, index int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_INDEX_RANGE, int(returnType), index)
+}
+
+// ListGetByIndexRangeCountOp creates list get by index range operation.
+// Server selects "count" list items starting at specified index and returns selected data specified
+// by returnType.
+func ListGetByIndexRangeCountOp(binName string, index, count int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_INDEX_RANGE, int(returnType), index, count)
+}
+
+// ListGetByRankOp creates a list get by rank operation.
+// Server selects list item identified by rank and returns selected data specified by returnType.
+func ListGetByRankOp(binName string, rank int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_RANK, int(returnType), rank)
+}
+
+// ListGetByRankRangeOp creates a list get by rank range operation.
+// Server selects list items starting at specified rank to the last ranked item and returns selected
+// data specified by returnType.
+func ListGetByRankRangeOp(binName string, rank int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_RANK_RANGE, int(returnType), rank)
+}
+
+// ListGetByRankRangeCountOp creates a list get by rank range operation.
+// Server selects "count" list items starting at specified rank and returns selected data specified by returnType.
+func ListGetByRankRangeCountOp(binName string, rank, count int, returnType ListReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTOperation(CDT_READ, binName, ctx, _CDT_LIST_GET_BY_RANK_RANGE, int(returnType), rank, count)
+}
@@ -0,0 +1,84 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// ListOrderType determines the order of a list.
+type ListOrderType int
+
+const (
+	// ListOrderUnordered means the list is not ordered. This is the default.
+	ListOrderUnordered ListOrderType = 0
+
+	// ListOrderOrdered means the list is kept ordered by value.
+	ListOrderOrdered ListOrderType = 1
+)
+
+// ListWriteFlags determines the behavior of list write operations.
+// Flags can be combined using bitwise OR.
+type ListWriteFlags int
+
+const (
+	// ListWriteFlagsDefault is the default. Allows duplicate values and inserts at any index.
+	ListWriteFlagsDefault ListWriteFlags = 0
+
+	// ListWriteFlagsAddUnique only adds unique values.
+	ListWriteFlagsAddUnique ListWriteFlags = 1
+
+	// ListWriteFlagsInsertBounded enforces list boundaries when inserting.
+	// Do not allow values to be inserted at an index outside the current list boundaries.
+	ListWriteFlagsInsertBounded ListWriteFlags = 2
+
+	// ListWriteFlagsNoFail means: do not raise an error if a list item fails due to write flag constraints.
+	ListWriteFlagsNoFail ListWriteFlags = 4
+
+	// ListWriteFlagsPartial allows other valid list items to be committed if a list item fails
+	// due to write flag constraints. Should be combined with ListWriteFlagsNoFail.
+	ListWriteFlagsPartial ListWriteFlags = 8
+)
+
+// ListSortFlags determines the sort behavior of a list.
+type ListSortFlags int
+
+const (
+	// ListSortFlagsDefault sorts the list in ascending order.
+	ListSortFlagsDefault ListSortFlags = 0
+
+	// ListSortFlagsDescending sorts the list in descending order.
+	ListSortFlagsDescending ListSortFlags = 1
+
+	// ListSortFlagsDropDuplicates drops duplicate values while sorting.
+	ListSortFlagsDropDuplicates ListSortFlags = 2
+)
+
+// ListPolicy determines the list order and write flags
+// used in list write operations.
+type ListPolicy struct {
+	attributes ListOrderType
+	flags      ListWriteFlags
+}
+
+// NewListPolicy creates a list policy with the specified order and write flags.
+// The order is only used when the list is created.
+func NewListPolicy(order ListOrderType, flags ListWriteFlags) *ListPolicy {
+	return &ListPolicy{
+		attributes: order,
+		flags:      flags,
+	}
+}
+
+// DefaultListPolicy returns the default list policy: unordered list with default write flags.
+func DefaultListPolicy() *ListPolicy {
+	return NewListPolicy(ListOrderUnordered, ListWriteFlagsDefault)
+}
@@ -0,0 +1,227 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("CDT List Test", func() {
+	initTestVars()
+
+	// connection data
+	var client *Client
+	var err error
+	var ns = "test"
+	var set = randString(50)
+	var key *Key
+	var wpolicy = NewWritePolicy(0, 0)
+	var cdtBinName string
+	var list []interface{}
+
+	BeforeEach(func() {
+		client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+		key, err = NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		cdtBinName = randString(10)
+		list = []interface{}{0, 4, 5, 9, 11, 15}
+
+		_, err = client.Operate(wpolicy, key, ListAppendOp(cdtBinName, list...))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should append, insert, set and get list items", func() {
+		rec, err := client.Operate(wpolicy, key,
+			ListAppendOp(cdtBinName, 20),
+			ListInsertOp(cdtBinName, 0, -1, -2),
+			ListSetOp(cdtBinName, 1, -3),
+			ListSizeOp(cdtBinName),
+			ListGetOp(cdtBinName, 0),
+			ListGetRangeOp(cdtBinName, 0, 3),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{7, 9, 9, -1, []interface{}{-1, -3, 0}}))
+	})
+
+	It("should create an ordered list in the same Operate call", func() {
+		otherBin := randString(10)
+		rec, err := client.Operate(wpolicy, key,
+			ListCreateOp(otherBin, ListOrderOrdered),
+			ListAppendOp(otherBin, 3, 1, 2),
+			ListGetRangeOp(otherBin, 0, 3),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[otherBin]).To(Equal([]interface{}{nil, 3, []interface{}{1, 2, 3}}))
+	})
+
+	It("should pop, remove and trim list items", func() {
+		rec, err := client.Operate(wpolicy, key,
+			ListPopOp(cdtBinName, 0),
+			ListPopRangeOp(cdtBinName, 0, 2),
+			ListRemoveOp(cdtBinName, 0),
+			ListTrimOp(cdtBinName, 0, 1),
+			ListGetRangeFromOp(cdtBinName, 0),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{0, []interface{}{4, 5}, 1, 1, []interface{}{11}}))
+
+		rec, err = client.Operate(wpolicy, key,
+			ListClearOp(cdtBinName),
+			ListSizeOp(cdtBinName),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal(0))
+	})
+
+	It("should increment list items", func() {
+		rec, err := client.Operate(wpolicy, key,
+			ListIncrementOp(cdtBinName, 1, 10),
+			ListIncrementWithPolicyOp(DefaultListPolicy(), cdtBinName, 2, -5),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{14, 0}))
+	})
+
+	It("should sort and set the order of the list", func() {
+		rec, err := client.Operate(wpolicy, key,
+			ListSortOp(cdtBinName, ListSortFlagsDescending),
+			ListGetRangeFromOp(cdtBinName, 0),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{15, 11, 9, 5, 4, 0}))
+
+		rec, err = client.Operate(wpolicy, key,
+			ListSetOrderOp(cdtBinName, ListOrderOrdered),
+			ListAppendOp(cdtBinName, 7),
+			ListGetRangeFromOp(cdtBinName, 0),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{7, []interface{}{0, 4, 5, 7, 9, 11, 15}}))
+	})
+
+	It("should honor list write flags", func() {
+		policy := NewListPolicy(ListOrderUnordered, ListWriteFlagsAddUnique)
+		_, err := client.Operate(wpolicy, key, ListAppendWithPolicyOp(policy, cdtBinName, 4))
+		Expect(err).To(HaveOccurred())
+
+		policy = NewListPolicy(ListOrderUnordered, ListWriteFlagsAddUnique|ListWriteFlagsNoFail|ListWriteFlagsPartial)
+		rec, err := client.Operate(wpolicy, key, ListAppendWithPolicyOp(policy, cdtBinName, 4, 100))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal(7))
+
+		policy = NewListPolicy(ListOrderUnordered, ListWriteFlagsInsertBounded)
+		_, err = client.Operate(wpolicy, key, ListInsertWithPolicyOp(policy, cdtBinName, 100, 1))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should operate on nested lists", func() {
+		rec, err := client.Operate(wpolicy, key,
+			ListAppendOp(cdtBinName, []interface{}{3, 2, 1}),
+			ListSortOp(cdtBinName, ListSortFlagsDefault, CtxListIndex(-1)),
+			ListInsertWithPolicyContextOp(DefaultListPolicy(), cdtBinName, 0, []*CDTContext{CtxListIndex(-1)}, 0),
+			ListGetByRankOp(cdtBinName, -1, ListReturnTypeValue, CtxListIndex(-1)),
+			ListGetRangeFromOp(cdtBinName, 0, CtxListRank(-1)),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{7, 4, 3, []interface{}{0, 1, 2, 3}}))
+	})
+
+	It("should get list items by index, rank and value", func() {
+		rec, err := client.Operate(wpolicy, key,
+			ListGetByIndexOp(cdtBinName, 1, ListReturnTypeValue),
+			ListGetByIndexRangeCountOp(cdtBinName, 1, 2, ListReturnTypeValue),
+			ListGetByRankOp(cdtBinName, -1, ListReturnTypeValue),
+			ListGetByRankRangeOp(cdtBinName, 4, ListReturnTypeValue),
+			ListGetByValueOp(cdtBinName, 5, ListReturnTypeIndex),
+			ListGetByValueListOp(cdtBinName, []interface{}{0, 15}, ListReturnTypeCount),
+			ListGetByValueRangeOp(cdtBinName, 5, 11, ListReturnTypeValue),
+			ListGetByValueRangeOp(cdtBinName, 11, nil, ListReturnTypeValue),
+			ListGetByIndexRangeOp(cdtBinName, 4, ListReturnTypeValue|ListReturnTypeInverted),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{
+			4,
+			[]interface{}{4, 5},
+			15,
+			[]interface{}{11, 15},
+			[]interface{}{2},
+			2,
+			[]interface{}{5, 9},
+			[]interface{}{11, 15},
+			[]interface{}{0, 4, 5, 9},
+		}))
+	})
+
+	It("should get list items by value relative rank range", func() {
+		rec, err := client.Operate(wpolicy, key,
+			ListGetByValueRelativeRankRangeOp(cdtBinName, 5, 1, ListReturnTypeValue),
+			ListGetByValueRelativeRankRangeOp(cdtBinName, 3, -3, ListReturnTypeValue),
+			ListGetByValueRelativeRankRangeCountOp(cdtBinName, 5, -1, 2, ListReturnTypeValue),
+			ListGetByValueRelativeRankRangeCountOp(cdtBinName, 3, 3, 7, ListReturnTypeValue),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{
+			[]interface{}{9, 11, 15},
+			[]interface{}{0, 4, 5, 9, 11, 15},
+			[]interface{}{4, 5},
+			[]interface{}{11, 15},
+		}))
+	})
+
+	It("should remove list items by index, rank and value", func() {
+		rec, err := client.Operate(wpolicy, key,
+			ListRemoveByIndexOp(cdtBinName, 0, ListReturnTypeValue),
+			ListRemoveByRankOp(cdtBinName, -1, ListReturnTypeValue),
+			ListRemoveByValueOp(cdtBinName, 9, ListReturnTypeCount),
+			ListRemoveByValueListOp(cdtBinName, []interface{}{4, 100}, ListReturnTypeCount),
+			ListSizeOp(cdtBinName),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{0, 15, 1, 1, 2}))
+	})
+
+	It("should remove list item ranges", func() {
+		rec, err := client.Operate(wpolicy, key,
+			ListRemoveByValueRelativeRankRangeCountOp(cdtBinName, 5, 0, 2, ListReturnTypeValue),
+			ListRemoveByValueRangeOp(cdtBinName, nil, 5, ListReturnTypeValue),
+			ListRemoveByRankRangeCountOp(cdtBinName, 0, 1, ListReturnTypeValue),
+			ListGetRangeFromOp(cdtBinName, 0),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{
+			[]interface{}{5, 9},
+			[]interface{}{0, 4},
+			[]interface{}{11},
+			[]interface{}{15},
+		}))
+
+		rec, err = client.Operate(wpolicy, key,
+			ListAppendOp(cdtBinName, 1, 2, 3),
+			ListRemoveByIndexRangeCountOp(cdtBinName, 0, 2, ListReturnTypeCount),
+			ListRemoveByRankRangeOp(cdtBinName, 1, ListReturnTypeCount),
+			ListRemoveByValueRelativeRankRangeOp(cdtBinName, 0, 0, ListReturnTypeValue),
+			ListRemoveRangeFromOp(cdtBinName, 0),
+			ListPopRangeFromOp(cdtBinName, 0),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{4, 2, 1, []interface{}{2}, 0, []interface{}{}}))
+	})
+
+})
@@ -0,0 +1,471 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// Map operation commands.
+const (
+	_CDT_MAP_SET_TYPE                       = 64
+	_CDT_MAP_ADD                            = 65
+	_CDT_MAP_ADD_ITEMS                      = 66
+	_CDT_MAP_PUT                            = 67
+	_CDT_MAP_PUT_ITEMS                      = 68
+	_CDT_MAP_REPLACE                        = 69
+	_CDT_MAP_REPLACE_ITEMS                  = 70
+	_CDT_MAP_INCREMENT                      = 73
+	_CDT_MAP_DECREMENT                      = 74
+	_CDT_MAP_CLEAR                          = 75
+	_CDT_MAP_REMOVE_BY_KEY                  = 76
+	_CDT_MAP_REMOVE_BY_INDEX                = 77
+	_CDT_MAP_REMOVE_BY_RANK                 = 79
+	_CDT_MAP_REMOVE_BY_KEY_LIST             = 81
+	_CDT_MAP_REMOVE_BY_VALUE                = 82
+	_CDT_MAP_REMOVE_BY_VALUE_LIST           = 83
+	_CDT_MAP_REMOVE_BY_KEY_INTERVAL         = 84
+	_CDT_MAP_REMOVE_BY_INDEX_RANGE          = 85
+	_CDT_MAP_REMOVE_BY_VALUE_INTERVAL       = 86
+	_CDT_MAP_REMOVE_BY_RANK_RANGE           = 87
+	_CDT_MAP_REMOVE_BY_KEY_REL_INDEX_RANGE  = 88
+	_CDT_MAP_REMOVE_BY_VALUE_REL_RANK_RANGE = 89
+	_CDT_MAP_SIZE                           = 96
+	_CDT_MAP_GET_BY_KEY                     = 97
+	_CDT_MAP_GET_BY_INDEX                   = 98
+	_CDT_MAP_GET_BY_RANK                    = 100
+	_CDT_MAP_GET_BY_VALUE                   = 102
+	_CDT_MAP_GET_BY_KEY_INTERVAL            = 103
+	_CDT_MAP_GET_BY_INDEX_RANGE             = 104
+	_CDT_MAP_GET_BY_VALUE_INTERVAL          = 105
+	_CDT_MAP_GET_BY_RANK_RANGE              = 106
+	_CDT_MAP_GET_BY_KEY_LIST                = 107
+	_CDT_MAP_GET_BY_VALUE_LIST              = 108
+	_CDT_MAP_GET_BY_KEY_REL_INDEX_RANGE     = 109
+	_CDT_MAP_GET_BY_VALUE_REL_RANK_RANGE    = 110
+)
+
+// MapReturnType determines the data type returned by
+// map get-by and remove-by operations.
+type MapReturnType int
+
+const (
+	// MapReturnTypeNone will not return a result.
+	MapReturnTypeNone MapReturnType = 0
+
+	// MapReturnTypeIndex will return key index order.
+	// 0 = first key
+	// N = Nth key
+	// -1 = last key
+	MapReturnTypeIndex MapReturnType = 1
+
+	// MapReturnTypeReverseIndex will return reverse key order.
+	// 0 = last key
+	// -1 = first key
+	MapReturnTypeReverseIndex MapReturnType = 2
+
+	// MapReturnTypeRank will return value order.
+	// 0 = smallest value
+	// N = Nth smallest value
+	// -1 = largest value
+	MapReturnTypeRank MapReturnType = 3
+
+	// MapReturnTypeReverseRank will return reverse value order.
+	// 0 = largest value
+	// N = Nth largest value
+	// -1 = smallest value
+	MapReturnTypeReverseRank MapReturnType = 4
+
+	// MapReturnTypeCount will return count of items selected.
+	MapReturnTypeCount MapReturnType = 5
+
+	// MapReturnTypeKey will return key for single key read and key list for range read.
+	MapReturnTypeKey MapReturnType = 6
+
+	// MapReturnTypeValue will return value for single key read and value list for range read.
+	MapReturnTypeValue MapReturnType = 7
+
+	// MapReturnTypeKeyValue will return key/value items.
+	MapReturnTypeKeyValue MapReturnType = 8
+
+	// MapReturnTypeInverted will invert meaning of map command and return values.
+	// For example:
+	//   MapRemoveByKeyRangeOp(binName, keyBegin, keyEnd, MapReturnTypeKey|MapReturnTypeInverted)
+	// With the INVERTED flag enabled, the keys outside of the specified key range will be removed and returned.
+	MapReturnTypeInverted MapReturnType = 0x10000
+)
+
+// mapWriteOp creates a map write operation, appending the policy
+// attributes and write flags to the arguments.
+func mapWriteOp(policy *MapPolicy, binName string, ctx []*CDTContext, command int, args ...interface{}) *Operation {
+	args = append(args, int(policy.attributes))
+	if policy.flags != MapWriteFlagsDefault {
+		args = append(args, int(policy.flags))
+	}
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, command, args...)
+}
+
+// MapSetPolicyOp creates set map policy operation.
+// Server sets map policy attributes. Server returns nil.
+//
+// The required map policy attributes can be changed after the map is created.
+func MapSetPolicyOp(policy *MapPolicy, binName string, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_SET_TYPE, int(policy.attributes))
+}
+
+// MapCreateOp creates a map create operation.
+// Server creates an empty map with the specified order if the bin does not exist,
+// or sets the order of the existing map. Server returns nil.
+// Use it as the first operation of an Operate call, so that the following
+// by-key or by-rank operations behave deterministically on new records.
+func MapCreateOp(binName string, order MapOrderType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_SET_TYPE, int(order))
+}
+
+// MapPutOp creates map put operation.
+// Server writes key/value item to map bin and returns map size.
+//
+// The map policy dictates the type of map to create when it does not exist.
+// The map policy also specifies the flags used when writing items to the map.
+func MapPutOp(policy *MapPolicy, binName string, key interface{}, value interface{}, ctx ...*CDTContext) *Operation {
+	return mapWriteOp(policy, binName, ctx, _CDT_MAP_PUT, key, value)
+}
+
+// MapPutItemsOp creates map put items operation
+// Server writes each map item to map bin and returns map size.
+//
+// The map policy dictates the type of map to create when it does not exist.
+// The map policy also specifies the flags used when writing items to the map.
+func MapPutItemsOp(policy *MapPolicy, binName string, amap map[interface{}]interface{}, ctx ...*CDTContext) *Operation {
+	return mapWriteOp(policy, binName, ctx, _CDT_MAP_PUT_ITEMS, amap)
+}
+
+// MapIncrementOp creates map increment operation.
+// Server increments values by incr for all items identified by key and returns final result.
+// Valid only for numbers.
+//
+// The map policy dictates the type of map to create when it does not exist.
+func MapIncrementOp(policy *MapPolicy, binName string, key interface{}, incr interface{}, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_INCREMENT, key, incr, int(policy.attributes))
+}
+
+// MapDecrementOp creates map decrement operation.
+// Server decrements values by decr for all items identified by key and returns final result.
+// Valid only for numbers.
+//
+// The map policy dictates the type of map to create when it does not exist.
+func MapDecrementOp(policy *MapPolicy, binName string, key interface{}, decr interface{}, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_DECREMENT, key, decr, int(policy.attributes))
+}
+
+// MapClearOp creates map clear operation.
+// Server removes all items in map. Server returns nil.
+func MapClearOp(binName string, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_CLEAR)
+}
+
+// MapRemoveByKeyOp creates map remove operation.
+// Server removes map item identified by key and returns removed data specified by returnType.
+func MapRemoveByKeyOp(binName string, key interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_KEY, int(returnType), key)
+}
+
+// MapRemoveByKeyListOp creates map remove operation.
+// Server removes map items identified by keys and returns removed data specified by returnType.
+func MapRemoveByKeyListOp(binName string, keys []interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_KEY_LIST, int(returnType), keys)
+}
+
+// MapRemoveByKeyRangeOp creates map remove operation.
+// Server removes map items identified by key range (keyBegin inclusive, keyEnd exclusive).
+// If keyBegin is nil, the range is less than keyEnd.
+// If keyEnd is nil, the range is greater than equal to keyBegin.
+//
+// Server returns removed data specified by returnType.
+func MapRemoveByKeyRangeOp(binName string, keyBegin interface{}, keyEnd interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	if keyEnd == nil {
+		return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_KEY_INTERVAL, int(returnType), keyBegin)
+	}
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_KEY_INTERVAL, int(returnType), keyBegin, keyEnd)
+}
+
+// MapRemoveByKeyRelativeIndexRangeOp creates a map remove by key relative to index range operation.
+// Server removes map items nearest to key and greater by index.
+// Server returns removed data specified by returnType.
+//
+// Examples for map [{0=17},{4=2},{5=15},{9=10}]:
+//
+//	(value,index) = [removed items]
+//	(5,0) = [{5=15},{9=10}]
+//	(5,1) = [{9=10}]
+//	(5,-1) = [{4=2},{5=15},{9=10}]
+//	(3,2) = [{9=10}]
+//	(3,-2) = [{0=17},{4=2},{5=15},{9=10}]
+func MapRemoveByKeyRelativeIndexRangeOp(binName string, key interface{}, index int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_KEY_REL_INDEX_RANGE, int(returnType), key, index)
+}
+
+// MapRemoveByKeyRelativeIndexRangeCountOp creates map remove by key relative to index range operation.
+// Server removes "count" map items nearest to key and greater by index.
+// Server returns removed data specified by returnType.
+//
+// Examples for map [{0=17},{4=2},{5=15},{9=10}]:
+//
+//	(value,index,count) = [removed items]
+//	(5,0,1) = [{5=15}]
+//	(5,1,2) = [{9=10}]
+//	(5,-1,1) = [{4=2}]
+//	(3,2,1) = [{9=10}]
+//	(3,-2,2) = [{0=17}]
+func MapRemoveByKeyRelativeIndexRangeCountOp(binName string, key interface{}, index, count int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_KEY_REL_INDEX_RANGE, int(returnType), key, index, count)
+}
+
+// MapRemoveByValueOp creates map remove operation.
+// Server removes map items identified by value and returns removed data specified by returnType.
+func MapRemoveByValueOp(binName string, value interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_VALUE, int(returnType), value)
+}
+
+// MapRemoveByValueListOp creates map remove operation.
+// Server removes map items identified by values and returns removed data specified by returnType.
+func MapRemoveByValueListOp(binName string, values []interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_VALUE_LIST, int(returnType), values)
+}
+
+// MapRemoveByValueRangeOp creates map remove operation.
+// Server removes map items identified by value range (valueBegin inclusive, valueEnd exclusive).
+// If valueBegin is nil, the range is less than valueEnd.
+// If valueEnd is nil, the range is greater than equal to valueBegin.
+//
+// Server returns removed data specified by returnType.
+func MapRemoveByValueRangeOp(binName string, valueBegin interface{}, valueEnd interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	if valueEnd == nil {
+		return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_VALUE_INTERVAL, int(returnType), valueBegin)
+	}
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_VALUE_INTERVAL, int(returnType), valueBegin, valueEnd)
+}
+
+// MapRemoveByValueRelativeRankRangeOp creates a map remove by value relative to rank range operation.
+// Server removes map items nearest to value and greater by relative rank.
+// Server returns removed data specified by returnType.
+//
+// Examples for map [{4=2},{9=10},{5=15},{0=17}]:
+//
+//	(value,rank) = [removed items]
+//	(11,1) = [{0=17}]
+//	(11,-1) = [{9=10},{5=15},{0=17}]
+func MapRemoveByValueRelativeRankRangeOp(binName string, value interface{}, rank int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_VALUE_REL_RANK_RANGE, int(returnType), value, rank)
+}
+
+// MapRemoveByValueRelativeRankRangeCountOp creates a map remove by value relative to rank range operation.
+// Server removes "count" map items nearest to value and greater by relative rank.
+// Server returns removed data specified by returnType.
+//
+// Examples for map [{4=2},{9=10},{5=15},{0=17}]:
+//
+//	(value,rank,count) = [removed items]
+//	(11,1,1) = [{0=17}]
+//	(11,-1,1) = [{9=10}]
+func MapRemoveByValueRelativeRankRangeCountOp(binName string, value interface{}, rank, count int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_VALUE_REL_RANK_RANGE, int(returnType), value, rank, count)
+}
+
+// MapRemoveByIndexOp creates map remove operation.
+// Server removes map item identified by index and returns removed data specified by returnType.
+func MapRemoveByIndexOp(binName string, index int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_INDEX, int(returnType), index)
+}
+
+// MapRemoveByIndexRangeOp creates map remove operation.
+// Server removes map items starting at specified index to the end of map and returns removed
+// data specified by returnType.
+func MapRemoveByIndexRangeOp(binName string, index int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_INDEX_RANGE, int(returnType), index)
+}
+
+// MapRemoveByIndexRangeCountOp creates map remove operation.
+// Server removes "count" map items starting at specified index and returns removed data specified by returnType.
+func MapRemoveByIndexRangeCountOp(binName string, index int, count int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_INDEX_RANGE, int(returnType), index, count)
+}
+
+// MapRemoveByRankOp creates map remove operation.
+// Server removes map item identified by rank and returns removed data specified by returnType.
+func MapRemoveByRankOp(binName string, rank int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_RANK, int(returnType), rank)
+}
+
+// MapRemoveByRankRangeOp creates map remove operation.
+// Server removes map items starting at specified rank to the last ranked item and returns removed
+// data specified by returnType.
+func MapRemoveByRankRangeOp(binName string, rank int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_RANK_RANGE, int(returnType), rank)
+}
+
+// MapRemoveByRankRangeCountOp creates map remove operation.
+// Server removes "count" map items starting at specified rank and returns removed data specified by returnType.
+func MapRemoveByRankRangeCountOp(binName string, rank int, count int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_MODIFY, binName, ctx, _CDT_MAP_REMOVE_BY_RANK_RANGE, int(returnType), rank, count)
+}
+
+// MapSizeOp creates map size operation.
+// Server returns size of map.
+func MapSizeOp(binName string, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_SIZE)
+}
+
+// MapGetByKeyOp creates map get by key operation.
+// Server selects map item identified by key and returns selected data specified by returnType.
+func MapGetByKeyOp(binName string, key interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_KEY, int(returnType), key)
+}
+
+// MapGetByKeyListOp creates a map get by key list operation.
+// Server selects map items identified by keys and returns selected data specified by returnType.
+func MapGetByKeyListOp(binName string, keys []interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_KEY_LIST, int(returnType), keys)
+}
+
+// MapGetByKeyRangeOp creates map get by key range operation.
+// Server selects map items identified by key range (keyBegin inclusive, keyEnd exclusive).
+// If keyBegin is nil, the range is less than keyEnd.
+// If keyEnd is nil, the range is greater than equal to keyBegin.
+//
+// Server returns selected data specified by returnType.
+func MapGetByKeyRangeOp(binName string, keyBegin interface{}, keyEnd interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	if keyEnd == nil {
+		return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_KEY_INTERVAL, int(returnType), keyBegin)
+	}
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_KEY_INTERVAL, int(returnType), keyBegin, keyEnd)
+}
+
+// MapGetByKeyRelativeIndexRangeOp creates a map get by key relative to index range operation.
+// Server selects map items nearest to key and greater by index.
+// Server returns selected data specified by returnType.
+//
+// Examples for ordered map [{0=17},{4=2},{5=15},{9=10}]:
+//
+//	(value,index) = [selected items]
+//	(5,0) = [{5=15},{9=10}]
+//	(5,1) = [{9=10}]
+//	(5,-1) = [{4=2},{5=15},{9=10}]
+//	(3,2) = [{9=10}]
+//	(3,-2) = [{0=17},{4=2},{5=15},{9=10}]
+func MapGetByKeyRelativeIndexRangeOp(binName string, key interface{}, index int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_KEY_REL_INDEX_RANGE, int(returnType), key, index)
+}
+
+// MapGetByKeyRelativeIndexRangeCountOp creates a map get by key relative to index range operation.
+// Server selects "count" map items nearest to key and greater by index.
+// Server returns selected data specified by returnType.
+//
+// Examples for ordered map [{0=17},{4=2},{5=15},{9=10}]:
+//
+//	(value,index,count) = [selected items]
+//	(5,0,1) = [{5=15}]
+//	(5,1,2) = [{9=10}]
+//	(5,-1,1) = [{4=2}]
+//	(3,2,1) = [{9=10}]
+//	(3,-2,2) = [{0=17}]
+func MapGetByKeyRelativeIndexRangeCountOp(binName string, key interface{}, index, count int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_KEY_REL_INDEX_RANGE, int(returnType), key, index, count)
+}
+
+// MapGetByValueOp creates map get by value operation.
+// Server selects map items identified by value and returns selected data specified by returnType.
+func MapGetByValueOp(binName string, value interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_VALUE, int(returnType), value)
+}
+
+// MapGetByValueListOp creates map get by value list operation.
+// Server selects map items identified by values and returns selected data specified by returnType.
+func MapGetByValueListOp(binName string, values []interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_VALUE_LIST, int(returnType), values)
+}
+
+// MapGetByValueRangeOp creates map get by value range operation.
+// Server selects map items identified by value range (valueBegin inclusive, valueEnd exclusive)
+// If valueBegin is nil, the range is less than valueEnd.
+// If valueEnd is nil, the range is greater than equal to valueBegin.
+//
+// Server returns selected data specified by returnType.
+func MapGetByValueRangeOp(binName string, valueBegin interface{}, valueEnd interface{}, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	if valueEnd == nil {
+		return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_VALUE_INTERVAL, int(returnType), valueBegin)
+	}
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_VALUE_INTERVAL, int(returnType), valueBegin, valueEnd)
+}
+
+// MapGetByValueRelativeRankRangeOp creates a map get by value relative to rank range operation.
+// Server selects map items nearest to value and greater by relative rank.
+// Server returns selected data specified by returnType.
+//
+// Examples for map [{4=2},{9=10},{5=15},{0=17}]:
+//
+//	(value,rank) = [selected items]
+//	(11,1) = [{0=17}]
+//	(11,-1) = [{9=10},{5=15},{0=17}]
+func MapGetByValueRelativeRankRangeOp(binName string, value interface{}, rank int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_VALUE_REL_RANK_RANGE, int(returnType), value, rank)
+}
+
+// MapGetByValueRelativeRankRangeCountOp creates a map get by value relative to rank range operation.
+// Server selects "count" map items nearest to value and greater by relative rank.
+// Server returns selected data specified by returnType.
+//
+// Examples for map [{4=2},{9=10},{5=15},{0=17}]:
+//
+//	(value,rank,count) = [selected items]
+//	(11,1,1) = [{0=17}]
+//	(11,-1,1) = [{9=10}]
+func MapGetByValueRelativeRankRangeCountOp(binName string, value interface{}, rank, count int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_VALUE_REL_RANK_RANGE, int(returnType), value, rank, count)
+}
+
+// MapGetByIndexOp creates map get by index operation.
+// Server selects map item identified by index and returns selected data specified by returnType.
+func MapGetByIndexOp(binName string, index int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_INDEX, int(returnType), index)
+}
+
+// MapGetByIndexRangeOp creates map get by index range operation.
+// Server selects map items starting at specified index to the end of map and returns selected
+// data specified by returnType.
+func MapGetByIndexRangeOp(binName string, index int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_INDEX_RANGE, int(returnType), index)
+}
+
+// MapGetByIndexRangeCountOp creates map get by index range operation.
+// Server selects "count" map items starting at specified index and returns selected data specified by returnType.
+func MapGetByIndexRangeCountOp(binName string, index int, count int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_INDEX_RANGE, int(returnType), index, count)
+}
+
+// MapGetByRankOp creates map get by rank operation.
+// Server selects map item identified by rank and returns selected data specified by returnType.
+func MapGetByRankOp(binName string, rank int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_RANK, int(returnType), rank)
+}
+
+// MapGetByRankRangeOp creates map get by rank range operation.
+// Server selects map items starting at specified rank to the last ranked item and returns selected
+// data specified by returnType.
+func MapGetByRankRangeOp(binName string, rank int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_RANK_RANGE, int(returnType), rank)
+}
+
+// MapGetByRankRangeCountOp creates map get by rank range operation.
+// Server selects "count" map items starting at specified rank and returns selected data specified by returnType.
+func MapGetByRankRangeCountOp(binName string, rank int, count int, returnType MapReturnType, ctx ...*CDTContext) *Operation {
+	return newCDTMapOperation(CDT_READ, binName, ctx, _CDT_MAP_GET_BY_RANK_RANGE, int(returnType), rank, count)
+}
@@ -0,0 +1,74 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// MapOrderType determines the order of a map.
+type MapOrderType int
+
+const (
+	// MapOrderUnordered means the map is not ordered. This is the default.
+	MapOrderUnordered MapOrderType = 0
+
+	// MapOrderKeyOrdered means the map is ordered by key.
+	MapOrderKeyOrdered MapOrderType = 1
+
+	// MapOrderKeyValueOrdered means the map is ordered by key and value.
+	MapOrderKeyValueOrdered MapOrderType = 3
+)
+
+// MapWriteFlags determines the behavior of map write operations.
+// Flags can be combined using bitwise OR.
+type MapWriteFlags int
+
+const (
+	// MapWriteFlagsDefault is the default. Allows create or update.
+	MapWriteFlagsDefault MapWriteFlags = 0
+
+	// MapWriteFlagsCreateOnly means: If the key already exists, the item will be denied.
+	// If the key does not exist, a new item will be created.
+	MapWriteFlagsCreateOnly MapWriteFlags = 1
+
+	// MapWriteFlagsUpdateOnly means: If the key already exists, the item will be overwritten.
+	// If the key does not exist, the item will be denied.
+	MapWriteFlagsUpdateOnly MapWriteFlags = 2
+
+	// MapWriteFlagsNoFail means: Do not raise error if a map item is denied due to write flag constraints.
+	MapWriteFlagsNoFail MapWriteFlags = 4
+
+	// MapWriteFlagsPartial means: Allow other valid map items to be committed if a map item is denied due to
+	// write flag constraints. Should be combined with MapWriteFlagsNoFail.
+	MapWriteFlagsPartial MapWriteFlags = 8
+)
+
+// MapPolicy determines the map order and write flags
+// used in map write operations.
+type MapPolicy struct {
+	attributes MapOrderType
+	flags      MapWriteFlags
+}
+
+// NewMapPolicy creates a map policy with the specified order and write flags.
+// The order is only used when the map is created.
+func NewMapPolicy(order MapOrderType, flags MapWriteFlags) *MapPolicy {
+	return &MapPolicy{
+		attributes: order,
+		flags:      flags,
+	}
+}
+
+// DefaultMapPolicy returns the default map policy: unordered map with default write flags.
+func DefaultMapPolicy() *MapPolicy {
+	return NewMapPolicy(MapOrderUnordered, MapWriteFlagsDefault)
+}
@@ -0,0 +1,252 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("CDT Map Test", func() {
+	initTestVars()
+
+	// connection data
+	var client *Client
+	var err error
+	var ns = "test"
+	var set = randString(50)
+	var key *Key
+	var wpolicy = NewWritePolicy(0, 0)
+	var mpolicy = NewMapPolicy(MapOrderKeyOrdered, MapWriteFlagsDefault)
+	var cdtBinName string
+
+	BeforeEach(func() {
+		client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+		key, err = NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		cdtBinName = randString(10)
+		items := map[interface{}]interface{}{"k1": 1, "k2": 2, "k3": 3}
+
+		rec, err := client.Operate(wpolicy, key, MapPutItemsOp(mpolicy, cdtBinName, items))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal(3))
+	})
+
+	It("should put and get map items", func() {
+		rec, err := client.Operate(wpolicy, key,
+			MapPutOp(mpolicy, cdtBinName, "k4", 4),
+			MapSizeOp(cdtBinName),
+			MapGetByKeyOp(cdtBinName, "k2", MapReturnTypeValue),
+			MapGetByKeyRangeOp(cdtBinName, "k1", "k3", MapReturnTypeKey),
+			MapGetByKeyRangeOp(cdtBinName, "k3", nil, MapReturnTypeKeyValue),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{
+			4,
+			4,
+			2,
+			[]interface{}{"k1", "k2"},
+			map[interface{}]interface{}{"k3": 3, "k4": 4},
+		}))
+	})
+
+	It("should create ordered maps with Put and Operate", func() {
+		items := map[interface{}]interface{}{"z": 1, "y": 2, "x": 3}
+		err := client.PutBins(wpolicy, key, NewBin(cdtBinName, NewOrderedMapValue(items, MapOrderKeyOrdered)))
+		Expect(err).ToNot(HaveOccurred())
+
+		rec, err := client.Operate(wpolicy, key, MapGetByIndexOp(cdtBinName, 0, MapReturnTypeKey))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal("x"))
+
+		otherBin := randString(10)
+		rec, err = client.Operate(wpolicy, key,
+			MapCreateOp(otherBin, MapOrderKeyOrdered),
+			MapPutItemsOp(DefaultMapPolicy(), otherBin, items),
+			MapGetByIndexOp(otherBin, 0, MapReturnTypeKey),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[otherBin]).To(Equal([]interface{}{nil, 3, "x"}))
+	})
+
+	It("should increment and decrement map items", func() {
+		rec, err := client.Operate(wpolicy, key,
+			MapIncrementOp(mpolicy, cdtBinName, "k1", 10),
+			MapDecrementOp(mpolicy, cdtBinName, "k2", 1),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{11, 1}))
+	})
+
+	It("should honor map write flags", func() {
+		policy := NewMapPolicy(MapOrderKeyOrdered, MapWriteFlagsCreateOnly)
+		_, err := client.Operate(wpolicy, key, MapPutOp(policy, cdtBinName, "k1", 10))
+		Expect(err).To(HaveOccurred())
+
+		policy = NewMapPolicy(MapOrderKeyOrdered, MapWriteFlagsCreateOnly|MapWriteFlagsNoFail|MapWriteFlagsPartial)
+		rec, err := client.Operate(wpolicy, key, MapPutItemsOp(policy, cdtBinName, map[interface{}]interface{}{"k1": 10, "k5": 5}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal(4))
+
+		policy = NewMapPolicy(MapOrderKeyOrdered, MapWriteFlagsUpdateOnly)
+		_, err = client.Operate(wpolicy, key, MapPutOp(policy, cdtBinName, "k9", 9))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should get map items by index, rank and value", func() {
+		rec, err := client.Operate(wpolicy, key,
+			MapGetByIndexOp(cdtBinName, 0, MapReturnTypeKey),
+			MapGetByIndexRangeCountOp(cdtBinName, 1, 2, MapReturnTypeValue),
+			MapGetByRankOp(cdtBinName, -1, MapReturnTypeValue),
+			MapGetByRankRangeOp(cdtBinName, 1, MapReturnTypeCount),
+			MapGetByValueOp(cdtBinName, 2, MapReturnTypeKey),
+			MapGetByValueListOp(cdtBinName, []interface{}{1, 3}, MapReturnTypeKey),
+			MapGetByValueRangeOp(cdtBinName, 2, nil, MapReturnTypeCount),
+			MapGetByKeyListOp(cdtBinName, []interface{}{"k1"}, MapReturnTypeKey|MapReturnTypeInverted),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{
+			"k1",
+			[]interface{}{2, 3},
+			3,
+			2,
+			[]interface{}{"k2"},
+			[]interface{}{"k1", "k3"},
+			2,
+			[]interface{}{"k2", "k3"},
+		}))
+	})
+
+	It("should get map items relative to a key or value", func() {
+		rec, err := client.Operate(wpolicy, key,
+			MapGetByKeyRelativeIndexRangeOp(cdtBinName, "k2", 0, MapReturnTypeKey),
+			MapGetByKeyRelativeIndexRangeCountOp(cdtBinName, "k2", -1, 1, MapReturnTypeKey),
+			MapGetByValueRelativeRankRangeOp(cdtBinName, 2, 1, MapReturnTypeValue),
+			MapGetByValueRelativeRankRangeCountOp(cdtBinName, 2, -1, 1, MapReturnTypeValue),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{
+			[]interface{}{"k2", "k3"},
+			[]interface{}{"k1"},
+			[]interface{}{3},
+			[]interface{}{1},
+		}))
+	})
+
+	It("should remove map items", func() {
+		rec, err := client.Operate(wpolicy, key,
+			MapPutItemsOp(mpolicy, cdtBinName, map[interface{}]interface{}{"k4": 4, "k5": 5, "k6": 6}),
+			MapRemoveByKeyOp(cdtBinName, "k1", MapReturnTypeValue),
+			MapRemoveByValueOp(cdtBinName, 3, MapReturnTypeKey),
+			MapRemoveByKeyListOp(cdtBinName, []interface{}{"k2", "k9"}, MapReturnTypeCount),
+			MapRemoveByIndexOp(cdtBinName, 0, MapReturnTypeKey),
+			MapRemoveByRankOp(cdtBinName, -1, MapReturnTypeValue),
+			MapSizeOp(cdtBinName),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{6, 1, []interface{}{"k3"}, 1, "k4", 6, 1}))
+	})
+
+	It("should remove map item ranges", func() {
+		rec, err := client.Operate(wpolicy, key,
+			MapPutItemsOp(mpolicy, cdtBinName, map[interface{}]interface{}{"k4": 4, "k5": 5, "k6": 6}),
+			MapRemoveByKeyRangeOp(cdtBinName, "k1", "k3", MapReturnTypeCount),
+			MapRemoveByValueRangeOp(cdtBinName, nil, 4, MapReturnTypeKey),
+			MapRemoveByIndexRangeCountOp(cdtBinName, 0, 1, MapReturnTypeValue|MapReturnTypeInverted),
+			MapGetByIndexRangeOp(cdtBinName, 0, MapReturnTypeKey),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{
+			6,
+			2,
+			[]interface{}{"k3"},
+			[]interface{}{5, 6},
+			[]interface{}{"k4"},
+		}))
+
+		rec, err = client.Operate(wpolicy, key,
+			MapPutItemsOp(mpolicy, cdtBinName, map[interface{}]interface{}{"k5": 5, "k6": 6, "k7": 7}),
+			MapRemoveByIndexRangeOp(cdtBinName, 3, MapReturnTypeKey),
+			MapRemoveByRankRangeCountOp(cdtBinName, 0, 1, MapReturnTypeKey),
+			MapRemoveByKeyRelativeIndexRangeCountOp(cdtBinName, "k5", 0, 1, MapReturnTypeKey),
+			MapRemoveByValueRelativeRankRangeOp(cdtBinName, 6, 0, MapReturnTypeKey),
+			MapRemoveByRankRangeOp(cdtBinName, 0, MapReturnTypeCount),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{
+			4,
+			[]interface{}{"k7"},
+			[]interface{}{"k4"},
+			[]interface{}{"k5"},
+			[]interface{}{"k6"},
+			0,
+		}))
+	})
+
+	It("should operate on nested lists and maps", func() {
+		rec, err := client.Operate(wpolicy, key,
+			MapPutOp(mpolicy, cdtBinName, "tokens", []interface{}{1, 2, 3}),
+			ListAppendWithPolicyContextOp(DefaultListPolicy(), cdtBinName, []*CDTContext{CtxMapKey("tokens")}, 4),
+			ListGetOp(cdtBinName, -1, CtxMapKey("tokens")),
+			MapPutOp(mpolicy, cdtBinName, "nested", map[interface{}]interface{}{"a": map[interface{}]interface{}{"b": 1}}),
+			MapIncrementOp(mpolicy, cdtBinName, "b", 10, CtxMapKey("nested"), CtxMapKey("a")),
+			MapGetByKeyOp(cdtBinName, "b", MapReturnTypeValue, CtxMapKey("nested"), CtxMapIndex(0)),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{4, 4, 4, 5, 11, 11}))
+	})
+
+	It("should set the map policy and clear the map", func() {
+		rec, err := client.Operate(wpolicy, key,
+			MapSetPolicyOp(NewMapPolicy(MapOrderKeyValueOrdered, MapWriteFlagsDefault), cdtBinName),
+			MapClearOp(cdtBinName),
+			MapSizeOp(cdtBinName),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{nil, nil, 0}))
+	})
+
+	It("should upsert maps and set the TTL only on creation", func() {
+		upsertKey, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		upolicy := NewWritePolicy(0, 100)
+		rec, created, err := client.MapUpsert(upolicy, upsertKey, cdtBinName, mpolicy,
+			MapIncrementOp(mpolicy, cdtBinName, "count", 1),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(created).To(BeTrue())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{nil, 1}))
+
+		upolicy = NewWritePolicy(0, 10000)
+		rec, created, err = client.MapUpsert(upolicy, upsertKey, cdtBinName, mpolicy,
+			MapIncrementOp(mpolicy, cdtBinName, "count", 1),
+			MapGetByKeyOp(cdtBinName, "count", MapReturnTypeValue),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(created).To(BeFalse())
+		Expect(rec.Bins[cdtBinName]).To(Equal([]interface{}{nil, 2, 2}))
+
+		// the TTL set on creation was kept
+		rec, err = client.GetHeader(nil, upsertKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Expiration).To(BeNumerically("<=", 100))
+	})
+
+})
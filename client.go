@@ -16,16 +16,20 @@ package aerospike
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	. "github.com/aerospike/aerospike-client-go/types"
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
 )
 
 // Client encapsulates an Aerospike cluster.
@@ -43,6 +47,8 @@ type Client struct {
 	DefaultQueryPolicy *QueryPolicy
 	// DefaultAdminPolicy is used for all security commands without a specific policy.
 	DefaultAdminPolicy *AdminPolicy
+
+	writeValidators *writeValidators
 }
 
 //-------------------------------------------------------
@@ -79,6 +85,7 @@ func NewClientWithPolicyAndHost(policy *ClientPolicy, hosts ...*Host) (*Client,
 		DefaultScanPolicy:  NewScanPolicy(),
 		DefaultQueryPolicy: NewQueryPolicy(),
 		DefaultAdminPolicy: NewAdminPolicy(),
+		writeValidators:    newWriteValidators(),
 	}, nil
 
 }
@@ -97,6 +104,36 @@ func (clnt *Client) IsConnected() bool {
 	return clnt.cluster.IsConnected()
 }
 
+// Reconfigure applies the ConnectionQueueSize, ConnectionDrainInterval, User and
+// Password of the policy to the client. Its other fields are ignored; TLS is not
+// supported by this client.
+// The connections opened before are not closed at once, which would make every
+// command open a new connection. Instead, each node replaces one of them every
+// ConnectionDrainInterval as they are used, and the pool keeps as many idle
+// connections as fit in the new size.
+func (clnt *Client) Reconfigure(policy *ClientPolicy) error {
+	if policy == nil {
+		return NewAerospikeError(PARAMETER_ERROR, "policy is nil")
+	}
+	return clnt.cluster.reconfigure(policy)
+}
+
+// Stats returns a snapshot of the statistics of the client: per node connection
+// counts and totals, tend counts, partition map generations and command latencies,
+// along with the client build metadata and the server build and protocol features
+// of each node. The result can be serialized to JSON.
+func (clnt *Client) Stats() *ClusterStats {
+	return clnt.cluster.Stats()
+}
+
+// ResetStats returns a snapshot of the statistics of the cluster and its nodes
+// like Stats, and resets their counters and distributions, so that each
+// snapshot only covers the period since the previous one.
+// Use either ResetStats, or Stats with ClusterStats.Diff, in a reporter.
+func (clnt *Client) ResetStats() *ClusterStats {
+	return clnt.cluster.ResetStats()
+}
+
 // GetNodes returns an array of active server nodes in the cluster.
 func (clnt *Client) GetNodes() []*Node {
 	return clnt.cluster.GetNodes()
@@ -136,24 +173,164 @@ func (clnt *Client) Put(policy *WritePolicy, key *Key, binMap BinMap) error {
 // If the policy is nil, the default relevant policy will be used.
 func (clnt *Client) PutBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 	policy = clnt.getUsableWritePolicy(policy)
-	command := newWriteCommand(clnt.cluster, policy, key, bins, WRITE)
+	if err := clnt.validateWrite(key, binOperations(WRITE, bins)); err != nil {
+		return err
+	}
+	command, err := newWriteCommand(clnt.cluster, policy, key, bins, WRITE)
+	if err != nil {
+		return err
+	}
 	return command.Execute()
 }
 
 // PutObject writes record bin(s) to the server.
 // The policy specifies the transaction timeout, record expiration and how the transaction is
 // handled when the record already exists.
+// If several fields of the object map to the same bin name, an error listing
+// the offending fields is returned and nothing is written.
+// Fields whose types implement AerospikeMarshaler are stored as the value they return.
+// time.Time fields are stored as Unix times and time.Duration fields as integers, both in
+// nanoseconds, or in milliseconds for fields tagged with the millis option, e.g. `as:"ts,millis"`.
+// Embedded structs are stored as nested maps like other struct fields, unless tagged with
+// the flatten option, e.g. `as:",flatten"`, in which case their fields are stored in the
+// bins of the parent; GetObject reads both back, allocating nil embedded pointers as needed.
 // If the policy is nil, the default relevant policy will be used.
 func (clnt *Client) PutObject(policy *WritePolicy, key *Key, obj interface{}) (err error) {
 	policy = clnt.getUsableWritePolicy(policy)
 
-	bins := marshal(obj)
-	command := newWriteCommand(clnt.cluster, policy, key, bins, WRITE)
+	if err := validateObjectBins(reflect.TypeOf(obj)); err != nil {
+		return err
+	}
+
+	bins, err := marshal(obj)
+	if err != nil {
+		return err
+	}
+	if err := clnt.validateWrite(key, binOperations(WRITE, bins)); err != nil {
+		binPool.Put(bins)
+		return err
+	}
+	command, err := newWriteCommand(clnt.cluster, policy, key, bins, WRITE)
+	if err != nil {
+		binPool.Put(bins)
+		return err
+	}
 	res := command.Execute()
 	binPool.Put(bins)
 	return res
 }
 
+//-------------------------------------------------------
+// Conditional Write Operations
+//-------------------------------------------------------
+
+// PutIfAbsent creates the record with the bins only if it does not exist yet.
+// It returns false without an error if the record already exists.
+// The RecordExistsAction and GenerationPolicy of the policy are ignored.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) PutIfAbsent(policy *WritePolicy, key *Key, bins ...*Bin) (bool, error) {
+	return clnt.putConditionally(policy, key, CREATE_ONLY, NONE, 0, KEY_EXISTS_ERROR, bins)
+}
+
+// ReplaceOnly replaces all the bins of the record with the bins only if the record exists.
+// Existing bins not referenced by the bins are deleted.
+// It returns false without an error if the record does not exist.
+// The RecordExistsAction and GenerationPolicy of the policy are ignored.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ReplaceOnly(policy *WritePolicy, key *Key, bins ...*Bin) (bool, error) {
+	return clnt.putConditionally(policy, key, REPLACE_ONLY, NONE, 0, KEY_NOT_FOUND_ERROR, bins)
+}
+
+// ReplaceIfGeneration replaces all the bins of the record with the bins only if the
+// record exists and its generation is still the one read before, e.g. rec.Generation.
+// It returns false without an error if the record was modified in between;
+// the record should then be read again and the change retried.
+// An error with KEY_NOT_FOUND_ERROR result code is returned if the record does not exist.
+// The RecordExistsAction, GenerationPolicy and Generation of the policy are ignored.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ReplaceIfGeneration(policy *WritePolicy, key *Key, generation int, bins ...*Bin) (bool, error) {
+	return clnt.putConditionally(policy, key, REPLACE_ONLY, EXPECT_GEN_EQUAL, int32(generation), GENERATION_ERROR, bins)
+}
+
+// putConditionally writes the bins with the record exists action and generation
+// policy overridden on a copy of the policy. The tolerated result code is reported
+// as a write that was not applied instead of an error.
+func (clnt *Client) putConditionally(policy *WritePolicy, key *Key, action RecordExistsAction, genPolicy GenerationPolicy, generation int32, tolerated ResultCode, bins []*Bin) (bool, error) {
+	policy = clnt.getUsableWritePolicy(policy)
+
+	// do not modify the caller's policy
+	wp := *policy
+	wp.RecordExistsAction = action
+	wp.GenerationPolicy = genPolicy
+	wp.Generation = generation
+
+	err := clnt.PutBins(&wp, key, bins...)
+	if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == tolerated {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// number of attempts of Update if none is specified
+const _DEFAULT_UPDATE_ATTEMPTS = 5
+
+// Update applies fn to the record with optimistic locking. The record is read
+// from its master and passed to fn, nil if it does not exist, and the bins
+// returned by fn are written only if the record was not modified in between,
+// or created only if it still does not exist. On a conflict, the record is
+// read again and fn is invoked again, up to maxAttempts times in total
+// (5 if not positive); the GENERATION_ERROR or KEY_EXISTS_ERROR of the last
+// attempt is returned when they are exhausted.
+// If fn returns an error, Update returns it without writing. If fn returns
+// no bins, nothing is written.
+// The GenerationPolicy and Generation of the policy are ignored, and so is
+// its RecordExistsAction when the record is created.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) Update(policy *WritePolicy, key *Key, maxAttempts int, fn func(rec *Record) (BinMap, error)) error {
+	policy = clnt.getUsableWritePolicy(policy)
+	if maxAttempts <= 0 {
+		maxAttempts = _DEFAULT_UPDATE_ATTEMPTS
+	}
+
+	// do not modify the caller's policy
+	rp := policy.BasePolicy
+	rp.ReplicaPolicy = MASTER
+	rp.KeyNotFoundAsError = false
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var rec *Record
+		if rec, err = clnt.Get(&rp, key); err != nil {
+			return err
+		}
+
+		var bins BinMap
+		if bins, err = fn(rec); err != nil {
+			return err
+		}
+		if len(bins) == 0 {
+			return nil
+		}
+
+		wp := *policy
+		if rec == nil {
+			wp.RecordExistsAction = CREATE_ONLY
+			wp.GenerationPolicy = NONE
+		} else {
+			wp.GenerationPolicy = EXPECT_GEN_EQUAL
+			wp.Generation = int32(rec.Generation)
+		}
+
+		err = clnt.Put(&wp, key, bins)
+		if ae, ok := err.(AerospikeError); ok && (ae.ResultCode() == GENERATION_ERROR || ae.ResultCode() == KEY_EXISTS_ERROR) {
+			// modified concurrently; read the record again
+			continue
+		}
+		return err
+	}
+	return err
+}
+
 //-------------------------------------------------------
 // Operations string
 //-------------------------------------------------------
@@ -174,7 +351,13 @@ func (clnt *Client) Append(policy *WritePolicy, key *Key, binMap BinMap) error {
 // AppendBins works the same as Append, but avoids BinMap allocation and iteration.
 func (clnt *Client) AppendBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 	policy = clnt.getUsableWritePolicy(policy)
-	command := newWriteCommand(clnt.cluster, policy, key, bins, APPEND)
+	if err := clnt.validateWrite(key, binOperations(APPEND, bins)); err != nil {
+		return err
+	}
+	command, err := newWriteCommand(clnt.cluster, policy, key, bins, APPEND)
+	if err != nil {
+		return err
+	}
 	return command.Execute()
 }
 
@@ -193,7 +376,13 @@ func (clnt *Client) Prepend(policy *WritePolicy, key *Key, binMap BinMap) error
 // PrependBins works the same as Prepend, but avoids BinMap allocation and iteration.
 func (clnt *Client) PrependBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 	policy = clnt.getUsableWritePolicy(policy)
-	command := newWriteCommand(clnt.cluster, policy, key, bins, PREPEND)
+	if err := clnt.validateWrite(key, binOperations(PREPEND, bins)); err != nil {
+		return err
+	}
+	command, err := newWriteCommand(clnt.cluster, policy, key, bins, PREPEND)
+	if err != nil {
+		return err
+	}
 	return command.Execute()
 }
 
@@ -217,7 +406,13 @@ func (clnt *Client) Add(policy *WritePolicy, key *Key, binMap BinMap) error {
 // AddBins works the same as Add, but avoids BinMap allocation and iteration.
 func (clnt *Client) AddBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 	policy = clnt.getUsableWritePolicy(policy)
-	command := newWriteCommand(clnt.cluster, policy, key, bins, ADD)
+	if err := clnt.validateWrite(key, binOperations(ADD, bins)); err != nil {
+		return err
+	}
+	command, err := newWriteCommand(clnt.cluster, policy, key, bins, ADD)
+	if err != nil {
+		return err
+	}
 	return command.Execute()
 }
 
@@ -249,6 +444,53 @@ func (clnt *Client) Touch(policy *WritePolicy, key *Key) error {
 	return command.Execute()
 }
 
+// TouchKeys updates the metadata of multiple records with a different
+// expiration for each key, without rewriting their bins.
+// The expirations are in positional order with the keys, and override the policy's expiration.
+// This is not a batch command: the server protocol has no batch writes, so each
+// record is touched by its own Touch command. Keys are grouped by node, and the
+// records of each namespace on a node are touched one by one, concurrently with
+// the other nodes and namespaces.
+// The returned error array is in positional order with the original key array;
+// a nil entry means the record was touched successfully.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) TouchKeys(policy *WritePolicy, keys []*Key, expirations []int32) ([]error, error) {
+	policy = clnt.getUsableWritePolicy(policy)
+
+	if len(keys) != len(expirations) {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "TouchKeys requires an expiration for each key.")
+	}
+
+	batchNodes, err := newBatchNodeList(clnt.cluster, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// same array can be used without synchronization;
+	// each goroutine only sets the errors of its own keys
+	errs := make([]error, len(keys))
+
+	var wg sync.WaitGroup
+	for _, batchNode := range batchNodes {
+		for _, bns := range batchNode.BatchNamespaces {
+			wg.Add(1)
+			go func(bns *batchNamespace) {
+				defer wg.Done()
+				for _, offset := range bns.offsets[:bns.offsetSize] {
+					// copy policies to set the expiration per key
+					keyPolicy := *policy
+					keyPolicy.Expiration = expirations[offset]
+					command := newTouchCommand(clnt.cluster, &keyPolicy, keys[offset])
+					errs[offset] = command.Execute()
+				}
+			}(bns)
+		}
+	}
+	wg.Wait()
+
+	return errs, nil
+}
+
 //-------------------------------------------------------
 // Existence-Check Operations
 //-------------------------------------------------------
@@ -283,12 +525,39 @@ func (clnt *Client) BatchExists(policy *BasePolicy, keys []*Key) ([]bool, error)
 	return existsArray, nil
 }
 
+// BatchExistsWithErrors determines if multiple record keys exist in one batch request
+// like BatchExists, but reports the errors per key instead of failing the whole batch.
+// Both returned arrays are in positional order with the original key array.
+// A missing record is reported as false with a nil error; if a record could not be
+// checked, e.g. because its node failed, its entry is false and its error is set.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) BatchExistsWithErrors(policy *BasePolicy, keys []*Key) ([]bool, []error, error) {
+	policy = clnt.getUsablePolicy(policy)
+
+	// same arrays can be used without synchronization;
+	// each command only sets the entries of its own keys
+	existsArray := make([]bool, len(keys))
+	errs := make([]error, len(keys))
+
+	if err := clnt.batchExecute(keys, func(node *Node, bns *batchNamespace) command {
+		command := newBatchCommandExists(node, bns, policy, keys, existsArray)
+		command.errs = errs
+		return command
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return existsArray, errs, nil
+}
+
 //-------------------------------------------------------
 // Read Record Operations
 //-------------------------------------------------------
 
 // Get reads a record header and bins for specified key.
 // The policy can be used to specify timeouts.
+// If the record does not exist, a nil record is returned, or ErrKeyNotFound
+// if the policy's KeyNotFoundAsError is set.
 // If the policy is nil, the default relevant policy will be used.
 func (clnt *Client) Get(policy *BasePolicy, key *Key, binNames ...string) (*Record, error) {
 	policy = clnt.getUsablePolicy(policy)
@@ -297,10 +566,14 @@ func (clnt *Client) Get(policy *BasePolicy, key *Key, binNames ...string) (*Reco
 	if err := command.Execute(); err != nil {
 		return nil, err
 	}
+	if command.GetRecord() == nil && policy.KeyNotFoundAsError {
+		return nil, ErrKeyNotFound
+	}
 	return command.GetRecord(), nil
 }
 
 // GetObject reads a record for specified key and puts the result into the provided object.
+// Fields whose types implement AerospikeUnmarshaler decode their own bin values.
 // The policy can be used to specify timeouts.
 // If the policy is nil, the default relevant policy will be used.
 func (clnt *Client) GetObject(policy *BasePolicy, key *Key, obj interface{}) error {
@@ -315,9 +588,28 @@ func (clnt *Client) GetObject(policy *BasePolicy, key *Key, obj interface{}) err
 	return nil
 }
 
+// GetObjectWithHeader reads a record into the provided object like GetObject, and
+// returns the record's generation and expiration in a Record without bins.
+// Both are read by the same command, so the generation matches the object's
+// contents and can be used for generation-checked writes.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) GetObjectWithHeader(policy *BasePolicy, key *Key, obj interface{}) (*Record, error) {
+	policy = clnt.getUsablePolicy(policy)
+
+	binNames := objectMappings.getFields(reflect.ValueOf(obj).Type().Elem().Name())
+	command := newReadCommand(clnt.cluster, policy, key, binNames)
+	command.object = obj
+	if err := command.Execute(); err != nil {
+		return nil, err
+	}
+	return command.GetRecord(), nil
+}
+
 // GetHeader reads a record generation and expiration only for specified key.
 // Bins are not read.
 // The policy can be used to specify timeouts.
+// If the record does not exist, a nil record is returned, or ErrKeyNotFound
+// if the policy's KeyNotFoundAsError is set.
 // If the policy is nil, the default relevant policy will be used.
 func (clnt *Client) GetHeader(policy *BasePolicy, key *Key) (*Record, error) {
 	policy = clnt.getUsablePolicy(policy)
@@ -326,6 +618,9 @@ func (clnt *Client) GetHeader(policy *BasePolicy, key *Key) (*Record, error) {
 	if err := command.Execute(); err != nil {
 		return nil, err
 	}
+	if command.GetRecord() == nil && policy.KeyNotFoundAsError {
+		return nil, ErrKeyNotFound
+	}
 	return command.GetRecord(), nil
 }
 
@@ -360,6 +655,31 @@ func (clnt *Client) BatchGet(policy *BasePolicy, keys []*Key, binNames ...string
 	return records, nil
 }
 
+// BatchGetComplex reads multiple records in one batch request, each with the bins
+// selected by its BatchRead, or only its header. The read records are set in the
+// Record field of the batch reads; it is nil if the record does not exist.
+// The policy can be used to specify timeouts.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) BatchGetComplex(policy *BasePolicy, records []*BatchRead) error {
+	policy = clnt.getUsablePolicy(policy)
+
+	keys := make([]*Key, len(records))
+	for i, br := range records {
+		keys[i] = br.Key
+		br.Record = nil
+	}
+
+	// same arrays can be used without synchronization;
+	// each command only sets the entries of its own keys
+	results := make([]*Record, len(keys))
+
+	return clnt.batchExecute(keys, func(node *Node, bns *batchNamespace) command {
+		command := newBatchCommandGet(node, bns, policy, keys, nil, results, _INFO1_READ)
+		command.batchReads = records
+		return command
+	})
+}
+
 // BatchGetHeader reads multiple record header data for specified keys in one batch request.
 // The returned records are in positional order with the original key array order.
 // If a key is not found, the positional record will be nil.
@@ -382,6 +702,84 @@ func (clnt *Client) BatchGetHeader(policy *BasePolicy, keys []*Key) ([]*Record,
 	return records, nil
 }
 
+// BatchGetHeaderWithErrors reads multiple record headers like BatchGetHeader,
+// but reports the errors per key instead of failing the whole batch.
+// Both returned arrays are in positional order with the original key array.
+// A missing record is reported as a nil record with a nil error; if a record
+// could not be read, e.g. because its node failed, its record is nil and its error is set.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) BatchGetHeaderWithErrors(policy *BasePolicy, keys []*Key) ([]*Record, []error, error) {
+	policy = clnt.getUsablePolicy(policy)
+
+	// same arrays can be used without synchronization;
+	// each command only sets the entries of its own keys
+	records := make([]*Record, len(keys))
+	errs := make([]error, len(keys))
+
+	err := clnt.batchExecute(keys, func(node *Node, bns *batchNamespace) command {
+		command := newBatchCommandGet(node, bns, policy, keys, nil, records, _INFO1_READ|_INFO1_NOBINDATA)
+		command.errs = errs
+		return command
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return records, errs, nil
+}
+
+// BatchGetFunc reads multiple records for specified keys in one batch request
+// like BatchGet, but invokes fn for each key as soon as its record is received
+// from its node, instead of returning all the records at the end.
+// index is the position of the key in keys, and record is nil if the key was not found.
+// fn is not invoked concurrently. The batch is aborted on the first error returned
+// by fn, and that error is returned; node errors are returned once the other nodes
+// are done.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) BatchGetFunc(policy *BasePolicy, keys []*Key, fn func(index int, record *Record) error, binNames ...string) error {
+	binSet := map[string]struct{}{}
+	for idx := range binNames {
+		binSet[binNames[idx]] = struct{}{}
+	}
+
+	return clnt.batchGetFunc(clnt.getUsablePolicy(policy), keys, binSet, _INFO1_READ, fn)
+}
+
+// BatchGetHeaderFunc reads multiple record headers for specified keys in one batch
+// request like BatchGetHeader, but invokes fn for each key as soon as its record
+// is received, like BatchGetFunc.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) BatchGetHeaderFunc(policy *BasePolicy, keys []*Key, fn func(index int, record *Record) error) error {
+	return clnt.batchGetFunc(clnt.getUsablePolicy(policy), keys, nil, _INFO1_READ|_INFO1_NOBINDATA, fn)
+}
+
+func (clnt *Client) batchGetFunc(policy *BasePolicy, keys []*Key, binSet map[string]struct{}, readAttr int, fn func(index int, record *Record) error) error {
+	var mutex sync.Mutex
+	var fnErr error
+
+	callback := func(offset int, record *Record) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		// abort the commands of the other nodes too
+		if fnErr == nil {
+			fnErr = fn(offset, record)
+		}
+		return fnErr
+	}
+
+	err := clnt.batchExecute(keys, func(node *Node, bns *batchNamespace) command {
+		command := newBatchCommandGet(node, bns, policy, keys, binSet, nil, readAttr)
+		command.callback = callback
+		return command
+	})
+
+	if fnErr != nil {
+		return fnErr
+	}
+	return err
+}
+
 //-------------------------------------------------------
 // Generic Database Operations
 //-------------------------------------------------------
@@ -395,13 +793,125 @@ func (clnt *Client) BatchGetHeader(policy *BasePolicy, keys []*Key) ([]*Record,
 // If the policy is nil, the default relevant policy will be used.
 func (clnt *Client) Operate(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, error) {
 	policy = clnt.getUsableWritePolicy(policy)
-	command := newOperateCommand(clnt.cluster, policy, key, operations)
+	if err := clnt.validateWrite(key, func() []*Operation { return operations }); err != nil {
+		return nil, err
+	}
+	command, err := newOperateCommand(clnt.cluster, policy, key, operations)
+	if err != nil {
+		return nil, err
+	}
 	if err := command.Execute(); err != nil {
 		return nil, err
 	}
 	return command.GetRecord(), nil
 }
 
+// OperateWithResults performs multiple read/write operations on a single key
+// like Operate, and additionally returns the result of each operation in the
+// order of the operations. This makes it possible to inspect the outcome of each
+// sub-operation, e.g. the list size returned by a list append with
+// ListWriteFlagsAddUnique|ListWriteFlagsNoFail will not change if the value was skipped.
+//
+// Operations for which the server does not return a value, like bin writes,
+// have a nil Value. If any operation fails without a NO_FAIL write flag,
+// the whole command fails and an error with the server result code is returned.
+//
+// The results have no per-operation result code or error: the wire protocol
+// returns a single result code for the whole command, and only a value for each
+// operation. An operation skipped because of a NO_FAIL flag is not reported
+// as such by the server; tell it from its value, e.g. an unchanged list size.
+//
+// Unlike the bins of the record, where the results of several operations on the
+// same bin are collected in a list, the results keep their operations, so they
+// can not be confused with a single list value; use OpResults.ForBin to
+// retrieve all the results of a bin.
+//
+// GetOp and GetHeaderOp are not supported, since their results can not be
+// matched to a single operation.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) OperateWithResults(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, OpResults, error) {
+	policy = clnt.getUsableWritePolicy(policy)
+	for _, op := range operations {
+		if op.OpType == READ && (op.headerOnly || op.BinName == "") {
+			return nil, nil, NewAerospikeError(PARAMETER_ERROR, "GetOp and GetHeaderOp are not supported in OperateWithResults.")
+		}
+	}
+	if err := clnt.validateWrite(key, func() []*Operation { return operations }); err != nil {
+		return nil, nil, err
+	}
+
+	command, err := newOperateCommand(clnt.cluster, policy, key, operations)
+	if err != nil {
+		return nil, nil, err
+	}
+	command.respondAllOps = true
+	if err := command.Execute(); err != nil {
+		return nil, nil, err
+	}
+	return command.GetRecord(), command.operationResults(operations), nil
+}
+
+// OperateIf performs the operations on the key only if the condition expression
+// evaluates to true for the record, e.g. to read a bin only when a flag bin exists:
+//
+//	client.OperateIf(nil, key, ExpBinExists("flag"), GetOpForBin("feature"))
+//
+// The condition is combined with the policy's FilterExpression, if any.
+// If the condition is false, no operation is applied and a nil record is returned
+// without an error. Requires server version 5.2+.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) OperateIf(policy *WritePolicy, key *Key, condition *Expression, operations ...*Operation) (*Record, error) {
+	policy = clnt.getUsableWritePolicy(policy)
+
+	// do not modify the caller's policy
+	wp := *policy
+	if wp.FilterExpression != nil {
+		wp.FilterExpression = ExpAnd(wp.FilterExpression, condition)
+	} else {
+		wp.FilterExpression = condition
+	}
+
+	rec, err := clnt.Operate(&wp, key, operations...)
+	if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == FILTERED_OUT {
+		return nil, nil
+	}
+	return rec, err
+}
+
+// ApplyWrites applies the writes grouped by the master node of their partition,
+// with up to nodeConcurrency writes in flight per node. The writes of the same key
+// are applied in the order they appear in the slice; once one of them fails, the
+// following writes of that key are not applied and report the same error.
+// The returned errors correspond to the writes, and are nil for the applied ones.
+// An error is returned without applying any write if the writes cannot be routed,
+// e.g. because the cluster is empty.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ApplyWrites(policy *WritePolicy, nodeConcurrency int, writes []*PendingWrite) ([]error, error) {
+	policy = clnt.getUsableWritePolicy(policy)
+
+	writeNodes, err := groupWritesByNode(clnt.cluster, writes)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(writes))
+
+	var wg sync.WaitGroup
+	wg.Add(len(writeNodes))
+	for _, wn := range writeNodes {
+		go func(wn *writeNode) {
+			defer wg.Done()
+			wn.apply(writes, errs, nodeConcurrency, func(write *PendingWrite) error {
+				_, err := clnt.Operate(policy, write.Key, write.Operations...)
+				return err
+			})
+		}(wn)
+	}
+	wg.Wait()
+
+	return errs, nil
+}
+
 //-------------------------------------------------------
 // Scan Operations
 //-------------------------------------------------------
@@ -409,6 +919,11 @@ func (clnt *Client) Operate(policy *WritePolicy, key *Key, operations ...*Operat
 // ScanAll reads all records in specified namespace and set from all nodes.
 // If the policy's concurrentNodes is specified, each server node will be read in
 // parallel. Otherwise, server nodes are read sequentially.
+// If the policy's PartitionFilter is set, only the partitions of the filter
+// are read, and the filter is updated with their progress.
+// Partitions are read from their masters, unless the policy's ReplicaPolicy
+// is MASTER_PROLES or PROLES, in which case all partitions are read as a
+// partition scan from the replicas it selects. Requires server version 4.9+.
 // If the policy is nil, the default relevant policy will be used.
 func (clnt *Client) ScanAll(apolicy *ScanPolicy, namespace string, setName string, binNames ...string) (*Recordset, error) {
 	policy := *clnt.getUsableScanPolicy(apolicy)
@@ -425,25 +940,65 @@ func (clnt *Client) ScanAll(apolicy *ScanPolicy, namespace string, setName strin
 		}
 	}
 
+	if policy.ReplicaPolicy != MASTER && policy.PartitionFilter == nil {
+		// only partition scans can be sent to the replicas; do not modify the caller's policy
+		mp := *policy.MultiPolicy
+		mp.PartitionFilter = NewPartitionFilterAll()
+		policy.MultiPolicy = &mp
+	}
+
+	// partitions assigned to each node, if this is a partition scan
+	var partitions []*nodePartitions
+	if policy.PartitionFilter != nil {
+		var err error
+		if partitions, err = policy.PartitionFilter.assign(clnt.cluster, namespace, policy.ReplicaPolicy, policy.MaxRecords); err != nil {
+			return nil, err
+		}
+
+		nodes = make([]*Node, len(partitions))
+		for i, np := range partitions {
+			nodes[i] = np.node
+		}
+	}
+
 	// result recordset
-	res := newRecordset(policy.RecordQueueSize, len(nodes))
+	res, err := clnt.newLimitedRecordset(policy.RecordQueueSize, len(nodes))
+	if err != nil {
+		return nil, err
+	}
+	if policy.CollectPartitionStats {
+		res.stats = &partitionStats{}
+	}
+	if len(nodes) == 0 {
+		// all partitions are already done
+		res.Close()
+		return res, nil
+	}
+
+	// returns the partitions of the node at index i, if any
+	nodePartitionsAt := func(i int) *nodePartitions {
+		if partitions == nil {
+			return nil
+		}
+		return partitions[i]
+	}
 
 	// the whole call should be wrapped in a goroutine
 	if policy.ConcurrentNodes {
-		for _, node := range nodes {
-			go func(node *Node) {
-				if err := clnt.scanNode(&policy, node, res, namespace, setName, binNames...); err != nil {
+		for i, node := range nodes {
+			go func(node *Node, partitions *nodePartitions) {
+				if err := clnt.scanNode(&policy, node, partitions, res, namespace, setName, binNames...); err != nil {
 					if _, ok := <-res.Errors; ok {
 						res.Errors <- err
 					}
 				}
-			}(node)
+			}(node, nodePartitionsAt(i))
 		}
 	} else {
 		// scan nodes one by one
 		go func() {
-			for _, node := range nodes {
-				if err := clnt.scanNode(&policy, node, res, namespace, setName, binNames...); err != nil {
+			for i, node := range nodes {
+				if err := clnt.scanNode(&policy, node, nodePartitionsAt(i), res, namespace, setName, binNames...); err != nil {
 					if _, ok := <-res.Errors; ok {
 						res.Errors <- err
 					}
@@ -456,21 +1011,154 @@ func (clnt *Client) ScanAll(apolicy *ScanPolicy, namespace string, setName strin
 	return res, nil
 }
 
+// ScanAllFunc reads all records in specified namespace and set from all nodes,
+// like ScanAll, and invokes fn for each of them from policy.CallbackWorkers
+// goroutines. The scan is aborted on the first error returned by fn or by a
+// node, and that error is returned. ScanAllFunc returns when all records have
+// been processed or the scan was aborted.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ScanAllFunc(apolicy *ScanPolicy, namespace string, setName string, fn func(*Record) error, binNames ...string) error {
+	policy := clnt.getUsableScanPolicy(apolicy)
+
+	workers := policy.CallbackWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	res, err := clnt.ScanAll(policy, namespace, setName, binNames...)
+	if err != nil {
+		return err
+	}
+
+	var errOnce sync.Once
+	var firstErr error
+	aborted := NewAtomicBool(false)
+	abort := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			aborted.Set(true)
+			// closing waits for the node goroutines; do not block the workers
+			// which keep draining the results meanwhile
+			go res.Close()
+		})
+	}
+
+	results := res.Results()
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for r := range results {
+				if r.Err != nil {
+					abort(r.Err)
+					continue
+				}
+				if aborted.Get() {
+					// aborted; discard the rest
+					continue
+				}
+				if err := fn(r.Record); err != nil {
+					abort(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// ScanAllObjects reads all records in specified namespace and set from all nodes,
+// like ScanAll, and sends each of them on objChan, decoded into a new struct with
+// the same mapping as GetObject. objChan must be a channel of pointers to structs,
+// e.g. make(chan *User, 100); it is closed once the scan is finished, and the scan
+// is aborted on the first error. Check ObjectRecordset.Err afterwards.
+// To process the records in a callback instead, use ScanAllFunc with UnmarshalBins.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ScanAllObjects(apolicy *ScanPolicy, objChan interface{}, namespace string, setName string, binNames ...string) (*ObjectRecordset, error) {
+	rv, err := objectChanOf(objChan)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := clnt.ScanAll(apolicy, namespace, setName, binNames...)
+	if err != nil {
+		return nil, err
+	}
+	return newObjectRecordset(res, rv), nil
+}
+
+// ScanAllContext reads all records in specified namespace and set from all nodes,
+// like ScanAll. When ctx is done, the scan is cancelled: the node goroutines are
+// stopped, their connections are closed, including those still being dialed or
+// authenticated, and the records left in the Recordset are discarded.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ScanAllContext(ctx context.Context, apolicy *ScanPolicy, namespace string, setName string, binNames ...string) (*Recordset, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	res, err := clnt.ScanAll(apolicy, namespace, setName, binNames...)
+	if err != nil {
+		return nil, err
+	}
+
+	res.closeOnDone(ctx)
+	return res, nil
+}
+
+// IncrementalScan reads the records in specified namespace and set that were
+// last updated at or after since, filtering them on the server with a
+// last-update-time expression. The expression is combined with the policy's
+// FilterExpression, if any.
+// Partitions are read from the replicas selected by the policy's ReplicaPolicy,
+// their masters by default. To track or resume the progress
+// of the scan, set the policy's PartitionFilter; otherwise all partitions are read.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) IncrementalScan(apolicy *ScanPolicy, namespace string, setName string, since time.Time, binNames ...string) (*Recordset, error) {
+	// do not modify the caller's policy
+	policy := *clnt.getUsableScanPolicy(apolicy)
+	mp := *policy.MultiPolicy
+	bp := *mp.BasePolicy
+	mp.BasePolicy = &bp
+	policy.MultiPolicy = &mp
+
+	exp := ExpGreaterEq(ExpLastUpdate(), ExpIntVal(since.UnixNano()))
+	if bp.FilterExpression != nil {
+		exp = ExpAnd(bp.FilterExpression, exp)
+	}
+	bp.FilterExpression = exp
+
+	if mp.PartitionFilter == nil {
+		mp.PartitionFilter = NewPartitionFilterAll()
+	}
+
+	return clnt.ScanAll(&policy, namespace, setName, binNames...)
+}
+
 // ScanNode reads all records in specified namespace and set for one node only.
 // If the policy is nil, the default relevant policy will be used.
 func (clnt *Client) ScanNode(apolicy *ScanPolicy, node *Node, namespace string, setName string, binNames ...string) (*Recordset, error) {
 	policy := *clnt.getUsableScanPolicy(apolicy)
 
 	// results channel must be async for performance
-	res := newRecordset(policy.RecordQueueSize, 1)
+	res, err := clnt.newLimitedRecordset(policy.RecordQueueSize, 1)
+	if err != nil {
+		return nil, err
+	}
+	if policy.CollectPartitionStats {
+		res.stats = &partitionStats{}
+	}
 
-	go clnt.scanNode(&policy, node, res, namespace, setName, binNames...)
+	go clnt.scanNode(&policy, node, nil, res, namespace, setName, binNames...)
 	return res, nil
 }
 
 // ScanNode reads all records in specified namespace and set for one node only.
 // If the policy is nil, the default relevant policy will be used.
-func (clnt *Client) scanNode(policy *ScanPolicy, node *Node, recordset *Recordset, namespace string, setName string, binNames ...string) error {
+func (clnt *Client) scanNode(policy *ScanPolicy, node *Node, partitions *nodePartitions, recordset *Recordset, namespace string, setName string, binNames ...string) error {
 	if policy.WaitUntilMigrationsAreOver {
 		// wait until migrations on node are finished
 		if err := node.WaitUntillMigrationIsFinished(policy.Timeout); err != nil {
@@ -480,6 +1168,7 @@ func (clnt *Client) scanNode(policy *ScanPolicy, node *Node, recordset *Recordse
 	}
 
 	command := newScanCommand(node, policy, namespace, setName, binNames, recordset)
+	command.partitions = partitions
 	return command.Execute()
 }
 
@@ -553,6 +1242,24 @@ func (clnt *Client) RegisterUDFFromFile(policy *WritePolicy, clientPath string,
 	return clnt.RegisterUDF(policy, udfBody, serverPath, language)
 }
 
+// RegisterUDFFromReader reads the package containing user defined functions from
+// reader and registers it with the server. This allows registering Lua sources
+// which are not on disk, e.g. files of an embedded file system.
+// This asynchronous server call will return before command is complete.
+// The user can optionally wait for command completion by using the returned
+// RegisterTask instance.
+//
+// This method is only supported by Aerospike 3 servers.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) RegisterUDFFromReader(policy *WritePolicy, reader io.Reader, serverPath string, language Language) (*RegisterTask, error) {
+	udfBody, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return clnt.RegisterUDF(policy, udfBody, serverPath, language)
+}
+
 // RegisterUDF registers a package containing user defined functions with server.
 // This asynchronous server call will return before command is complete.
 // The user can optionally wait for command completion by using the returned
@@ -661,6 +1368,8 @@ func (clnt *Client) RemoveUDF(policy *WritePolicy, udfName string) (*RemoveTask,
 		}
 	}
 
+	node.PutConnection(conn)
+
 	if response == "ok" {
 		return NewRemoveTask(clnt.cluster, udfName), nil
 	}
@@ -820,6 +1529,75 @@ func (clnt *Client) ExecuteUDF(policy *QueryPolicy,
 	return NewExecuteTask(clnt.cluster, statement), mergeErrors(errs)
 }
 
+// QueryExecute applies the operations on the server to every record selected
+// by the statement and the policy's FilterExpression, as a background job on all nodes.
+// The returned task can be used to poll for the job's completion.
+//
+// This method is only supported by Aerospike 4.7+ servers.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) QueryExecute(policy *QueryPolicy,
+	statement *Statement,
+	ops ...*Operation,
+) (*ExecuteTask, error) {
+	policy = clnt.getUsableQueryPolicy(policy)
+
+	if len(ops) == 0 {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "QueryExecute requires at least one operation.")
+	}
+
+	nodes := clnt.cluster.GetNodes()
+	if len(nodes) == 0 {
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "QueryExecute failed because cluster is empty.")
+	}
+
+	// wait until all migrations are finished
+	if err := clnt.cluster.WaitUntillMigrationIsFinished(policy.Timeout); err != nil {
+		return nil, err
+	}
+
+	// do not modify the caller's statement; a statement with operations
+	// is sent as a background write
+	stmt := *statement
+	stmt.operations = ops
+	stmt.returnData = false
+	stmt.setTaskId()
+
+	errs := []error{}
+	for i := range nodes {
+		command := newServerCommand(nodes[i], policy, &stmt)
+		if err := command.Execute(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return NewExecuteTask(clnt.cluster, &stmt), mergeErrors(errs)
+}
+
+// QueryDelete deletes every record selected by the statement and the filter
+// expression on the server, as a background job on all nodes.
+// If filterExp is nil, the policy's FilterExpression is used instead; if both
+// are nil, all records selected by the statement are deleted.
+// The returned task can be used to poll for the job's completion.
+//
+// This method is only supported by Aerospike 4.7+ servers.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) QueryDelete(policy *QueryPolicy, statement *Statement, filterExp *Expression) (*ExecuteTask, error) {
+	policy = clnt.getUsableQueryPolicy(policy)
+
+	if filterExp != nil {
+		// do not modify the caller's policy
+		bp := *policy.BasePolicy
+		bp.FilterExpression = filterExp
+		mp := *policy.MultiPolicy
+		mp.BasePolicy = &bp
+		qp := *policy
+		qp.MultiPolicy = &mp
+		policy = &qp
+	}
+
+	return clnt.QueryExecute(policy, statement, DeleteOp())
+}
+
 //--------------------------------------------------------
 // Query functions (Supported by Aerospike 3 servers only)
 //--------------------------------------------------------
@@ -829,7 +1607,15 @@ func (clnt *Client) ExecuteUDF(policy *QueryPolicy,
 // The caller can concurrently pop records off the channel through the
 // Recordset.Records channel.
 //
-// This method is only supported by Aerospike 3 servers.
+// If all nodes of the cluster support partition queries (server 6.0+), or the
+// policy's PartitionFilter is set, queries are partition aware: each node is
+// queried for the partitions it is the master of, and partitions which a node
+// could not complete, e.g. during migrations, are queried again on their new
+// master nodes according to the policy's PartitionRetries.
+// If the policy's PartitionFilter is set, only the partitions of the filter
+// are queried, and the filter is updated with their progress; this requires
+// server version 6.0+. Otherwise, the nodes are queried one by one as a whole.
+//
 // If the policy is nil, the default relevant policy will be used.
 func (clnt *Client) Query(policy *QueryPolicy, statement *Statement) (*Recordset, error) {
 	policy = clnt.getUsableQueryPolicy(policy)
@@ -846,10 +1632,43 @@ func (clnt *Client) Query(policy *QueryPolicy, statement *Statement) (*Recordset
 		}
 	}
 
-	// results channel must be async for performance
-	recSet := newRecordset(policy.RecordQueueSize, len(nodes))
+	if policy.PartitionFilter != nil {
+		return clnt.queryPartitions(policy, statement)
+	}
+
+	if supportsPartitionQuery(nodes) {
+		// query all partitions; do not modify the caller's policy
+		multiPolicy := *policy.MultiPolicy
+		multiPolicy.PartitionFilter = NewPartitionFilterAll()
+
+		queryPolicy := *policy
+		queryPolicy.MultiPolicy = &multiPolicy
+		return clnt.queryPartitions(&queryPolicy, statement)
+	}
+
+	return clnt.queryNodes(policy, statement, nodes)
+}
+
+// supportsPartitionQuery returns true if all the nodes support partition queries.
+func supportsPartitionQuery(nodes []*Node) bool {
+	for _, node := range nodes {
+		if !node.supportsPartitionQuery {
+			return false
+		}
+	}
+	return true
+}
 
+// queryNodes queries the nodes one by one as a whole, for servers which do not
+// support partition queries.
+func (clnt *Client) queryNodes(policy *QueryPolicy, statement *Statement, nodes []*Node) (*Recordset, error) {
 	// results channel must be async for performance
+	recSet, err := clnt.newLimitedRecordset(policy.RecordQueueSize, len(nodes))
+	if err != nil {
+		return nil, err
+	}
+
+	// the whole call sits in a separate goroutine, so it won't block
 	for _, node := range nodes {
 		// copy policies to avoid race conditions
 		newPolicy := *policy
@@ -860,6 +1679,144 @@ func (clnt *Client) Query(policy *QueryPolicy, statement *Statement) (*Recordset
 	return recSet, nil
 }
 
+// queryPartitions queries the partitions of the policy's partition filter on their master nodes.
+func (clnt *Client) queryPartitions(policy *QueryPolicy, statement *Statement) (*Recordset, error) {
+	partitions, err := policy.PartitionFilter.assign(clnt.cluster, statement.Namespace, MASTER, policy.MaxRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	// results channel must be async for performance
+	recSet, err := clnt.newLimitedRecordset(policy.RecordQueueSize, len(partitions))
+	if err != nil {
+		return nil, err
+	}
+	if len(partitions) == 0 {
+		// all partitions are already done
+		recSet.Close()
+		return recSet, nil
+	}
+
+	for _, np := range partitions {
+		// copy policies to avoid race conditions
+		newPolicy := *policy
+		go clnt.queryNodePartitions(&newPolicy, statement, np, recSet)
+	}
+
+	return recSet, nil
+}
+
+// isRetryableQueryError returns true for the network and connection errors of a
+// partition query. These errors are not reported by the command itself.
+func isRetryableQueryError(err error) bool {
+	ae, ok := err.(AerospikeError)
+	if !ok {
+		return true
+	}
+
+	switch ae.ResultCode() {
+	case TIMEOUT, NO_AVAILABLE_CONNECTIONS_TO_NODE, SERVER_NOT_AVAILABLE, INVALID_NODE_ERROR:
+		return true
+	default:
+		return false
+	}
+}
+
+// queryNodePartitions queries the partitions assigned to a node. The partitions the
+// nodes could not complete are assigned to their current master nodes and queried
+// again, up to the policy's PartitionRetries times. A node which fails with an error
+// other than a network error reports it, and the other nodes are still queried.
+func (clnt *Client) queryNodePartitions(policy *QueryPolicy, statement *Statement, np *nodePartitions, recSet *Recordset) {
+	defer recSet.signalEnd()
+
+	pending := []*nodePartitions{np}
+	var unfinished []*PartitionStatus
+	var lastErr error
+	for retries := 0; ; retries++ {
+		if retries > 0 {
+			select {
+			case <-time.After(policy.SleepBetweenRetries):
+			case <-recSet.cancelled:
+				return
+			}
+
+			// if the partitions can not be assigned, they are assigned again on the next retry
+			reassigned, err := np.filter.assignPartitions(clnt.cluster, statement.Namespace, MASTER, unfinished, 0)
+			if err != nil {
+				lastErr = err
+				pending = nil
+			} else {
+				pending = reassigned
+				unfinished = nil
+			}
+		}
+
+		for _, np := range pending {
+			np.sendBVal = len(statement.Filters) > 0
+			command := newQueryRecordCommand(np.node, policy, statement, recSet)
+			command.partitions = np
+			if err := command.execute(command); err != nil {
+				if !recSet.IsActive() {
+					return
+				}
+				if !isRetryableQueryError(err) {
+					// the error was reported by the command
+					continue
+				}
+				lastErr = newNodeError(np.node, err)
+			}
+			unfinished = append(unfinished, np.unfinished()...)
+		}
+
+		if len(unfinished) == 0 || policy.MaxRecords > 0 || retries >= policy.PartitionRetries {
+			// errors are only reported once the retries are exhausted
+			if lastErr != nil && len(unfinished) > 0 {
+				recSet.Errors <- lastErr
+			}
+			return
+		}
+	}
+}
+
+// QueryObjects executes a query like Query, and sends each record on objChan,
+// decoded into a new struct with the same mapping as GetObject. objChan must be
+// a channel of pointers to structs; it is closed once the query is finished, and
+// the query is aborted on the first error. Check ObjectRecordset.Err afterwards.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) QueryObjects(policy *QueryPolicy, statement *Statement, objChan interface{}) (*ObjectRecordset, error) {
+	rv, err := objectChanOf(objChan)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := clnt.Query(policy, statement)
+	if err != nil {
+		return nil, err
+	}
+	return newObjectRecordset(res, rv), nil
+}
+
+// QueryContext executes a query and returns a Recordset, like Query.
+// When ctx is done, the query is cancelled: the node goroutines are stopped,
+// their connections are closed, including those still being dialed or authenticated,
+// and the records left in the Recordset are discarded.
+//
+// This method is only supported by Aerospike 3 servers.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) QueryContext(ctx context.Context, policy *QueryPolicy, statement *Statement) (*Recordset, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	res, err := clnt.Query(policy, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	res.closeOnDone(ctx)
+	return res, nil
+}
+
 // QueryNode executes a query on a specific node and returns a recordset.
 // The caller can concurrently pop records off the channel through the
 // record channel.
@@ -877,7 +1834,10 @@ func (clnt *Client) QueryNode(policy *QueryPolicy, node *Node, statement *Statem
 	}
 
 	// results channel must be async for performance
-	recSet := newRecordset(policy.RecordQueueSize, 1)
+	recSet, err := clnt.newLimitedRecordset(policy.RecordQueueSize, 1)
+	if err != nil {
+		return nil, err
+	}
 
 	// copy policies to avoid race conditions
 	newPolicy := *policy
@@ -920,6 +1880,28 @@ func (clnt *Client) CreateIndex(
 	indexName string,
 	binName string,
 	indexType IndexType,
+) (*IndexTask, error) {
+	return clnt.CreateComplexIndex(policy, namespace, setName, indexName, binName, indexType, ICT_DEFAULT)
+}
+
+// CreateComplexIndex creates a secondary index, with the ability to put indexes
+// on bin containing complex data types, e.g: Maps and Lists.
+// If a context path is specified, the index is created on the nested list or map
+// identified by the path. Indexes on context paths require server version 6.1+.
+// This asynchronous server call will return before the command is complete.
+// The user can optionally wait for command completion by using the returned
+// IndexTask instance.
+// This method is only supported by Aerospike 3 servers.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) CreateComplexIndex(
+	policy *WritePolicy,
+	namespace string,
+	setName string,
+	indexName string,
+	binName string,
+	indexType IndexType,
+	indexCollectionType IndexCollectionType,
+	ctx ...*CDTContext,
 ) (*IndexTask, error) {
 	policy = clnt.getUsableWritePolicy(policy)
 
@@ -934,6 +1916,21 @@ func (clnt *Client) CreateIndex(
 
 	_, err = strCmd.WriteString(";indexname=")
 	_, err = strCmd.WriteString(indexName)
+
+	if len(ctx) > 0 {
+		packedCtx, err := packCDTContextBytes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		_, err = strCmd.WriteString(";context=")
+		_, err = strCmd.WriteString(base64.StdEncoding.EncodeToString(packedCtx))
+	}
+
+	if indexCollectionType != ICT_DEFAULT {
+		_, err = strCmd.WriteString(";indextype=")
+		_, err = strCmd.WriteString(ictToString(indexCollectionType))
+	}
+
 	_, err = strCmd.WriteString(";numbins=1")
 	_, err = strCmd.WriteString(";indexdata=")
 	_, err = strCmd.WriteString(binName)
@@ -1009,6 +2006,55 @@ func (clnt *Client) DropIndex(
 	return NewAerospikeError(INDEX_GENERIC, "Drop index failed: "+response)
 }
 
+// Truncate removes records in specified namespace/set efficiently. This method is many orders
+// of magnitude faster than deleting records one at a time.
+// If setName is empty, all records in the namespace are removed.
+// If beforeLastUpdate is not nil, only the records whose last update time is before it
+// are removed; records written afterwards survive. It must not be in the future.
+// If beforeLastUpdate is nil, all records existing at the time the server receives the
+// command are removed.
+// The command is sent to one node, which distributes it to the rest of the cluster.
+//
+// This method is only supported by Aerospike 3.12+ servers.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) Truncate(policy *WritePolicy, namespace string, setName string, beforeLastUpdate *time.Time) error {
+	policy = clnt.getUsableWritePolicy(policy)
+
+	if beforeLastUpdate != nil && beforeLastUpdate.After(time.Now()) {
+		return NewAerospikeError(PARAMETER_ERROR, "Truncate's beforeLastUpdate must not be in the future")
+	}
+
+	var strCmd bytes.Buffer
+	_, err := strCmd.WriteString("truncate:namespace=")
+	_, err = strCmd.WriteString(namespace)
+
+	if len(setName) > 0 {
+		_, err = strCmd.WriteString(";set=")
+		_, err = strCmd.WriteString(setName)
+	}
+
+	if beforeLastUpdate != nil {
+		_, err = strCmd.WriteString(";lut=")
+		_, err = strCmd.WriteString(strconv.FormatInt(beforeLastUpdate.UnixNano(), 10))
+	}
+
+	responseMap, err := clnt.sendInfoCommand(policy, strCmd.String())
+	if err != nil {
+		return err
+	}
+
+	response := ""
+	for _, v := range responseMap {
+		response = v
+
+		if strings.ToUpper(response) == "OK" {
+			return nil
+		}
+	}
+
+	return NewAerospikeError(SERVER_ERROR, "Truncate failed: "+response)
+}
+
 //-------------------------------------------------------
 // User administration
 //-------------------------------------------------------
@@ -1038,7 +2084,8 @@ func (clnt *Client) DropUser(policy *AdminPolicy, user string) error {
 func (clnt *Client) ChangePassword(policy *AdminPolicy, user string, password string) error {
 	policy = clnt.getUsableAdminPolicy(policy)
 
-	if clnt.cluster.user == "" {
+	currentUser, _ := clnt.cluster.getCredentials()
+	if currentUser == "" {
 		return NewAerospikeError(INVALID_USER)
 	}
 
@@ -1048,7 +2095,7 @@ func (clnt *Client) ChangePassword(policy *AdminPolicy, user string, password st
 	}
 	command := newAdminCommand()
 
-	if user == clnt.cluster.user {
+	if user == currentUser {
 		// Change own password.
 		if err := command.changePassword(clnt.cluster, policy, user, hash); err != nil {
 			return err
@@ -1203,6 +2250,19 @@ func (clnt *Client) getUsableScanPolicy(policy *ScanPolicy) *ScanPolicy {
 	return policy
 }
 
+// newLimitedRecordset reserves a slot for a concurrent scan or query and
+// creates a recordset which releases the slot when it is closed.
+func (clnt *Client) newLimitedRecordset(recSize, goroutines int) (*Recordset, error) {
+	release, err := clnt.cluster.acquireScanSlot()
+	if err != nil {
+		return nil, err
+	}
+
+	res := newRecordset(recSize, goroutines)
+	res.onClose = release
+	return res, nil
+}
+
 func (clnt *Client) getUsableQueryPolicy(policy *QueryPolicy) *QueryPolicy {
 	if policy == nil {
 		if clnt.DefaultQueryPolicy != nil {
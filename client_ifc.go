@@ -0,0 +1,124 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ClientIfc abstracts the commands of Client, so that code using the client can be
+// unit tested against FakeClient, or any other implementation, without a server.
+// The methods bound to the server nodes, such as GetNodes and ScanNode, and the
+// large data types are not part of it.
+type ClientIfc interface {
+	Close()
+	IsConnected() bool
+	Stats() *ClusterStats
+	ResetStats() *ClusterStats
+	GetNodeNames() []string
+	CloseGracefully(timeout time.Duration) error
+
+	// Write Record Operations
+	Put(policy *WritePolicy, key *Key, binMap BinMap) error
+	PutBins(policy *WritePolicy, key *Key, bins ...*Bin) error
+	PutObject(policy *WritePolicy, key *Key, obj interface{}) (err error)
+	PutJSON(policy *WritePolicy, key *Key, data []byte) error
+	PutIfAbsent(policy *WritePolicy, key *Key, bins ...*Bin) (bool, error)
+	ReplaceOnly(policy *WritePolicy, key *Key, bins ...*Bin) (bool, error)
+	ReplaceIfGeneration(policy *WritePolicy, key *Key, generation int, bins ...*Bin) (bool, error)
+	Update(policy *WritePolicy, key *Key, maxAttempts int, fn func(rec *Record) (BinMap, error)) error
+	Append(policy *WritePolicy, key *Key, binMap BinMap) error
+	AppendBins(policy *WritePolicy, key *Key, bins ...*Bin) error
+	Prepend(policy *WritePolicy, key *Key, binMap BinMap) error
+	PrependBins(policy *WritePolicy, key *Key, bins ...*Bin) error
+	Add(policy *WritePolicy, key *Key, binMap BinMap) error
+	AddBins(policy *WritePolicy, key *Key, bins ...*Bin) error
+	Delete(policy *WritePolicy, key *Key) (bool, error)
+	Touch(policy *WritePolicy, key *Key) error
+	TouchKeys(policy *WritePolicy, keys []*Key, expirations []int32) ([]error, error)
+	BatchPutByDigest(policy *WritePolicy, namespace, setName string, digests []PartitionDigest, bins [][]*Bin) ([]error, error)
+	ApplyWrites(policy *WritePolicy, nodeConcurrency int, writes []*PendingWrite) ([]error, error)
+	PutBinsWithToken(policy *WritePolicy, key *Key, bins ...*Bin) (*ConsistencyToken, error)
+
+	// Read Record Operations
+	Exists(policy *BasePolicy, key *Key) (bool, error)
+	BatchExists(policy *BasePolicy, keys []*Key) ([]bool, error)
+	BatchExistsWithErrors(policy *BasePolicy, keys []*Key) ([]bool, []error, error)
+	Get(policy *BasePolicy, key *Key, binNames ...string) (*Record, error)
+	GetObject(policy *BasePolicy, key *Key, obj interface{}) error
+	GetObjectWithHeader(policy *BasePolicy, key *Key, obj interface{}) (*Record, error)
+	GetJSON(policy *BasePolicy, key *Key, binNames ...string) ([]byte, error)
+	GetHeader(policy *BasePolicy, key *Key) (*Record, error)
+	GetWithToken(policy *BasePolicy, token *ConsistencyToken, key *Key, binNames ...string) (*Record, error)
+	BatchGet(policy *BasePolicy, keys []*Key, binNames ...string) ([]*Record, error)
+	BatchGetComplex(policy *BasePolicy, records []*BatchRead) error
+	BatchGetHeader(policy *BasePolicy, keys []*Key) ([]*Record, error)
+	BatchGetHeaderWithErrors(policy *BasePolicy, keys []*Key) ([]*Record, []error, error)
+	BatchGetFunc(policy *BasePolicy, keys []*Key, fn func(index int, record *Record) error, binNames ...string) error
+	BatchGetHeaderFunc(policy *BasePolicy, keys []*Key, fn func(index int, record *Record) error) error
+	BatchGetByDigest(policy *BasePolicy, namespace string, digests []PartitionDigest, binNames ...string) ([]*Record, error)
+
+	// Generic Database Operations
+	Operate(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, error)
+	OperateWithResults(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, OpResults, error)
+	OperateIf(policy *WritePolicy, key *Key, condition *Expression, operations ...*Operation) (*Record, error)
+	OperateWithToken(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, *ConsistencyToken, error)
+	MapUpsert(policy *WritePolicy, key *Key, binName string, mapPolicy *MapPolicy, operations ...*Operation) (*Record, bool, error)
+
+	// Scan Operations
+	ScanAll(apolicy *ScanPolicy, namespace string, setName string, binNames ...string) (*Recordset, error)
+	ScanAllFunc(apolicy *ScanPolicy, namespace string, setName string, fn func(*Record) error, binNames ...string) error
+	ScanAllObjects(apolicy *ScanPolicy, objChan interface{}, namespace string, setName string, binNames ...string) (*ObjectRecordset, error)
+	ScanAllContext(ctx context.Context, apolicy *ScanPolicy, namespace string, setName string, binNames ...string) (*Recordset, error)
+	IncrementalScan(apolicy *ScanPolicy, namespace string, setName string, since time.Time, binNames ...string) (*Recordset, error)
+	ExportByDigest(apolicy *ScanPolicy, namespace string, setName string, chunkSize int, fn func(*ExportChunk) error, binNames ...string) error
+
+	// User defined functions
+	RegisterUDFFromFile(policy *WritePolicy, clientPath string, serverPath string, language Language) (*RegisterTask, error)
+	RegisterUDFFromReader(policy *WritePolicy, reader io.Reader, serverPath string, language Language) (*RegisterTask, error)
+	RegisterUDF(policy *WritePolicy, udfBody []byte, serverPath string, language Language) (*RegisterTask, error)
+	RemoveUDF(policy *WritePolicy, udfName string) (*RemoveTask, error)
+	ListUDF(policy *BasePolicy) ([]*UDF, error)
+	Execute(policy *WritePolicy, key *Key, packageName string, functionName string, args ...Value) (interface{}, error)
+
+	// Query Operations
+	ExecuteUDF(policy *QueryPolicy, statement *Statement, packageName string, functionName string, functionArgs ...Value) (*ExecuteTask, error)
+	QueryExecute(policy *QueryPolicy, statement *Statement, ops ...*Operation) (*ExecuteTask, error)
+	QueryDelete(policy *QueryPolicy, statement *Statement, filterExp *Expression) (*ExecuteTask, error)
+	Query(policy *QueryPolicy, statement *Statement) (*Recordset, error)
+	QueryObjects(policy *QueryPolicy, statement *Statement, objChan interface{}) (*ObjectRecordset, error)
+	QueryContext(ctx context.Context, policy *QueryPolicy, statement *Statement) (*Recordset, error)
+	DeleteWhere(policy *QueryPolicy, namespace string, setName string, filter *Filter, filterExp *Expression, deletesPerSecond int) (*DeleteWhereTask, error)
+
+	// Index and Truncate Operations
+	CreateIndex(policy *WritePolicy, namespace string, setName string, indexName string, binName string, indexType IndexType) (*IndexTask, error)
+	CreateComplexIndex(policy *WritePolicy, namespace string, setName string, indexName string, binName string, indexType IndexType, indexCollectionType IndexCollectionType, ctx ...*CDTContext) (*IndexTask, error)
+	DropIndex(policy *WritePolicy, namespace string, setName string, indexName string) error
+	Truncate(policy *WritePolicy, namespace string, setName string, beforeLastUpdate *time.Time) error
+
+	// User administration
+	CreateUser(policy *AdminPolicy, user string, password string, roles []string) error
+	DropUser(policy *AdminPolicy, user string) error
+	ChangePassword(policy *AdminPolicy, user string, password string) error
+	GrantRoles(policy *AdminPolicy, user string, roles []string) error
+	RevokeRoles(policy *AdminPolicy, user string, roles []string) error
+	ReplaceRoles(policy *AdminPolicy, user string, roles []string) error
+	QueryUser(policy *AdminPolicy, user string) (*UserRoles, error)
+	QueryUsers(policy *AdminPolicy) ([]*UserRoles, error)
+}
+
+var _ ClientIfc = &Client{}
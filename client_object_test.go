@@ -25,6 +25,32 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+// reversedString is stored reversed, to test custom marshalers
+type reversedString string
+
+func reverseString(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+func (s reversedString) MarshalAerospike() (interface{}, error) {
+	return reverseString(string(s)), nil
+}
+
+func (s *reversedString) UnmarshalAerospike(value interface{}) error {
+	*s = reversedString(reverseString(value.(string)))
+	return nil
+}
+
+// Auditable is embedded in the objects of the flattening tests
+type Auditable struct {
+	CreatedBy string `as:"created_by"`
+	Revision  int    `as:"revision"`
+}
+
 // ALL tests are isolated by SetName and Key, which are 50 random charachters
 var _ = Describe("Aerospike", func() {
 	initTestVars()
@@ -350,7 +376,252 @@ var _ = Describe("Aerospike", func() {
 
 			})
 
+			It("must skip empty fields tagged with omitempty", func() {
+
+				type OmitEmptyStruct struct {
+					Name  string            `as:"name,omitempty"`
+					Count int               `as:"count,omitempty"`
+					Tags  []string          `as:",omitempty"`
+					Attrs map[string]string `as:"attrs,omitempty"`
+					Kept  int               `as:"kept"`
+				}
+
+				testObj := OmitEmptyStruct{Count: 3}
+				err := client.PutObject(nil, key, &testObj)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err := client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(rec.Bins)).To(Equal(2))
+				Expect(rec.Bins["count"]).To(Equal(3))
+				Expect(rec.Bins["kept"]).To(Equal(0))
+
+				resObj := &OmitEmptyStruct{}
+				err = client.GetObject(nil, key, resObj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(resObj).To(Equal(&testObj))
+
+			})
+
+			It("must store fields tagged with msgpack as blobs and read them back", func() {
+
+				type PackedInner struct {
+					Label string `as:"label"`
+					Score int
+				}
+
+				type PackedStruct struct {
+					Inner  PackedInner       `as:"inner,msgpack"`
+					Values []int             `as:"values,msgpack"`
+					Attrs  map[string]string `as:"attrs,msgpack,omitempty"`
+					Plain  string
+				}
+
+				testObj := PackedStruct{
+					Inner:  PackedInner{Label: "a", Score: 7},
+					Values: []int{1, 2, 3},
+					Plain:  "p",
+				}
+				err := client.PutObject(nil, key, &testObj)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err := client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(rec.Bins)).To(Equal(3))
+				Expect(rec.Bins["inner"]).To(BeAssignableToTypeOf([]byte{}))
+				Expect(rec.Bins["values"]).To(BeAssignableToTypeOf([]byte{}))
+				Expect(rec.Bins["Plain"]).To(Equal("p"))
+
+				resObj := &PackedStruct{}
+				err = client.GetObject(nil, key, resObj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(resObj).To(Equal(&testObj))
+
+			})
+
+			It("must store fields with custom marshalers and read them back", func() {
+
+				type MarshaledStruct struct {
+					Secret reversedString `as:"secret"`
+					Plain  string         `as:"plain"`
+				}
+
+				testObj := MarshaledStruct{Secret: "abc", Plain: "abc"}
+				err := client.PutObject(nil, key, &testObj)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err := client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"secret": "cba", "plain": "abc"}))
+
+				resObj := &MarshaledStruct{}
+				err = client.GetObject(nil, key, resObj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(resObj).To(Equal(&testObj))
+
+			})
+
+			It("must store times and durations as integers in the requested precision", func() {
+
+				type TimedStruct struct {
+					Created time.Time     `as:"created"`
+					Updated time.Time     `as:"updated,millis"`
+					Deleted time.Time     `as:"deleted,omitempty"`
+					TTL     time.Duration `as:"ttl,millis"`
+				}
+
+				now := time.Now()
+				testObj := TimedStruct{Created: now, Updated: now, TTL: time.Minute}
+				err := client.PutObject(nil, key, &testObj)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err := client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{
+					"created": int(now.UnixNano()),
+					"updated": int(now.UnixNano() / int64(time.Millisecond)),
+					"ttl":     60000,
+				}))
+
+				resObj := &TimedStruct{}
+				err = client.GetObject(nil, key, resObj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(resObj.Created.Equal(now)).To(BeTrue())
+				Expect(resObj.Updated.Equal(now.Truncate(time.Millisecond))).To(BeTrue())
+				Expect(resObj.Deleted.IsZero()).To(BeTrue())
+				Expect(resObj.TTL).To(Equal(time.Minute))
+
+			})
+
+			It("must flatten embedded structs tagged with flatten and read them back", func() {
+
+				type FlattenedStruct struct {
+					Auditable `as:",flatten"`
+					Name      string `as:"name"`
+				}
+
+				type NestedStruct struct {
+					*Auditable
+					Name string `as:"name"`
+				}
+
+				testObj := FlattenedStruct{Auditable: Auditable{CreatedBy: "me", Revision: 3}, Name: "n"}
+				err := client.PutObject(nil, key, &testObj)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err := client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"created_by": "me", "revision": 3, "name": "n"}))
+
+				resObj := &FlattenedStruct{}
+				err = client.GetObject(nil, key, resObj)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(resObj).To(Equal(&testObj))
+
+				nestedObj := NestedStruct{Auditable: &Auditable{CreatedBy: "me"}, Name: "n"}
+				err = client.PutObject(nil, key, &nestedObj)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["Auditable"]).To(BeAssignableToTypeOf(map[interface{}]interface{}{}))
+
+				resNested := &NestedStruct{}
+				err = client.GetObject(nil, key, resNested)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(resNested).To(Equal(&nestedObj))
+
+			})
+
+			It("must return an error listing the fields which map to the same bin", func() {
+
+				type CollidingStruct struct {
+					Name                 string
+					Alias                string `as:"Name"`
+					VeryLongFieldName123 int
+					VeryLongFieldName456 int
+					Other                int
+				}
+
+				testObj := CollidingStruct{Name: "a", Alias: "b", VeryLongFieldName123: 1, VeryLongFieldName456: 2}
+				err := client.PutObject(nil, key, &testObj)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Name, Alias"))
+				Expect(err.Error()).To(ContainSubstring("VeryLongFieldName123, VeryLongFieldName456"))
+				Expect(err.Error()).ToNot(ContainSubstring("Other"))
+
+				exists, err := client.Exists(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).To(BeFalse())
+
+			})
+
 		}) // GetHeader context
 
+		Context("ScanAllObjects and QueryObjects operations", func() {
+
+			type ScannedStruct struct {
+				Name  string `as:"name"`
+				Count int    `as:"count"`
+			}
+
+			var scanSet string
+
+			BeforeEach(func() {
+				scanSet = randString(50)
+				for i := 0; i < 10; i++ {
+					key, err := NewKey(ns, scanSet, i)
+					Expect(err).ToNot(HaveOccurred())
+					err = client.PutObject(nil, key, &ScannedStruct{Name: "obj", Count: i})
+					Expect(err).ToNot(HaveOccurred())
+				}
+			})
+
+			It("must scan all records into a channel of structs", func() {
+				objChan := make(chan *ScannedStruct, 10)
+				rs, err := client.ScanAllObjects(nil, objChan, ns, scanSet)
+				Expect(err).ToNot(HaveOccurred())
+
+				counts := map[int]bool{}
+				for obj := range objChan {
+					Expect(obj.Name).To(Equal("obj"))
+					counts[obj.Count] = true
+				}
+				Expect(rs.Err()).ToNot(HaveOccurred())
+				Expect(len(counts)).To(Equal(10))
+			})
+
+			It("must query records into a channel of structs", func() {
+				objChan := make(chan *ScannedStruct)
+				rs, err := client.QueryObjects(nil, NewStatement(ns, scanSet), objChan)
+				Expect(err).ToNot(HaveOccurred())
+
+				cnt := 0
+				for range objChan {
+					cnt++
+				}
+				Expect(rs.Err()).ToNot(HaveOccurred())
+				Expect(cnt).To(Equal(10))
+			})
+
+			It("must stop sending records once closed", func() {
+				objChan := make(chan *ScannedStruct)
+				rs, err := client.ScanAllObjects(nil, objChan, ns, scanSet)
+				Expect(err).ToNot(HaveOccurred())
+
+				<-objChan
+				rs.Close()
+				for range objChan {
+				}
+				Expect(rs.Err()).ToNot(HaveOccurred())
+			})
+
+			It("must reject channels of other types", func() {
+				_, err := client.ScanAllObjects(nil, make(chan ScannedStruct), ns, scanSet)
+				Expect(err).To(HaveOccurred())
+			})
+
+		})
+
 	})
 })
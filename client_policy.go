@@ -16,6 +16,8 @@ package aerospike
 
 import (
 	"time"
+
+	. "github.com/aerospike/aerospike-client-go/logger"
 )
 
 // ClientPolicy encapsulates parameters for client policy command.
@@ -34,6 +36,19 @@ type ClientPolicy struct {
 	// Size of the Connection Queue cache.
 	ConnectionQueueSize int //= 256
 
+	// ConnectionDrainInterval is the minimum interval between the replacements of two
+	// connections of a node after Client.Reconfigure, so that the connections opened
+	// before are replaced gradually instead of all at once.
+	// Default (0) replaces them as soon as they are used.
+	ConnectionDrainInterval time.Duration //= 0
+
+	// PipelinedConnections, if set, is the number of connections per node shared by the
+	// single record commands. Each command is written without waiting for the responses
+	// of the previous ones, so much fewer connections are needed at high throughput.
+	// Batch, scan and query commands still use the connection pool.
+	// Default (0) gives each command a connection of its own from the pool.
+	PipelinedConnections int //= 0
+
 	// If set to true, will not create a new connection
 	// to the node if there are already `ConnectionQueueSize` active connections.
 	LimitConnectionsToQueueSize bool //= false
@@ -41,9 +56,117 @@ type ClientPolicy struct {
 	// Throw exception if host connection fails during addHost().
 	FailIfNotConnected bool //= true
 
+	// LazyConnect determines that the client is created without contacting the
+	// seeds, so that applications can start while the cluster is unreachable.
+	// The cluster is tended in the background from then on, and commands fail
+	// until a node is reached; use Client.IsConnected to check. FailIfNotConnected
+	// is ignored when set.
+	LazyConnect bool //= false
+
 	// TendInterval determines interval for checking for cluster state changes.
 	// Minimum possible interval is 10 Miliseconds.
 	TendInterval time.Duration //= 1 second
+
+	// MaxRefreshBackoff, if set, backs off the refreshes of a node which keeps failing
+	// during cluster tend. After each consecutive failure the node is skipped for twice
+	// as long as before, starting with TendInterval, up to MaxRefreshBackoff.
+	// Default (0) refreshes every node on every tend.
+	MaxRefreshBackoff time.Duration //= 0
+
+	// LatencyAwareSeeds measures the info round trip time of each seed during
+	// seeding, and sorts the seeds and the new nodes in order of increasing
+	// latency, so that the closest are tried and tended first.
+	// Node latencies are re-evaluated on every tend. They are measured on the
+	// wire only, excluding dialing and waiting for a connection.
+	LatencyAwareSeeds bool //= false
+
+	// PreferClosestNode makes Cluster.GetRandomNode return the node with the
+	// lowest measured latency instead of a random node. It only affects the
+	// commands which are not bound to a partition: RegisterUDF, RemoveUDF,
+	// ListUDF, CreateIndex, DropIndex, Truncate and user administration, and
+	// the commands on keys whose partition has no active node.
+	// Reads, writes, batches, scans and queries are still routed by partition.
+	PreferClosestNode bool //= false
+
+	// RequestProleReplicas determines if the replicated (prole) partition map
+	// of each node is requested during cluster tend. It is required for reads
+	// with BasePolicy.ReplicaPolicy other than MASTER, and for scans from proles.
+	RequestProleReplicas bool //= false
+
+	// KeyHash determines the hash function used to compute the secondary
+	// hash of user keys stored with WritePolicy.SendKeyHash.
+	// If nil, DefaultKeyHash is used.
+	KeyHash KeyHashFunc
+
+	// MaxConcurrentScans limits the number of scans and queries returning a Recordset
+	// which may run concurrently on this client. A scan or query holds its slot until
+	// its Recordset is closed, either after all records have been received or by
+	// calling Recordset.Close. Default (0) is no limit.
+	MaxConcurrentScans int //= 0
+
+	// ScanQueueTimeout determines how long a scan or query waits for a free slot when
+	// MaxConcurrentScans are already running. If zero (default), the scan or query
+	// fails immediately with a COMMAND_REJECTED error.
+	ScanQueueTimeout time.Duration //= 0
+
+	// MaxCommandsPerSecondPerNode limits the rate of the commands this client sends
+	// to each node, retries included, with bursts of up to a second worth of commands.
+	// Default (0) is no limit.
+	MaxCommandsPerSecondPerNode int //= 0
+
+	// RateLimitQueueTimeout determines how long a command waits for its turn when
+	// a node's command rate limit is reached, bounded by the command's own timeout.
+	// If zero (default), the command fails immediately with ErrRateLimited.
+	RateLimitQueueTimeout time.Duration //= 0
+
+	// DialAllAddresses determines if all the addresses known for a node (its aliases,
+	// e.g. internal, alternate and IPv6 addresses) are tried in order when a connection
+	// cannot be opened to its current address. The first address which accepts the
+	// connection is used for the node from then on.
+	DialAllAddresses bool //= false
+
+	// HostResolver, if set, resolves the hosts of the seeds and of the nodes advertised
+	// by the cluster to the addresses the client connects to, instead of a DNS lookup
+	// of host names. Default (nil) looks up host names and uses IP addresses as is.
+	HostResolver HostResolver
+
+	// Logger receives the log events of this client instead of the global Logger,
+	// so that the logs of multiple clients in a process are kept apart and can be
+	// sent to a structured logging library. Level filtering is left to the logger;
+	// implement LevelEnabler to avoid building the events of disabled levels.
+	// Default (nil) uses the global Logger.
+	Logger StructuredLogger
+
+	// LatencyBuckets are the upper bounds of the buckets of the per node latency
+	// histograms returned by Node.LatencyStats, in ascending order.
+	// Default (nil) is 1ms, 2ms, 4ms, ... 1024ms.
+	LatencyBuckets []time.Duration
+
+	// DecompressionWorkers, if set, is the number of goroutines shared by the scans and
+	// queries of this client to decompress the responses compressed by the nodes, see
+	// BasePolicy.UseCompression. Each scan or query then reads its messages ahead while
+	// they are decompressed, and decodes the records apart from the reads, so that slow
+	// decompression does not stall the connection. Default (0) decompresses each message
+	// where it is read.
+	DecompressionWorkers int //= 0
+
+	// InitialBufferSize is the size of the buffers allocated for database commands.
+	// Buffers are pooled per client and grown as needed by larger commands and records.
+	// Default (0) is 16KiB.
+	InitialBufferSize int //= 16 * 1024
+
+	// MaxPooledBufferSize is the size of the largest command buffer kept in the pool.
+	// Larger buffers are released after use, which bounds the memory held by the pool.
+	// Default (0) is 128KiB.
+	MaxPooledBufferSize int //= 128 * 1024
+
+	// MetricsPolicy determines how the metrics of commands and connections are reported.
+	// Leave nil (default) to disable metrics.
+	MetricsPolicy *MetricsPolicy
+
+	// FaultPolicy determines the faults injected into database commands for chaos testing.
+	// Leave nil (default) to disable fault injection.
+	FaultPolicy *FaultPolicy
 }
 
 // NewClientPolicy generates a new ClientPolicy with default values.
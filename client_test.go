@@ -16,11 +16,18 @@ package aerospike_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"math"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
 	. "github.com/aerospike/aerospike-client-go/utils/buffer"
 
 	. "github.com/onsi/ginkgo"
@@ -41,6 +48,87 @@ var _ = Describe("Aerospike", func() {
 			client.Close()
 			Expect(client.IsConnected()).To(BeFalse())
 		})
+
+		It("must create the client without contacting the seeds with LazyConnect", func() {
+			policy := *clientPolicy
+			policy.LazyConnect = true
+			policy.Timeout = 100 * time.Millisecond
+
+			// nothing listens on port 1
+			client, err := NewClientWithPolicy(&policy, "127.0.0.1", 1)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+			Expect(client.IsConnected()).To(BeFalse())
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = client.Get(nil, key)
+			Expect(err).To(HaveOccurred())
+
+			// a reachable cluster is connected in the background
+			lazy, err := NewClientWithPolicy(&policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer lazy.Close()
+
+			deadline := time.Now().Add(5 * time.Second)
+			for !lazy.IsConnected() && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			Expect(lazy.IsConnected()).To(BeTrue())
+		})
+
+		It("must return JSON serializable statistics", func() {
+			client, err := NewClientWithPolicy(clientPolicy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = client.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+
+			stats := client.Stats()
+			Expect(stats.TendCount).To(BeNumerically(">", 0))
+			Expect(len(stats.Nodes)).To(Equal(len(client.GetNodes())))
+			Expect(stats.Build.ClientVersion).To(Equal(ClientVersion))
+			Expect(stats.Build.GoVersion).ToNot(BeEmpty())
+
+			commands := int64(0)
+			for name, nodeStats := range stats.Nodes {
+				Expect(nodeStats.Name).To(Equal(name))
+				Expect(nodeStats.Build).ToNot(BeEmpty())
+				Expect(nodeStats.ConnectionsOpened).To(BeNumerically(">", 0))
+				Expect(nodeStats.PartitionGeneration).To(BeNumerically(">=", 0))
+				commands += nodeStats.CommandLatency.Total()
+			}
+			Expect(commands).To(Equal(int64(1)))
+
+			_, err = json.Marshal(stats)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("must grow command buffers beyond the pooled buffer sizes", func() {
+			policy := *clientPolicy
+			policy.InitialBufferSize = 64
+			policy.MaxPooledBufferSize = 256
+
+			client, err := NewClientWithPolicy(&policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			blob := []byte(randString(10 * 1024))
+			for i := 0; i < 3; i++ {
+				err = client.PutBins(nil, key, NewBin("blob", blob))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err := client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["blob"]).To(Equal(blob))
+			}
+		})
 	})
 
 	Describe("Data operations on native types", func() {
@@ -163,6 +251,39 @@ var _ = Describe("Aerospike", func() {
 				})
 			})
 
+			Context("Bins with `bool` values", func() {
+				It("must save a key with native boolean bins", func() {
+					bin1 := NewBin("Aerospike1", true)
+					bin2 := NewBin("Aerospike2", false)
+					err = client.PutBins(wpolicy, key, bin1, bin2)
+					Expect(err).ToNot(HaveOccurred())
+
+					rec, err = client.Get(rpolicy, key)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(rec.Bins[bin1.Name]).To(Equal(true))
+					Expect(rec.Bins[bin2.Name]).To(Equal(false))
+				})
+
+				It("must save boolean bins as integers when BoolAsInteger is set", func() {
+					bpolicy := NewWritePolicy(0, 0)
+					bpolicy.BoolAsInteger = true
+
+					bin1 := NewBin("Aerospike1", true)
+					bin2 := NewBin("Aerospike2", false)
+					err = client.PutBins(bpolicy, key, bin1, bin2)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = client.Operate(bpolicy, key, PutOp(NewBin("Aerospike3", true)))
+					Expect(err).ToNot(HaveOccurred())
+
+					rec, err = client.Get(rpolicy, key)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(rec.Bins[bin1.Name]).To(Equal(1))
+					Expect(rec.Bins[bin2.Name]).To(Equal(0))
+					Expect(rec.Bins["Aerospike3"]).To(Equal(1))
+				})
+			})
+
 			Context("Bins with `int8` and `uint8` values", func() {
 				It("must save a key with SINGLE bin", func() {
 					bin := NewBin("Aerospike", int8(rand.Intn(math.MaxInt8)))
@@ -401,6 +522,21 @@ var _ = Describe("Aerospike", func() {
 
 			}) // context complex types
 
+			It("must verify the replication of the written record if requested", func() {
+				vpolicy := NewWritePolicy(0, 0)
+				vpolicy.VerifyRead = true
+
+				err = client.PutBins(vpolicy, key, NewBin("bin", 1))
+				Expect(err).ToNot(HaveOccurred())
+				err = client.AddBins(vpolicy, key, NewBin("bin", 1))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"bin": 2}))
+				Expect(rec.Generation).To(Equal(2))
+			})
+
 		}) // put context
 
 		Context("Append operations", func() {
@@ -523,8 +659,169 @@ var _ = Describe("Aerospike", func() {
 				Expect(existed).To(Equal(false))
 			})
 
+			It("must Delete durably on Enterprise servers", func() {
+				dpolicy := NewWritePolicy(0, 0)
+				dpolicy.DurableDelete = true
+
+				var existed bool
+				existed, err = client.Delete(dpolicy, key)
+				if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == ENTERPRISE_ONLY {
+					// community servers do not support tombstones
+					return
+				}
+				Expect(err).ToNot(HaveOccurred())
+				Expect(existed).To(Equal(true))
+
+				existed, err = client.Exists(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(existed).To(Equal(false))
+
+				// deleting the record with an operation leaves a tombstone as well
+				err = client.PutBins(wpolicy, key, bin)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = client.Operate(dpolicy, key, DeleteOp())
+				Expect(err).ToNot(HaveOccurred())
+
+				existed, err = client.Exists(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(existed).To(Equal(false))
+			})
+
 		}) // Delete context
 
+		Context("Get operations", func() {
+
+			It("must return a nil record for a non-existing key by default", func() {
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec).To(BeNil())
+			})
+
+			It("must return ErrKeyNotFound for a non-existing key if requested", func() {
+				policy := NewPolicy()
+				policy.KeyNotFoundAsError = true
+
+				rec, err = client.Get(policy, key)
+				Expect(err).To(Equal(ErrKeyNotFound))
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(KEY_NOT_FOUND_ERROR))
+				Expect(rec).To(BeNil())
+
+				rec, err = client.GetHeader(policy, key)
+				Expect(err).To(Equal(ErrKeyNotFound))
+				Expect(rec).To(BeNil())
+			})
+
+		}) // Get context
+
+		Context("JSON operations", func() {
+
+			It("must put a JSON object and get it back", func() {
+				doc := `{"name":"a","count":1,"ratio":0.5,"tags":["x","y"],"address":{"city":"b","zip":123}}`
+				err = client.PutJSON(wpolicy, key, []byte(doc))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["count"]).To(Equal(1))
+				Expect(rec.Bins["address"]).To(Equal(map[interface{}]interface{}{"city": "b", "zip": 123}))
+
+				data, err := client.GetJSON(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+
+				var expected, res map[string]interface{}
+				Expect(json.Unmarshal([]byte(doc), &expected)).ToNot(HaveOccurred())
+				Expect(json.Unmarshal(data, &res)).ToNot(HaveOccurred())
+				Expect(res).To(Equal(expected))
+			})
+
+			It("must reject JSON documents which are not objects", func() {
+				err = client.PutJSON(wpolicy, key, []byte(`[1, 2]`))
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+			})
+
+			It("must return nil for a non-existing key", func() {
+				data, err := client.GetJSON(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(data).To(BeNil())
+			})
+
+		}) // JSON context
+
+		Context("Write journal operations", func() {
+
+			It("must write through the journal and leave no pending writes", func() {
+				dir, err := ioutil.TempDir("", "journal")
+				Expect(err).ToNot(HaveOccurred())
+				defer os.RemoveAll(dir)
+
+				journal, err := NewWriteJournal(client, filepath.Join(dir, "writes.journal"))
+				Expect(err).ToNot(HaveOccurred())
+				defer journal.Close()
+
+				replayed, err := journal.Replay(wpolicy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(replayed).To(Equal(0))
+
+				err = journal.Put(wpolicy, key, BinMap{"Aerospike": 1})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(journal.Pending()).To(Equal(0))
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["Aerospike"]).To(Equal(1))
+
+				existed, err := journal.Delete(wpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(existed).To(BeTrue())
+				Expect(journal.Pending()).To(Equal(0))
+			})
+
+		}) // Write journal context
+
+		Context("Truncate operations", func() {
+
+			It("must Truncate the records of a set written before the specified time", func() {
+				tset := randString(50)
+
+				oldKey, err := NewKey(ns, tset, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+				err = client.PutBins(wpolicy, oldKey, NewBin("Aerospike", 1))
+				Expect(err).ToNot(HaveOccurred())
+
+				time.Sleep(10 * time.Millisecond)
+				lut := time.Now()
+				time.Sleep(10 * time.Millisecond)
+
+				newKey, err := NewKey(ns, tset, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+				err = client.PutBins(wpolicy, newKey, NewBin("Aerospike", 2))
+				Expect(err).ToNot(HaveOccurred())
+
+				err = client.Truncate(nil, ns, tset, &lut)
+				Expect(err).ToNot(HaveOccurred())
+
+				// truncation is applied by the nodes asynchronously
+				time.Sleep(time.Second)
+
+				exists, err := client.Exists(rpolicy, oldKey)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).To(BeFalse())
+
+				exists, err = client.Exists(rpolicy, newKey)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).To(BeTrue())
+			})
+
+			It("must reject a last update time in the future", func() {
+				future := time.Now().Add(time.Hour)
+				err := client.Truncate(nil, ns, set, &future)
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+			})
+
+		}) // Truncate context
+
 		Context("Touch operations", func() {
 			bin := NewBin("Aerospike", rand.Intn(math.MaxInt16))
 
@@ -568,6 +865,42 @@ var _ = Describe("Aerospike", func() {
 				}
 			})
 
+			It("must touch keys with different expirations", func() {
+				nxkey, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				keys := []*Key{key}
+				expirations := []int32{100}
+				for i := 0; i < 10; i++ {
+					k, err := NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					err = client.PutBins(wpolicy, k, bin)
+					Expect(err).ToNot(HaveOccurred())
+
+					keys = append(keys, k)
+					expirations = append(expirations, int32(1000*(i+1)))
+				}
+				keys = append(keys, nxkey)
+				expirations = append(expirations, 100)
+
+				errs, err := client.TouchKeys(nil, keys, expirations)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(errs)).To(Equal(len(keys)))
+
+				for i, k := range keys[:len(keys)-1] {
+					Expect(errs[i]).ToNot(HaveOccurred())
+
+					rec, err = client.GetHeader(rpolicy, k)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(rec.Expiration).To(BeNumerically("<=", expirations[i]))
+					Expect(rec.Expiration).To(BeNumerically(">", expirations[i]-10))
+				}
+				Expect(errs[len(keys)-1]).To(HaveOccurred())
+
+				_, err = client.TouchKeys(nil, keys, expirations[1:])
+				Expect(err).To(HaveOccurred())
+			})
+
 		}) // Touch context
 
 		Context("Exists operations", func() {
@@ -634,6 +967,45 @@ var _ = Describe("Aerospike", func() {
 				}
 			})
 
+			It("must report the errors per key", func() {
+				keys := []*Key{}
+				for i := 0; i < 10; i++ {
+					key, err := NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					keys = append(keys, key)
+
+					if i%2 == 0 {
+						err = client.PutBins(wpolicy, key, bin)
+						Expect(err).ToNot(HaveOccurred())
+					}
+				}
+
+				// the keys of an unknown namespace fail without failing the others
+				badKey, err := NewKey("invalid-"+randString(10), set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+				keys = append(keys, badKey)
+
+				exists, errs, err := client.BatchExistsWithErrors(rpolicy, keys)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(exists)).To(Equal(len(keys)))
+				Expect(len(errs)).To(Equal(len(keys)))
+				for idx := range keys[:10] {
+					Expect(errs[idx]).ToNot(HaveOccurred())
+					Expect(exists[idx]).To(Equal(idx%2 == 0))
+				}
+				Expect(exists[10]).To(BeFalse())
+				Expect(errs[10]).To(HaveOccurred())
+
+				records, errs, err := client.BatchGetHeaderWithErrors(rpolicy, keys)
+				Expect(err).ToNot(HaveOccurred())
+				for idx := range keys[:10] {
+					Expect(errs[idx]).ToNot(HaveOccurred())
+					Expect(records[idx] != nil).To(Equal(idx%2 == 0))
+				}
+				Expect(records[10]).To(BeNil())
+				Expect(errs[10]).To(HaveOccurred())
+			})
+
 		}) // Batch Exists context
 
 		Context("Batch Get operations", func() {
@@ -705,6 +1077,112 @@ var _ = Describe("Aerospike", func() {
 				}
 			})
 
+			It("must stream the records to the callback as they are received", func() {
+				keys := make([]*Key, 100)
+				for i := range keys {
+					keys[i], err = NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					if i%2 == 0 {
+						err = client.PutBins(wpolicy, keys[i], NewBin("a", i), NewBin("b", "b"))
+						Expect(err).ToNot(HaveOccurred())
+					}
+				}
+
+				seen := make([]bool, len(keys))
+				err = client.BatchGetFunc(rpolicy, keys, func(index int, rec *Record) error {
+					Expect(seen[index]).To(BeFalse())
+					seen[index] = true
+					if index%2 == 0 {
+						Expect(rec.Bins).To(Equal(BinMap{"a": index}))
+					} else {
+						Expect(rec).To(BeNil())
+					}
+					return nil
+				}, "a")
+				Expect(err).ToNot(HaveOccurred())
+				for _, s := range seen {
+					Expect(s).To(BeTrue())
+				}
+
+				headers := 0
+				err = client.BatchGetHeaderFunc(rpolicy, keys, func(index int, rec *Record) error {
+					if rec != nil {
+						Expect(rec.Bins).To(BeNil())
+						Expect(rec.Generation).To(BeNumerically(">", 0))
+						headers++
+					}
+					return nil
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(headers).To(Equal(len(keys) / 2))
+
+				errStop := errors.New("stop")
+				calls := 0
+				err = client.BatchGetFunc(rpolicy, keys, func(index int, rec *Record) error {
+					calls++
+					return errStop
+				})
+				Expect(err).To(Equal(errStop))
+				Expect(calls).To(Equal(1))
+			})
+
+			It("must read different bins of each record in the same batch", func() {
+				keys := make([]*Key, 4)
+				for i := range keys {
+					keys[i], err = NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+				}
+				for _, key := range keys[:3] {
+					err = client.PutBins(wpolicy, key, NewBin("a", 1), NewBin("b", 2))
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				reads := []*BatchRead{
+					NewBatchRead(keys[0]),
+					NewBatchRead(keys[1], "b"),
+					NewBatchReadHeader(keys[2]),
+					NewBatchRead(keys[3], "a"),
+				}
+				err = client.BatchGetComplex(rpolicy, reads)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(reads[0].Record.Bins).To(Equal(BinMap{"a": 1, "b": 2}))
+				Expect(reads[1].Record.Bins).To(Equal(BinMap{"b": 2}))
+				Expect(reads[2].Record.Bins).To(BeEmpty())
+				Expect(reads[2].Record.Generation).To(Equal(1))
+				Expect(reads[3].Record).To(BeNil())
+			})
+
+			It("must write and read records by partition and digest", func() {
+				digests := make([]PartitionDigest, 3)
+				binsList := make([][]*Bin, len(digests))
+				for i := range digests {
+					k, err := NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					digests[i] = NewPartitionDigest(k.Digest())
+					binsList[i] = []*Bin{NewBin("a", i)}
+				}
+
+				errs, err := client.BatchPutByDigest(wpolicy, ns, set, digests[:2], binsList[:2])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(errs).To(Equal([]error{nil, nil}))
+
+				records, err := client.BatchGetByDigest(rpolicy, ns, digests)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(records)).To(Equal(3))
+				Expect(records[0].Bins).To(Equal(BinMap{"a": 0}))
+				Expect(records[1].Bins).To(Equal(BinMap{"a": 1}))
+				Expect(records[1].Key.Digest()).To(Equal(digests[1].Digest))
+				Expect(records[2]).To(BeNil())
+
+				// the partition must match the digest
+				digests[0].PartitionId = (digests[0].PartitionId + 1) % 4096
+				_, err = client.BatchGetByDigest(rpolicy, ns, digests)
+				Expect(err).To(HaveOccurred())
+				_, err = client.BatchPutByDigest(wpolicy, ns, set, digests, binsList)
+				Expect(err).To(HaveOccurred())
+			})
+
 		}) // Batch Get context
 
 		Context("GetHeader operations", func() {
@@ -786,6 +1264,237 @@ var _ = Describe("Aerospike", func() {
 
 		}) // Batch Get Header context
 
+		Context("Write validators", func() {
+
+			errTooManyBins := errors.New("too many bins")
+
+			BeforeEach(func() {
+				client.RegisterWriteValidator(ns, set, func(write *PendingWrite) error {
+					if len(write.Operations) > 1 {
+						return errTooManyBins
+					}
+					return nil
+				})
+			})
+
+			AfterEach(func() {
+				client.RemoveWriteValidators(ns, set)
+			})
+
+			It("must reject invalid puts without writing them", func() {
+				err = client.PutBins(wpolicy, key, NewBin("bin1", 1), NewBin("bin2", 2))
+				Expect(err).To(Equal(errTooManyBins))
+
+				exists, err := client.Exists(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).To(BeFalse())
+
+				err = client.PutBins(wpolicy, key, NewBin("bin1", 1))
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("must reject invalid operations", func() {
+				_, err = client.Operate(wpolicy, key, PutOp(NewBin("bin1", 1)), GetOp())
+				Expect(err).To(Equal(errTooManyBins))
+
+				_, err = client.Operate(wpolicy, key, PutOp(NewBin("bin1", 1)))
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("must not validate the writes to other sets", func() {
+				other, err := NewKey(ns, randString(50), randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				err = client.PutBins(wpolicy, other, NewBin("bin1", 1), NewBin("bin2", 2))
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+		})
+
+		Context("Conditional Put operations", func() {
+
+			It("must create a record only if it is absent", func() {
+				written, err := client.PutIfAbsent(wpolicy, key, NewBin("bin", "first"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(written).To(BeTrue())
+
+				written, err = client.PutIfAbsent(wpolicy, key, NewBin("bin", "second"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(written).To(BeFalse())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"bin": "first"}))
+			})
+
+			It("must replace a record only if it exists", func() {
+				written, err := client.ReplaceOnly(wpolicy, key, NewBin("bin", "value"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(written).To(BeFalse())
+
+				err = client.PutBins(wpolicy, key, NewBin("bin", "value"), NewBin("other", 1))
+				Expect(err).ToNot(HaveOccurred())
+
+				written, err = client.ReplaceOnly(wpolicy, key, NewBin("bin", "replaced"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(written).To(BeTrue())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"bin": "replaced"}))
+			})
+
+			It("must replace a record only if its generation did not change", func() {
+				err = client.PutBins(wpolicy, key, NewBin("bin", 1))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				generation := rec.Generation
+
+				written, err := client.ReplaceIfGeneration(wpolicy, key, generation, NewBin("bin", 2))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(written).To(BeTrue())
+
+				// the record was modified since it was read
+				written, err = client.ReplaceIfGeneration(wpolicy, key, generation, NewBin("bin", 3))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(written).To(BeFalse())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"bin": 2}))
+			})
+
+			It("must not modify the policy", func() {
+				policy := NewWritePolicy(0, 0)
+				_, err := client.PutIfAbsent(policy, key, NewBin("bin", 1))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(policy.RecordExistsAction).To(Equal(UPDATE))
+			})
+
+			It("must Update a record with optimistic locking", func() {
+				increment := func(rec *Record) (BinMap, error) {
+					if rec == nil {
+						return BinMap{"counter": 1}, nil
+					}
+					return BinMap{"counter": rec.Bins["counter"].(int) + 1}, nil
+				}
+
+				err = client.Update(wpolicy, key, 0, increment)
+				Expect(err).ToNot(HaveOccurred())
+				err = client.Update(wpolicy, key, 0, increment)
+				Expect(err).ToNot(HaveOccurred())
+
+				// the record is modified concurrently on the first attempt
+				attempts := 0
+				err = client.Update(wpolicy, key, 0, func(rec *Record) (BinMap, error) {
+					if attempts++; attempts == 1 {
+						Expect(client.PutBins(wpolicy, key, NewBin("counter", 10))).ToNot(HaveOccurred())
+					}
+					return increment(rec)
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(attempts).To(Equal(2))
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"counter": 11}))
+			})
+
+			It("must give up the Update after the maximum attempts", func() {
+				// always conflicts with the record read
+				conflict := func(rec *Record) (BinMap, error) {
+					Expect(client.PutBins(wpolicy, key, NewBin("bin", 1))).ToNot(HaveOccurred())
+					return BinMap{"bin": 2}, nil
+				}
+
+				// the record is created by fn before the single attempt
+				err = client.Update(wpolicy, key, 1, conflict)
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(KEY_EXISTS_ERROR))
+
+				// the record is modified by fn before every attempt
+				err = client.Update(wpolicy, key, 2, conflict)
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(GENERATION_ERROR))
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"bin": 1}))
+			})
+
+		})
+
+		Context("Consistency token operations", func() {
+
+			It("must read a version at least as fresh as the token", func() {
+				token, err := client.PutBinsWithToken(wpolicy, key, NewBin("bin", 1))
+				Expect(err).ToNot(HaveOccurred())
+
+				// the token is passed around as a string
+				token, err = ParseConsistencyToken(token.String())
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.GetWithToken(rpolicy, token, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"bin": 1}))
+				Expect(uint32(rec.Generation)).To(Equal(token.Generation))
+
+				rec, token, err = client.OperateWithToken(wpolicy, key, AddOp(NewBin("bin", 1)), GetOp())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(uint32(rec.Generation)).To(Equal(token.Generation))
+
+				rec, err = client.GetWithToken(rpolicy, token, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"bin": 2}))
+			})
+
+			It("must reject tokens of other keys", func() {
+				token, err := client.PutBinsWithToken(wpolicy, key, NewBin("bin", 1))
+				Expect(err).ToNot(HaveOccurred())
+
+				other, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = client.GetWithToken(rpolicy, token, other)
+				Expect(err).To(HaveOccurred())
+			})
+
+		})
+
+		Context("ApplyWrites operations", func() {
+
+			It("must apply the writes of each key in order", func() {
+				keys := make([]*Key, 20)
+				var writes []*PendingWrite
+				for i := range keys {
+					keys[i], err = NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+
+					writes = append(writes, NewPendingWrite(keys[i], PutOp(NewBin("counter", 1))))
+				}
+				for _, key := range keys {
+					writes = append(writes, NewPendingWrite(key, AddOp(NewBin("counter", 10))))
+					writes = append(writes, NewPendingWrite(key, PutOp(NewBin("last", "done"))))
+				}
+
+				errs, err := client.ApplyWrites(nil, 4, writes)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(errs)).To(Equal(len(writes)))
+				for _, err := range errs {
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				for _, key := range keys {
+					rec, err = client.Get(rpolicy, key)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(rec.Bins).To(Equal(BinMap{"counter": 11, "last": "done"}))
+				}
+			})
+
+		})
+
 		Context("Operate operations", func() {
 			bin1 := NewBin("Aerospike1", rand.Intn(math.MaxInt16))
 			bin2 := NewBin("Aerospike2", randString(100))
@@ -795,6 +1504,32 @@ var _ = Describe("Aerospike", func() {
 				// Expect(err).ToNot(HaveOccurred())
 			})
 
+			It("must apply operations only if the condition is true", func() {
+				err := client.PutBins(wpolicy, key, NewBin("flag", 1), NewBin("feature", "on"))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.OperateIf(nil, key, ExpBinExists("flag"), GetOpForBin("feature"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"feature": "on"}))
+
+				rec, err = client.OperateIf(nil, key, ExpEq(ExpIntBin("flag"), ExpIntVal(1)), GetOpForBin("feature"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"feature": "on"}))
+
+				rec, err = client.OperateIf(nil, key, ExpBinExists("missing"), GetOpForBin("feature"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec).To(BeNil())
+
+				// filtered writes are not applied
+				rec, err = client.OperateIf(nil, key, ExpEq(ExpIntBin("flag"), ExpIntVal(2)), PutOp(NewBin("feature", "off")))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec).To(BeNil())
+
+				rec, err = client.Get(rpolicy, key, "feature")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"feature": "on"}))
+			})
+
 			It("must send key on Put operations", func() {
 				key, err := NewKey(ns, set, randString(50))
 				Expect(err).ToNot(HaveOccurred())
@@ -866,6 +1601,79 @@ var _ = Describe("Aerospike", func() {
 				}
 			})
 
+			It("must store and verify the key hash when SendKeyHash is set", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				wpolicy := NewWritePolicy(0, 0)
+				wpolicy.SendKeyHash = true
+				_, err = client.Operate(wpolicy, key, PutOp(bin1))
+				Expect(err).ToNot(HaveOccurred())
+
+				// the hidden bin is not returned on reads
+				rpolicy := NewPolicy()
+				rpolicy.VerifyKeyHash = true
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{bin1.Name: bin1.Value.GetObject()}))
+
+				// but it is returned on scans
+				recordset, err := client.ScanAll(nil, key.Namespace(), key.SetName())
+				Expect(err).ToNot(HaveOccurred())
+
+				for r := range recordset.Records {
+					if bytes.Equal(key.Digest(), r.Key.Digest()) {
+						Expect(r.Key.Value()).To(BeNil())
+						Expect(r.Bins[KeyHashBinName]).ToNot(BeNil())
+					}
+				}
+
+				// corrupt the hash
+				err = client.PutBins(nil, key, NewBin(KeyHashBinName, 0))
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = client.Get(rpolicy, key)
+				Expect(err).To(HaveOccurred())
+
+				_, err = client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("must return the result of each operation in order", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				lpolicy := NewListPolicy(ListOrderUnordered, ListWriteFlagsAddUnique|ListWriteFlagsNoFail)
+				ops := []*Operation{
+					PutOp(bin1),
+					ListAppendWithPolicyOp(lpolicy, "list", 1),
+					ListAppendWithPolicyOp(lpolicy, "list", 1),
+					ListAppendWithPolicyOp(lpolicy, "list", 2),
+					GetOpForBin(bin1.Name),
+				}
+				rec, results, err := client.OperateWithResults(nil, key, ops...)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins[bin1.Name]).To(Equal([]interface{}{nil, bin1.Value.GetObject()}))
+
+				Expect(len(results)).To(Equal(len(ops)))
+				for i := range results {
+					Expect(results[i].Operation).To(Equal(ops[i]))
+				}
+				Expect(results[0].Value).To(BeNil())
+				Expect(results[1].Value).To(Equal(1))
+				// skipped by the server; list size didn't change
+				Expect(results[2].Value).To(Equal(1))
+				Expect(results[3].Value).To(Equal(2))
+				Expect(results[4].Value).To(Equal(bin1.Value.GetObject()))
+
+				Expect(results.ForBin("list")).To(Equal([]interface{}{1, 1, 2}))
+				Expect(results.ForBin(bin1.Name)).To(Equal([]interface{}{nil, bin1.Value.GetObject()}))
+				Expect(results.Values()).To(Equal([]interface{}{nil, 1, 1, 2, bin1.Value.GetObject()}))
+
+				_, _, err = client.OperateWithResults(nil, key, GetOp())
+				Expect(err).To(HaveOccurred())
+			})
+
 			It("must apply all operations, and result should match expectation", func() {
 				key, err := NewKey(ns, set, randString(50))
 				Expect(err).ToNot(HaveOccurred())
@@ -17,6 +17,7 @@ package aerospike
 import (
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -41,13 +42,28 @@ type Cluster struct {
 	// Hints for best node for a partition
 	partitionWriteMap map[string][]*Node
 
+	// Hints for replicated (prole) nodes of a partition.
+	// Only populated if ClientPolicy.RequestProleReplicas is set.
+	partitionProleMap map[string][]*Node
+
+	// Round-robin index for distributing reads across replicas.
+	replicaIndex *AtomicInt
+
 	// Random node index.
 	nodeIndex *AtomicInt
 
+	// Number of times the cluster was tended.
+	tendCount *AtomicInt
+
 	clientPolicy ClientPolicy
 
-	mutex       sync.RWMutex
-	wgTend      sync.WaitGroup
+	mutex  sync.RWMutex
+	wgTend sync.WaitGroup
+
+	// Held while tending and reconfiguring the cluster, so that the nodes
+	// added by the tend goroutine use the current clientPolicy.
+	tendMutex sync.Mutex
+
 	tendChannel chan struct{}
 	closed      AtomicBool
 
@@ -56,6 +72,29 @@ type Cluster struct {
 
 	// Password in hashed format in bytes.
 	password []byte
+
+	// Slots of concurrently running scans and queries.
+	// Only set if ClientPolicy.MaxConcurrentScans is set.
+	scanSlots chan struct{}
+
+	// Pool of the buffers of database commands.
+	bufferPool *SyncBufferPool
+
+	// Incremented by each reconfiguration; connections opened before are replaced
+	// one per node every drainInterval nanoseconds.
+	configGeneration *AtomicInt
+	drainInterval    *AtomicInt
+
+	// Counts the keys of single record commands.
+	// Only set if MetricsPolicy.HotKeyInterval is set.
+	hotKeys *hotKeySampler
+
+	// Decompresses the responses of scans and queries.
+	// Only set if ClientPolicy.DecompressionWorkers is set.
+	inflatePool *inflatePool
+
+	// Number of commands in progress, waited for by Client.CloseGracefully.
+	commandsInProgress AtomicInt
 }
 
 // NewCluster generates a Cluster instance.
@@ -66,9 +105,31 @@ func NewCluster(policy *ClientPolicy, hosts []*Host) (*Cluster, error) {
 		aliases:           make(map[Host]*Node),
 		nodes:             []*Node{},
 		partitionWriteMap: make(map[string][]*Node),
+		partitionProleMap: make(map[string][]*Node),
 		nodeIndex:         NewAtomicInt(0),
+		replicaIndex:      NewAtomicInt(0),
+		tendCount:         NewAtomicInt(0),
 		tendChannel:       make(chan struct{}),
+		configGeneration:  NewAtomicInt(0),
+		drainInterval:     NewAtomicInt(int(policy.ConnectionDrainInterval)),
+	}
+
+	if policy.MaxConcurrentScans > 0 {
+		newCluster.scanSlots = make(chan struct{}, policy.MaxConcurrentScans)
+	}
+
+	if policy.DecompressionWorkers > 0 {
+		newCluster.inflatePool = newInflatePool(policy.DecompressionWorkers)
+	}
+
+	initBufSize, maxBufSize := policy.InitialBufferSize, policy.MaxPooledBufferSize
+	if initBufSize <= 0 {
+		initBufSize = 16 * 1024
+	}
+	if maxBufSize <= 0 {
+		maxBufSize = 128 * 1024
 	}
+	newCluster.bufferPool = NewSyncBufferPool(initBufSize, maxBufSize)
 
 	// setup auth info for cluster
 	var err error
@@ -79,19 +140,27 @@ func NewCluster(policy *ClientPolicy, hosts []*Host) (*Cluster, error) {
 		}
 	}
 
-	// try to seed connections for first use
-	newCluster.waitTillStabilized()
+	if !policy.LazyConnect {
+		// try to seed connections for first use
+		newCluster.waitTillStabilized()
 
-	// apply policy rules
-	if policy.FailIfNotConnected && !newCluster.IsConnected() {
-		return nil, fmt.Errorf("Failed to connect to host(s): %v", hosts)
+		// apply policy rules
+		if policy.FailIfNotConnected && !newCluster.IsConnected() {
+			return nil, fmt.Errorf("Failed to connect to host(s): %v", hosts)
+		}
 	}
 
 	// start up cluster maintenance go routine
 	newCluster.wgTend.Add(1)
 	go newCluster.clusterBoss(policy)
 
-	Logger.Debug("New cluster initialized and ready to be used...")
+	if mp := policy.MetricsPolicy; mp != nil && mp.HotKeyInterval > 0 {
+		newCluster.hotKeys = newHotKeySampler(mp)
+		newCluster.wgTend.Add(1)
+		go newCluster.reportHotKeys(mp.HotKeyInterval)
+	}
+
+	newCluster.logEvent(DEBUG, SUBSYSTEM_CLUSTER, "", nil, "New cluster initialized and ready to be used...")
 	return newCluster, nil
 }
 
@@ -105,6 +174,13 @@ func (clstr *Cluster) clusterBoss(policy *ClientPolicy) {
 		tendInterval = 10 * time.Millisecond
 	}
 
+	// lazily connected clusters are seeded in the background
+	if policy.LazyConnect {
+		if err := clstr.tend(); err != nil {
+			clstr.logEvent(WARNING, SUBSYSTEM_CLUSTER, "", nil, "%s", err)
+		}
+	}
+
 Loop:
 	for {
 		select {
@@ -113,7 +189,7 @@ Loop:
 			break Loop
 		case <-time.After(tendInterval):
 			if err := clstr.tend(); err != nil {
-				Logger.Warn(err.Error())
+				clstr.logEvent(WARNING, SUBSYSTEM_CLUSTER, "", nil, "%s", err)
 			}
 		}
 	}
@@ -128,6 +204,123 @@ Loop:
 	}
 }
 
+// ClusterStats is a snapshot of the statistics of a cluster and its nodes.
+// It can be serialized to JSON.
+type ClusterStats struct {
+	// Build describes the client library and the Go runtime.
+	Build BuildInfo `json:"build"`
+
+	// TendCount is the number of times the cluster was tended.
+	TendCount int `json:"tend_count"`
+
+	// Connections is the number of open connections to all nodes.
+	Connections int `json:"connections"`
+
+	// Nodes are the statistics of the active nodes, by node name.
+	Nodes map[string]NodeStats `json:"nodes"`
+}
+
+// Stats returns a snapshot of the statistics of the cluster and its nodes.
+func (clstr *Cluster) Stats() *ClusterStats {
+	nodes := clstr.GetNodes()
+
+	res := &ClusterStats{
+		Build:     GetBuildInfo(),
+		TendCount: clstr.tendCount.Get(),
+		Nodes:     make(map[string]NodeStats, len(nodes)),
+	}
+
+	for _, node := range nodes {
+		stats := node.Stats()
+		res.Connections += stats.Connections
+		res.Nodes[stats.Name] = stats
+	}
+
+	return res
+}
+
+// ResetStats returns a snapshot of the statistics of the cluster and its nodes
+// like Stats, and resets their counters and distributions. See Node.ResetStats.
+func (clstr *Cluster) ResetStats() *ClusterStats {
+	nodes := clstr.GetNodes()
+
+	res := &ClusterStats{
+		Build:     GetBuildInfo(),
+		TendCount: clstr.tendCount.GetAndSet(0),
+		Nodes:     make(map[string]NodeStats, len(nodes)),
+	}
+
+	for _, node := range nodes {
+		stats := node.ResetStats()
+		res.Connections += stats.Connections
+		res.Nodes[stats.Name] = stats
+	}
+
+	return res
+}
+
+// Diff returns the statistics accumulated since the prev snapshot, so that
+// periodic reporters can compute rates without keeping the previous values
+// of each counter. Nodes missing from prev, e.g. new nodes, are returned as is.
+// Connections are the current values.
+func (cs *ClusterStats) Diff(prev *ClusterStats) *ClusterStats {
+	res := &ClusterStats{
+		Build:       cs.Build,
+		TendCount:   cs.TendCount,
+		Connections: cs.Connections,
+		Nodes:       make(map[string]NodeStats, len(cs.Nodes)),
+	}
+	if prev != nil {
+		res.TendCount -= prev.TendCount
+	}
+
+	for name, stats := range cs.Nodes {
+		if prev != nil {
+			if prevStats, exists := prev.Nodes[name]; exists {
+				stats = stats.Diff(prevStats)
+			}
+		}
+		res.Nodes[name] = stats
+	}
+
+	return res
+}
+
+// logEnabled returns true if the events of the level are logged, either by
+// ClientPolicy.Logger or by the global Logger. Check it before building the
+// fields and arguments of the events of frequent debug messages.
+func (clstr *Cluster) logEnabled(level LogPriority) bool {
+	if clstr.clientPolicy.Logger == nil {
+		return Logger.Enabled(level)
+	}
+	if enabler, ok := clstr.clientPolicy.Logger.(LevelEnabler); ok {
+		return enabler.Enabled(level)
+	}
+	return true
+}
+
+// logEvent logs a structured event to ClientPolicy.Logger if set,
+// and to the global Logger otherwise.
+func (clstr *Cluster) logEvent(level LogPriority, subsystem string, node string, fields map[string]interface{}, format string, v ...interface{}) {
+	if !clstr.logEnabled(level) {
+		return
+	}
+	if clstr.clientPolicy.Logger == nil {
+		Logger.LogEvent(level, subsystem, node, fields, format, v...)
+		return
+	}
+
+	evt := &Event{
+		Time:      time.Now(),
+		Level:     level,
+		Subsystem: subsystem,
+		Node:      node,
+		Message:   fmt.Sprintf(format, v...),
+		Fields:    fields,
+	}
+	evt.LogTo(clstr.clientPolicy.Logger)
+}
+
 // AddSeeds adds new hosts to the cluster.
 // They will be added to the cluster on next tend call.
 func (clstr *Cluster) AddSeeds(hosts []*Host) {
@@ -145,12 +338,17 @@ func (clstr *Cluster) getSeeds() []*Host {
 
 // Updates cluster state
 func (clstr *Cluster) tend() error {
+	clstr.tendMutex.Lock()
+	defer clstr.tendMutex.Unlock()
+
+	clstr.tendCount.IncrementAndGet()
+
 	nodes := clstr.GetNodes()
 
 	// All node additions/deletions are performed in tend goroutine.
 	// If active nodes don't exist, seed cluster.
 	if len(nodes) == 0 {
-		Logger.Info("No connections available; seeding...")
+		clstr.logEvent(INFO, SUBSYSTEM_CLUSTER, "", nil, "No connections available; seeding...")
 		clstr.seedNodes()
 
 		// refresh nodes list after seeding
@@ -167,9 +365,22 @@ func (clstr *Cluster) tend() error {
 		node.responded = false
 
 		if node.IsActive() {
+			// the node failed repeatedly, and is not due to be refreshed yet
+			if node.skipRefreshes > 0 {
+				node.skipRefreshes--
+				continue
+			}
+
+			node.tendCount.IncrementAndGet()
 			if friends, err := node.Refresh(); err != nil {
-				Logger.Warn("Node `%s` refresh failed: %s", node, err)
+				node.tendErrors.IncrementAndGet()
+				if backoff := clstr.backOffRefresh(node); backoff > 0 {
+					clstr.logEvent(WARNING, SUBSYSTEM_CLUSTER, node.GetName(), map[string]interface{}{"address": node.GetHost().String(), "error": err, "failures": node.refreshFailures, "backoff": backoff}, "refresh failed: %s; next refresh in %s", err, backoff)
+				} else {
+					clstr.logEvent(WARNING, SUBSYSTEM_CLUSTER, node.GetName(), map[string]interface{}{"address": node.GetHost().String(), "error": err}, "refresh failed: %s", err)
+				}
 			} else {
+				node.refreshFailures = 0
 				refreshCount++
 				if friends != nil {
 					friendList = append(friendList, friends...)
@@ -190,10 +401,41 @@ func (clstr *Cluster) tend() error {
 		clstr.removeNodes(removeList)
 	}
 
-	Logger.Info("Tend finished. Live node count: %d", len(clstr.GetNodes()))
+	clstr.logEvent(INFO, SUBSYSTEM_CLUSTER, "", nil, "Tend finished. Live node count: %d", len(clstr.GetNodes()))
 	return nil
 }
 
+// backOffRefresh records a failed refresh of the node and, if ClientPolicy.MaxRefreshBackoff
+// is set, postpones its next refresh. The delay starts with the tend interval and doubles
+// with each consecutive failure, up to MaxRefreshBackoff; the node is skipped by the tends
+// in between. It returns the delay.
+func (clstr *Cluster) backOffRefresh(node *Node) time.Duration {
+	node.refreshFailures++
+
+	max := clstr.clientPolicy.MaxRefreshBackoff
+	if max <= 0 {
+		return 0
+	}
+
+	interval := clstr.clientPolicy.TendInterval
+	if interval <= 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+
+	backoff := interval
+	for i := 1; i < node.refreshFailures && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+
+	if node.skipRefreshes = int(backoff/interval) - 1; node.skipRefreshes < 0 {
+		node.skipRefreshes = 0
+	}
+	return backoff
+}
+
 // Tend the cluster until it has stabilized and return control.
 // This helps avoid initial database request timeout issues when
 // a large number of threads are initiated at client startup.
@@ -210,7 +452,7 @@ func (clstr *Cluster) waitTillStabilized() {
 	go func() {
 		for {
 			if err := clstr.tend(); err != nil {
-				Logger.Warn(err.Error())
+				clstr.logEvent(WARNING, SUBSYSTEM_CLUSTER, "", nil, "%s", err)
 			}
 
 			// Check to see if cluster has changed since the last Tend().
@@ -256,39 +498,66 @@ func (clstr *Cluster) getPartitions() map[string][]*Node {
 	return res
 }
 
+func (clstr *Cluster) setProlePartitions(partMap map[string][]*Node) {
+	clstr.mutex.Lock()
+	clstr.partitionProleMap = partMap
+	clstr.mutex.Unlock()
+}
+
+func (clstr *Cluster) getProlePartitions() map[string][]*Node {
+	clstr.mutex.RLock()
+	res := clstr.partitionProleMap
+	clstr.mutex.RUnlock()
+	return res
+}
+
 func (clstr *Cluster) updatePartitions(conn *Connection, node *Node) error {
-	// TODO: Cluster should not care about version of tokenizer
-	// decouple clstr interface
-	var nmap map[string][]*Node
-	if node.useNewInfo {
-		Logger.Info("Updating partitions using new protocol...")
-		tokens, err := newPartitionTokenizerNew(conn)
-		if err != nil {
-			return err
-		}
-		nmap, err = tokens.UpdatePartition(clstr.getPartitions(), node)
+	nmap, err := clstr.parsePartitions(conn, node, replicasName, clstr.getPartitions())
+	if err != nil {
+		return err
+	}
+
+	// update partition write map
+	if nmap != nil {
+		clstr.setPartitions(nmap)
+	}
+
+	if clstr.clientPolicy.RequestProleReplicas {
+		pmap, err := clstr.parsePartitions(conn, node, replicasProleName, clstr.getProlePartitions())
 		if err != nil {
 			return err
 		}
-	} else {
-		Logger.Info("Updating partitions using old protocol...")
-		tokens, err := newPartitionTokenizerOld(conn)
-		if err != nil {
-			return err
+
+		// update partition prole map
+		if pmap != nil {
+			clstr.setProlePartitions(pmap)
 		}
-		nmap, err = tokens.UpdatePartition(clstr.getPartitions(), node)
+	}
+
+	clstr.logEvent(INFO, SUBSYSTEM_CLUSTER, "", nil, "Partitions updated...")
+	return nil
+}
+
+// parsePartitions requests the partitions of the node for the specified replica
+// info command, and merges them into the partition map.
+func (clstr *Cluster) parsePartitions(conn *Connection, node *Node, replicasName string, pmap map[string][]*Node) (map[string][]*Node, error) {
+	// TODO: Cluster should not care about version of tokenizer
+	// decouple clstr interface
+	if node.useNewInfo {
+		clstr.logEvent(INFO, SUBSYSTEM_CLUSTER, "", nil, "Updating partitions using new protocol...")
+		tokens, err := newPartitionTokenizerNew(conn, replicasName)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		return tokens.UpdatePartition(pmap, node)
 	}
 
-	// update partition write map
-	if nmap != nil {
-		clstr.setPartitions(nmap)
+	clstr.logEvent(INFO, SUBSYSTEM_CLUSTER, "", nil, "Updating partitions using old protocol...")
+	tokens, err := newPartitionTokenizerOld(conn, replicasName)
+	if err != nil {
+		return nil, err
 	}
-
-	Logger.Info("Partitions updated...")
-	return nil
+	return tokens.UpdatePartition(pmap, node)
 }
 
 // Adds seeds to the cluster
@@ -296,17 +565,19 @@ func (clstr *Cluster) seedNodes() {
 	// Must copy array reference for copy on write semantics to work.
 	seedArray := clstr.getSeeds()
 
-	Logger.Info("Seeding the cluster. Seeds count: %d", len(seedArray))
+	clstr.logEvent(INFO, SUBSYSTEM_CLUSTER, "", nil, "Seeding the cluster. Seeds count: %d", len(seedArray))
 
 	// Add all nodes at once to avoid copying entire array multiple times.
 	list := []*Node{}
+	seedLatency := make(map[*Host]time.Duration, len(seedArray))
 
 	for _, seed := range seedArray {
 		seedNodeValidator, err := newNodeValidator(clstr, seed, clstr.clientPolicy.Timeout)
 		if err != nil {
-			Logger.Warn("Seed %s failed: %s", seed.String(), err.Error())
+			clstr.logEvent(WARNING, SUBSYSTEM_CLUSTER, "", map[string]interface{}{"address": seed.String(), "error": err}, "Seed %s failed: %s", seed, err)
 			continue
 		}
+		seedLatency[seed] = seedNodeValidator.latency
 
 		var nv *nodeValidator
 		// Seed host may have multiple aliases in the case of round-robin dns configurations.
@@ -317,7 +588,7 @@ func (clstr *Cluster) seedNodes() {
 			} else {
 				nv, err = newNodeValidator(clstr, alias, clstr.clientPolicy.Timeout)
 				if err != nil {
-					Logger.Warn("Seed %s failed: %s", seed.String(), err.Error())
+					clstr.logEvent(WARNING, SUBSYSTEM_CLUSTER, "", map[string]interface{}{"address": alias.String(), "error": err}, "Seed %s failed: %s", seed, err)
 					continue
 				}
 			}
@@ -330,11 +601,39 @@ func (clstr *Cluster) seedNodes() {
 		}
 	}
 
+	if clstr.clientPolicy.LatencyAwareSeeds {
+		// closest seeds will be tried first on the next seeding,
+		// and closest nodes will be tended first
+		clstr.sortSeeds(seedLatency)
+		sort.Stable(nodesByLatency(list))
+	}
+
 	if len(list) > 0 {
 		clstr.addNodesCopy(list)
 	}
 }
 
+// Sorts seeds by their measured latency. Unreachable seeds are moved to the end.
+func (clstr *Cluster) sortSeeds(latency map[*Host]time.Duration) {
+	clstr.mutex.Lock()
+	defer clstr.mutex.Unlock()
+
+	// Must copy array for copy on write semantics to work.
+	seeds := make([]*Host, len(clstr.seeds))
+	copy(seeds, clstr.seeds)
+	sort.Stable(&seedsByLatency{seeds: seeds, latency: latency})
+	clstr.seeds = seeds
+
+	if !clstr.logEnabled(DEBUG) {
+		return
+	}
+	for _, seed := range seeds {
+		if l, exists := latency[seed]; exists {
+			clstr.logEvent(DEBUG, SUBSYSTEM_CLUSTER, "", nil, "Seed %s latency: %s", seed.String(), l)
+		}
+	}
+}
+
 // Finds a node by name in a list of nodes
 func (clstr *Cluster) findNodeName(list []*Node, name string) bool {
 	for _, node := range list {
@@ -366,7 +665,7 @@ func (clstr *Cluster) findNodesToAdd(hosts []*Host) []*Node {
 
 	for _, host := range hosts {
 		if nv, err := newNodeValidator(clstr, host, clstr.clientPolicy.Timeout); err != nil {
-			Logger.Warn("Add node %s failed: %s", err.Error())
+			clstr.logEvent(WARNING, SUBSYSTEM_CLUSTER, "", map[string]interface{}{"address": host.String(), "error": err}, "Add node %s failed: %s", host, err)
 		} else {
 			node := clstr.findNodeByName(nv.name)
 			// make sure node is not already in the list to add
@@ -482,6 +781,10 @@ func (clstr *Cluster) addAliases(node *Node) {
 }
 
 func (clstr *Cluster) addNodesCopy(nodesToAdd []*Node) {
+	for _, node := range nodesToAdd {
+		clstr.logEvent(INFO, SUBSYSTEM_CLUSTER, node.GetName(), map[string]interface{}{"address": node.GetHost().String()}, "Added node")
+	}
+
 	clstr.mutex.Lock()
 	clstr.nodes = append(clstr.nodes, nodesToAdd...)
 	clstr.mutex.Unlock()
@@ -497,7 +800,7 @@ func (clstr *Cluster) removeNodes(nodesToRemove []*Node) {
 		// Remove node's aliases from cluster alias set.
 		// Aliases are only used in tend goroutine, so synchronization is not necessary.
 		for _, alias := range node.GetAliases() {
-			Logger.Debug("Removing alias ", alias)
+			clstr.logEvent(DEBUG, SUBSYSTEM_CLUSTER, "", nil, "Removing alias %s", alias)
 			clstr.removeAlias(alias)
 		}
 		go node.Close()
@@ -526,7 +829,7 @@ func (clstr *Cluster) removeNodesCopy(nodesToRemove []*Node) {
 	// Add nodes that are not in remove list.
 	for _, node := range nodes {
 		if clstr.nodeExists(node, nodesToRemove) {
-			Logger.Info("Removed node `%s`", node)
+			clstr.logEvent(INFO, SUBSYSTEM_CLUSTER, node.GetName(), map[string]interface{}{"address": node.GetHost().String()}, "Removed node")
 		} else {
 			nodeArray[count] = node
 			count++
@@ -535,7 +838,7 @@ func (clstr *Cluster) removeNodesCopy(nodesToRemove []*Node) {
 
 	// Do sanity check to make sure assumptions are correct.
 	if count < len(nodeArray) {
-		Logger.Warn(fmt.Sprintf("Node remove mismatch. Expected %d, Received %d", len(nodeArray), count))
+		clstr.logEvent(WARNING, SUBSYSTEM_CLUSTER, "", nil, "Node remove mismatch. Expected %d, Received %d", len(nodeArray), count)
 
 		// Resize array.
 		nodeArray2 := make([]*Node, count)
@@ -576,8 +879,58 @@ func (clstr *Cluster) GetNode(partition *Partition) (*Node, error) {
 	return clstr.GetRandomNode()
 }
 
-// GetRandomNode returns a random node on the cluster
+// getReadNode returns the node to read the partition from,
+// according to the replica policy.
+func (clstr *Cluster) getReadNode(partition *Partition, replica ReplicaPolicy) (*Node, error) {
+	switch replica {
+	case MASTER_PROLES:
+		if clstr.replicaIndex.IncrementAndGet()%2 == 0 {
+			return clstr.getProleNode(partition)
+		}
+	case PROLES:
+		return clstr.getProleNode(partition)
+	}
+	return clstr.GetNode(partition)
+}
+
+// getScanNode returns the node to scan the partition from, according to the
+// replica policy. Unlike getReadNode, the choice only depends on the partition,
+// so that the partitions of a scan are spread evenly between the replicas.
+func (clstr *Cluster) getScanNode(partition *Partition, replica ReplicaPolicy) (*Node, error) {
+	switch replica {
+	case MASTER_PROLES:
+		if partition.PartitionId%2 == 1 {
+			return clstr.getProleNode(partition)
+		}
+	case PROLES:
+		return clstr.getProleNode(partition)
+	}
+	return clstr.GetNode(partition)
+}
+
+// getProleNode returns the prole node of the partition, or its master
+// if the prole is unknown or inactive.
+func (clstr *Cluster) getProleNode(partition *Partition) (*Node, error) {
+	// Must copy hashmap reference for copy on write semantics to work.
+	pmap := clstr.getProlePartitions()
+	if nodeArray, exists := pmap[partition.Namespace]; exists {
+		node := nodeArray[partition.PartitionId]
+
+		if node != nil && node.IsActive() {
+			return node, nil
+		}
+	}
+	return clstr.GetNode(partition)
+}
+
+// GetRandomNode returns a random node on the cluster.
+// If ClientPolicy.PreferClosestNode is set, the active node with
+// the lowest latency is returned instead.
 func (clstr *Cluster) GetRandomNode() (*Node, error) {
+	if clstr.clientPolicy.PreferClosestNode {
+		return clstr.GetClosestNode()
+	}
+
 	// Must copy array reference for copy on write semantics to work.
 	nodeArray := clstr.GetNodes()
 	length := len(nodeArray)
@@ -594,6 +947,24 @@ func (clstr *Cluster) GetRandomNode() (*Node, error) {
 	return nil, NewAerospikeError(INVALID_NODE_ERROR)
 }
 
+// GetClosestNode returns the active node with the lowest measured latency.
+func (clstr *Cluster) GetClosestNode() (*Node, error) {
+	// Must copy array reference for copy on write semantics to work.
+	nodeArray := clstr.GetNodes()
+
+	var closest *Node
+	for _, node := range nodeArray {
+		if node.IsActive() && (closest == nil || node.GetLatency() < closest.GetLatency()) {
+			closest = node
+		}
+	}
+
+	if closest == nil {
+		return nil, NewAerospikeError(INVALID_NODE_ERROR)
+	}
+	return closest, nil
+}
+
 // GetNodes returns a list of all nodes in the cluster
 func (clstr *Cluster) GetNodes() []*Node {
 	clstr.mutex.RLock()
@@ -635,6 +1006,10 @@ func (clstr *Cluster) Close() {
 
 		// wait until tend is over
 		clstr.wgTend.Wait()
+
+		if clstr.inflatePool != nil {
+			clstr.inflatePool.close()
+		}
 	}
 }
 
@@ -674,6 +1049,31 @@ func (clstr *Cluster) MigrationInProgress(timeout time.Duration) (res bool, err
 	}
 }
 
+// acquireScanSlot reserves a slot for a concurrent scan or query, waiting up to
+// ClientPolicy.ScanQueueTimeout for one to be released. The returned function
+// releases the slot.
+func (clstr *Cluster) acquireScanSlot() (func(), error) {
+	if clstr.scanSlots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case clstr.scanSlots <- struct{}{}:
+	default:
+		if clstr.clientPolicy.ScanQueueTimeout <= 0 {
+			return nil, NewAerospikeError(COMMAND_REJECTED, "Maximum number of concurrent scans and queries reached.")
+		}
+
+		select {
+		case clstr.scanSlots <- struct{}{}:
+		case <-time.After(clstr.clientPolicy.ScanQueueTimeout):
+			return nil, NewAerospikeError(COMMAND_REJECTED, "Timeout waiting for a concurrent scan or query to finish.")
+		}
+	}
+
+	return func() { <-clstr.scanSlots }, nil
+}
+
 // WaitUntillMigrationIsFinished will block until all
 // migration operations in the cluster all finished.
 func (clstr *Cluster) WaitUntillMigrationIsFinished(timeout time.Duration) (err error) {
@@ -703,8 +1103,86 @@ func (clstr *Cluster) WaitUntillMigrationIsFinished(timeout time.Duration) (err
 }
 
 func (clstr *Cluster) changePassword(user string, password []byte) {
+	clstr.mutex.Lock()
+	defer clstr.mutex.Unlock()
+
 	// change password ONLY if the user is the same
 	if clstr.user == user {
 		clstr.password = password
 	}
 }
+
+// getCredentials returns the user and hashed password new connections authenticate with.
+func (clstr *Cluster) getCredentials() (string, []byte) {
+	clstr.mutex.RLock()
+	defer clstr.mutex.RUnlock()
+
+	return clstr.user, clstr.password
+}
+
+// reconfigure applies the connection pool size, drain interval and credentials of the
+// policy. The connections opened before are replaced gradually as they are used.
+func (clstr *Cluster) reconfigure(policy *ClientPolicy) error {
+	if policy.ConnectionQueueSize <= 0 {
+		return NewAerospikeError(PARAMETER_ERROR, "ConnectionQueueSize must be positive")
+	}
+
+	var password []byte
+	if policy.RequiresAuthentication() {
+		var err error
+		if password, err = hashPassword(policy.Password); err != nil {
+			return err
+		}
+	}
+
+	// the nodes added from now on read the pool size from the policy
+	clstr.tendMutex.Lock()
+	defer clstr.tendMutex.Unlock()
+
+	clstr.clientPolicy.ConnectionQueueSize = policy.ConnectionQueueSize
+	clstr.clientPolicy.ConnectionDrainInterval = policy.ConnectionDrainInterval
+	clstr.clientPolicy.User = policy.User
+	clstr.clientPolicy.Password = policy.Password
+
+	clstr.mutex.Lock()
+	clstr.user = policy.User
+	clstr.password = password
+	clstr.mutex.Unlock()
+
+	clstr.drainInterval.Set(int(policy.ConnectionDrainInterval))
+
+	// mark the existing connections as stale once the new credentials are in place
+	clstr.configGeneration.IncrementAndGet()
+
+	for _, node := range clstr.GetNodes() {
+		node.resizeConnectionPool(policy.ConnectionQueueSize)
+	}
+
+	clstr.logEvent(INFO, SUBSYSTEM_CLUSTER, "", map[string]interface{}{"connection_queue_size": policy.ConnectionQueueSize, "drain_interval": policy.ConnectionDrainInterval}, "Cluster reconfigured")
+	return nil
+}
+
+// nodesByLatency sorts nodes by increasing latency.
+type nodesByLatency []*Node
+
+func (nodes nodesByLatency) Len() int           { return len(nodes) }
+func (nodes nodesByLatency) Swap(i, j int)      { nodes[i], nodes[j] = nodes[j], nodes[i] }
+func (nodes nodesByLatency) Less(i, j int) bool { return nodes[i].GetLatency() < nodes[j].GetLatency() }
+
+// seedsByLatency sorts seeds by increasing latency.
+// Seeds without a measured latency are considered the farthest.
+type seedsByLatency struct {
+	seeds   []*Host
+	latency map[*Host]time.Duration
+}
+
+func (sl *seedsByLatency) Len() int      { return len(sl.seeds) }
+func (sl *seedsByLatency) Swap(i, j int) { sl.seeds[i], sl.seeds[j] = sl.seeds[j], sl.seeds[i] }
+func (sl *seedsByLatency) Less(i, j int) bool {
+	li, iok := sl.latency[sl.seeds[i]]
+	lj, jok := sl.latency[sl.seeds[j]]
+	if !iok || !jok {
+		return iok && !jok
+	}
+	return li < lj
+}
@@ -0,0 +1,40 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import "time"
+
+// ClusterDownPolicy determines how single record commands behave
+// when no node of the cluster is available to send them to, e.g. while
+// the whole cluster is unreachable during a load balancer failover.
+type ClusterDownPolicy int
+
+const (
+	// CLUSTER_DOWN_RETRY treats the missing node like any other transient error:
+	// the command is retried up to MaxRetries times, sleeping SleepBetweenRetries
+	// in between, and then times out.
+	CLUSTER_DOWN_RETRY ClusterDownPolicy = iota
+
+	// CLUSTER_DOWN_FAIL fails the command immediately with an INVALID_NODE_ERROR.
+	CLUSTER_DOWN_FAIL
+
+	// CLUSTER_DOWN_WAIT holds the command for up to ClusterDownGracePeriod
+	// while the cluster is rediscovered by the tend goroutine. The wait does
+	// not count as a retry, but it is bounded by the command's Timeout.
+	CLUSTER_DOWN_WAIT
+)
+
+// interval at which waiting commands check if the cluster is back
+const _CLUSTER_DOWN_POLL_INTERVAL = 50 * time.Millisecond
@@ -0,0 +1,73 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+var _ = Describe("Cluster Down Policy Test", func() {
+
+	var cluster *Cluster
+	var key *Key
+
+	BeforeEach(func() {
+		// a cluster without any nodes, as during a full cluster outage
+		cluster = &Cluster{}
+
+		var err error
+		key, err = NewKey("test", "set", "key")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fail immediately when requested", func() {
+		policy := NewPolicy()
+		policy.ClusterDownPolicy = CLUSTER_DOWN_FAIL
+
+		start := time.Now()
+		err := newReadCommand(cluster, policy, key, nil).Execute()
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(INVALID_NODE_ERROR))
+		Expect(time.Since(start)).To(BeNumerically("<", policy.SleepBetweenRetries))
+	})
+
+	It("should wait for the grace period without retrying", func() {
+		policy := NewPolicy()
+		policy.ClusterDownPolicy = CLUSTER_DOWN_WAIT
+		policy.ClusterDownGracePeriod = 200 * time.Millisecond
+
+		start := time.Now()
+		err := newReadCommand(cluster, policy, key, nil).Execute()
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(INVALID_NODE_ERROR))
+		Expect(time.Since(start)).To(BeNumerically(">=", policy.ClusterDownGracePeriod))
+		Expect(time.Since(start)).To(BeNumerically("<", policy.SleepBetweenRetries))
+	})
+
+	It("should bound the wait by the command timeout", func() {
+		policy := NewPolicy()
+		policy.ClusterDownPolicy = CLUSTER_DOWN_WAIT
+		policy.ClusterDownGracePeriod = time.Minute
+		policy.Timeout = 100 * time.Millisecond
+
+		start := time.Now()
+		err := newReadCommand(cluster, policy, key, nil).Execute()
+		Expect(err).To(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+})
@@ -0,0 +1,45 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cluster Reconfigure Test", func() {
+
+	It("should size the pools of the nodes added afterwards", func() {
+		cluster := &Cluster{
+			clientPolicy:     *NewClientPolicy(),
+			configGeneration: NewAtomicInt(0),
+			drainInterval:    NewAtomicInt(0),
+		}
+
+		policy := NewClientPolicy()
+		policy.ConnectionQueueSize = 7
+		policy.ConnectionDrainInterval = time.Second
+		Expect(cluster.reconfigure(policy)).ToNot(HaveOccurred())
+
+		node := newNode(cluster, &nodeValidator{name: "A", aliases: []*Host{NewHost("127.0.0.1", 3000)}})
+		Expect(node.connectionQueueSize.Get()).To(Equal(7))
+		Expect(cluster.clientPolicy.ConnectionDrainInterval).To(Equal(time.Second))
+	})
+
+})
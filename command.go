@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -38,6 +39,9 @@ const (
 	// Involve all replicas in read operation.
 	_INFO1_CONSISTENCY_ALL = (1 << 6)
 
+	// Allow the server to compress the response.
+	_INFO1_COMPRESS_RESPONSE int = (1 << 7)
+
 	// Create or update record
 	_INFO2_WRITE int = (1 << 0)
 	// Fling a record into the belly of Moloch.
@@ -46,15 +50,19 @@ const (
 	_INFO2_GENERATION int = (1 << 2)
 	// Update if new generation >= old, good for restore.
 	_INFO2_GENERATION_GT int = (1 << 3)
-	// Create a duplicate on a generation collision.
-	_INFO2_GENERATION_DUP int = (1 << 4)
+	// Leave a tombstone if the command deletes the record (Enterprise only).
+	_INFO2_DURABLE_DELETE int = (1 << 4)
 	// Create only. Fail if record already exists.
 	_INFO2_CREATE_ONLY int = (1 << 5)
+	// Return a result for every operation.
+	_INFO2_RESPOND_ALL_OPS int = (1 << 7)
 
 	// This is the last of a multi-part message.
 	_INFO3_LAST int = (1 << 0)
 	// Commit to master only before declaring success.
 	_INFO3_COMMIT_MASTER int = (1 << 1)
+	// Partition is complete response in scan or query.
+	_INFO3_PARTITION_DONE int = (1 << 2)
 	// Update only. Merge bins.
 	_INFO3_UPDATE_ONLY int = (1 << 3)
 
@@ -70,6 +78,7 @@ const (
 	_DIGEST_SIZE               uint8 = 20
 	_CL_MSG_VERSION            int64 = 2
 	_AS_MSG_TYPE               int64 = 3
+	_AS_MSG_TYPE_COMPRESSED    int64 = 4
 )
 
 // command intrerface describes all commands available
@@ -94,14 +103,30 @@ type baseCommand struct {
 	node *Node
 	conn *Connection
 
+	// ctx aborts the creation of new connections when done; nil if not cancellable
+	ctx context.Context
+
 	dataBuffer []byte
 	dataOffset int
 }
 
+// context returns the context of the command, or a context which is never done.
+func (cmd *baseCommand) context() context.Context {
+	if cmd.ctx == nil {
+		return context.Background()
+	}
+	return cmd.ctx
+}
+
 // Writes the command for write operations
 func (cmd *baseCommand) setWrite(policy *WritePolicy, operation OperationType, key *Key, bins []*Bin) error {
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key, policy.SendKey)
+	packedExp, expFieldCount, err := cmd.estimateExpressionSize(policy.FilterExpression)
+	if err != nil {
+		return err
+	}
+	fieldCount += expFieldCount
 
 	for i := range bins {
 		cmd.estimateOperationSizeForBin(bins[i])
@@ -111,6 +136,7 @@ func (cmd *baseCommand) setWrite(policy *WritePolicy, operation OperationType, k
 	}
 	cmd.writeHeaderWithPolicy(policy, 0, _INFO2_WRITE, fieldCount, len(bins))
 	cmd.writeKey(key, policy.SendKey)
+	cmd.writeFilterExpression(packedExp)
 
 	for i := range bins {
 		if err := cmd.writeOperationForBin(bins[i], operation); err != nil {
@@ -126,11 +152,17 @@ func (cmd *baseCommand) setWrite(policy *WritePolicy, operation OperationType, k
 func (cmd *baseCommand) setDelete(policy *WritePolicy, key *Key) error {
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key, false)
+	packedExp, expFieldCount, err := cmd.estimateExpressionSize(policy.FilterExpression)
+	if err != nil {
+		return err
+	}
+	fieldCount += expFieldCount
 	if err := cmd.sizeBuffer(); err != nil {
 		return nil
 	}
 	cmd.writeHeaderWithPolicy(policy, 0, _INFO2_WRITE|_INFO2_DELETE, fieldCount, 0)
 	cmd.writeKey(key, false)
+	cmd.writeFilterExpression(packedExp)
 	cmd.end()
 	return nil
 
@@ -140,6 +172,11 @@ func (cmd *baseCommand) setDelete(policy *WritePolicy, key *Key) error {
 func (cmd *baseCommand) setTouch(policy *WritePolicy, key *Key) error {
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key, policy.SendKey)
+	packedExp, expFieldCount, err := cmd.estimateExpressionSize(policy.FilterExpression)
+	if err != nil {
+		return err
+	}
+	fieldCount += expFieldCount
 
 	cmd.estimateOperationSize()
 	if err := cmd.sizeBuffer(); err != nil {
@@ -147,6 +184,7 @@ func (cmd *baseCommand) setTouch(policy *WritePolicy, key *Key) error {
 	}
 	cmd.writeHeaderWithPolicy(policy, 0, _INFO2_WRITE, fieldCount, 1)
 	cmd.writeKey(key, policy.SendKey)
+	cmd.writeFilterExpression(packedExp)
 	cmd.writeOperationForOperationType(TOUCH)
 	cmd.end()
 	return nil
@@ -157,11 +195,17 @@ func (cmd *baseCommand) setTouch(policy *WritePolicy, key *Key) error {
 func (cmd *baseCommand) setExists(policy *BasePolicy, key *Key) error {
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key, false)
+	packedExp, expFieldCount, err := cmd.estimateExpressionSize(policy.FilterExpression)
+	if err != nil {
+		return err
+	}
+	fieldCount += expFieldCount
 	if err := cmd.sizeBuffer(); err != nil {
 		return nil
 	}
 	cmd.writeHeader(policy.GetBasePolicy(), _INFO1_READ|_INFO1_NOBINDATA, 0, fieldCount, 0)
 	cmd.writeKey(key, false)
+	cmd.writeFilterExpression(packedExp)
 	cmd.end()
 	return nil
 
@@ -171,11 +215,17 @@ func (cmd *baseCommand) setExists(policy *BasePolicy, key *Key) error {
 func (cmd *baseCommand) setReadForKeyOnly(policy *BasePolicy, key *Key) error {
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key, false)
+	packedExp, expFieldCount, err := cmd.estimateExpressionSize(policy.FilterExpression)
+	if err != nil {
+		return err
+	}
+	fieldCount += expFieldCount
 	if err := cmd.sizeBuffer(); err != nil {
 		return nil
 	}
 	cmd.writeHeader(policy, _INFO1_READ|_INFO1_GET_ALL, 0, fieldCount, 0)
 	cmd.writeKey(key, false)
+	cmd.writeFilterExpression(packedExp)
 	cmd.end()
 	return nil
 
@@ -186,6 +236,11 @@ func (cmd *baseCommand) setRead(policy *BasePolicy, key *Key, binNames []string)
 	if binNames != nil && len(binNames) > 0 {
 		cmd.begin()
 		fieldCount := cmd.estimateKeySize(key, false)
+		packedExp, expFieldCount, err := cmd.estimateExpressionSize(policy.FilterExpression)
+		if err != nil {
+			return err
+		}
+		fieldCount += expFieldCount
 
 		for i := range binNames {
 			cmd.estimateOperationSizeForBinName(binNames[i])
@@ -195,6 +250,7 @@ func (cmd *baseCommand) setRead(policy *BasePolicy, key *Key, binNames []string)
 		}
 		cmd.writeHeader(policy.GetBasePolicy(), _INFO1_READ, 0, fieldCount, len(binNames))
 		cmd.writeKey(key, false)
+		cmd.writeFilterExpression(packedExp)
 
 		for i := range binNames {
 			cmd.writeOperationForBinName(binNames[i], READ)
@@ -211,6 +267,11 @@ func (cmd *baseCommand) setRead(policy *BasePolicy, key *Key, binNames []string)
 func (cmd *baseCommand) setReadHeader(policy *BasePolicy, key *Key) error {
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key, false)
+	packedExp, expFieldCount, err := cmd.estimateExpressionSize(policy.FilterExpression)
+	if err != nil {
+		return err
+	}
+	fieldCount += expFieldCount
 	cmd.estimateOperationSizeForBinName("")
 	if err := cmd.sizeBuffer(); err != nil {
 		return nil
@@ -219,14 +280,16 @@ func (cmd *baseCommand) setReadHeader(policy *BasePolicy, key *Key) error {
 	cmd.writeHeader(policy.GetBasePolicy(), _INFO1_READ|_INFO1_NOBINDATA, 0, fieldCount, 1)
 
 	cmd.writeKey(key, false)
+	cmd.writeFilterExpression(packedExp)
 	cmd.writeOperationForBinName("", READ)
 	cmd.end()
 	return nil
 
 }
 
-// Implements different command operations
-func (cmd *baseCommand) setOperate(policy *WritePolicy, key *Key, operations []*Operation) error {
+// Implements different command operations.
+// If respondAllOps is set, the server returns a result for every operation.
+func (cmd *baseCommand) setOperate(policy *WritePolicy, key *Key, operations []*Operation, respondAllOps bool) error {
 	cmd.begin()
 	fieldCount := 0
 	readAttr := 0
@@ -249,13 +312,29 @@ func (cmd *baseCommand) setOperate(policy *WritePolicy, key *Key, operations []*
 				readAttr |= _INFO1_READ
 				readHeader = true
 			}
+		case CDT_READ, BIT_READ, HLL_READ:
+			readAttr |= _INFO1_READ
+			readBin = true
 		default:
 			writeAttr = _INFO2_WRITE
 		}
+
+		if operations[i].respondAllOps {
+			respondAllOps = true
+		}
 		cmd.estimateOperationSizeForOperation(operations[i])
 	}
 
+	if respondAllOps {
+		writeAttr |= _INFO2_RESPOND_ALL_OPS
+	}
+
 	fieldCount = cmd.estimateKeySize(key, policy.SendKey && writeAttr != 0)
+	packedExp, expFieldCount, err := cmd.estimateExpressionSize(policy.FilterExpression)
+	if err != nil {
+		return err
+	}
+	fieldCount += expFieldCount
 
 	if err := cmd.sizeBuffer(); err != nil {
 		return nil
@@ -271,6 +350,7 @@ func (cmd *baseCommand) setOperate(policy *WritePolicy, key *Key, operations []*
 		cmd.writeHeader(policy.GetBasePolicy(), readAttr, writeAttr, fieldCount, len(operations))
 	}
 	cmd.writeKey(key, policy.SendKey && writeAttr != 0)
+	cmd.writeFilterExpression(packedExp)
 
 	for _, operation := range operations {
 		if err := cmd.writeOperationForOperation(operation); err != nil {
@@ -286,6 +366,11 @@ func (cmd *baseCommand) setOperate(policy *WritePolicy, key *Key, operations []*
 func (cmd *baseCommand) setUdf(policy Policy, key *Key, packageName string, functionName string, args []Value) error {
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key, false)
+	packedExp, expFieldCount, err := cmd.estimateExpressionSize(policy.GetBasePolicy().FilterExpression)
+	if err != nil {
+		return err
+	}
+	fieldCount += expFieldCount
 	argBytes, err := packValueArray(args)
 	if err != nil {
 		return err
@@ -295,8 +380,13 @@ func (cmd *baseCommand) setUdf(policy Policy, key *Key, packageName string, func
 	if err := cmd.sizeBuffer(); err != nil {
 		return nil
 	}
-	cmd.writeHeader(policy.GetBasePolicy(), 0, _INFO2_WRITE, fieldCount, 0)
+	writeAttr := _INFO2_WRITE
+	if wp, ok := policy.(*WritePolicy); ok && wp.DurableDelete {
+		writeAttr |= _INFO2_DURABLE_DELETE
+	}
+	cmd.writeHeader(policy.GetBasePolicy(), 0, writeAttr, fieldCount, 0)
 	cmd.writeKey(key, false)
+	cmd.writeFilterExpression(packedExp)
 	cmd.writeFieldString(packageName, UDF_PACKAGE_NAME)
 	cmd.writeFieldString(functionName, UDF_FUNCTION)
 	cmd.writeFieldBytes(argBytes, UDF_ARGLIST)
@@ -371,10 +461,23 @@ func (cmd *baseCommand) setBatchGet(policy Policy, keys []*Key, batch *batchName
 	return nil
 }
 
-func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *string, binNames []string) error {
+func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *string, binNames []string, partitions *nodePartitions) error {
 	cmd.begin()
 	fieldCount := 0
 
+	packedExp, err := policy.FilterExpression.pack()
+	if err != nil {
+		return err
+	}
+	if packedExp != nil {
+		cmd.dataOffset += len(packedExp) + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
+	if partitions != nil {
+		fieldCount += cmd.estimatePartitionsSize(partitions)
+	}
+
 	if namespace != nil {
 		cmd.dataOffset += len(*namespace) + int(_FIELD_HEADER_SIZE)
 		fieldCount++
@@ -389,6 +492,11 @@ func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *
 	cmd.dataOffset += 2 + int(_FIELD_HEADER_SIZE)
 	fieldCount++
 
+	if policy.RecordsPerSecond > 0 {
+		cmd.dataOffset += 4 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
 	if binNames != nil {
 		for i := range binNames {
 			cmd.estimateOperationSizeForBinName(binNames[i])
@@ -417,6 +525,10 @@ func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *
 		cmd.writeFieldString(*setName, TABLE)
 	}
 
+	if partitions != nil {
+		cmd.writePartitions(partitions)
+	}
+
 	cmd.writeFieldHeader(2, SCAN_OPTIONS)
 	priority := byte(policy.Priority)
 	priority <<= 4
@@ -429,6 +541,16 @@ func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *
 	cmd.dataBuffer[cmd.dataOffset] = byte(policy.ScanPercent)
 	cmd.dataOffset++
 
+	if policy.RecordsPerSecond > 0 {
+		cmd.writeFieldHeader(4, RECORDS_PER_SECOND)
+		Buffer.Int32ToBytes(int32(policy.RecordsPerSecond), cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 4
+	}
+
+	if packedExp != nil {
+		cmd.writeFieldBytes(packedExp, FILTER_EXP)
+	}
+
 	if binNames != nil {
 		for i := range binNames {
 			cmd.writeOperationForBinName(binNames[i], READ)
@@ -439,6 +561,68 @@ func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *
 	return nil
 }
 
+// estimatePartitionsSize estimates the size of the partition fields of
+// a scan or query, and returns the number of fields.
+func (cmd *baseCommand) estimatePartitionsSize(partitions *nodePartitions) int {
+	fieldCount := 0
+
+	if len(partitions.full) > 0 {
+		cmd.dataOffset += len(partitions.full)*2 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
+	if len(partitions.partial) > 0 {
+		cmd.dataOffset += len(partitions.partial)*int(_DIGEST_SIZE) + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
+	if len(partitions.partial) > 0 && partitions.sendBVal {
+		cmd.dataOffset += len(partitions.partial)*8 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
+	if partitions.maxRecords > 0 {
+		cmd.dataOffset += 8 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
+	return fieldCount
+}
+
+// writePartitions writes the partition ids, the digests to resume after,
+// and the maximum number of records to return.
+func (cmd *baseCommand) writePartitions(partitions *nodePartitions) {
+	if len(partitions.full) > 0 {
+		cmd.writeFieldHeader(len(partitions.full)*2, PID_ARRAY)
+		for _, ps := range partitions.full {
+			Buffer.Int16ToLittleBytes(int16(ps.Id), cmd.dataBuffer, cmd.dataOffset)
+			cmd.dataOffset += 2
+		}
+	}
+
+	if len(partitions.partial) > 0 {
+		cmd.writeFieldHeader(len(partitions.partial)*int(_DIGEST_SIZE), DIGEST_ARRAY)
+		for _, ps := range partitions.partial {
+			copy(cmd.dataBuffer[cmd.dataOffset:], ps.Digest)
+			cmd.dataOffset += int(_DIGEST_SIZE)
+		}
+	}
+
+	if len(partitions.partial) > 0 && partitions.sendBVal {
+		cmd.writeFieldHeader(len(partitions.partial)*8, BVAL_ARRAY)
+		for _, ps := range partitions.partial {
+			Buffer.Int64ToLittleBytes(ps.BVal, cmd.dataBuffer, cmd.dataOffset)
+			cmd.dataOffset += 8
+		}
+	}
+
+	if partitions.maxRecords > 0 {
+		cmd.writeFieldHeader(8, MAX_RECORDS)
+		Buffer.Int64ToBytes(partitions.maxRecords, cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 8
+	}
+}
+
 func (cmd *baseCommand) estimateKeySize(key *Key, sendKey bool) int {
 	fieldCount := 0
 
@@ -464,6 +648,25 @@ func (cmd *baseCommand) estimateKeySize(key *Key, sendKey bool) int {
 	return fieldCount
 }
 
+// estimateExpressionSize adds the size of the filter expression field, if any,
+// and returns the packed expression and the number of fields it adds.
+func (cmd *baseCommand) estimateExpressionSize(exp *Expression) ([]byte, int, error) {
+	packedExp, err := exp.pack()
+	if err != nil || packedExp == nil {
+		return nil, 0, err
+	}
+
+	cmd.dataOffset += len(packedExp) + int(_FIELD_HEADER_SIZE)
+	return packedExp, 1, nil
+}
+
+// writeFilterExpression writes the packed filter expression field, if any.
+func (cmd *baseCommand) writeFilterExpression(packedExp []byte) {
+	if packedExp != nil {
+		cmd.writeFieldBytes(packedExp, FILTER_EXP)
+	}
+}
+
 func (cmd *baseCommand) estimateUdfSize(packageName string, functionName string, bytes []byte) int {
 	cmd.dataOffset += len(packageName) + int(_FIELD_HEADER_SIZE)
 	cmd.dataOffset += len(functionName) + int(_FIELD_HEADER_SIZE)
@@ -545,6 +748,10 @@ func (cmd *baseCommand) writeHeaderWithPolicy(policy *WritePolicy, readAttr int,
 		infoAttr |= _INFO3_COMMIT_MASTER
 	}
 
+	if policy.DurableDelete {
+		writeAttr |= _INFO2_DURABLE_DELETE
+	}
+
 	if policy.ConsistencyLevel == CONSISTENCY_ALL {
 		readAttr |= _INFO1_CONSISTENCY_ALL
 	}
@@ -723,7 +930,7 @@ func (cmd *baseCommand) end() {
 
 ////////////////////////////////////
 
-// a custom buffer pool with fine grained control over its contents
+// a custom buffer pool with fine grained control over its contents, used by admin commands
 // maxSize: 128KiB
 // initial bufferSize: 16 KiB
 // maximum buffer size to keep in the pool: 128K
@@ -731,6 +938,10 @@ var bufPool = NewBufferPool(512, 16*1024, 128*1024)
 
 // SetCommandBufferPool can be used to customize the command Buffer Pool parameters to calibrate
 // the pool for different workloads
+//
+// Deprecated: Database commands use a buffer pool per client, configured by
+// ClientPolicy.InitialBufferSize and ClientPolicy.MaxPooledBufferSize.
+// This pool is only used by user administration commands.
 func SetCommandBufferPool(poolSize, initBufSize, maxBufferSize int) {
 	bufPool = NewBufferPool(poolSize, initBufSize, maxBufferSize)
 }
@@ -739,6 +950,46 @@ func (cmd *baseCommand) execute(ifc command) (err error) {
 	policy := ifc.getPolicy(ifc).GetBasePolicy()
 	iterations := 0
 
+	// attempts sent to a node and the latency of the last one, for the slow command log
+	attempts := 0
+	var lastLatency time.Duration
+
+	// count the command as in progress in its cluster, once it was routed to a node
+	var cluster *Cluster
+	defer func() {
+		if cluster != nil {
+			cluster.commandsInProgress.DecrementAndGet()
+		}
+	}()
+
+	// report the command to the metrics listener, once it was routed to a node
+	begin := time.Now()
+	defer func() {
+		if cmd.node == nil {
+			return
+		}
+		latency := time.Since(begin)
+		if listener := cmd.node.metricsListener(); listener != nil {
+			listener.OnCommand(cmd.node, commandTypeOf(ifc), latency, err)
+		}
+		if cmd.node.cluster != nil && cmd.node.cluster.hotKeys != nil {
+			if key := commandKeyOf(ifc); key != nil {
+				cmd.node.cluster.hotKeys.sample(key, cmd.node)
+			}
+		}
+		if policy.SlowLogThreshold > 0 && latency >= policy.SlowLogThreshold {
+			cmd.node.reportSlowCommand(&SlowCommand{
+				Node:               cmd.node,
+				CommandType:        commandTypeOf(ifc),
+				Digest:             commandDigest(ifc),
+				Attempts:           attempts,
+				Latency:            latency,
+				LastAttemptLatency: lastLatency,
+				Err:                err,
+			})
+		}
+	}()
+
 	// set timeout outside the loop
 	limit := time.Now().Add(policy.Timeout)
 
@@ -761,46 +1012,104 @@ func (cmd *baseCommand) execute(ifc command) (err error) {
 
 		node, err := ifc.getNode(ifc)
 		if err != nil {
-			// Node is currently inactive.  Retry.
-			continue
+			switch policy.ClusterDownPolicy {
+			case CLUSTER_DOWN_FAIL:
+				return err
+			case CLUSTER_DOWN_WAIT:
+				if node, err = cmd.waitForNode(ifc, policy, limit); err != nil {
+					return err
+				}
+			default:
+				// Node is currently inactive.  Retry.
+				continue
+			}
 		}
 
 		// set command node, so when you return a record it has the node
 		cmd.node = node
+		attempts++
 
-		cmd.conn, err = node.GetConnection(policy.Timeout)
+		if cluster == nil && node.cluster != nil {
+			cluster = node.cluster
+			cluster.commandsInProgress.IncrementAndGet()
+		}
+
+		// Throttle the commands sent to the node if requested. Do not retry.
+		if node.rateLimiter != nil {
+			maxWait := node.cluster.clientPolicy.RateLimitQueueTimeout
+			if policy.Timeout > 0 {
+				if remaining := limit.Sub(time.Now()); remaining < maxWait {
+					maxWait = remaining
+				}
+			}
+			if err = node.rateLimiter.wait(maxWait); err != nil {
+				return err
+			}
+		}
+
+		// single record commands share the pipelined connections of the node, if enabled
+		if node.cluster.clientPolicy.PipelinedConnections > 0 && isSingleRecordCommand(ifc) {
+			var retry bool
+			if retry, err = cmd.executePipelined(ifc, node, policy, &lastLatency); retry {
+				node.DecreaseHealth()
+				node.cluster.logEvent(WARNING, SUBSYSTEM_COMMAND, node.GetName(), map[string]interface{}{"address": node.GetHost().String(), "error": err}, "%s", err)
+				continue
+			}
+			if err == nil {
+				node.RestoreHealth()
+			}
+			return err
+		}
+
+		cmd.conn, err = node.GetConnectionContext(cmd.context(), policy.Timeout)
 		if err != nil {
+			// the caller is not waiting for the command anymore. Do not retry.
+			if cmd.context().Err() != nil {
+				return err
+			}
+
 			// Socket connection error has occurred. Decrease health and retry.
 			node.DecreaseHealth()
 
-			Logger.Warn("Node " + node.String() + ": " + err.Error())
+			node.cluster.logEvent(WARNING, SUBSYSTEM_COMMAND, node.GetName(), map[string]interface{}{"address": node.GetHost().String(), "error": err}, "%s", err)
 			continue
 		}
 
-		// Draw a buffer from buffer pool, and make sure it will be put back
-		cmd.dataBuffer = bufPool.Get()
-		// defer bufPool.Put(cmd.dataBuffer)
-
-		// Set command buffer.
-		err = ifc.writeBuffer(ifc)
-		if err != nil {
+		// Set command buffer; it is put back in the cluster's buffer pool once the command is done with it
+		if err = cmd.prepareBuffer(ifc, node, policy); err != nil {
 			// All runtime exceptions are considered fatal. Do not retry.
 			// Close socket to flush out possible garbage. Do not put back in pool.
 			cmd.conn.Close()
 			return err
 		}
+		bufferPool := node.cluster.bufferPool
 
-		// Reset timeout in send buffer (destined for server) and socket.
-		Buffer.Int32ToBytes(int32(policy.Timeout/time.Millisecond), cmd.dataBuffer, 22)
+		// Inject faults for chaos testing if requested.
+		if err = injectFaults(node); err == errInjectedConnectionDrop {
+			// Handle like an IO error. Retry.
+			cmd.conn.Close()
+			bufferPool.Put(cmd.dataBuffer)
+
+			node.cluster.logEvent(WARNING, SUBSYSTEM_COMMAND, node.GetName(), map[string]interface{}{"address": node.GetHost().String(), "error": err}, "%s", err)
+			node.DecreaseHealth()
+			continue
+		} else if err != nil {
+			// Nothing has been sent; the connection can be reused.
+			node.PutConnection(cmd.conn)
+			bufferPool.Put(cmd.dataBuffer)
+			return err
+		}
 
 		// Send command.
+		start := time.Now()
 		_, err = cmd.conn.Write(cmd.dataBuffer[:cmd.dataOffset])
 		if err != nil {
 			// IO errors are considered temporary anomalies. Retry.
 			// Close socket to flush out possible garbage. Do not put back in pool.
 			cmd.conn.Close()
+			bufferPool.Put(cmd.dataBuffer)
 
-			Logger.Warn("Node " + node.String() + ": " + err.Error())
+			node.cluster.logEvent(WARNING, SUBSYSTEM_COMMAND, node.GetName(), map[string]interface{}{"address": node.GetHost().String(), "error": err}, "%s", err)
 			// IO error means connection to server node is unhealthy.
 			// Reflect cmd status.
 			node.DecreaseHealth()
@@ -809,12 +1118,17 @@ func (cmd *baseCommand) execute(ifc command) (err error) {
 
 		// Parse results.
 		err = ifc.parseResult(ifc, cmd.conn)
+		latency := time.Since(start)
+		lastLatency = latency
+		node.commandLatency.add(int64(latency / time.Millisecond))
+		node.addLatency(commandTypeOf(ifc), latency)
 		if err != nil {
 			// close the connection
 			// cancelling/closing the batch/multi commands will return an error, which will
 			// close the connection to throw away its data and signal the server about the
 			// situation. We will not put back the connection in the buffer.
 			cmd.conn.Close()
+			bufferPool.Put(cmd.dataBuffer)
 			return err
 		}
 
@@ -825,7 +1139,7 @@ func (cmd *baseCommand) execute(ifc command) (err error) {
 		node.PutConnection(cmd.conn)
 
 		// put back buffer to the pool
-		bufPool.Put(cmd.dataBuffer)
+		bufferPool.Put(cmd.dataBuffer)
 
 		// command has completed successfully.  Exit method.
 		return nil
@@ -836,6 +1150,112 @@ func (cmd *baseCommand) execute(ifc command) (err error) {
 	return NewAerospikeError(TIMEOUT, "command execution timed out.")
 }
 
+// waitForNode polls for a node to send the command to while the cluster is
+// rediscovered, for up to the policy's grace period and the command's timeout.
+func (cmd *baseCommand) waitForNode(ifc command, policy *BasePolicy, limit time.Time) (*Node, error) {
+	deadline := time.Now().Add(policy.ClusterDownGracePeriod)
+	if policy.Timeout > 0 && limit.Before(deadline) {
+		deadline = limit
+	}
+
+	for {
+		if !time.Now().Before(deadline) {
+			return nil, NewAerospikeError(INVALID_NODE_ERROR, "No node became available within the cluster down grace period.")
+		}
+		time.Sleep(_CLUSTER_DOWN_POLL_INTERVAL)
+
+		if node, err := ifc.getNode(ifc); err == nil {
+			return node, nil
+		}
+	}
+}
+
+// prepareBuffer draws a buffer from the cluster's buffer pool and writes the command
+// to it, with the policy's timeout, compressed if requested. The buffer is put back
+// in the pool on error.
+func (cmd *baseCommand) prepareBuffer(ifc command, node *Node, policy *BasePolicy) error {
+	bufferPool := node.cluster.bufferPool
+	cmd.dataBuffer = bufferPool.Get()
+
+	if err := ifc.writeBuffer(ifc); err != nil {
+		bufferPool.Put(cmd.dataBuffer)
+		return err
+	}
+
+	// Reset timeout in send buffer (destined for server).
+	Buffer.Int32ToBytes(int32(policy.Timeout/time.Millisecond), cmd.dataBuffer, 22)
+
+	// Compress the command, and allow the server to compress the response.
+	if policy.UseCompression && node.supportsCompression {
+		cmd.dataBuffer[9] |= byte(_INFO1_COMPRESS_RESPONSE)
+		if err := cmd.compress(); err != nil {
+			bufferPool.Put(cmd.dataBuffer)
+			return err
+		}
+	}
+	return nil
+}
+
+// injectFaults applies the client's fault policy, if any, before a command is sent.
+// It returns errInjectedConnectionDrop if the command is to be handled as if its
+// connection dropped, or the injected result code error.
+func injectFaults(node *Node) error {
+	faultPolicy := node.cluster.clientPolicy.FaultPolicy
+	if faultPolicy == nil {
+		return nil
+	}
+
+	faultPolicy.injectLatency()
+	if faultPolicy.dropConnection() {
+		return errInjectedConnectionDrop
+	}
+	return faultPolicy.resultCodeError()
+}
+
+// executePipelined sends the command on a pipelined connection of the node and parses
+// its response. It returns true if the command was not sent and can be retried.
+func (cmd *baseCommand) executePipelined(ifc command, node *Node, policy *BasePolicy, lastLatency *time.Duration) (bool, error) {
+	ctx := cmd.context()
+
+	// the deadline of this attempt, for connecting, sending and waiting for the response
+	var deadline time.Time
+	if policy.Timeout > 0 {
+		deadline = time.Now().Add(policy.Timeout)
+	}
+
+	pipeline, err := node.getPipelinedConnection(ctx, deadline)
+	if err != nil {
+		// the caller is not waiting for the command anymore. Do not retry.
+		return ctx.Err() == nil, err
+	}
+
+	if err := cmd.prepareBuffer(ifc, node, policy); err != nil {
+		return false, err
+	}
+	defer func() { node.cluster.bufferPool.Put(cmd.dataBuffer) }()
+
+	if err := injectFaults(node); err != nil {
+		// only this command is dropped; the others keep sharing the connection
+		return err == errInjectedConnectionDrop, err
+	}
+
+	start := time.Now()
+	ticket, err := pipeline.send(cmd.dataBuffer[:cmd.dataOffset], deadline)
+	if err != nil {
+		// nothing was sent, or the connection broke while sending
+		return ctx.Err() == nil, err
+	}
+
+	err = pipeline.receive(ticket, deadline, ctx, func(conn *Connection) error {
+		return ifc.parseResult(ifc, conn)
+	})
+	latency := time.Since(start)
+	*lastLatency = latency
+	node.commandLatency.add(int64(latency / time.Millisecond))
+	node.addLatency(commandTypeOf(ifc), latency)
+	return false, err
+}
+
 func (cmd *baseCommand) parseRecordResults(ifc command, receiveSize int) (bool, error) {
 	panic(errors.New("Abstract method. Should not end up here"))
 }
@@ -0,0 +1,124 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sync"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+)
+
+// commands smaller than this are not worth compressing
+const _COMPRESS_THRESHOLD = 128
+
+// pool of zlib writers, which are expensive to allocate
+var zlibWriterPool = sync.Pool{
+	New: func() interface{} {
+		w, _ := zlib.NewWriterLevel(nil, zlib.BestSpeed)
+		return w
+	},
+}
+
+// isCompressed returns true if the proto header at the start of the buffer
+// belongs to a compressed message.
+func isCompressed(header []byte) bool {
+	return int64(header[1]) == _AS_MSG_TYPE_COMPRESSED
+}
+
+// compress replaces the command in the buffer with its compressed form: a proto
+// header of the compressed type, the size of the command and the zlib stream.
+// Commands which are small or do not shrink are left as they are.
+func (cmd *baseCommand) compress() error {
+	if cmd.dataOffset <= _COMPRESS_THRESHOLD {
+		return nil
+	}
+
+	var out bytes.Buffer
+	out.Grow(cmd.dataOffset)
+	out.Write(make([]byte, 16))
+
+	w := zlibWriterPool.Get().(*zlib.Writer)
+	defer zlibWriterPool.Put(w)
+
+	w.Reset(&out)
+	if _, err := w.Write(cmd.dataBuffer[:cmd.dataOffset]); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	compressed := out.Bytes()
+	if len(compressed) >= cmd.dataOffset {
+		return nil
+	}
+
+	size := int64(len(compressed)-8) | (_CL_MSG_VERSION << 56) | (_AS_MSG_TYPE_COMPRESSED << 48)
+	Buffer.Int64ToBytes(size, compressed, 0)
+	Buffer.Int64ToBytes(int64(cmd.dataOffset), compressed, 8)
+
+	cmd.dataOffset = copy(cmd.dataBuffer, compressed)
+	return nil
+}
+
+// inflate decompresses the payload of a compressed message, made of the size
+// of the original message and its zlib stream. It returns the original message,
+// starting with its own proto header.
+func inflate(payload []byte) ([]byte, error) {
+	if len(payload) < 8 {
+		return nil, NewAerospikeError(PARSE_ERROR, "Invalid compressed message")
+	}
+
+	size := Buffer.BytesToInt64(payload, 0)
+	if size < 8 || size > _MAX_BUFFER_SIZE {
+		return nil, NewAerospikeError(PARSE_ERROR, fmt.Sprintf("Invalid size for compressed message: %d", size))
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(payload[8:]))
+	if err != nil {
+		return nil, NewAerospikeError(PARSE_ERROR, "Invalid compressed message: "+err.Error())
+	}
+	defer r.Close()
+
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, NewAerospikeError(PARSE_ERROR, "Invalid compressed message: "+err.Error())
+	}
+	return msg, nil
+}
+
+// readCompressed reads the payload of the compressed message whose proto header
+// is at the start of the buffer, and replaces the buffer with the original message.
+func (cmd *baseCommand) readCompressed(conn *Connection) error {
+	size := int(Buffer.BytesToInt64(cmd.dataBuffer, 0) & 0xFFFFFFFFFFFF)
+	if err := cmd.sizeBufferSz(size); err != nil {
+		return err
+	}
+	if _, err := conn.Read(cmd.dataBuffer, size); err != nil {
+		return err
+	}
+
+	msg, err := inflate(cmd.dataBuffer[:size])
+	if err != nil {
+		return err
+	}
+	cmd.dataBuffer = msg
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+)
+
+var _ = Describe("Compression Test", func() {
+
+	newCommand := func(size int) (*baseCommand, []byte) {
+		cmd := &baseCommand{dataBuffer: make([]byte, 1024)}
+		cmd.dataOffset = size
+		copy(cmd.dataBuffer[8:], bytes.Repeat([]byte("aerospike"), size/9))
+		cmd.end()
+		return cmd, append([]byte(nil), cmd.dataBuffer[:size]...)
+	}
+
+	It("should compress large commands and inflate them back", func() {
+		cmd, original := newCommand(512)
+
+		Expect(cmd.compress()).ToNot(HaveOccurred())
+		Expect(cmd.dataOffset).To(BeNumerically("<", len(original)))
+		Expect(isCompressed(cmd.dataBuffer)).To(BeTrue())
+
+		size := int(Buffer.BytesToInt64(cmd.dataBuffer, 0) & 0xFFFFFFFFFFFF)
+		Expect(size).To(Equal(cmd.dataOffset - 8))
+
+		msg, err := inflate(cmd.dataBuffer[8:cmd.dataOffset])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(msg).To(Equal(original))
+		Expect(isCompressed(msg)).To(BeFalse())
+	})
+
+	It("should not compress small commands", func() {
+		cmd, original := newCommand(64)
+
+		Expect(cmd.compress()).ToNot(HaveOccurred())
+		Expect(cmd.dataBuffer[:cmd.dataOffset]).To(Equal(original))
+	})
+
+	It("should reject corrupted compressed messages", func() {
+		_, err := inflate([]byte{0, 0, 0, 0, 0, 0, 1, 0, 1, 2, 3})
+		Expect(err).To(HaveOccurred())
+	})
+
+})
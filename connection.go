@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"net"
 	"time"
 
@@ -29,6 +30,16 @@ type Connection struct {
 
 	// connection object
 	conn net.Conn
+
+	// node which owns the connection pool; nil for connections outside a pool
+	node *Node
+
+	// creation time and number of times the connection was taken from the pool
+	created time.Time
+	reuses  int64
+
+	// configuration generation of the cluster when the connection was opened
+	generation int
 }
 
 func errToTimeoutErr(err error) error {
@@ -43,11 +54,22 @@ func errToTimeoutErr(err error) error {
 // If the connection is not established in the specified timeout,
 // an error will be returned
 func NewConnection(address string, timeout time.Duration) (*Connection, error) {
-	newConn := &Connection{}
+	return NewConnectionContext(context.Background(), address, timeout)
+}
+
+// NewConnectionContext creates a connection on the network like NewConnection.
+// If the context is done before the connection is established, the dial is aborted
+// and the context's error is returned.
+func NewConnectionContext(ctx context.Context, address string, timeout time.Duration) (*Connection, error) {
+	newConn := &Connection{created: time.Now()}
 
-	conn, err := net.DialTimeout("tcp", address, timeout)
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
 	if err != nil {
-		Logger.Error("Connection to address `" + address + "` failed to establish with error: " + err.Error())
+		Logger.LogEvent(ERR, SUBSYSTEM_NODE, "", map[string]interface{}{"address": address, "error": err}, "Connection to address `%s` failed to establish with error: %s", address, err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		return nil, errToTimeoutErr(err)
 	}
 	newConn.conn = conn
@@ -125,6 +147,32 @@ func (ctn *Connection) SetTimeout(timeout time.Duration) error {
 	return nil
 }
 
+// interruptOn unblocks pending reads and writes on the connection when the
+// cancelled channel is closed. The returned function stops watching the channel
+// and reports if the connection was interrupted, in which case it must not be reused.
+func (ctn *Connection) interruptOn(cancelled <-chan struct{}) func() bool {
+	conn := ctn.conn
+	done := make(chan struct{})
+	interrupted := make(chan bool, 1)
+
+	go func() {
+		select {
+		case <-cancelled:
+			if conn != nil {
+				conn.SetDeadline(time.Now())
+			}
+			interrupted <- true
+		case <-done:
+			interrupted <- false
+		}
+	}()
+
+	return func() bool {
+		close(done)
+		return <-interrupted
+	}
+}
+
 // Close closes the connection
 func (ctn *Connection) Close() {
 	if ctn != nil && ctn.conn != nil {
@@ -132,15 +180,35 @@ func (ctn *Connection) Close() {
 			Logger.Warn(err.Error())
 		}
 		ctn.conn = nil
+
+		if ctn.node != nil {
+			ctn.node.connectionsClosed.IncrementAndGet()
+			ctn.node.connectionReuses.add(ctn.reuses)
+			if listener := ctn.node.metricsListener(); listener != nil {
+				listener.OnConnectionClosed(ctn.node)
+			}
+		}
 	}
 }
 
 // Authenticate will send authentication information to the server.
 func (ctn *Connection) Authenticate(user string, password []byte) error {
+	return ctn.AuthenticateContext(context.Background(), user, password)
+}
+
+// AuthenticateContext sends authentication information to the server like Authenticate.
+// If the context is done before the server responds, the exchange is aborted and the
+// context's error is returned. The connection must not be used afterwards.
+func (ctn *Connection) AuthenticateContext(ctx context.Context, user string, password []byte) error {
 	// need to authenticate
 	if user != "" {
+		stop := ctn.interruptOn(ctx.Done())
 		command := newAdminCommand()
-		if err := command.authenticate(ctn, user, password); err != nil {
+		err := command.authenticate(ctn, user, password)
+		if stop() {
+			return ctx.Err()
+		}
+		if err != nil {
 			// Socket not authenticated. Do not put back into pool.
 			return err
 		}
@@ -0,0 +1,158 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+const (
+	_CONSISTENCY_TOKEN_VERSION = 1
+	_CONSISTENCY_TOKEN_SIZE    = 1 + 20 + 4 + 8
+)
+
+// ConsistencyToken identifies the version of a record produced by a write, so
+// that a later read, possibly by another service, can require a version of the
+// record at least as fresh as that write.
+// Tokens are returned by PutBinsWithToken and OperateWithToken, and checked by
+// GetWithToken. Pass them between services as strings with String and
+// ParseConsistencyToken.
+//
+// Freshness is determined by the record generation only. The server's 16 bit
+// generation counter wraps around, so a token only orders versions which are
+// less than 32768 writes apart. The last update time and the strong consistency
+// regime are not returned to the client by the protocol, so they are not part of
+// the token: a record which was deleted and written again may satisfy a token
+// issued for its previous incarnation.
+type ConsistencyToken struct {
+	// Digest is the digest of the written key.
+	Digest []byte
+
+	// Generation is the generation of the record after the write.
+	Generation uint32
+
+	// WriteTime is the client time at which the write was acknowledged.
+	// It is informative only, and not used to check freshness.
+	WriteTime time.Time
+}
+
+func newConsistencyToken(key *Key, generation uint32) *ConsistencyToken {
+	return &ConsistencyToken{
+		Digest:     key.Digest(),
+		Generation: generation,
+		WriteTime:  time.Now(),
+	}
+}
+
+// String encodes the token as an opaque URL-safe string.
+func (ct *ConsistencyToken) String() string {
+	buf := make([]byte, _CONSISTENCY_TOKEN_SIZE)
+	buf[0] = _CONSISTENCY_TOKEN_VERSION
+	copy(buf[1:21], ct.Digest)
+	binary.BigEndian.PutUint32(buf[21:25], ct.Generation)
+	binary.BigEndian.PutUint64(buf[25:33], uint64(ct.WriteTime.UnixNano()))
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// ParseConsistencyToken decodes a token encoded by ConsistencyToken.String.
+func ParseConsistencyToken(s string) (*ConsistencyToken, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(buf) != _CONSISTENCY_TOKEN_SIZE || buf[0] != _CONSISTENCY_TOKEN_VERSION {
+		return nil, NewAerospikeError(PARSE_ERROR, "Invalid consistency token")
+	}
+
+	return &ConsistencyToken{
+		Digest:     append([]byte(nil), buf[1:21]...),
+		Generation: binary.BigEndian.Uint32(buf[21:25]),
+		WriteTime:  time.Unix(0, int64(binary.BigEndian.Uint64(buf[25:33]))),
+	}, nil
+}
+
+// isSatisfiedBy returns true if the generation is at least as recent as the token's.
+func (ct *ConsistencyToken) isSatisfiedBy(generation uint32) bool {
+	// compare the 16 bit server generations in serial number arithmetic
+	return int16(uint16(generation)-uint16(ct.Generation)) >= 0
+}
+
+// PutBinsWithToken writes the bins like PutBins, and returns a token of the
+// written version of the record.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) PutBinsWithToken(policy *WritePolicy, key *Key, bins ...*Bin) (*ConsistencyToken, error) {
+	policy = clnt.getUsableWritePolicy(policy)
+	if err := clnt.validateWrite(key, binOperations(WRITE, bins)); err != nil {
+		return nil, err
+	}
+	command, err := newWriteCommand(clnt.cluster, policy, key, bins, WRITE)
+	if err != nil {
+		return nil, err
+	}
+	if err := command.Execute(); err != nil {
+		return nil, err
+	}
+	return newConsistencyToken(key, command.generation), nil
+}
+
+// OperateWithToken performs the operations like Operate, and returns a token
+// of the resulting version of the record.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) OperateWithToken(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, *ConsistencyToken, error) {
+	rec, err := clnt.Operate(policy, key, operations...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rec, newConsistencyToken(key, uint32(rec.Generation)), nil
+}
+
+// GetWithToken reads a record like Get, requiring a version at least as fresh
+// as the write the token was issued for. The read is sent to the master replica
+// regardless of the policy's ReplicaPolicy. If the master returns an older version,
+// e.g. during a migration, the read is retried up to the policy's MaxRetries times,
+// and then fails with a STALE_READ error.
+// A record deleted after the write is returned as not found, like Get does.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) GetWithToken(policy *BasePolicy, token *ConsistencyToken, key *Key, binNames ...string) (*Record, error) {
+	if token == nil {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "Consistency token cannot be nil")
+	}
+	if !bytes.Equal(token.Digest, key.Digest()) {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "Consistency token was not issued for this key")
+	}
+
+	policy = clnt.getUsablePolicy(policy)
+
+	// do not modify the caller's policy
+	bp := *policy
+	bp.ReplicaPolicy = MASTER
+
+	for attempt := 0; ; attempt++ {
+		rec, err := clnt.Get(&bp, key, binNames...)
+		if err != nil || rec == nil || token.isSatisfiedBy(uint32(rec.Generation)) {
+			return rec, err
+		}
+
+		if attempt >= bp.MaxRetries {
+			return nil, NewAerospikeError(STALE_READ, fmt.Sprintf("Record generation %d is older than the consistency token's generation %d", rec.Generation, token.Generation))
+		}
+		if bp.SleepBetweenRetries > 0 {
+			time.Sleep(bp.SleepBetweenRetries)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+var _ = Describe("Consistency Token Test", func() {
+
+	It("should round trip through its string encoding", func() {
+		key, err := NewKey("test", "set", "key")
+		Expect(err).ToNot(HaveOccurred())
+
+		token := newConsistencyToken(key, 42)
+		parsed, err := ParseConsistencyToken(token.String())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Digest).To(Equal(key.Digest()))
+		Expect(parsed.Generation).To(Equal(uint32(42)))
+		Expect(parsed.WriteTime.Equal(token.WriteTime.Round(0))).To(BeTrue())
+	})
+
+	It("should reject invalid tokens", func() {
+		_, err := ParseConsistencyToken("not a token")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseConsistencyToken("")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should order generations across the wrap around", func() {
+		token := &ConsistencyToken{Generation: 10, WriteTime: time.Now()}
+		Expect(token.isSatisfiedBy(10)).To(BeTrue())
+		Expect(token.isSatisfiedBy(11)).To(BeTrue())
+		Expect(token.isSatisfiedBy(9)).To(BeFalse())
+
+		token.Generation = 65535
+		Expect(token.isSatisfiedBy(1)).To(BeTrue())
+		Expect(token.isSatisfiedBy(65534)).To(BeFalse())
+	})
+
+	It("should reject a nil token", func() {
+		key, err := NewKey("test", "set", "key")
+		Expect(err).ToNot(HaveOccurred())
+
+		client := &Client{cluster: &Cluster{}}
+		_, err = client.GetWithToken(nil, nil, key)
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+	})
+
+})
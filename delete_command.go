@@ -68,5 +68,6 @@ func (cmd *deleteCommand) Existed() bool {
 }
 
 func (cmd *deleteCommand) Execute() error {
+	cmd.policy.ReadYourWrites.Track(cmd.key)
 	return cmd.execute(cmd)
 }
@@ -0,0 +1,239 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+
+	. "github.com/aerospike/aerospike-client-go/logger"
+	. "github.com/aerospike/aerospike-client-go/types"
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+)
+
+// DeleteWhereTask is used to poll for the completion of a DeleteWhere job.
+// The job runs either on the servers, or on the client when a node does not
+// support background operations.
+type DeleteWhereTask struct {
+	*BaseTask
+
+	// set if the job runs on the servers
+	serverTask *ExecuteTask
+
+	// state of a job running on the client
+	done    chan struct{}
+	err     error
+	deleted *AtomicInt
+}
+
+// IsDone returns true when the job has completed on all nodes.
+func (dtsk *DeleteWhereTask) IsDone() (bool, error) {
+	if dtsk.serverTask != nil {
+		return dtsk.serverTask.IsDone()
+	}
+
+	select {
+	case <-dtsk.done:
+		return true, dtsk.err
+	default:
+		return false, nil
+	}
+}
+
+// Progress returns the lowest percentage of the job completed on a node.
+// Jobs running on the client only report 0% or 100%.
+func (dtsk *DeleteWhereTask) Progress() (int, error) {
+	if dtsk.serverTask != nil {
+		return dtsk.serverTask.Progress()
+	}
+	return progressOf(dtsk)
+}
+
+// OnComplete returns a channel which will be closed when the task is
+// completed.
+// If an error is encountered while performing the task, an error
+// will be sent on the channel.
+func (dtsk *DeleteWhereTask) OnComplete() chan error {
+	return dtsk.onComplete(dtsk)
+}
+
+// Wait blocks until the job is completed, an error is encountered,
+// or the context is canceled.
+func (dtsk *DeleteWhereTask) Wait(ctx context.Context) error {
+	return dtsk.wait(ctx, dtsk)
+}
+
+// IsServerSide returns true if the job runs on the servers.
+func (dtsk *DeleteWhereTask) IsServerSide() bool {
+	return dtsk.serverTask != nil
+}
+
+// Deleted returns the number of records deleted so far by a job running on the
+// client. Jobs running on the servers do not report it.
+func (dtsk *DeleteWhereTask) Deleted() int {
+	return dtsk.deleted.Get()
+}
+
+// DeleteWhere deletes the records of the set which match the secondary index
+// filter and the filter expression, as a background job. Either may be nil;
+// if both are nil, all the records of the set are deleted.
+// If the filterExp is nil, the policy's FilterExpression is used instead.
+//
+// If all nodes support background operations (server version 4.7+), the job runs
+// on the servers like QueryDelete. Otherwise the records are read by a query, or
+// a scan if the filter is nil, of each node as a whole, and deleted one by one by
+// the client in a goroutine, at most deletesPerSecond per second if it is
+// positive. The filter expression is then evaluated by the client, which does not
+// support the expressions reading the device size, the last update time or the
+// digest. The policy's PartitionFilter is not supported either. The client-side
+// job stops at the first error, which the returned task reports.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) DeleteWhere(policy *QueryPolicy, namespace string, setName string, filter *Filter, filterExp *Expression, deletesPerSecond int) (*DeleteWhereTask, error) {
+	policy = clnt.getUsableQueryPolicy(policy)
+
+	nodes := clnt.cluster.GetNodes()
+	if len(nodes) == 0 {
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "DeleteWhere failed because cluster is empty.")
+	}
+
+	serverSide := true
+	for _, node := range nodes {
+		serverSide = serverSide && node.supportsBackgroundOps
+	}
+
+	task := &DeleteWhereTask{
+		BaseTask: NewTask(clnt.cluster, false),
+		deleted:  NewAtomicInt(0),
+	}
+
+	if serverSide {
+		statement := NewStatement(namespace, setName)
+		if filter != nil {
+			statement.Addfilter(filter)
+		}
+
+		serverTask, err := clnt.QueryDelete(policy, statement, filterExp)
+		if err != nil {
+			return nil, err
+		}
+		task.serverTask = serverTask
+		return task, nil
+	}
+
+	if policy.PartitionFilter != nil {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "DeleteWhere does not support a PartitionFilter on servers without background operations.")
+	}
+	if filterExp == nil {
+		filterExp = policy.FilterExpression
+	}
+	if filterExp != nil {
+		if err := filterExp.checkClientSide(); err != nil {
+			return nil, err
+		}
+	}
+
+	// old servers do not support filter expressions and partition queries;
+	// do not modify the caller's policy
+	bp := *policy.BasePolicy
+	bp.FilterExpression = nil
+	bp.ReplicaPolicy = MASTER
+	mp := *policy.MultiPolicy
+	mp.BasePolicy = &bp
+	mp.LazyBinDecoding = false
+
+	var recordset *Recordset
+	var err error
+	if filter != nil {
+		// only the filter bin is needed, unless the filter expression reads the others
+		statement := NewStatement(namespace, setName)
+		if filterExp == nil {
+			statement.BinNames = []string{filter.name}
+		}
+		statement.Addfilter(filter)
+
+		qp := *policy
+		qp.MultiPolicy = &mp
+		recordset, err = clnt.queryNodes(&qp, statement, nodes)
+	} else {
+		sp := NewScanPolicy()
+		sp.MultiPolicy = &mp
+		sp.IncludeBinData = filterExp != nil
+		recordset, err = clnt.ScanAll(sp, namespace, setName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	task.done = make(chan struct{})
+	go clnt.deleteRecords(policy, recordset, filterExp, deletesPerSecond, task)
+	return task, nil
+}
+
+// deleteRecords deletes the records of the recordset for a client-side DeleteWhere job.
+// Records which do not match the filter expression, if any, are skipped.
+func (clnt *Client) deleteRecords(policy *QueryPolicy, recordset *Recordset, filterExp *Expression, deletesPerSecond int, task *DeleteWhereTask) {
+	defer close(task.done)
+
+	wp := NewWritePolicy(0, 0)
+	wp.Timeout = policy.Timeout
+	wp.MaxRetries = policy.MaxRetries
+	wp.SleepBetweenRetries = policy.SleepBetweenRetries
+
+	var limiter *rateLimiter
+	if deletesPerSecond > 0 {
+		limiter = newRateLimiter(deletesPerSecond)
+	}
+
+	fail := func(err error) {
+		task.err = err
+		clnt.cluster.logEvent(WARNING, SUBSYSTEM_CLIENT, "", map[string]interface{}{"deleted": task.deleted.Get(), "error": err}, "DeleteWhere aborted: %s", err)
+		// closing waits for the node goroutines; keep draining the results meanwhile
+		go recordset.Close()
+	}
+
+	for res := range recordset.Results() {
+		if task.err != nil {
+			continue
+		}
+		if res.Err != nil {
+			fail(res.Err)
+			continue
+		}
+
+		if filterExp != nil {
+			matches, err := filterExp.matches(res.Record)
+			if err != nil {
+				fail(err)
+				continue
+			}
+			if !matches {
+				continue
+			}
+		}
+
+		if limiter != nil {
+			// waits as long as needed
+			limiter.wait(_NO_TIMEOUT)
+		}
+
+		existed, err := clnt.Delete(wp, res.Record.Key)
+		if err != nil {
+			fail(err)
+			continue
+		}
+		if existed {
+			task.deleted.IncrementAndGet()
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// oldServer answers like a single server version 4.5 node, which supports
+// neither background operations, filter expressions nor partition queries.
+// It serves its records to scans and queries, and deletes them on request.
+type oldServer struct {
+	listener net.Listener
+
+	mutex   sync.Mutex
+	records map[string]*Record // by digest
+	fields  [][]FieldType      // fields of the scans and queries received
+}
+
+func newOldServer(records ...*Record) (*oldServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	srv := &oldServer{listener: listener, records: map[string]*Record{}}
+	for _, rec := range records {
+		srv.records[string(rec.Key.Digest())] = rec
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(conn)
+		}
+	}()
+	return srv, nil
+}
+
+func (srv *oldServer) port() int {
+	_, port, _ := net.SplitHostPort(srv.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+func (srv *oldServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		header := make([]byte, 8)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		body := make([]byte, Buffer.BytesToInt64(header, 0)&0xFFFFFFFFFFFF)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+
+		var res []byte
+		if header[1] == byte(MSG_INFO) {
+			res = srv.info(body)
+		} else {
+			res = srv.command(body)
+		}
+		if _, err := conn.Write(res); err != nil {
+			return
+		}
+	}
+}
+
+func (srv *oldServer) info(body []byte) []byte {
+	// all partitions of the namespace
+	replicas := "test:" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{0xFF}, _PARTITIONS/8))
+	values := map[string]string{
+		"node":                 "BB9000000000001",
+		"build":                "4.5.0.0",
+		"partition-generation": "1",
+		"replicas-master":      replicas,
+	}
+
+	var buf bytes.Buffer
+	for _, name := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+		buf.WriteString(name + "\t" + values[name] + "\n")
+	}
+	return append(protoHeader(int(MSG_INFO), buf.Len()), buf.Bytes()...)
+}
+
+func (srv *oldServer) command(body []byte) []byte {
+	info2 := int(body[2])
+	fieldCount := int(Buffer.BytesToInt16(body, 18))
+
+	var fields []FieldType
+	var digest []byte
+	offset := 22
+	for i := 0; i < fieldCount; i++ {
+		size := int(Buffer.BytesToInt32(body, offset))
+		fields = append(fields, FieldType(body[offset+4]))
+		if FieldType(body[offset+4]) == DIGEST_RIPE {
+			digest = body[offset+5 : offset+4+size]
+		}
+		offset += 4 + size
+	}
+
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	if info2&_INFO2_DELETE != 0 {
+		resultCode := KEY_NOT_FOUND_ERROR
+		if _, exists := srv.records[string(digest)]; exists {
+			delete(srv.records, string(digest))
+			resultCode = OK
+		}
+		msg := make([]byte, 22)
+		msg[0], msg[5] = 22, byte(resultCode)
+		return append(protoHeader(MSG_MESSAGE, len(msg)), msg...)
+	}
+
+	// a scan or a query
+	srv.fields = append(srv.fields, fields)
+
+	var buf bytes.Buffer
+	for _, rec := range srv.records {
+		msg := make([]byte, 22)
+		msg[0] = 22
+		Buffer.Int16ToBytes(3, msg, 18)
+		Buffer.Int16ToBytes(int16(len(rec.Bins)), msg, 20)
+		buf.Write(msg)
+
+		writeOldServerField(&buf, NAMESPACE, []byte(rec.Key.Namespace()))
+		writeOldServerField(&buf, TABLE, []byte(rec.Key.SetName()))
+		writeOldServerField(&buf, DIGEST_RIPE, rec.Key.Digest())
+
+		for name, value := range rec.Bins {
+			particle := make([]byte, 8)
+			Buffer.Int64ToBytes(int64(value.(int)), particle, 0)
+
+			op := make([]byte, 8)
+			Buffer.Int32ToBytes(int32(4+len(name)+len(particle)), op, 0)
+			op[4], op[5], op[7] = byte(READ), ParticleType.INTEGER, byte(len(name))
+			buf.Write(op)
+			buf.WriteString(name)
+			buf.Write(particle)
+		}
+	}
+
+	last := make([]byte, 22)
+	last[0], last[3] = 22, byte(_INFO3_LAST)
+	buf.Write(last)
+
+	return append(protoHeader(MSG_MESSAGE, buf.Len()), buf.Bytes()...)
+}
+
+// received returns the fields of the scans and queries received so far.
+func (srv *oldServer) received() [][]FieldType {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+	return srv.fields
+}
+
+// remaining returns the number of records which were not deleted.
+func (srv *oldServer) remaining() int {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+	return len(srv.records)
+}
+
+func (srv *oldServer) close() {
+	srv.listener.Close()
+}
+
+func protoHeader(msgType int, size int) []byte {
+	header := make([]byte, 8)
+	Buffer.Int64ToBytes(int64(size), header, 0)
+	header[0], header[1] = 2, byte(msgType)
+	return header
+}
+
+func writeOldServerField(buf *bytes.Buffer, fieldType FieldType, data []byte) {
+	header := make([]byte, 5)
+	Buffer.Int32ToBytes(int32(len(data)+1), header, 0)
+	header[4] = byte(fieldType)
+	buf.Write(header)
+	buf.Write(data)
+}
+
+var _ = Describe("DeleteWhere Old Server Test", func() {
+
+	var server *oldServer
+	var client *Client
+
+	BeforeEach(func() {
+		var records []*Record
+		for i := 1; i <= 3; i++ {
+			key, err := NewKey("test", "set", i)
+			Expect(err).ToNot(HaveOccurred())
+			records = append(records, newRecord(nil, key, BinMap{"a": i}, 1, 0))
+		}
+
+		var err error
+		server, err = newOldServer(records...)
+		Expect(err).ToNot(HaveOccurred())
+
+		policy := NewClientPolicy()
+		policy.Timeout = time.Second
+		client, err = NewClientWithPolicy(policy, "127.0.0.1", server.port())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		client.Close()
+		server.close()
+	})
+
+	It("should scan the nodes and filter the records on the client", func() {
+		exp := ExpGreaterEq(ExpIntBin("a"), ExpIntVal(2))
+		task, err := client.DeleteWhere(nil, "test", "set", nil, exp, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(task.IsServerSide()).To(BeFalse())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		Expect(task.Wait(ctx)).ToNot(HaveOccurred())
+		Expect(task.Deleted()).To(Equal(2))
+		Expect(server.remaining()).To(Equal(1))
+
+		// a plain node scan, without filter expression nor partitions
+		received := server.received()
+		Expect(len(received)).To(Equal(1))
+		Expect(received[0]).ToNot(ContainElement(FILTER_EXP))
+		Expect(received[0]).ToNot(ContainElement(PID_ARRAY))
+	})
+
+	It("should query the nodes for a secondary index filter", func() {
+		task, err := client.DeleteWhere(nil, "test", "set", NewRangeFilter("a", 1, 3), nil, 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		Expect(task.Wait(ctx)).ToNot(HaveOccurred())
+		Expect(task.Deleted()).To(Equal(3))
+
+		received := server.received()
+		Expect(len(received)).To(Equal(1))
+		Expect(received[0]).To(ContainElement(INDEX_RANGE))
+		Expect(received[0]).ToNot(ContainElement(PID_ARRAY))
+	})
+
+	It("should reject the expressions the client cannot evaluate", func() {
+		exp := ExpLess(ExpSinceUpdate(), ExpIntVal(3600000))
+		_, err := client.DeleteWhere(nil, "test", "set", nil, exp, 0)
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+	})
+
+})
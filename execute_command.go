@@ -42,6 +42,11 @@ func (cmd *executeCommand) writeBuffer(ifc command) error {
 	return cmd.setUdf(cmd.policy, cmd.key, cmd.packageName, cmd.functionName, cmd.args)
 }
 
+func (cmd *executeCommand) getNode(ifc command) (*Node, error) {
+	return cmd.cluster.GetNode(cmd.partition)
+}
+
 func (cmd *executeCommand) Execute() error {
+	cmd.policy.GetBasePolicy().ReadYourWrites.Track(cmd.key)
 	return cmd.execute(cmd)
 }
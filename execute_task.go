@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"strconv"
 	"strings"
 
@@ -40,6 +41,17 @@ func NewExecuteTask(cluster *Cluster, statement *Statement) *ExecuteTask {
 
 // IsDone queries all nodes for task completion status.
 func (etsk *ExecuteTask) IsDone() (bool, error) {
+	pct, err := etsk.Progress()
+	if err != nil {
+		return false, err
+	}
+	return pct == 100, nil
+}
+
+// Progress queries all nodes for the job status and returns the lowest
+// percentage of the job completed on a node. Nodes which do not list the job
+// anymore are considered done.
+func (etsk *ExecuteTask) Progress() (int, error) {
 	var command string
 	if etsk.scan {
 		command = "scan-list"
@@ -48,53 +60,86 @@ func (etsk *ExecuteTask) IsDone() (bool, error) {
 	}
 
 	nodes := etsk.cluster.GetNodes()
-	done := false
+	if len(nodes) == 0 {
+		return 0, nil
+	}
 
+	progress := 100
 	for _, node := range nodes {
 		conn, err := node.GetConnection(0)
 		if err != nil {
-			return false, err
+			return 0, err
 		}
 		responseMap, err := RequestInfo(conn, command)
 		if err != nil {
-			return false, err
+			conn.Close()
+			return 0, err
 		}
 
 		node.PutConnection(conn)
 
-		response := responseMap[command]
-		find := "job_id=" + strconv.FormatInt(etsk.taskId, 10) + ":"
-		index := strings.Index(response, find)
-
-		if index < 0 {
-			done = true
-			continue
+		pct, err := etsk.jobProgress(responseMap[command])
+		if err != nil {
+			return 0, err
+		}
+		if pct < progress {
+			progress = pct
 		}
+	}
+
+	return progress, nil
+}
+
+// jobProgress parses the status of the job from a job list response.
+func (etsk *ExecuteTask) jobProgress(response string) (int, error) {
+	find := "job_id=" + strconv.FormatInt(etsk.taskId, 10) + ":"
+	index := strings.Index(response, find)
 
-		begin := index + len(find)
-		response = response[begin:]
-		find = "job_status="
-		index = strings.Index(response, find)
+	if index < 0 {
+		return 100, nil
+	}
+
+	// limit the response to the job's entry
+	response = response[index+len(find):]
+	if end := strings.Index(response, ";"); end >= 0 {
+		response = response[:end]
+	}
 
-		if index < 0 {
+	var status string
+	pct := -1
+	for _, field := range strings.Split(response, ":") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
 			continue
 		}
 
-		begin = index + len(find)
-		response = response[begin:]
-		end := strings.Index(response, ":")
-		status := response[:end]
-
-		if status == "ABORTED" {
-			return false, NewAerospikeError(QUERY_TERMINATED)
-		} else if status == "IN PROGRESS" {
-			return false, nil
-		} else if status == "DONE" {
-			done = true
+		switch kv[0] {
+		case "job_status":
+			status = kv[1]
+		case "job_progress(%)", "job-progress":
+			if f, err := strconv.ParseFloat(kv[1], 64); err == nil {
+				pct = int(f)
+			}
 		}
 	}
 
-	return done, nil
+	switch status {
+	case "ABORTED":
+		return 0, NewAerospikeError(QUERY_TERMINATED)
+	case "DONE":
+		return 100, nil
+	case "IN PROGRESS":
+		if pct >= 100 {
+			// still in progress until the server reports it done
+			return 99, nil
+		} else if pct < 0 {
+			return 0, nil
+		}
+		return pct, nil
+	}
+
+	// unknown status
+	return 0, nil
 }
 
 // OnComplete returns a channel which will be closed when the task is
@@ -104,3 +149,9 @@ func (etsk *ExecuteTask) IsDone() (bool, error) {
 func (etsk *ExecuteTask) OnComplete() chan error {
 	return etsk.onComplete(etsk)
 }
+
+// Wait blocks until the job is completed on all nodes, an error is encountered,
+// or the context is canceled.
+func (etsk *ExecuteTask) Wait(ctx context.Context) error {
+	return etsk.wait(ctx, etsk)
+}
@@ -0,0 +1,180 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"math"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// ExpiringMap stores entries with individual expirations in a map bin, which
+// is useful for sub-values living shorter than their record, like per-device tokens.
+//
+// Each map value is stored as a list of its expiration, in Unix milliseconds,
+// and the value itself. Since lists are ordered by their first element, the
+// live entries are selected, and the expired ones removed, by value range
+// operations on the server. Expired entries are never returned, and are
+// removed lazily by Put and PutItems, or explicitly by Prune.
+type ExpiringMap struct {
+	client *Client
+
+	// BinName is the name of the map bin.
+	BinName string
+
+	// MapPolicy is used to write the map entries.
+	MapPolicy *MapPolicy
+}
+
+// NewExpiringMap generates an ExpiringMap helper for the map bin.
+func NewExpiringMap(client *Client, binName string) *ExpiringMap {
+	return &ExpiringMap{
+		client:    client,
+		BinName:   binName,
+		MapPolicy: DefaultMapPolicy(),
+	}
+}
+
+// expiringMapExpiry returns the stored expiration of an entry living for ttl.
+// Entries with a zero or negative ttl never expire.
+func expiringMapExpiry(now time.Time, ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return math.MaxInt64
+	}
+	return now.Add(ttl).UnixNano() / int64(time.Millisecond)
+}
+
+// expiringMapNow returns the lower bound of the live entries at the time.
+func expiringMapNow(now time.Time) []interface{} {
+	return []interface{}{now.UnixNano() / int64(time.Millisecond)}
+}
+
+// pruneOp removes the entries expired at the time.
+func (em *ExpiringMap) pruneOp(now time.Time, returnType MapReturnType) *Operation {
+	return MapRemoveByValueRangeOp(em.BinName, nil, expiringMapNow(now), returnType)
+}
+
+// Put stores the value for the map key, to expire after ttl, and removes the
+// expired entries of the map. A zero or negative ttl never expires.
+// If the policy is nil, the default relevant policy will be used.
+func (em *ExpiringMap) Put(policy *WritePolicy, key *Key, mapKey interface{}, value interface{}, ttl time.Duration) error {
+	now := time.Now()
+	entry := []interface{}{expiringMapExpiry(now, ttl), value}
+	_, err := em.client.Operate(policy, key,
+		MapPutOp(em.MapPolicy, em.BinName, mapKey, entry),
+		em.pruneOp(now, MapReturnTypeNone),
+	)
+	return err
+}
+
+// PutItems stores the items, all expiring after ttl, and removes the expired
+// entries of the map. A zero or negative ttl never expires.
+// If the policy is nil, the default relevant policy will be used.
+func (em *ExpiringMap) PutItems(policy *WritePolicy, key *Key, items map[interface{}]interface{}, ttl time.Duration) error {
+	now := time.Now()
+	expiry := expiringMapExpiry(now, ttl)
+
+	entries := make(map[interface{}]interface{}, len(items))
+	for k, v := range items {
+		entries[k] = []interface{}{expiry, v}
+	}
+
+	_, err := em.client.Operate(policy, key,
+		MapPutItemsOp(em.MapPolicy, em.BinName, entries),
+		em.pruneOp(now, MapReturnTypeNone),
+	)
+	return err
+}
+
+// Get returns the value of the map key, or nil if it does not exist or has expired.
+// If the policy is nil, the default relevant policy will be used.
+func (em *ExpiringMap) Get(policy *WritePolicy, key *Key, mapKey interface{}) (interface{}, error) {
+	rec, err := em.client.Operate(policy, key, MapGetByKeyOp(em.BinName, mapKey, MapReturnTypeValue))
+	if err != nil || rec == nil {
+		return nil, err
+	}
+
+	value, live, err := unwrapExpiringMapEntry(time.Now(), rec.Bins[em.BinName])
+	if err != nil || !live {
+		return nil, err
+	}
+	return value, nil
+}
+
+// GetAll returns the live entries of the map.
+// If the policy is nil, the default relevant policy will be used.
+func (em *ExpiringMap) GetAll(policy *WritePolicy, key *Key) (map[interface{}]interface{}, error) {
+	now := time.Now()
+	rec, err := em.client.Operate(policy, key, MapGetByValueRangeOp(em.BinName, expiringMapNow(now), nil, MapReturnTypeKeyValue))
+	if err != nil || rec == nil {
+		return nil, err
+	}
+
+	entries, _ := rec.Bins[em.BinName].(map[interface{}]interface{})
+	res := make(map[interface{}]interface{}, len(entries))
+	for k, entry := range entries {
+		value, live, err := unwrapExpiringMapEntry(now, entry)
+		if err != nil {
+			return nil, err
+		}
+		if live {
+			res[k] = value
+		}
+	}
+	return res, nil
+}
+
+// Remove removes the map key.
+// If the policy is nil, the default relevant policy will be used.
+func (em *ExpiringMap) Remove(policy *WritePolicy, key *Key, mapKey interface{}) error {
+	_, err := em.client.Operate(policy, key, MapRemoveByKeyOp(em.BinName, mapKey, MapReturnTypeNone))
+	return err
+}
+
+// Prune removes the expired entries of the map, and returns how many were removed.
+// If the policy is nil, the default relevant policy will be used.
+func (em *ExpiringMap) Prune(policy *WritePolicy, key *Key) (int, error) {
+	rec, err := em.client.Operate(policy, key, em.pruneOp(time.Now(), MapReturnTypeCount))
+	if err != nil || rec == nil {
+		return 0, err
+	}
+	count, _ := rec.Bins[em.BinName].(int)
+	return count, nil
+}
+
+// unwrapExpiringMapEntry returns the value of a stored entry, and whether it is live at the time.
+func unwrapExpiringMapEntry(now time.Time, entry interface{}) (interface{}, bool, error) {
+	if entry == nil {
+		return nil, false, nil
+	}
+
+	list, ok := entry.([]interface{})
+	if !ok || len(list) != 2 {
+		return nil, false, NewAerospikeError(PARSE_ERROR, "Invalid expiring map entry")
+	}
+
+	var expiry int64
+	switch v := list[0].(type) {
+	case int:
+		expiry = int64(v)
+	case int64:
+		expiry = v
+	default:
+		return nil, false, NewAerospikeError(PARSE_ERROR, "Invalid expiring map entry")
+	}
+
+	return list[1], expiry >= now.UnixNano()/int64(time.Millisecond), nil
+}
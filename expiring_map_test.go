@@ -0,0 +1,107 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("Expiring Map Test", func() {
+	initTestVars()
+
+	var ns = "test"
+	var set = randString(50)
+	var client *Client
+	var key *Key
+	var em *ExpiringMap
+	var err error
+
+	BeforeEach(func() {
+		client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+
+		key, err = NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		em = NewExpiringMap(client, "tokens")
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("must not return expired entries", func() {
+		err = em.Put(nil, key, "phone", "token1", time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+		err = em.PutItems(nil, key, map[interface{}]interface{}{"tablet": "token2", "watch": "token3"}, time.Millisecond)
+		Expect(err).ToNot(HaveOccurred())
+		err = em.Put(nil, key, "laptop", "token4", 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		time.Sleep(10 * time.Millisecond)
+
+		value, err := em.Get(nil, key, "phone")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal("token1"))
+
+		value, err = em.Get(nil, key, "tablet")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(BeNil())
+
+		entries, err := em.GetAll(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(Equal(map[interface{}]interface{}{"phone": "token1", "laptop": "token4"}))
+
+		removed, err := em.Prune(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(Equal(2))
+
+		err = em.Remove(nil, key, "phone")
+		Expect(err).ToNot(HaveOccurred())
+		entries, err = em.GetAll(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(Equal(map[interface{}]interface{}{"laptop": "token4"}))
+	})
+
+	It("must prune the expired entries on writes", func() {
+		err = em.Put(nil, key, "old", 1, time.Millisecond)
+		Expect(err).ToNot(HaveOccurred())
+
+		time.Sleep(10 * time.Millisecond)
+
+		err = em.Put(nil, key, "new", 2, time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+
+		rec, err := client.Get(nil, key, "tokens")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(rec.Bins["tokens"].(map[interface{}]interface{}))).To(Equal(1))
+	})
+
+	It("must return nothing for missing records", func() {
+		value, err := em.Get(nil, key, "phone")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(BeNil())
+
+		entries, err := em.GetAll(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(BeNil())
+	})
+
+})
@@ -0,0 +1,146 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// ExportChunk is a run of consecutive records of a partition, in digest order,
+// produced by Client.ExportByDigest.
+type ExportChunk struct {
+	// PartitionId is the partition the records belong to.
+	PartitionId int
+
+	// Index is the position of the chunk in its partition, starting from 0.
+	Index int
+
+	// Records holds the records of the chunk in digest order. All chunks but the
+	// last of a partition hold exactly the chunk size records.
+	Records []*Record
+
+	// Checksum is a SHA-256 hash of the digests and generations of the records.
+	// A chunk whose partition, index and checksum match those of a previous
+	// export holds the same record versions, and can be skipped by differential
+	// backups.
+	Checksum []byte
+}
+
+func newExportChunk(partitionId, index int, records []*Record) *ExportChunk {
+	h := sha256.New()
+	var gen [4]byte
+	for _, rec := range records {
+		h.Write(rec.Key.Digest())
+		binary.BigEndian.PutUint32(gen[:], uint32(rec.Generation))
+		h.Write(gen[:])
+	}
+
+	return &ExportChunk{
+		PartitionId: partitionId,
+		Index:       index,
+		Records:     records,
+		Checksum:    h.Sum(nil),
+	}
+}
+
+// ExportByDigest reads the records of the namespace and set one partition at a
+// time, in partition id order and in digest order within each partition, and
+// passes them to fn in chunks of chunkSize records. The chunk boundaries only
+// depend on the records of the partition, so that successive exports of an
+// unchanged partition produce the same chunks.
+// A partition scan interrupted by a node error is resumed after its last record,
+// up to the policy's MaxRetries times. The export is aborted on the first error
+// returned by fn or by the scan, and that error is returned.
+// If the policy's PartitionFilter is set, only the partitions of the filter are
+// exported; the filter itself is not modified.
+// Requires server version 4.9+.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) ExportByDigest(apolicy *ScanPolicy, namespace string, setName string, chunkSize int, fn func(*ExportChunk) error, binNames ...string) error {
+	if chunkSize <= 0 {
+		return NewAerospikeError(PARAMETER_ERROR, "Export chunk size must be positive")
+	}
+
+	policy := clnt.getUsableScanPolicy(apolicy)
+
+	begin, count := 0, _PARTITIONS
+	if policy.PartitionFilter != nil {
+		if err := policy.PartitionFilter.validate(); err != nil {
+			return err
+		}
+		begin, count = policy.PartitionFilter.Begin(), policy.PartitionFilter.Count()
+	}
+
+	for partitionId := begin; partitionId < begin+count; partitionId++ {
+		if err := clnt.exportPartition(policy, namespace, setName, partitionId, chunkSize, fn, binNames); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportPartition exports a single partition for ExportByDigest.
+func (clnt *Client) exportPartition(policy *ScanPolicy, namespace string, setName string, partitionId int, chunkSize int, fn func(*ExportChunk) error, binNames []string) error {
+	// do not modify the caller's policy
+	sp := *policy
+	sp.PartitionFilter = NewPartitionFilterById(partitionId)
+	sp.MaxRecords = 0
+
+	index := 0
+	records := make([]*Record, 0, chunkSize)
+	var lastErr error
+	for attempt := 0; !sp.PartitionFilter.IsDone(); attempt++ {
+		if attempt > sp.MaxRetries {
+			if lastErr == nil {
+				lastErr = NewAerospikeError(SERVER_NOT_AVAILABLE, fmt.Sprintf("Partition %d could not be exported", partitionId))
+			}
+			return lastErr
+		}
+
+		res, err := clnt.ScanAll(&sp, namespace, setName, binNames...)
+		if err != nil {
+			return err
+		}
+
+		for r := range res.Results() {
+			if r.Err != nil {
+				if !isRetryableQueryError(r.Err) {
+					res.Close()
+					return r.Err
+				}
+				lastErr = r.Err
+				continue
+			}
+
+			records = append(records, r.Record)
+			if len(records) == chunkSize {
+				if err := fn(newExportChunk(partitionId, index, records)); err != nil {
+					res.Close()
+					return err
+				}
+				index++
+				records = make([]*Record, 0, chunkSize)
+			}
+		}
+	}
+
+	if len(records) > 0 {
+		return fn(newExportChunk(partitionId, index, records))
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export streams the records of a scan or query to an io.Writer as CSV
+// or JSON Lines, for quick data dumps without external tools.
+//
+//	recordset, err := client.ScanAll(nil, "test", "users")
+//	...
+//	n, err := export.CSV(os.Stdout, recordset, &export.Options{Bins: []string{"name", "age"}})
+package export
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+// Options determine which bins and metadata of the records are exported.
+type Options struct {
+	// Bins are the bins exported, in order. Default (nil) exports all the bins
+	// of each record for JSON Lines, and the bins of the first record, sorted by
+	// name, for CSV.
+	Bins []string
+
+	// Metadata exports the user key, digest, generation and expiration of the
+	// records. The user key is only available if it was stored with the record.
+	Metadata bool
+}
+
+// CSV writes the records of the recordset to w as CSV, with a header line naming
+// the columns: the metadata columns "key", "digest", "generation" and "expiration"
+// if requested, then the bins.
+// Strings, numbers and booleans are written as is, blobs as base64 and lists and
+// maps as JSON. Missing bins are written as empty fields.
+// The export is aborted on the first error returned by a node or by w; the
+// recordset is closed in either case. It returns the number of records written.
+func CSV(w io.Writer, recordset *Recordset, options *Options) (int, error) {
+	results := recordset.Results()
+	n, err := writeCSV(w, results, options)
+	closeRecordset(recordset, results)
+	return n, err
+}
+
+// JSONLines writes the records of the recordset to w as JSON Lines: one JSON object
+// per line, holding the bins of the record, or the record with its metadata and
+// bins if requested, as encoded by Record.MarshalJSON.
+// The export is aborted on the first error returned by a node or by w; the
+// recordset is closed in either case. It returns the number of records written.
+func JSONLines(w io.Writer, recordset *Recordset, options *Options) (int, error) {
+	results := recordset.Results()
+	n, err := writeJSONLines(w, results, options)
+	closeRecordset(recordset, results)
+	return n, err
+}
+
+// closeRecordset closes the recordset, and discards the results left.
+func closeRecordset(recordset *Recordset, results <-chan *Result) {
+	go func() {
+		for range results {
+		}
+	}()
+	recordset.Close()
+}
+
+func writeCSV(w io.Writer, results <-chan *Result, options *Options) (n int, err error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	// the records written before an error are flushed as well
+	out := csv.NewWriter(w)
+	defer func() {
+		out.Flush()
+		if err == nil {
+			err = out.Error()
+		}
+	}()
+
+	bins := options.Bins
+
+	for res := range results {
+		if res.Err != nil {
+			return n, res.Err
+		}
+
+		rec := res.Record
+		if err := rec.DecodeBins(); err != nil {
+			return n, err
+		}
+
+		if n == 0 {
+			if bins == nil {
+				bins = sortedBinNames(rec.Bins)
+			}
+
+			header := bins
+			if options.Metadata {
+				header = append([]string{"key", "digest", "generation", "expiration"}, bins...)
+			}
+			if err := out.Write(header); err != nil {
+				return n, err
+			}
+		}
+
+		row := make([]string, 0, len(bins)+4)
+		if options.Metadata {
+			row = append(row, formatValue(userKey(rec)), hex.EncodeToString(rec.Key.Digest()), strconv.Itoa(rec.Generation), strconv.Itoa(rec.Expiration))
+		}
+		for _, name := range bins {
+			row = append(row, formatValue(rec.Bins[name]))
+		}
+
+		if err := out.Write(row); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+func writeJSONLines(w io.Writer, results <-chan *Result, options *Options) (n int, err error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	// the records written before an error are flushed as well
+	out := bufio.NewWriter(w)
+	defer func() {
+		if ferr := out.Flush(); err == nil {
+			err = ferr
+		}
+	}()
+
+	encoder := json.NewEncoder(out)
+
+	for res := range results {
+		if res.Err != nil {
+			return n, res.Err
+		}
+
+		rec := res.Record
+		if err := rec.DecodeBins(); err != nil {
+			return n, err
+		}
+
+		bins := rec.Bins
+		if options.Bins != nil {
+			bins = make(BinMap, len(options.Bins))
+			for _, name := range options.Bins {
+				if value, exists := rec.Bins[name]; exists {
+					bins[name] = value
+				}
+			}
+		}
+
+		if options.Metadata {
+			err = encoder.Encode(&Record{Key: rec.Key, Bins: bins, Generation: rec.Generation, Expiration: rec.Expiration})
+		} else {
+			err = encoder.Encode(bins)
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// sortedBinNames returns the names of the bins, sorted.
+func sortedBinNames(bins BinMap) []string {
+	names := make([]string, 0, len(bins))
+	for name := range bins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// userKey returns the user key of the record, if it is available.
+func userKey(rec *Record) interface{} {
+	if rec.Key == nil || rec.Key.Value() == nil {
+		return nil
+	}
+	return rec.Key.Value().GetObject()
+}
+
+// formatValue formats a value for a CSV field.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}, map[interface{}]interface{}:
+		// encode the value like BinMap.MarshalJSON encodes bins, without the enclosing object
+		data, err := json.Marshal(BinMap{"v": v})
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data[len(`{"v":`) : len(data)-1])
+	}
+	return fmt.Sprint(value)
+}
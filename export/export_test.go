@@ -0,0 +1,114 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+func TestExport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Aerospike Client Library Export Suite")
+}
+
+var _ = Describe("Export Test", func() {
+
+	var records []*Record
+
+	BeforeEach(func() {
+		key1, err := NewKey("test", "set", "k1")
+		Expect(err).ToNot(HaveOccurred())
+		key2, err := NewKey("test", "set", 2)
+		Expect(err).ToNot(HaveOccurred())
+
+		records = []*Record{
+			{Key: key1, Generation: 1, Expiration: 100, Bins: BinMap{"name": "a,b", "age": 30, "blob": []byte{1, 2}}},
+			{Key: key2, Generation: 2, Expiration: 200, Bins: BinMap{"name": "c", "tags": []interface{}{"x", 1}, "attrs": map[interface{}]interface{}{"k": 1.5}}},
+		}
+	})
+
+	results := func(err error, records ...*Record) <-chan *Result {
+		res := make(chan *Result, len(records)+1)
+		for _, rec := range records {
+			res <- &Result{Record: rec}
+		}
+		if err != nil {
+			res <- &Result{Err: err}
+		}
+		close(res)
+		return res
+	}
+
+	It("should write CSV with the bins of the first record", func() {
+		var buf bytes.Buffer
+		n, err := writeCSV(&buf, results(nil, records...), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(Equal(2))
+		Expect(buf.String()).To(Equal("age,blob,name\n30,AQI=,\"a,b\"\n,,c\n"))
+	})
+
+	It("should write CSV with the selected bins and metadata", func() {
+		var buf bytes.Buffer
+		n, err := writeCSV(&buf, results(nil, records...), &Options{Bins: []string{"name", "tags", "attrs"}, Metadata: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(Equal(2))
+
+		lines := strings.Split(buf.String(), "\n")
+		Expect(lines[0]).To(Equal("key,digest,generation,expiration,name,tags,attrs"))
+		Expect(strings.HasPrefix(lines[1], "k1,")).To(BeTrue())
+		Expect(strings.HasSuffix(lines[1], ",1,100,\"a,b\",,")).To(BeTrue())
+		Expect(strings.HasPrefix(lines[2], "2,")).To(BeTrue())
+		Expect(strings.HasSuffix(lines[2], `,2,200,c,"[""x"",1]","{""k"":1.5}"`)).To(BeTrue())
+	})
+
+	It("should write JSON Lines with the selected bins", func() {
+		var buf bytes.Buffer
+		n, err := writeJSONLines(&buf, results(nil, records...), &Options{Bins: []string{"name", "attrs"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(Equal(2))
+		Expect(buf.String()).To(Equal("{\"name\":\"a,b\"}\n{\"attrs\":{\"k\":1.5},\"name\":\"c\"}\n"))
+	})
+
+	It("should write JSON Lines with the metadata", func() {
+		var buf bytes.Buffer
+		_, err := writeJSONLines(&buf, results(nil, records[1]), &Options{Bins: []string{"name"}, Metadata: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.HasPrefix(buf.String(), `{"namespace":"test","set":"set","key":2,"digest":"`)).To(BeTrue())
+		Expect(strings.HasSuffix(buf.String(), `","generation":2,"expiration":200,"bins":{"name":"c"}}`+"\n")).To(BeTrue())
+	})
+
+	It("should abort on the first error, after flushing the records written", func() {
+		var buf bytes.Buffer
+		n, err := writeJSONLines(&buf, results(errors.New("node failed"), records...), nil)
+		Expect(err).To(MatchError("node failed"))
+		Expect(n).To(Equal(2))
+		Expect(strings.Count(buf.String(), "\n")).To(Equal(2))
+
+		buf.Reset()
+		n, err = writeCSV(&buf, results(errors.New("node failed"), records...), nil)
+		Expect(err).To(MatchError("node failed"))
+		Expect(n).To(Equal(2))
+		Expect(buf.String()).To(Equal("age,blob,name\n30,AQI=,\"a,b\"\n,,c\n"))
+	})
+
+})
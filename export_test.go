@@ -0,0 +1,114 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("Export Test", func() {
+	initTestVars()
+
+	var ns = "test"
+	var set = randString(50)
+	var client *Client
+	var err error
+
+	const keyCount = 200
+
+	BeforeEach(func() {
+		client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := 0; i < keyCount; i++ {
+			key, err := NewKey(ns, set, i)
+			Expect(err).ToNot(HaveOccurred())
+			err = client.PutBins(nil, key, NewBin("bin", i))
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	export := func(policy *ScanPolicy) []*ExportChunk {
+		var chunks []*ExportChunk
+		err := client.ExportByDigest(policy, ns, set, 3, func(chunk *ExportChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return chunks
+	}
+
+	It("must export the records in digest order in stable chunks", func() {
+		chunks := export(nil)
+
+		total := 0
+		for i, chunk := range chunks {
+			total += len(chunk.Records)
+			Expect(len(chunk.Records) <= 3).To(BeTrue())
+
+			if i == 0 {
+				continue
+			}
+			prev := chunks[i-1]
+			Expect(prev.PartitionId <= chunk.PartitionId).To(BeTrue())
+			if prev.PartitionId == chunk.PartitionId {
+				Expect(chunk.Index).To(Equal(prev.Index + 1))
+				Expect(len(prev.Records)).To(Equal(3))
+				last := prev.Records[len(prev.Records)-1].Key.Digest()
+				Expect(bytes.Compare(last, chunk.Records[0].Key.Digest()) < 0).To(BeTrue())
+			} else {
+				Expect(chunk.Index).To(Equal(0))
+			}
+		}
+		Expect(total).To(Equal(keyCount))
+
+		// an unchanged set exports the same chunks
+		again := export(nil)
+		Expect(len(again)).To(Equal(len(chunks)))
+		for i := range chunks {
+			Expect(again[i].Checksum).To(Equal(chunks[i].Checksum))
+		}
+
+		// rewriting a record only changes the checksum of its chunk
+		changed := chunks[0].Records[0]
+		err = client.PutBins(nil, changed.Key, NewBin("bin", -1))
+		Expect(err).ToNot(HaveOccurred())
+
+		again = export(nil)
+		Expect(again[0].Checksum).ToNot(Equal(chunks[0].Checksum))
+		for i := 1; i < len(chunks); i++ {
+			Expect(again[i].Checksum).To(Equal(chunks[i].Checksum))
+		}
+	})
+
+	It("must only export the partitions of the filter", func() {
+		policy := NewScanPolicy()
+		policy.PartitionFilter = NewPartitionFilterByRange(100, 50)
+
+		for _, chunk := range export(policy) {
+			Expect(chunk.PartitionId >= 100 && chunk.PartitionId < 150).To(BeTrue())
+		}
+	})
+
+})
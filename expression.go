@@ -0,0 +1,342 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"encoding/base64"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// Expression operation codes.
+const (
+	_EXP_EQ            = 1
+	_EXP_NE            = 2
+	_EXP_GT            = 3
+	_EXP_GE            = 4
+	_EXP_LT            = 5
+	_EXP_LE            = 6
+	_EXP_REGEX         = 7
+	_EXP_AND           = 16
+	_EXP_OR            = 17
+	_EXP_NOT           = 18
+	_EXP_DIGEST_MODULO = 64
+	_EXP_DEVICE_SIZE   = 65
+	_EXP_LAST_UPDATE   = 66
+	_EXP_SINCE_UPDATE  = 67
+	_EXP_VOID_TIME     = 68
+	_EXP_TTL           = 69
+	_EXP_SET_NAME      = 70
+	_EXP_KEY_EXISTS    = 71
+	_EXP_IS_TOMBSTONE  = 72
+	_EXP_KEY           = 80
+	_EXP_BIN           = 81
+	_EXP_BIN_TYPE      = 82
+
+	// marks a literal value node
+	_EXP_VAL = -1
+)
+
+// ExpType determines the type of a bin or record key read in an expression.
+type ExpType int
+
+const (
+	EXP_TYPE_NIL    ExpType = 0
+	EXP_TYPE_BOOL   ExpType = 1
+	EXP_TYPE_INT    ExpType = 2
+	EXP_TYPE_STRING ExpType = 3
+	EXP_TYPE_LIST   ExpType = 4
+	EXP_TYPE_MAP    ExpType = 5
+	EXP_TYPE_BLOB   ExpType = 6
+	EXP_TYPE_FLOAT  ExpType = 7
+	EXP_TYPE_GEO    ExpType = 8
+	EXP_TYPE_HLL    ExpType = 9
+)
+
+// Expression is a predicate evaluated by the server against each record's
+// metadata and bins. When set as the FilterExpression of a policy, records
+// for which the expression is false are skipped by scans and queries.
+//
+// Expressions are built by nesting the Exp* functions, for example:
+//
+//	// a >= 11 and the record was updated in the last hour
+//	ExpAnd(
+//		ExpGreaterEq(ExpIntBin("a"), ExpIntVal(11)),
+//		ExpLess(ExpSinceUpdate(), ExpIntVal(3600000)),
+//	)
+type Expression struct {
+	op    int
+	val   Value
+	bin   string
+	args  []*Expression
+	flags int64
+}
+
+// String returns the base64 encoding of the packed expression.
+func (exp *Expression) String() string {
+	b, err := exp.pack()
+	if err != nil {
+		return err.Error()
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// pack returns the wire representation of the expression, or nil for a nil expression.
+func (exp *Expression) pack() ([]byte, error) {
+	if exp == nil {
+		return nil, nil
+	}
+
+	packer := newPacker()
+	if err := exp.packTo(packer); err != nil {
+		return nil, err
+	}
+	return packer.buffer.Bytes(), nil
+}
+
+func (exp *Expression) packTo(packer *packer) error {
+	switch exp.op {
+	case _EXP_VAL:
+		return exp.val.pack(packer)
+	case _EXP_BIN:
+		packer.PackArrayBegin(3)
+		packer.PackAInt(_EXP_BIN)
+		packer.PackALong(exp.flags)
+		packRawString(packer, exp.bin)
+		return nil
+	case _EXP_BIN_TYPE:
+		packer.PackArrayBegin(2)
+		packer.PackAInt(_EXP_BIN_TYPE)
+		packRawString(packer, exp.bin)
+		return nil
+	case _EXP_KEY, _EXP_DIGEST_MODULO:
+		packer.PackArrayBegin(2)
+		packer.PackAInt(exp.op)
+		packer.PackALong(exp.flags)
+		return nil
+	case _EXP_REGEX:
+		packer.PackArrayBegin(4)
+		packer.PackAInt(_EXP_REGEX)
+		packer.PackALong(exp.flags)
+		packRawString(packer, exp.bin)
+		return exp.args[0].packTo(packer)
+	}
+
+	packer.PackArrayBegin(len(exp.args) + 1)
+	packer.PackAInt(exp.op)
+	for _, arg := range exp.args {
+		if arg == nil {
+			return NewAerospikeError(PARAMETER_ERROR, "Expression argument cannot be nil")
+		}
+		if err := arg.packTo(packer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// packRawString packs a string without the particle type prefix used for values.
+func packRawString(packer *packer, str string) {
+	packer.PackByteArrayBegin(len(str))
+	packer.buffer.WriteString(str)
+}
+
+func newCmdExp(op int, args ...*Expression) *Expression {
+	return &Expression{op: op, args: args}
+}
+
+func newValExp(val Value) *Expression {
+	return &Expression{op: _EXP_VAL, val: val}
+}
+
+//-------------------------------------------------------
+// Values
+//-------------------------------------------------------
+
+// ExpIntVal creates a 64 bit integer value.
+func ExpIntVal(val int64) *Expression {
+	return newValExp(LongValue(val))
+}
+
+// ExpStringVal creates a string value.
+func ExpStringVal(val string) *Expression {
+	return newValExp(StringValue(val))
+}
+
+// ExpBoolVal creates a boolean value.
+func ExpBoolVal(val bool) *Expression {
+	return newValExp(BoolValue(val))
+}
+
+// ExpBlobVal creates a byte array value.
+func ExpBlobVal(val []byte) *Expression {
+	return newValExp(BytesValue(val))
+}
+
+//-------------------------------------------------------
+// Bins and record key
+//-------------------------------------------------------
+
+// ExpBin reads the value of a bin of the given type.
+func ExpBin(name string, binType ExpType) *Expression {
+	return &Expression{op: _EXP_BIN, bin: name, flags: int64(binType)}
+}
+
+// ExpIntBin reads an integer bin.
+func ExpIntBin(name string) *Expression {
+	return ExpBin(name, EXP_TYPE_INT)
+}
+
+// ExpStringBin reads a string bin.
+func ExpStringBin(name string) *Expression {
+	return ExpBin(name, EXP_TYPE_STRING)
+}
+
+// ExpBoolBin reads a boolean bin.
+func ExpBoolBin(name string) *Expression {
+	return ExpBin(name, EXP_TYPE_BOOL)
+}
+
+// ExpBlobBin reads a byte array bin.
+func ExpBlobBin(name string) *Expression {
+	return ExpBin(name, EXP_TYPE_BLOB)
+}
+
+// ExpBinType returns the particle type of a bin, or 0 if the bin does not exist.
+func ExpBinType(name string) *Expression {
+	return &Expression{op: _EXP_BIN_TYPE, bin: name}
+}
+
+// ExpBinExists is true if the bin exists in the record.
+func ExpBinExists(name string) *Expression {
+	return ExpNotEq(ExpBinType(name), ExpIntVal(0))
+}
+
+// ExpKey reads the user key of the record, if it was stored with SendKey.
+func ExpKey(keyType ExpType) *Expression {
+	return &Expression{op: _EXP_KEY, flags: int64(keyType)}
+}
+
+//-------------------------------------------------------
+// Record metadata
+//-------------------------------------------------------
+
+// ExpKeyExists is true if the user key was stored with the record.
+func ExpKeyExists() *Expression {
+	return newCmdExp(_EXP_KEY_EXISTS)
+}
+
+// ExpSetName returns the name of the set the record belongs to.
+func ExpSetName() *Expression {
+	return newCmdExp(_EXP_SET_NAME)
+}
+
+// ExpDeviceSize returns the size of the record on disk in bytes,
+// or 0 for in-memory namespaces.
+func ExpDeviceSize() *Expression {
+	return newCmdExp(_EXP_DEVICE_SIZE)
+}
+
+// ExpLastUpdate returns the last update time of the record in nanoseconds since the Unix epoch.
+func ExpLastUpdate() *Expression {
+	return newCmdExp(_EXP_LAST_UPDATE)
+}
+
+// ExpSinceUpdate returns the number of milliseconds since the record was last updated.
+func ExpSinceUpdate() *Expression {
+	return newCmdExp(_EXP_SINCE_UPDATE)
+}
+
+// ExpVoidTime returns the expiration time of the record in nanoseconds since
+// the Unix epoch, or -1 if the record never expires.
+func ExpVoidTime() *Expression {
+	return newCmdExp(_EXP_VOID_TIME)
+}
+
+// ExpTTL returns the remaining time to live of the record in seconds,
+// or -1 if the record never expires.
+func ExpTTL() *Expression {
+	return newCmdExp(_EXP_TTL)
+}
+
+// ExpIsTombstone is true if the record is a tombstone left by a durable delete.
+func ExpIsTombstone() *Expression {
+	return newCmdExp(_EXP_IS_TOMBSTONE)
+}
+
+// ExpDigestModulo returns the record digest modulo mod.
+// It can be used to sample a fraction of the records.
+func ExpDigestModulo(mod int64) *Expression {
+	return &Expression{op: _EXP_DIGEST_MODULO, flags: mod}
+}
+
+//-------------------------------------------------------
+// Comparisons
+//-------------------------------------------------------
+
+// ExpEq is true if left == right.
+func ExpEq(left, right *Expression) *Expression {
+	return newCmdExp(_EXP_EQ, left, right)
+}
+
+// ExpNotEq is true if left != right.
+func ExpNotEq(left, right *Expression) *Expression {
+	return newCmdExp(_EXP_NE, left, right)
+}
+
+// ExpGreater is true if left > right.
+func ExpGreater(left, right *Expression) *Expression {
+	return newCmdExp(_EXP_GT, left, right)
+}
+
+// ExpGreaterEq is true if left >= right.
+func ExpGreaterEq(left, right *Expression) *Expression {
+	return newCmdExp(_EXP_GE, left, right)
+}
+
+// ExpLess is true if left < right.
+func ExpLess(left, right *Expression) *Expression {
+	return newCmdExp(_EXP_LT, left, right)
+}
+
+// ExpLessEq is true if left <= right.
+func ExpLessEq(left, right *Expression) *Expression {
+	return newCmdExp(_EXP_LE, left, right)
+}
+
+// ExpRegexCompare is true if the string expression matches the regular expression.
+// Flags are the POSIX regcomp flags (1 = extended, 2 = ignore case, 4 = no sub, 8 = newline).
+func ExpRegexCompare(regex string, flags int64, str *Expression) *Expression {
+	return &Expression{op: _EXP_REGEX, bin: regex, flags: flags, args: []*Expression{str}}
+}
+
+//-------------------------------------------------------
+// Logical operators
+//-------------------------------------------------------
+
+// ExpAnd is true if all expressions are true.
+func ExpAnd(exps ...*Expression) *Expression {
+	return newCmdExp(_EXP_AND, exps...)
+}
+
+// ExpOr is true if any of the expressions is true.
+func ExpOr(exps ...*Expression) *Expression {
+	return newCmdExp(_EXP_OR, exps...)
+}
+
+// ExpNot negates the expression.
+func ExpNot(exp *Expression) *Expression {
+	return newCmdExp(_EXP_NOT, exp)
+}
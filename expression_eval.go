@@ -0,0 +1,302 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// POSIX regcomp flags of ExpRegexCompare.
+const (
+	_REGEX_ICASE   = 2
+	_REGEX_NEWLINE = 8
+)
+
+// checkClientSide returns an error if the expression reads record metadata
+// which is not sent to the client, and thus cannot be evaluated by matches.
+func (exp *Expression) checkClientSide() error {
+	switch exp.op {
+	case _EXP_DEVICE_SIZE, _EXP_LAST_UPDATE, _EXP_SINCE_UPDATE, _EXP_DIGEST_MODULO:
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Expression operation %d cannot be evaluated by the client.", exp.op))
+	}
+
+	for _, arg := range exp.args {
+		if arg == nil {
+			return NewAerospikeError(PARAMETER_ERROR, "Expression argument cannot be nil")
+		}
+		if err := arg.checkClientSide(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matches evaluates the expression against a record read by the client, for
+// servers which do not support filter expressions. Like on the server, the
+// records for which the expression is unknown, e.g. because a bin does not
+// exist or has another type, do not match.
+// Regular expressions use the Go syntax, which is close to the POSIX extended syntax.
+func (exp *Expression) matches(rec *Record) (bool, error) {
+	res, err := exp.eval(rec)
+	if err != nil {
+		return false, err
+	}
+	b, ok := res.(bool)
+	return ok && b, nil
+}
+
+// eval returns the value of the expression for the record, or nil if it is unknown.
+// Integers are returned as int64.
+func (exp *Expression) eval(rec *Record) (interface{}, error) {
+	switch exp.op {
+	case _EXP_VAL:
+		return expValueOf(exp.val.GetObject()), nil
+
+	case _EXP_BIN:
+		value, exists := rec.Bins[exp.bin]
+		if !exists || expTypeOf(value) != ExpType(exp.flags) {
+			return nil, nil
+		}
+		return expValueOf(value), nil
+
+	case _EXP_BIN_TYPE:
+		value, exists := rec.Bins[exp.bin]
+		if !exists {
+			return int64(0), nil
+		}
+		return int64(NewValue(value).GetType()), nil
+
+	case _EXP_KEY:
+		if rec.Key == nil || rec.Key.Value() == nil {
+			return nil, nil
+		}
+		value := rec.Key.Value().GetObject()
+		if expTypeOf(value) != ExpType(exp.flags) {
+			return nil, nil
+		}
+		return expValueOf(value), nil
+
+	case _EXP_KEY_EXISTS:
+		return rec.Key != nil && rec.Key.Value() != nil, nil
+
+	case _EXP_SET_NAME:
+		if rec.Key == nil {
+			return nil, nil
+		}
+		return rec.Key.SetName(), nil
+
+	case _EXP_TTL:
+		// records which never expire have a void time of 0, which is in the past
+		if rec.Expiration < 0 {
+			return int64(-1), nil
+		}
+		return int64(rec.Expiration), nil
+
+	case _EXP_VOID_TIME:
+		if rec.Expiration < 0 {
+			return int64(-1), nil
+		}
+		return time.Now().Add(time.Duration(rec.Expiration) * time.Second).UnixNano(), nil
+
+	case _EXP_IS_TOMBSTONE:
+		// scans and queries do not return tombstones
+		return false, nil
+
+	case _EXP_REGEX:
+		return exp.evalRegex(rec)
+
+	case _EXP_EQ, _EXP_NE, _EXP_GT, _EXP_GE, _EXP_LT, _EXP_LE:
+		left, err := exp.args[0].eval(rec)
+		if err != nil {
+			return nil, err
+		}
+		right, err := exp.args[1].eval(rec)
+		if err != nil {
+			return nil, err
+		}
+		return compareExpValues(exp.op, left, right), nil
+
+	case _EXP_AND, _EXP_OR:
+		// false for AND, and true for OR, decide the result even if another argument is unknown
+		decisive := exp.op == _EXP_OR
+		var res interface{} = !decisive
+		for _, arg := range exp.args {
+			value, err := arg.eval(rec)
+			if err != nil {
+				return nil, err
+			}
+			b, ok := value.(bool)
+			if !ok {
+				res = nil
+			} else if b == decisive {
+				return decisive, nil
+			}
+		}
+		return res, nil
+
+	case _EXP_NOT:
+		value, err := exp.args[0].eval(rec)
+		if err != nil {
+			return nil, err
+		}
+		if b, ok := value.(bool); ok {
+			return !b, nil
+		}
+		return nil, nil
+	}
+
+	return nil, NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Expression operation %d cannot be evaluated by the client.", exp.op))
+}
+
+// evalRegex matches the string argument against the regular expression.
+func (exp *Expression) evalRegex(rec *Record) (interface{}, error) {
+	value, err := exp.args[0].eval(rec)
+	if err != nil {
+		return nil, err
+	}
+	str, ok := value.(string)
+	if !ok {
+		return nil, nil
+	}
+
+	// without REG_NEWLINE, POSIX regular expressions match newlines with dots
+	flags := "(?s)"
+	if exp.flags&_REGEX_NEWLINE != 0 {
+		flags = "(?m)"
+	}
+	if exp.flags&_REGEX_ICASE != 0 {
+		flags += "(?i)"
+	}
+
+	re, err := regexp.Compile(flags + exp.bin)
+	if err != nil {
+		return nil, NewAerospikeError(PARAMETER_ERROR, err.Error())
+	}
+	return re.MatchString(str), nil
+}
+
+// expTypeOf returns the expression type of a bin or key value.
+func expTypeOf(value interface{}) ExpType {
+	switch value.(type) {
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		return EXP_TYPE_INT
+	case float32, float64:
+		return EXP_TYPE_FLOAT
+	case string:
+		return EXP_TYPE_STRING
+	case bool:
+		return EXP_TYPE_BOOL
+	case []byte:
+		return EXP_TYPE_BLOB
+	case []interface{}:
+		return EXP_TYPE_LIST
+	case map[interface{}]interface{}:
+		return EXP_TYPE_MAP
+	case GeoJSONValue:
+		return EXP_TYPE_GEO
+	case HLLValue:
+		return EXP_TYPE_HLL
+	}
+	return EXP_TYPE_NIL
+}
+
+// expValueOf converts integers to int64 and floats to float64, so that they can be compared.
+func expValueOf(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
+
+// compareExpValues compares two values of the same type, and returns nil if
+// either is unknown, or if they cannot be compared.
+func compareExpValues(op int, left, right interface{}) interface{} {
+	if left == nil || right == nil || reflect.TypeOf(left) != reflect.TypeOf(right) {
+		return nil
+	}
+
+	var cmp int
+	switch l := left.(type) {
+	case int64:
+		r := right.(int64)
+		cmp = compareOrdered(l < r, l > r)
+	case float64:
+		r := right.(float64)
+		cmp = compareOrdered(l < r, l > r)
+	case string:
+		r := right.(string)
+		cmp = compareOrdered(l < r, l > r)
+	case bool:
+		r := right.(bool)
+		cmp = compareOrdered(!l && r, l && !r)
+	case []byte:
+		cmp = bytes.Compare(l, right.([]byte))
+	default:
+		// lists, maps and other values are only compared for equality
+		switch op {
+		case _EXP_EQ:
+			return reflect.DeepEqual(left, right)
+		case _EXP_NE:
+			return !reflect.DeepEqual(left, right)
+		}
+		return nil
+	}
+
+	switch op {
+	case _EXP_EQ:
+		return cmp == 0
+	case _EXP_NE:
+		return cmp != 0
+	case _EXP_GT:
+		return cmp > 0
+	case _EXP_GE:
+		return cmp >= 0
+	case _EXP_LT:
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
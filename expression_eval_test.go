@@ -0,0 +1,71 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Expression Evaluation Test", func() {
+
+	var rec *Record
+
+	BeforeEach(func() {
+		key, err := NewKey("test", "set", "key")
+		Expect(err).ToNot(HaveOccurred())
+		rec = newRecord(nil, key, BinMap{"a": 11, "s": "Hello\nWorld"}, 1, 100)
+	})
+
+	matches := func(exp *Expression) bool {
+		res, err := exp.matches(rec)
+		Expect(err).ToNot(HaveOccurred())
+		return res
+	}
+
+	It("should compare bins and metadata", func() {
+		Expect(matches(ExpGreaterEq(ExpIntBin("a"), ExpIntVal(11)))).To(BeTrue())
+		Expect(matches(ExpLess(ExpIntBin("a"), ExpIntVal(11)))).To(BeFalse())
+		Expect(matches(ExpEq(ExpSetName(), ExpStringVal("set")))).To(BeTrue())
+		Expect(matches(ExpLessEq(ExpTTL(), ExpIntVal(100)))).To(BeTrue())
+		Expect(matches(ExpBinExists("b"))).To(BeFalse())
+		Expect(matches(ExpKeyExists())).To(BeTrue())
+	})
+
+	It("should not match unknown values", func() {
+		// missing bin, and bin of another type
+		Expect(matches(ExpEq(ExpIntBin("b"), ExpIntVal(0)))).To(BeFalse())
+		Expect(matches(ExpNot(ExpEq(ExpIntBin("b"), ExpIntVal(0))))).To(BeFalse())
+		Expect(matches(ExpEq(ExpStringBin("a"), ExpStringVal("11")))).To(BeFalse())
+
+		// unless the other operands decide the result
+		Expect(matches(ExpOr(ExpEq(ExpIntBin("b"), ExpIntVal(0)), ExpBinExists("a")))).To(BeTrue())
+		Expect(matches(ExpAnd(ExpEq(ExpIntBin("b"), ExpIntVal(0)), ExpBinExists("a")))).To(BeFalse())
+	})
+
+	It("should match regular expressions with the POSIX flags", func() {
+		Expect(matches(ExpRegexCompare("hello.world", 0, ExpStringBin("s")))).To(BeFalse())
+		Expect(matches(ExpRegexCompare("hello.world", 2, ExpStringBin("s")))).To(BeTrue())
+		Expect(matches(ExpRegexCompare("Hello.World", 8, ExpStringBin("s")))).To(BeFalse())
+		Expect(matches(ExpRegexCompare("^World", 8, ExpStringBin("s")))).To(BeTrue())
+	})
+
+	It("should reject the metadata which is not sent to the client", func() {
+		Expect(ExpLess(ExpSinceUpdate(), ExpIntVal(1000)).checkClientSide()).To(HaveOccurred())
+		Expect(ExpEq(ExpDigestModulo(3), ExpIntVal(0)).checkClientSide()).To(HaveOccurred())
+		Expect(ExpGreater(ExpTTL(), ExpIntVal(0)).checkClientSide()).ToNot(HaveOccurred())
+	})
+
+})
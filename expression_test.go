@@ -0,0 +1,43 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("Expression Test", func() {
+	initTestVars()
+
+	It("must pack bin comparisons", func() {
+		exp := ExpGreaterEq(ExpIntBin("a"), ExpIntVal(11))
+		Expect(exp.String()).To(Equal("kwSTUQKhYQs="))
+	})
+
+	It("must pack logical operators with metadata and string values", func() {
+		exp := ExpAnd(
+			ExpEq(ExpStringBin("s"), ExpStringVal("x")),
+			ExpLess(ExpSinceUpdate(), ExpIntVal(1000)),
+		)
+		Expect(exp.String()).To(Equal("kxCTAZNRA6FzogN4kwWRQ80D6A=="))
+	})
+
+	It("must pack bin existence checks", func() {
+		Expect(ExpBinExists("b").String()).To(Equal("kwKSUqFiAA=="))
+	})
+})
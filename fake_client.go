@@ -0,0 +1,1171 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+)
+
+// FakeClient is an in-memory implementation of ClientIfc for unit tests which
+// need neither a server nor a network. Like a single server node, it honors the
+// keys, generations and expirations of the records, the RecordExistsAction,
+// GenerationPolicy, Expiration and SendKey of write policies, and the read,
+// write, add, append, prepend, touch and delete operations. Bin values are
+// read back as the client decodes them from a server, e.g. integers as int.
+// Commands which need server side features, such as CDT, bit and HLL operations,
+// expressions, UDFs, queries, secondary indexes, consistency tokens and user
+// administration, fail with UNSUPPORTED_FEATURE. Timeouts and retries are ignored.
+// A FakeClient is safe for concurrent use.
+type FakeClient struct {
+	// DefaultTTL is the TTL of the records written with an expiration of 0, like
+	// the default-ttl of a namespace. Default (0) is never expire.
+	DefaultTTL time.Duration
+
+	// Now returns the current time, e.g. to expire records in tests without
+	// waiting. Default (nil) is time.Now.
+	Now func() time.Time
+
+	mutex     sync.Mutex
+	records   map[fakeKey]*fakeRecord
+	connected bool
+}
+
+var _ ClientIfc = &FakeClient{}
+
+type fakeKey struct {
+	namespace string
+	digest    string
+}
+
+type fakeRecord struct {
+	// key holds the user key only if it was sent
+	key        *Key
+	bins       map[string]fakeBin
+	generation int
+	voidTime   time.Time // zero if the record never expires
+	lastUpdate time.Time
+}
+
+// fakeBin is a bin value in the wire protocol, so that values are decoded
+// like those received from a server, and never shared with the callers.
+type fakeBin struct {
+	particleType int
+	data         []byte
+}
+
+// NewFakeClient generates an empty, connected FakeClient.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		records:   map[fakeKey]*fakeRecord{},
+		connected: true,
+	}
+}
+
+// Close closes the client. Its records are kept.
+func (fc *FakeClient) Close() {
+	fc.mutex.Lock()
+	fc.connected = false
+	fc.mutex.Unlock()
+}
+
+// IsConnected returns true until the client is closed.
+func (fc *FakeClient) IsConnected() bool {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+	return fc.connected
+}
+
+// Stats returns empty statistics.
+func (fc *FakeClient) Stats() *ClusterStats {
+	return &ClusterStats{Build: GetBuildInfo(), Nodes: map[string]NodeStats{}}
+}
+
+// ResetStats returns empty statistics.
+func (fc *FakeClient) ResetStats() *ClusterStats {
+	return fc.Stats()
+}
+
+// GetNodeNames returns no node names.
+func (fc *FakeClient) GetNodeNames() []string {
+	return []string{}
+}
+
+// CloseGracefully closes the client; no command is ever in progress.
+func (fc *FakeClient) CloseGracefully(timeout time.Duration) error {
+	fc.Close()
+	return nil
+}
+
+// Len returns the number of records which have not expired.
+func (fc *FakeClient) Len() int {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	now := fc.now()
+	n := 0
+	for _, rec := range fc.records {
+		if !rec.expired(now) {
+			n++
+		}
+	}
+	return n
+}
+
+//-------------------------------------------------------
+// Write Record Operations
+//-------------------------------------------------------
+
+// Put writes the bins of the record like Client.Put.
+func (fc *FakeClient) Put(policy *WritePolicy, key *Key, binMap BinMap) error {
+	return fc.PutBins(policy, key, binMapToNewBins(binMap)...)
+}
+
+// PutBins writes the bins of the record like Client.PutBins.
+func (fc *FakeClient) PutBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
+	_, _, err := fc.operate(policy, key, binOperations(WRITE, bins)())
+	return err
+}
+
+// PutObject writes the fields of the object like Client.PutObject.
+func (fc *FakeClient) PutObject(policy *WritePolicy, key *Key, obj interface{}) (err error) {
+	bins, err := marshal(obj)
+	if err != nil {
+		return err
+	}
+	return fc.PutBins(policy, key, bins...)
+}
+
+// PutJSON writes the members of the JSON object as bins like Client.PutJSON.
+func (fc *FakeClient) PutJSON(policy *WritePolicy, key *Key, data []byte) error {
+	var bins BinMap
+	if err := json.Unmarshal(data, &bins); err != nil {
+		return NewAerospikeError(PARAMETER_ERROR, "Invalid JSON object: "+err.Error())
+	}
+	return fc.Put(policy, key, bins)
+}
+
+// PutIfAbsent creates the record only if it does not exist yet, like Client.PutIfAbsent.
+func (fc *FakeClient) PutIfAbsent(policy *WritePolicy, key *Key, bins ...*Bin) (bool, error) {
+	return fc.putConditionally(policy, key, CREATE_ONLY, NONE, 0, KEY_EXISTS_ERROR, bins)
+}
+
+// ReplaceOnly replaces the bins of the record only if it exists, like Client.ReplaceOnly.
+func (fc *FakeClient) ReplaceOnly(policy *WritePolicy, key *Key, bins ...*Bin) (bool, error) {
+	return fc.putConditionally(policy, key, REPLACE_ONLY, NONE, 0, KEY_NOT_FOUND_ERROR, bins)
+}
+
+// ReplaceIfGeneration replaces the bins of the record only if its generation has not
+// changed, like Client.ReplaceIfGeneration.
+func (fc *FakeClient) ReplaceIfGeneration(policy *WritePolicy, key *Key, generation int, bins ...*Bin) (bool, error) {
+	return fc.putConditionally(policy, key, REPLACE_ONLY, EXPECT_GEN_EQUAL, int32(generation), GENERATION_ERROR, bins)
+}
+
+func (fc *FakeClient) putConditionally(policy *WritePolicy, key *Key, action RecordExistsAction, genPolicy GenerationPolicy, generation int32, tolerated ResultCode, bins []*Bin) (bool, error) {
+	// do not modify the caller's policy
+	wp := *fakeWritePolicy(policy)
+	wp.RecordExistsAction = action
+	wp.GenerationPolicy = genPolicy
+	wp.Generation = generation
+
+	err := fc.PutBins(&wp, key, bins...)
+	if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == tolerated {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Update applies fn to the record with optimistic locking like Client.Update.
+func (fc *FakeClient) Update(policy *WritePolicy, key *Key, maxAttempts int, fn func(rec *Record) (BinMap, error)) error {
+	policy = fakeWritePolicy(policy)
+	if maxAttempts <= 0 {
+		maxAttempts = _DEFAULT_UPDATE_ATTEMPTS
+	}
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var rec *Record
+		if rec, _, err = fc.operate(policy, key, []*Operation{GetOp()}); err != nil {
+			return err
+		}
+
+		var bins BinMap
+		if bins, err = fn(rec); err != nil {
+			return err
+		}
+		if len(bins) == 0 {
+			return nil
+		}
+
+		wp := *policy
+		if rec == nil {
+			wp.RecordExistsAction = CREATE_ONLY
+			wp.GenerationPolicy = NONE
+		} else {
+			wp.GenerationPolicy = EXPECT_GEN_EQUAL
+			wp.Generation = int32(rec.Generation)
+		}
+
+		err = fc.Put(&wp, key, bins)
+		if ae, ok := err.(AerospikeError); ok && (ae.ResultCode() == GENERATION_ERROR || ae.ResultCode() == KEY_EXISTS_ERROR) {
+			// modified concurrently; read the record again
+			continue
+		}
+		return err
+	}
+	return err
+}
+
+// Append appends the string or blob values to the bins like Client.Append.
+func (fc *FakeClient) Append(policy *WritePolicy, key *Key, binMap BinMap) error {
+	return fc.AppendBins(policy, key, binMapToNewBins(binMap)...)
+}
+
+// AppendBins appends the string or blob values to the bins like Client.AppendBins.
+func (fc *FakeClient) AppendBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
+	_, _, err := fc.operate(policy, key, binOperations(APPEND, bins)())
+	return err
+}
+
+// Prepend prepends the string or blob values to the bins like Client.Prepend.
+func (fc *FakeClient) Prepend(policy *WritePolicy, key *Key, binMap BinMap) error {
+	return fc.PrependBins(policy, key, binMapToNewBins(binMap)...)
+}
+
+// PrependBins prepends the string or blob values to the bins like Client.PrependBins.
+func (fc *FakeClient) PrependBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
+	_, _, err := fc.operate(policy, key, binOperations(PREPEND, bins)())
+	return err
+}
+
+// Add adds the integer values to the bins like Client.Add.
+func (fc *FakeClient) Add(policy *WritePolicy, key *Key, binMap BinMap) error {
+	return fc.AddBins(policy, key, binMapToNewBins(binMap)...)
+}
+
+// AddBins adds the integer values to the bins like Client.AddBins.
+func (fc *FakeClient) AddBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
+	_, _, err := fc.operate(policy, key, binOperations(ADD, bins)())
+	return err
+}
+
+// Delete deletes the record, and returns whether it existed, like Client.Delete.
+func (fc *FakeClient) Delete(policy *WritePolicy, key *Key) (bool, error) {
+	policy = fakeWritePolicy(policy)
+
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	rec := fc.lookup(key, fc.now())
+	if rec == nil {
+		return false, nil
+	}
+	if err := checkGeneration(policy, rec); err != nil {
+		return false, err
+	}
+	delete(fc.records, fakeKeyOf(key))
+	return true, nil
+}
+
+// Touch resets the expiration of the record like Client.Touch.
+func (fc *FakeClient) Touch(policy *WritePolicy, key *Key) error {
+	_, _, err := fc.operate(policy, key, []*Operation{TouchOp()})
+	return err
+}
+
+// TouchKeys resets the expiration of each record like Client.TouchKeys.
+func (fc *FakeClient) TouchKeys(policy *WritePolicy, keys []*Key, expirations []int32) ([]error, error) {
+	policy = fakeWritePolicy(policy)
+	if len(keys) != len(expirations) {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "TouchKeys requires an expiration for each key.")
+	}
+
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		// copy policies to set the expiration per key
+		keyPolicy := *policy
+		keyPolicy.Expiration = expirations[i]
+		errs[i] = fc.Touch(&keyPolicy, key)
+	}
+	return errs, nil
+}
+
+// BatchPutByDigest writes the bins of each record like Client.BatchPutByDigest.
+func (fc *FakeClient) BatchPutByDigest(policy *WritePolicy, namespace, setName string, digests []PartitionDigest, bins [][]*Bin) ([]error, error) {
+	if len(digests) != len(bins) {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "BatchPutByDigest requires the bins of each digest.")
+	}
+
+	errs := make([]error, len(digests))
+	for i, pd := range digests {
+		key, err := NewKeyWithDigest(namespace, setName, nil, pd.Digest[:])
+		if err != nil {
+			return nil, err
+		}
+		errs[i] = fc.PutBins(policy, key, bins[i]...)
+	}
+	return errs, nil
+}
+
+// ApplyWrites applies the operations of each write like Client.ApplyWrites.
+func (fc *FakeClient) ApplyWrites(policy *WritePolicy, nodeConcurrency int, writes []*PendingWrite) ([]error, error) {
+	errs := make([]error, len(writes))
+	for i, write := range writes {
+		_, _, errs[i] = fc.operate(policy, write.Key, write.Operations)
+	}
+	return errs, nil
+}
+
+// PutBinsWithToken is not supported.
+func (fc *FakeClient) PutBinsWithToken(policy *WritePolicy, key *Key, bins ...*Bin) (*ConsistencyToken, error) {
+	return nil, fakeUnsupported("PutBinsWithToken")
+}
+
+//-------------------------------------------------------
+// Read Record Operations
+//-------------------------------------------------------
+
+// Exists returns whether the record exists like Client.Exists.
+func (fc *FakeClient) Exists(policy *BasePolicy, key *Key) (bool, error) {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+	return fc.lookup(key, fc.now()) != nil, nil
+}
+
+// BatchExists returns whether each record exists like Client.BatchExists.
+func (fc *FakeClient) BatchExists(policy *BasePolicy, keys []*Key) ([]bool, error) {
+	exists := make([]bool, len(keys))
+	for i, key := range keys {
+		exists[i], _ = fc.Exists(policy, key)
+	}
+	return exists, nil
+}
+
+// BatchExistsWithErrors returns whether each record exists like Client.BatchExistsWithErrors.
+func (fc *FakeClient) BatchExistsWithErrors(policy *BasePolicy, keys []*Key) ([]bool, []error, error) {
+	exists, err := fc.BatchExists(policy, keys)
+	return exists, make([]error, len(keys)), err
+}
+
+// Get reads the bins of the record like Client.Get.
+func (fc *FakeClient) Get(policy *BasePolicy, key *Key, binNames ...string) (*Record, error) {
+	ops := []*Operation{GetOp()}
+	if len(binNames) > 0 {
+		ops = make([]*Operation, len(binNames))
+		for i, binName := range binNames {
+			ops[i] = GetOpForBin(binName)
+		}
+	}
+	return fc.read(policy, key, ops)
+}
+
+// GetObject reads the record into the object like Client.GetObject.
+func (fc *FakeClient) GetObject(policy *BasePolicy, key *Key, obj interface{}) error {
+	_, err := fc.GetObjectWithHeader(policy, key, obj)
+	return err
+}
+
+// GetObjectWithHeader reads the record into the object, and returns its header,
+// like Client.GetObjectWithHeader.
+func (fc *FakeClient) GetObjectWithHeader(policy *BasePolicy, key *Key, obj interface{}) (*Record, error) {
+	rec, err := fc.Get(policy, key)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, ErrKeyNotFound
+	}
+	if err := UnmarshalBins(rec.Bins, obj); err != nil {
+		return nil, err
+	}
+	return newRecord(nil, key, nil, rec.Generation, rec.Expiration), nil
+}
+
+// GetJSON reads the bins of the record as a JSON object like Client.GetJSON.
+func (fc *FakeClient) GetJSON(policy *BasePolicy, key *Key, binNames ...string) ([]byte, error) {
+	rec, err := fc.Get(policy, key, binNames...)
+	if err != nil || rec == nil {
+		return nil, err
+	}
+	return json.Marshal(rec.Bins)
+}
+
+// GetHeader reads the generation and expiration of the record like Client.GetHeader.
+func (fc *FakeClient) GetHeader(policy *BasePolicy, key *Key) (*Record, error) {
+	return fc.read(policy, key, []*Operation{GetHeaderOp()})
+}
+
+// GetWithToken is not supported.
+func (fc *FakeClient) GetWithToken(policy *BasePolicy, token *ConsistencyToken, key *Key, binNames ...string) (*Record, error) {
+	return nil, fakeUnsupported("GetWithToken")
+}
+
+// BatchGet reads the bins of each record like Client.BatchGet.
+func (fc *FakeClient) BatchGet(policy *BasePolicy, keys []*Key, binNames ...string) ([]*Record, error) {
+	records := make([]*Record, len(keys))
+	err := fc.BatchGetFunc(policy, keys, func(index int, record *Record) error {
+		records[index] = record
+		return nil
+	}, binNames...)
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// BatchGetComplex reads the bins selected by each batch read like Client.BatchGetComplex.
+func (fc *FakeClient) BatchGetComplex(policy *BasePolicy, records []*BatchRead) error {
+	for _, br := range records {
+		var err error
+		switch {
+		case br.ReadAllBins:
+			br.Record, err = fc.batchRead(policy, br.Key, []*Operation{GetOp()})
+		case len(br.BinNames) == 0:
+			br.Record, err = fc.batchRead(policy, br.Key, []*Operation{GetHeaderOp()})
+		default:
+			br.Record, err = fc.batchRead(policy, br.Key, []*Operation{GetOp()})
+			br.Record = br.project(br.Record)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchGetHeader reads the header of each record like Client.BatchGetHeader.
+func (fc *FakeClient) BatchGetHeader(policy *BasePolicy, keys []*Key) ([]*Record, error) {
+	records := make([]*Record, len(keys))
+	err := fc.BatchGetHeaderFunc(policy, keys, func(index int, record *Record) error {
+		records[index] = record
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// BatchGetHeaderWithErrors reads the header of each record like Client.BatchGetHeaderWithErrors.
+func (fc *FakeClient) BatchGetHeaderWithErrors(policy *BasePolicy, keys []*Key) ([]*Record, []error, error) {
+	records, err := fc.BatchGetHeader(policy, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return records, make([]error, len(keys)), nil
+}
+
+// BatchGetFunc invokes fn with the bins of each record like Client.BatchGetFunc.
+func (fc *FakeClient) BatchGetFunc(policy *BasePolicy, keys []*Key, fn func(index int, record *Record) error, binNames ...string) error {
+	for i, key := range keys {
+		// a missing record is not an error in a batch
+		record, err := fc.batchGet(policy, key, binNames)
+		if err != nil {
+			return err
+		}
+		if err := fn(i, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchGetHeaderFunc invokes fn with the header of each record like Client.BatchGetHeaderFunc.
+func (fc *FakeClient) BatchGetHeaderFunc(policy *BasePolicy, keys []*Key, fn func(index int, record *Record) error) error {
+	for i, key := range keys {
+		record, err := fc.batchRead(policy, key, []*Operation{GetHeaderOp()})
+		if err != nil {
+			return err
+		}
+		if err := fn(i, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchGetByDigest reads the bins of each record like Client.BatchGetByDigest.
+func (fc *FakeClient) BatchGetByDigest(policy *BasePolicy, namespace string, digests []PartitionDigest, binNames ...string) ([]*Record, error) {
+	keys := make([]*Key, len(digests))
+	for i, pd := range digests {
+		var err error
+		if keys[i], err = NewKeyWithDigest(namespace, "", nil, pd.Digest[:]); err != nil {
+			return nil, err
+		}
+	}
+	return fc.BatchGet(policy, keys, binNames...)
+}
+
+func (fc *FakeClient) batchGet(policy *BasePolicy, key *Key, binNames []string) (*Record, error) {
+	if len(binNames) == 0 {
+		return fc.batchRead(policy, key, []*Operation{GetOp()})
+	}
+
+	ops := make([]*Operation, len(binNames))
+	for i, binName := range binNames {
+		ops[i] = GetOpForBin(binName)
+	}
+	return fc.batchRead(policy, key, ops)
+}
+
+// batchRead reads the record, which is nil if it does not exist regardless of
+// the policy's KeyNotFoundAsError, like the batch commands.
+func (fc *FakeClient) batchRead(policy *BasePolicy, key *Key, ops []*Operation) (*Record, error) {
+	if policy != nil && policy.KeyNotFoundAsError {
+		// do not modify the caller's policy
+		p := *policy
+		p.KeyNotFoundAsError = false
+		policy = &p
+	}
+	return fc.read(policy, key, ops)
+}
+
+func (fc *FakeClient) read(policy *BasePolicy, key *Key, ops []*Operation) (*Record, error) {
+	rec, _, err := fc.operate(nil, key, ops)
+	if err == nil && rec == nil && policy != nil && policy.KeyNotFoundAsError {
+		return nil, ErrKeyNotFound
+	}
+	return rec, err
+}
+
+//-------------------------------------------------------
+// Generic Database Operations
+//-------------------------------------------------------
+
+// Operate applies the operations to the record like Client.Operate.
+// Only the operations on whole bins are supported.
+func (fc *FakeClient) Operate(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, error) {
+	rec, _, err := fc.operate(policy, key, operations)
+	return rec, err
+}
+
+// OperateWithResults applies the operations to the record like Client.OperateWithResults.
+// Only the operations on whole bins are supported.
+func (fc *FakeClient) OperateWithResults(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, OpResults, error) {
+	return fc.operate(policy, key, operations)
+}
+
+// OperateIf is not supported.
+func (fc *FakeClient) OperateIf(policy *WritePolicy, key *Key, condition *Expression, operations ...*Operation) (*Record, error) {
+	return nil, fakeUnsupported("OperateIf")
+}
+
+// OperateWithToken is not supported.
+func (fc *FakeClient) OperateWithToken(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, *ConsistencyToken, error) {
+	return nil, nil, fakeUnsupported("OperateWithToken")
+}
+
+// MapUpsert is not supported.
+func (fc *FakeClient) MapUpsert(policy *WritePolicy, key *Key, binName string, mapPolicy *MapPolicy, operations ...*Operation) (*Record, bool, error) {
+	return nil, false, fakeUnsupported("MapUpsert")
+}
+
+// operate applies the operations to the record atomically. A nil policy means
+// the operations only read the record. The returned record is nil if the record
+// does not exist after a read.
+func (fc *FakeClient) operate(policy *WritePolicy, key *Key, operations []*Operation) (*Record, OpResults, error) {
+	write := false
+	for _, op := range operations {
+		switch op.OpType {
+		case READ:
+		case WRITE, ADD, APPEND, PREPEND, TOUCH, DELETE:
+			write = true
+		default:
+			return nil, nil, fakeUnsupported("Operations other than read, write, add, append, prepend, touch and delete")
+		}
+	}
+	if write {
+		policy = fakeWritePolicy(policy)
+	}
+
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	now := fc.now()
+	rec := fc.lookup(key, now)
+	if rec == nil && !write {
+		return nil, nil, nil
+	}
+
+	bins := map[string]fakeBin{}
+	generation := 0
+	if rec != nil {
+		generation = rec.generation
+	}
+
+	if write {
+		action := policy.RecordExistsAction
+		switch {
+		case rec == nil && (action == UPDATE_ONLY || action == REPLACE_ONLY):
+			return nil, nil, NewAerospikeError(KEY_NOT_FOUND_ERROR)
+		case rec != nil && action == CREATE_ONLY:
+			return nil, nil, NewAerospikeError(KEY_EXISTS_ERROR)
+		}
+		if rec != nil {
+			if err := checkGeneration(policy, rec); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if rec != nil && action != REPLACE && action != REPLACE_ONLY {
+			for name, bin := range rec.bins {
+				bins[name] = bin
+			}
+		}
+	} else {
+		bins = rec.bins
+	}
+
+	var readBins map[string]struct{}
+	readAll, headerOnly := false, true
+	results := make(OpResults, len(operations))
+
+	for i, op := range operations {
+		results[i] = &OperationResult{Operation: op}
+
+		switch op.OpType {
+		case READ:
+			if op.headerOnly {
+				continue
+			}
+			headerOnly = false
+			if op.BinName == "" {
+				readAll = true
+				continue
+			}
+			if readBins == nil {
+				readBins = map[string]struct{}{}
+			}
+			readBins[op.BinName] = struct{}{}
+			if bin, exists := bins[op.BinName]; exists {
+				value, err := bin.value()
+				if err != nil {
+					return nil, nil, err
+				}
+				results[i].Value = value
+			}
+
+		case WRITE:
+			bin, err := newFakeBin(op.BinValue)
+			if err != nil {
+				return nil, nil, err
+			}
+			if bin.particleType == ParticleType.NULL {
+				delete(bins, op.BinName)
+			} else {
+				bins[op.BinName] = bin
+			}
+
+		case ADD, APPEND, PREPEND:
+			bin, err := newFakeBin(op.BinValue)
+			if err != nil {
+				return nil, nil, err
+			}
+			if bin, err = combine(op.OpType, bins[op.BinName], bin); err != nil {
+				return nil, nil, err
+			}
+			bins[op.BinName] = bin
+
+		case TOUCH:
+			if rec == nil {
+				return nil, nil, NewAerospikeError(KEY_NOT_FOUND_ERROR)
+			}
+
+		case DELETE:
+			bins = map[string]fakeBin{}
+		}
+	}
+
+	expiration := TTL(0)
+	if write {
+		// writing no bins deletes the record
+		if len(bins) == 0 {
+			delete(fc.records, fakeKeyOf(key))
+			if rec == nil {
+				return nil, results, nil
+			}
+			return newRecord(nil, key, nil, generation, expiration), results, nil
+		}
+
+		storedKey := key
+		if !policy.SendKey {
+			storedKey, _ = NewKeyWithDigest(key.Namespace(), key.SetName(), nil, key.Digest())
+			if rec != nil && rec.key.Value() != nil && rec.key.Value().GetObject() != nil {
+				storedKey = rec.key
+			}
+		}
+
+		generation++
+		rec = &fakeRecord{
+			key:        storedKey,
+			bins:       bins,
+			generation: generation,
+			voidTime:   fc.voidTime(policy, rec, now),
+			lastUpdate: now,
+		}
+		fc.records[fakeKeyOf(key)] = rec
+	}
+	expiration = rec.ttl(now)
+
+	if headerOnly {
+		return newRecord(nil, key, nil, generation, expiration), results, nil
+	}
+
+	var values BinMap
+	for name, bin := range bins {
+		if _, requested := readBins[name]; !readAll && !requested {
+			continue
+		}
+		value, err := bin.value()
+		if err != nil {
+			return nil, nil, err
+		}
+		if values == nil {
+			values = BinMap{}
+		}
+		values[name] = value
+	}
+	return newRecord(nil, key, values, generation, expiration), results, nil
+}
+
+//-------------------------------------------------------
+// Scan Operations
+//-------------------------------------------------------
+
+// ScanAll reads the records of the set, or of the namespace if setName is empty,
+// like Client.ScanAll. Records are returned in digest order. If the policy's
+// PartitionFilter is set, only the records of its partitions are returned, but
+// the progress of the filter is not updated.
+func (fc *FakeClient) ScanAll(apolicy *ScanPolicy, namespace string, setName string, binNames ...string) (*Recordset, error) {
+	policy := apolicy
+	if policy == nil {
+		policy = NewScanPolicy()
+	}
+	return fc.scan(policy, namespace, setName, time.Time{}, binNames), nil
+}
+
+// ScanAllFunc invokes fn for each record of the scan like Client.ScanAllFunc.
+// fn is invoked from a single goroutine.
+func (fc *FakeClient) ScanAllFunc(apolicy *ScanPolicy, namespace string, setName string, fn func(*Record) error, binNames ...string) error {
+	res, err := fc.ScanAll(apolicy, namespace, setName, binNames...)
+	if err != nil {
+		return err
+	}
+
+	results := res.Results()
+	for r := range results {
+		if err := fn(r.Record); err != nil {
+			// discard the rest
+			go func() {
+				for range results {
+				}
+			}()
+			res.Close()
+			return err
+		}
+	}
+	return nil
+}
+
+// ScanAllObjects sends each record of the scan on objChan like Client.ScanAllObjects.
+func (fc *FakeClient) ScanAllObjects(apolicy *ScanPolicy, objChan interface{}, namespace string, setName string, binNames ...string) (*ObjectRecordset, error) {
+	rv, err := objectChanOf(objChan)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := fc.ScanAll(apolicy, namespace, setName, binNames...)
+	if err != nil {
+		return nil, err
+	}
+	return newObjectRecordset(res, rv), nil
+}
+
+// ScanAllContext reads the records of the scan like ScanAll, until ctx is done,
+// like Client.ScanAllContext.
+func (fc *FakeClient) ScanAllContext(ctx context.Context, apolicy *ScanPolicy, namespace string, setName string, binNames ...string) (*Recordset, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	res, err := fc.ScanAll(apolicy, namespace, setName, binNames...)
+	if err != nil {
+		return nil, err
+	}
+
+	res.closeOnDone(ctx)
+	return res, nil
+}
+
+// IncrementalScan reads the records of the scan last updated at or after since,
+// like Client.IncrementalScan.
+func (fc *FakeClient) IncrementalScan(apolicy *ScanPolicy, namespace string, setName string, since time.Time, binNames ...string) (*Recordset, error) {
+	policy := apolicy
+	if policy == nil {
+		policy = NewScanPolicy()
+	}
+	return fc.scan(policy, namespace, setName, since, binNames), nil
+}
+
+// ExportByDigest is not supported.
+func (fc *FakeClient) ExportByDigest(apolicy *ScanPolicy, namespace string, setName string, chunkSize int, fn func(*ExportChunk) error, binNames ...string) error {
+	return fakeUnsupported("ExportByDigest")
+}
+
+func (fc *FakeClient) scan(policy *ScanPolicy, namespace string, setName string, since time.Time, binNames []string) *Recordset {
+	fc.mutex.Lock()
+	now := fc.now()
+
+	var records []*Record
+	for k, rec := range fc.records {
+		if k.namespace != namespace || (setName != "" && rec.key.SetName() != setName) || rec.expired(now) || rec.lastUpdate.Before(since) {
+			continue
+		}
+		if pf := policy.PartitionFilter; pf != nil {
+			if id := NewPartitionByKey(rec.key).PartitionId; id < pf.begin || id >= pf.begin+pf.count {
+				continue
+			}
+		}
+
+		var bins BinMap
+		if policy.IncludeBinData {
+			for name, bin := range rec.bins {
+				if len(binNames) > 0 && !containsString(binNames, name) {
+					continue
+				}
+				value, err := bin.value()
+				if err != nil {
+					continue
+				}
+				if bins == nil {
+					bins = BinMap{}
+				}
+				bins[name] = value
+			}
+		}
+		records = append(records, newRecord(nil, rec.key, bins, rec.generation, rec.ttl(now)))
+	}
+	fc.mutex.Unlock()
+
+	sort.Slice(records, func(i, j int) bool {
+		return bytes.Compare(records[i].Key.Digest(), records[j].Key.Digest()) < 0
+	})
+
+	rs := newRecordset(policy.RecordQueueSize, 1)
+	go func() {
+		defer rs.signalEnd()
+		for _, rec := range records {
+			select {
+			case rs.Records <- rec:
+			case <-rs.cancelled:
+				return
+			}
+		}
+	}()
+	return rs
+}
+
+//-------------------------------------------------------
+// User defined functions, Queries and Indexes
+//-------------------------------------------------------
+
+// RegisterUDFFromFile is not supported.
+func (fc *FakeClient) RegisterUDFFromFile(policy *WritePolicy, clientPath string, serverPath string, language Language) (*RegisterTask, error) {
+	return nil, fakeUnsupported("RegisterUDFFromFile")
+}
+
+// RegisterUDFFromReader is not supported.
+func (fc *FakeClient) RegisterUDFFromReader(policy *WritePolicy, reader io.Reader, serverPath string, language Language) (*RegisterTask, error) {
+	return nil, fakeUnsupported("RegisterUDFFromReader")
+}
+
+// RegisterUDF is not supported.
+func (fc *FakeClient) RegisterUDF(policy *WritePolicy, udfBody []byte, serverPath string, language Language) (*RegisterTask, error) {
+	return nil, fakeUnsupported("RegisterUDF")
+}
+
+// RemoveUDF is not supported.
+func (fc *FakeClient) RemoveUDF(policy *WritePolicy, udfName string) (*RemoveTask, error) {
+	return nil, fakeUnsupported("RemoveUDF")
+}
+
+// ListUDF is not supported.
+func (fc *FakeClient) ListUDF(policy *BasePolicy) ([]*UDF, error) {
+	return nil, fakeUnsupported("ListUDF")
+}
+
+// Execute is not supported.
+func (fc *FakeClient) Execute(policy *WritePolicy, key *Key, packageName string, functionName string, args ...Value) (interface{}, error) {
+	return nil, fakeUnsupported("Execute")
+}
+
+// ExecuteUDF is not supported.
+func (fc *FakeClient) ExecuteUDF(policy *QueryPolicy, statement *Statement, packageName string, functionName string, functionArgs ...Value) (*ExecuteTask, error) {
+	return nil, fakeUnsupported("ExecuteUDF")
+}
+
+// QueryExecute is not supported.
+func (fc *FakeClient) QueryExecute(policy *QueryPolicy, statement *Statement, ops ...*Operation) (*ExecuteTask, error) {
+	return nil, fakeUnsupported("QueryExecute")
+}
+
+// QueryDelete is not supported.
+func (fc *FakeClient) QueryDelete(policy *QueryPolicy, statement *Statement, filterExp *Expression) (*ExecuteTask, error) {
+	return nil, fakeUnsupported("QueryDelete")
+}
+
+// Query is not supported.
+func (fc *FakeClient) Query(policy *QueryPolicy, statement *Statement) (*Recordset, error) {
+	return nil, fakeUnsupported("Query")
+}
+
+// QueryObjects is not supported.
+func (fc *FakeClient) QueryObjects(policy *QueryPolicy, statement *Statement, objChan interface{}) (*ObjectRecordset, error) {
+	return nil, fakeUnsupported("QueryObjects")
+}
+
+// QueryContext is not supported.
+func (fc *FakeClient) QueryContext(ctx context.Context, policy *QueryPolicy, statement *Statement) (*Recordset, error) {
+	return nil, fakeUnsupported("QueryContext")
+}
+
+// DeleteWhere is not supported.
+func (fc *FakeClient) DeleteWhere(policy *QueryPolicy, namespace string, setName string, filter *Filter, filterExp *Expression, deletesPerSecond int) (*DeleteWhereTask, error) {
+	return nil, fakeUnsupported("DeleteWhere")
+}
+
+// CreateIndex is not supported.
+func (fc *FakeClient) CreateIndex(policy *WritePolicy, namespace string, setName string, indexName string, binName string, indexType IndexType) (*IndexTask, error) {
+	return nil, fakeUnsupported("CreateIndex")
+}
+
+// CreateComplexIndex is not supported.
+func (fc *FakeClient) CreateComplexIndex(policy *WritePolicy, namespace string, setName string, indexName string, binName string, indexType IndexType, indexCollectionType IndexCollectionType, ctx ...*CDTContext) (*IndexTask, error) {
+	return nil, fakeUnsupported("CreateComplexIndex")
+}
+
+// DropIndex is not supported.
+func (fc *FakeClient) DropIndex(policy *WritePolicy, namespace string, setName string, indexName string) error {
+	return fakeUnsupported("DropIndex")
+}
+
+// Truncate removes the records of the set, or of the namespace if setName is
+// empty, like Client.Truncate.
+func (fc *FakeClient) Truncate(policy *WritePolicy, namespace string, setName string, beforeLastUpdate *time.Time) error {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	if beforeLastUpdate != nil && beforeLastUpdate.After(fc.now()) {
+		return NewAerospikeError(PARAMETER_ERROR, "Truncate cannot remove records updated in the future.")
+	}
+
+	for k, rec := range fc.records {
+		if k.namespace != namespace || (setName != "" && rec.key.SetName() != setName) {
+			continue
+		}
+		if beforeLastUpdate == nil || rec.lastUpdate.Before(*beforeLastUpdate) {
+			delete(fc.records, k)
+		}
+	}
+	return nil
+}
+
+//-------------------------------------------------------
+// User administration
+//-------------------------------------------------------
+
+// CreateUser is not supported.
+func (fc *FakeClient) CreateUser(policy *AdminPolicy, user string, password string, roles []string) error {
+	return fakeUnsupported("CreateUser")
+}
+
+// DropUser is not supported.
+func (fc *FakeClient) DropUser(policy *AdminPolicy, user string) error {
+	return fakeUnsupported("DropUser")
+}
+
+// ChangePassword is not supported.
+func (fc *FakeClient) ChangePassword(policy *AdminPolicy, user string, password string) error {
+	return fakeUnsupported("ChangePassword")
+}
+
+// GrantRoles is not supported.
+func (fc *FakeClient) GrantRoles(policy *AdminPolicy, user string, roles []string) error {
+	return fakeUnsupported("GrantRoles")
+}
+
+// RevokeRoles is not supported.
+func (fc *FakeClient) RevokeRoles(policy *AdminPolicy, user string, roles []string) error {
+	return fakeUnsupported("RevokeRoles")
+}
+
+// ReplaceRoles is not supported.
+func (fc *FakeClient) ReplaceRoles(policy *AdminPolicy, user string, roles []string) error {
+	return fakeUnsupported("ReplaceRoles")
+}
+
+// QueryUser is not supported.
+func (fc *FakeClient) QueryUser(policy *AdminPolicy, user string) (*UserRoles, error) {
+	return nil, fakeUnsupported("QueryUser")
+}
+
+// QueryUsers is not supported.
+func (fc *FakeClient) QueryUsers(policy *AdminPolicy) ([]*UserRoles, error) {
+	return nil, fakeUnsupported("QueryUsers")
+}
+
+//-------------------------------------------------------
+// Helpers
+//-------------------------------------------------------
+
+func (fc *FakeClient) now() time.Time {
+	if fc.Now != nil {
+		return fc.Now()
+	}
+	return time.Now()
+}
+
+// lookup returns the record of the key, or nil if it does not exist or expired.
+// Expired records are removed.
+func (fc *FakeClient) lookup(key *Key, now time.Time) *fakeRecord {
+	k := fakeKeyOf(key)
+	rec := fc.records[k]
+	if rec != nil && rec.expired(now) {
+		delete(fc.records, k)
+		return nil
+	}
+	return rec
+}
+
+// voidTime returns the time the record written with the policy expires.
+func (fc *FakeClient) voidTime(policy *WritePolicy, rec *fakeRecord, now time.Time) time.Time {
+	switch {
+	case policy.Expiration == -1:
+		return time.Time{}
+	case policy.Expiration == -2 && rec != nil:
+		return rec.voidTime
+	case policy.Expiration > 0:
+		return now.Add(time.Duration(policy.Expiration) * time.Second)
+	case fc.DefaultTTL > 0:
+		return now.Add(fc.DefaultTTL)
+	}
+	return time.Time{}
+}
+
+func (rec *fakeRecord) expired(now time.Time) bool {
+	return !rec.voidTime.IsZero() && !now.Before(rec.voidTime)
+}
+
+// ttl returns the expiration of the record as the client computes it from the void time.
+func (rec *fakeRecord) ttl(now time.Time) int {
+	voidTime := int64(0)
+	if !rec.voidTime.IsZero() {
+		voidTime = rec.voidTime.Unix() - CITRUSLEAF_EPOCH
+	}
+	return int(CITRUSLEAF_EPOCH + voidTime - now.Unix())
+}
+
+func fakeKeyOf(key *Key) fakeKey {
+	return fakeKey{namespace: key.Namespace(), digest: string(key.Digest())}
+}
+
+func newFakeBin(value Value) (fakeBin, error) {
+	data := make([]byte, value.estimateSize())
+	n, err := value.write(data, 0)
+	if err != nil {
+		return fakeBin{}, err
+	}
+	return fakeBin{particleType: value.GetType(), data: data[:n]}, nil
+}
+
+func (bin fakeBin) value() (interface{}, error) {
+	return bytesToParticle(bin.particleType, bin.data, 0, len(bin.data))
+}
+
+// combine applies an add, append or prepend operation to the bin.
+func combine(opType OperationType, bin fakeBin, operand fakeBin) (fakeBin, error) {
+	if opType == ADD && operand.particleType != ParticleType.INTEGER ||
+		opType != ADD && operand.particleType != ParticleType.STRING && operand.particleType != ParticleType.BLOB {
+		return fakeBin{}, NewAerospikeError(PARAMETER_ERROR)
+	}
+
+	if bin.data == nil {
+		return operand, nil
+	}
+	if bin.particleType != operand.particleType {
+		return fakeBin{}, NewAerospikeError(BIN_TYPE_ERROR)
+	}
+
+	switch opType {
+	case ADD:
+		return newFakeBin(NewLongValue(Buffer.BytesToInt64(bin.data, 0) + Buffer.BytesToInt64(operand.data, 0)))
+	case APPEND:
+		return fakeBin{particleType: bin.particleType, data: append(append([]byte{}, bin.data...), operand.data...)}, nil
+	default:
+		return fakeBin{particleType: bin.particleType, data: append(append([]byte{}, operand.data...), bin.data...)}, nil
+	}
+}
+
+// checkGeneration checks the generation of the record against the policy.
+func checkGeneration(policy *WritePolicy, rec *fakeRecord) error {
+	switch policy.GenerationPolicy {
+	case EXPECT_GEN_EQUAL:
+		if int(policy.Generation) != rec.generation {
+			return NewAerospikeError(GENERATION_ERROR)
+		}
+	case EXPECT_GEN_GT:
+		if int(policy.Generation) <= rec.generation {
+			return NewAerospikeError(GENERATION_ERROR)
+		}
+	}
+	return nil
+}
+
+func fakeWritePolicy(policy *WritePolicy) *WritePolicy {
+	if policy == nil {
+		return NewWritePolicy(0, 0)
+	}
+	return policy
+}
+
+func binMapToNewBins(binMap BinMap) []*Bin {
+	bins := make([]*Bin, 0, len(binMap))
+	for name, value := range binMap {
+		bins = append(bins, NewBin(name, value))
+	}
+	return bins
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func fakeUnsupported(command string) error {
+	return NewAerospikeError(UNSUPPORTED_FEATURE, command+" is not supported by FakeClient.")
+}
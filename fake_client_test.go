@@ -0,0 +1,263 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+var _ = Describe("Fake Client Test", func() {
+
+	var client *FakeClient
+	var now time.Time
+	var key *Key
+
+	resultCode := func(err error) ResultCode {
+		ae, ok := err.(AerospikeError)
+		Expect(ok).To(BeTrue())
+		return ae.ResultCode()
+	}
+
+	BeforeEach(func() {
+		now = time.Now()
+		client = NewFakeClient()
+		client.Now = func() time.Time { return now }
+
+		var err error
+		key, err = NewKey("test", "set", "key")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should write and read records like a server", func() {
+		err := client.Put(nil, key, BinMap{"int": int64(1), "str": "a", "list": []interface{}{1, "b"}, "map": map[interface{}]interface{}{"k": 2}})
+		Expect(err).ToNot(HaveOccurred())
+
+		rec, err := client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Generation).To(Equal(1))
+		Expect(rec.Bins).To(Equal(BinMap{"int": 1, "str": "a", "list": []interface{}{1, "b"}, "map": map[interface{}]interface{}{"k": 2}}))
+
+		rec.Bins["list"].([]interface{})[0] = 7
+		rec, err = client.Get(nil, key, "list", "missing")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(BinMap{"list": []interface{}{1, "b"}}))
+
+		Expect(client.PutBins(nil, key, NewBin("str", nil), NewBin("new", true))).ToNot(HaveOccurred())
+		rec, err = client.GetHeader(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Generation).To(Equal(2))
+		Expect(rec.Bins).To(BeEmpty())
+
+		rec, err = client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(BinMap{"int": 1, "new": true, "list": []interface{}{1, "b"}, "map": map[interface{}]interface{}{"k": 2}}))
+
+		existed, err := client.Delete(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(existed).To(BeTrue())
+
+		rec, err = client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec).To(BeNil())
+
+		_, err = client.Get(&BasePolicy{KeyNotFoundAsError: true}, key)
+		Expect(err).To(Equal(ErrKeyNotFound))
+	})
+
+	It("should honor the record exists actions and generation policies", func() {
+		ok, err := client.ReplaceOnly(nil, key, NewBin("a", 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeFalse())
+
+		ok, err = client.PutIfAbsent(nil, key, NewBin("a", 1), NewBin("b", 2))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		ok, err = client.PutIfAbsent(nil, key, NewBin("a", 2))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeFalse())
+
+		ok, err = client.ReplaceIfGeneration(nil, key, 5, NewBin("a", 3))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeFalse())
+
+		ok, err = client.ReplaceIfGeneration(nil, key, 1, NewBin("a", 3))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		rec, err := client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(BinMap{"a": 3}))
+		Expect(rec.Generation).To(Equal(2))
+
+		policy := NewWritePolicy(1, 0)
+		policy.GenerationPolicy = EXPECT_GEN_EQUAL
+		_, err = client.Delete(policy, key)
+		Expect(resultCode(err)).To(Equal(GENERATION_ERROR))
+
+		err = client.Update(nil, key, 0, func(rec *Record) (BinMap, error) {
+			return BinMap{"a": rec.Bins["a"].(int) * 2}, nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		rec, err = client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(BinMap{"a": 6}))
+	})
+
+	It("should expire records and reset their TTL", func() {
+		Expect(client.Put(NewWritePolicy(0, 10), key, BinMap{"a": 1})).ToNot(HaveOccurred())
+
+		rec, err := client.GetHeader(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Expiration).To(Equal(10))
+
+		now = now.Add(5 * time.Second)
+		Expect(client.Touch(NewWritePolicy(0, 20), key)).ToNot(HaveOccurred())
+		rec, err = client.GetHeader(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Expiration).To(Equal(20))
+		Expect(rec.Generation).To(Equal(2))
+
+		Expect(client.Put(NewWritePolicy(0, -2), key, BinMap{"b": 2})).ToNot(HaveOccurred())
+		now = now.Add(20 * time.Second)
+		exists, err := client.Exists(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+		Expect(client.Len()).To(Equal(0))
+
+		err = client.Touch(nil, key)
+		Expect(resultCode(err)).To(Equal(KEY_NOT_FOUND_ERROR))
+
+		client.DefaultTTL = time.Minute
+		Expect(client.Put(nil, key, BinMap{"a": 1})).ToNot(HaveOccurred())
+		rec, err = client.GetHeader(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Expiration).To(Equal(60))
+
+		Expect(client.Put(NewWritePolicy(0, -1), key, BinMap{"a": 1})).ToNot(HaveOccurred())
+		rec, err = client.GetHeader(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Expiration).To(Equal(int(CITRUSLEAF_EPOCH - now.Unix())))
+	})
+
+	It("should apply the operations on whole bins", func() {
+		Expect(client.Put(nil, key, BinMap{"n": 1, "s": "b"})).ToNot(HaveOccurred())
+		Expect(client.Add(nil, key, BinMap{"n": 2})).ToNot(HaveOccurred())
+		Expect(client.Append(nil, key, BinMap{"s": "c"})).ToNot(HaveOccurred())
+		Expect(client.Prepend(nil, key, BinMap{"s": "a"})).ToNot(HaveOccurred())
+
+		rec, results, err := client.OperateWithResults(nil, key, AddOp(NewBin("n", 10)), GetOpForBin("n"), GetOpForBin("s"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(BinMap{"n": 13, "s": "abc"}))
+		Expect(results.Values()).To(Equal([]interface{}{nil, 13, "abc"}))
+
+		err = client.Add(nil, key, BinMap{"s": 1})
+		Expect(resultCode(err)).To(Equal(BIN_TYPE_ERROR))
+
+		_, err = client.Operate(nil, key, ListAppendOp("l", 1))
+		Expect(resultCode(err)).To(Equal(UNSUPPORTED_FEATURE))
+
+		rec, err = client.Operate(nil, key, DeleteOp())
+		Expect(err).ToNot(HaveOccurred())
+		exists, err := client.Exists(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should read batches", func() {
+		key2, err := NewKey("test", "set", 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.Put(nil, key, BinMap{"a": 1, "b": 2})).ToNot(HaveOccurred())
+
+		records, err := client.BatchGet(&BasePolicy{KeyNotFoundAsError: true}, []*Key{key, key2}, "a")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records[0].Bins).To(Equal(BinMap{"a": 1}))
+		Expect(records[1]).To(BeNil())
+
+		reads := []*BatchRead{NewBatchRead(key, "b"), NewBatchReadHeader(key), NewBatchRead(key2)}
+		Expect(client.BatchGetComplex(nil, reads)).ToNot(HaveOccurred())
+		Expect(reads[0].Record.Bins).To(Equal(BinMap{"b": 2}))
+		Expect(reads[1].Record.Bins).To(BeEmpty())
+		Expect(reads[1].Record.Generation).To(Equal(1))
+		Expect(reads[2].Record).To(BeNil())
+
+		errs, err := client.TouchKeys(nil, []*Key{key, key2}, []int32{10, 10})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(errs[0]).ToNot(HaveOccurred())
+		Expect(resultCode(errs[1])).To(Equal(KEY_NOT_FOUND_ERROR))
+	})
+
+	It("should scan and truncate sets", func() {
+		policy := NewWritePolicy(0, 0)
+		policy.SendKey = true
+		for i := 0; i < 10; i++ {
+			k, err := NewKey("test", "set", i)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.Put(policy, k, BinMap{"i": i, "other": "x"})).ToNot(HaveOccurred())
+		}
+		Expect(client.Put(nil, key, BinMap{"i": 10})).ToNot(HaveOccurred())
+		other, err := NewKey("test", "other", 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.Put(nil, other, BinMap{"i": 11})).ToNot(HaveOccurred())
+
+		seen := map[int]bool{}
+		err = client.ScanAllFunc(nil, "test", "set", func(rec *Record) error {
+			Expect(rec.Bins).To(HaveLen(1))
+			seen[rec.Bins["i"].(int)] = true
+			if rec.Bins["i"].(int) < 10 {
+				Expect(rec.Key.Value().GetObject()).To(Equal(rec.Bins["i"]))
+			} else {
+				Expect(rec.Key.Value().GetObject()).To(BeNil())
+			}
+			return nil
+		}, "i")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(seen).To(HaveLen(11))
+
+		errStop := errors.New("stop")
+		err = client.ScanAllFunc(nil, "test", "", func(rec *Record) error { return errStop })
+		Expect(err).To(Equal(errStop))
+
+		filters, err := SplitPartitionFilters(4)
+		Expect(err).ToNot(HaveOccurred())
+		total := 0
+		for _, filter := range filters {
+			scanPolicy := NewScanPolicy()
+			scanPolicy.PartitionFilter = filter
+			rs, err := client.ScanAll(scanPolicy, "test", "")
+			Expect(err).ToNot(HaveOccurred())
+			for res := range rs.Results() {
+				Expect(res.Err).ToNot(HaveOccurred())
+				total++
+			}
+		}
+		Expect(total).To(Equal(12))
+
+		Expect(client.Truncate(nil, "test", "set", nil)).ToNot(HaveOccurred())
+		Expect(client.Len()).To(Equal(1))
+	})
+
+	It("should fail the commands which need a server", func() {
+		_, err := client.Query(nil, NewStatement("test", "set"))
+		Expect(resultCode(err)).To(Equal(UNSUPPORTED_FEATURE))
+		Expect(client.CreateUser(nil, "user", "pass", nil)).To(HaveOccurred())
+	})
+})
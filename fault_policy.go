@@ -0,0 +1,90 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// errInjectedConnectionDrop is returned when the fault layer drops a connection.
+var errInjectedConnectionDrop = errors.New("Connection dropped by fault injection policy.")
+
+// FaultPolicy determines the faults injected into database commands.
+// It is intended for chaos testing the application's retry and error handling
+// logic in staging environments, and should never be used in production.
+// Set ClientPolicy.FaultPolicy to enable fault injection for a client.
+type FaultPolicy struct {
+	// ConnectionDropRate is the probability (between 0 and 1) of dropping
+	// the connection before a command is sent to the server.
+	// Dropped connections are handled like network errors, and will be retried
+	// according to the command policy.
+	ConnectionDropRate float64 //= 0
+
+	// Latency is added to each command before it is sent to the server.
+	Latency time.Duration //= 0
+
+	// LatencyJitter determines the maximum random duration added to Latency.
+	LatencyJitter time.Duration //= 0
+
+	// ResultCodeRate is the probability (between 0 and 1) of failing
+	// a command with one of the ResultCodes instead of sending it to the server.
+	ResultCodeRate float64 //= 0
+
+	// ResultCodes is the list of result codes commands are failed with.
+	// A result code is chosen randomly for each failed command.
+	// If empty, SERVER_ERROR will be used.
+	ResultCodes []ResultCode
+}
+
+// NewFaultPolicy generates a new FaultPolicy which does not inject any faults.
+func NewFaultPolicy() *FaultPolicy {
+	return &FaultPolicy{}
+}
+
+// injectLatency sleeps for the configured latency.
+func (fp *FaultPolicy) injectLatency() {
+	latency := fp.Latency
+	if fp.LatencyJitter > 0 {
+		latency += time.Duration(rand.Int63n(int64(fp.LatencyJitter)))
+	}
+
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+}
+
+// dropConnection decides if the connection should be dropped.
+func (fp *FaultPolicy) dropConnection() bool {
+	return fp.ConnectionDropRate > 0 && rand.Float64() < fp.ConnectionDropRate
+}
+
+// resultCodeError decides if the command should fail with a forced result code.
+// It returns nil if the command should proceed.
+func (fp *FaultPolicy) resultCodeError() error {
+	if fp.ResultCodeRate <= 0 || rand.Float64() >= fp.ResultCodeRate {
+		return nil
+	}
+
+	if len(fp.ResultCodes) == 0 {
+		return NewAerospikeError(SERVER_ERROR, "Error injected by fault injection policy.")
+	}
+
+	resultCode := fp.ResultCodes[rand.Intn(len(fp.ResultCodes))]
+	return NewAerospikeError(resultCode, ResultCodeToString(resultCode), "(injected by fault injection policy)")
+}
@@ -0,0 +1,91 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+var _ = Describe("Fault Injection Policy Test", func() {
+	initTestVars()
+
+	var ns = "test"
+	var set = randString(50)
+
+	newFaultyClient := func(faultPolicy *FaultPolicy) *Client {
+		cpolicy := *clientPolicy
+		cpolicy.FaultPolicy = faultPolicy
+
+		client, err := NewClientWithPolicy(&cpolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+		return client
+	}
+
+	It("must fail commands with the injected result codes", func() {
+		faultPolicy := NewFaultPolicy()
+		faultPolicy.ResultCodeRate = 1
+		faultPolicy.ResultCodes = []ResultCode{KEY_BUSY}
+
+		client := newFaultyClient(faultPolicy)
+		defer client.Close()
+
+		key, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = client.Put(nil, key, BinMap{"bin": 1})
+		Expect(err).To(HaveOccurred())
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(KEY_BUSY))
+	})
+
+	It("must retry and time out when connections are dropped", func() {
+		faultPolicy := NewFaultPolicy()
+		faultPolicy.ConnectionDropRate = 1
+
+		client := newFaultyClient(faultPolicy)
+		defer client.Close()
+
+		key, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		wpolicy := NewWritePolicy(0, 0)
+		wpolicy.SleepBetweenRetries = 0
+		err = client.Put(wpolicy, key, BinMap{"bin": 1})
+		Expect(err).To(HaveOccurred())
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(TIMEOUT))
+	})
+
+	It("must add latency to commands", func() {
+		faultPolicy := NewFaultPolicy()
+		faultPolicy.Latency = 50 * time.Millisecond
+
+		client := newFaultyClient(faultPolicy)
+		defer client.Close()
+
+		key, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		start := time.Now()
+		err = client.Put(nil, key, BinMap{"bin": 1})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(time.Now().Sub(start)).To(BeNumerically(">=", faultPolicy.Latency))
+	})
+
+})
@@ -28,17 +28,25 @@ const (
 
 	//GU_TID FieldType = 5;
 
-	DIGEST_RIPE_ARRAY FieldType = 6
-	TRAN_ID           FieldType = 7 // user supplied transaction id, which is simply passed back
-	SCAN_OPTIONS      FieldType = 8
-	INDEX_NAME        FieldType = 21
-	INDEX_RANGE       FieldType = 22
-	INDEX_FILTER      FieldType = 23
-	INDEX_LIMIT       FieldType = 24
-	INDEX_ORDER_BY    FieldType = 25
-	UDF_PACKAGE_NAME  FieldType = 30
-	UDF_FUNCTION      FieldType = 31
-	UDF_ARGLIST       FieldType = 32
-	UDF_OP            FieldType = 33
-	QUERY_BINLIST     FieldType = 40
+	DIGEST_RIPE_ARRAY  FieldType = 6
+	TRAN_ID            FieldType = 7 // user supplied transaction id, which is simply passed back
+	SCAN_OPTIONS       FieldType = 8
+	PID_ARRAY          FieldType = 11
+	DIGEST_ARRAY       FieldType = 12
+	MAX_RECORDS        FieldType = 13
+	RECORDS_PER_SECOND FieldType = 14
+	BVAL_ARRAY         FieldType = 15
+	INDEX_CONTEXT      FieldType = 18
+	INDEX_NAME         FieldType = 21
+	INDEX_RANGE        FieldType = 22
+	INDEX_FILTER       FieldType = 23
+	INDEX_LIMIT        FieldType = 24
+	INDEX_ORDER_BY     FieldType = 25
+	INDEX_TYPE         FieldType = 26
+	UDF_PACKAGE_NAME   FieldType = 30
+	UDF_FUNCTION       FieldType = 31
+	UDF_ARGLIST        FieldType = 32
+	UDF_OP             FieldType = 33
+	QUERY_BINLIST      FieldType = 40
+	FILTER_EXP         FieldType = 43
 )
@@ -15,38 +15,134 @@
 package aerospike
 
 import (
+	"fmt"
+
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
 	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
 )
 
 // Filter specifies a query filter definition.
 type Filter struct {
-	name  string
-	begin Value
-	end   Value
+	name    string
+	idxType IndexCollectionType
+	valType int
+	begin   Value
+	end     Value
+
+	// context path of the indexed nested list or map
+	ctx []*CDTContext
 }
 
 // NewEqualFilter creates a new equality filter instance for query.
-func NewEqualFilter(binName string, value interface{}) *Filter {
+func NewEqualFilter(binName string, value interface{}, ctx ...*CDTContext) *Filter {
 	val := NewValue(value)
-	return newFilter(binName, val, val)
+	return newFilter(binName, ICT_DEFAULT, val, val, ctx)
 }
 
 // NewRangeFilter creates a range filter for query.
 // Range arguments must be int64 values.
 // String ranges are not supported.
-func NewRangeFilter(binName string, begin int64, end int64) *Filter {
-	return newFilter(binName, NewValue(begin), NewValue(end))
+func NewRangeFilter(binName string, begin int64, end int64, ctx ...*CDTContext) *Filter {
+	return newFilter(binName, ICT_DEFAULT, NewValue(begin), NewValue(end), ctx)
+}
+
+// NewContainsFilter creates a contains filter for query on collection index.
+// Matches the records whose list elements, map keys or map values,
+// depending on the index collection type, contain the value.
+func NewContainsFilter(binName string, indexCollectionType IndexCollectionType, value interface{}, ctx ...*CDTContext) *Filter {
+	val := NewValue(value)
+	return newFilter(binName, indexCollectionType, val, val, ctx)
+}
+
+// NewContainsRangeFilter creates a contains filter for query on ranges of data in a collection index.
+// Matches the records whose list elements, map keys or map values,
+// depending on the index collection type, fall within the range.
+func NewContainsRangeFilter(binName string, indexCollectionType IndexCollectionType, begin, end int64, ctx ...*CDTContext) *Filter {
+	return newFilter(binName, indexCollectionType, NewValue(begin), NewValue(end), ctx)
+}
+
+// NewGeoWithinRegionFilter creates a geospatial "within region" filter for query.
+// The region is a GeoJSON string. Matches the records whose GeoJSON point
+// bin falls within the region.
+// The bin must be indexed with a GEO2DSPHERE index.
+func NewGeoWithinRegionFilter(binName string, region string, ctx ...*CDTContext) *Filter {
+	return newGeoFilter(binName, ICT_DEFAULT, region, ctx)
+}
+
+// NewGeoWithinRegionForCollectionFilter creates a geospatial "within region" filter
+// for query on a collection index.
+func NewGeoWithinRegionForCollectionFilter(binName string, indexCollectionType IndexCollectionType, region string, ctx ...*CDTContext) *Filter {
+	return newGeoFilter(binName, indexCollectionType, region, ctx)
+}
+
+// NewGeoWithinRadiusFilter creates a geospatial "within radius" filter for query.
+// Matches the records whose GeoJSON point bin falls within the circle
+// with the center at (lng, lat) and the radius in meters.
+// The bin must be indexed with a GEO2DSPHERE index.
+func NewGeoWithinRadiusFilter(binName string, lng float64, lat float64, radius float64, ctx ...*CDTContext) *Filter {
+	return NewGeoWithinRadiusForCollectionFilter(binName, ICT_DEFAULT, lng, lat, radius, ctx...)
+}
+
+// NewGeoWithinRadiusForCollectionFilter creates a geospatial "within radius" filter
+// for query on a collection index.
+func NewGeoWithinRadiusForCollectionFilter(binName string, indexCollectionType IndexCollectionType, lng float64, lat float64, radius float64, ctx ...*CDTContext) *Filter {
+	region := fmt.Sprintf("{ \"type\": \"AeroCircle\", \"coordinates\": [[%.8f, %.8f], %f] }", lng, lat, radius)
+	return newGeoFilter(binName, indexCollectionType, region, ctx)
+}
+
+// NewGeoRegionsContainingPointFilter creates a geospatial "containing point" filter for query.
+// The point is a GeoJSON string. Matches the records whose GeoJSON region
+// bin contains the point.
+// The bin must be indexed with a GEO2DSPHERE index.
+func NewGeoRegionsContainingPointFilter(binName string, point string, ctx ...*CDTContext) *Filter {
+	return newGeoFilter(binName, ICT_DEFAULT, point, ctx)
+}
+
+// NewGeoRegionsContainingPointForCollectionFilter creates a geospatial "containing point" filter
+// for query on a collection index.
+func NewGeoRegionsContainingPointForCollectionFilter(binName string, indexCollectionType IndexCollectionType, point string, ctx ...*CDTContext) *Filter {
+	return newGeoFilter(binName, indexCollectionType, point, ctx)
+}
+
+// Create a geospatial filter for query.
+func newGeoFilter(name string, indexCollectionType IndexCollectionType, value string, ctx []*CDTContext) *Filter {
+	val := NewStringValue(value)
+	return &Filter{
+		name:    name,
+		idxType: indexCollectionType,
+		valType: ParticleType.GEOJSON,
+		begin:   val,
+		end:     val,
+		ctx:     ctx,
+	}
 }
 
 // Create a filter for query.
 // Range arguments must be longs or integers which can be cast to longs.
 // String ranges are not supported.
-func newFilter(name string, begin Value, end Value) *Filter {
+func newFilter(name string, indexCollectionType IndexCollectionType, begin Value, end Value, ctx []*CDTContext) *Filter {
 	return &Filter{
-		name:  name,
-		begin: begin,
-		end:   end,
+		name:    name,
+		idxType: indexCollectionType,
+		valType: begin.GetType(),
+		begin:   begin,
+		end:     end,
+		ctx:     ctx,
+	}
+}
+
+// IndexCollectionType returns the index collection type of the filter.
+func (fltr *Filter) IndexCollectionType() IndexCollectionType {
+	return fltr.idxType
+}
+
+// packedCtx returns the packed context path of the filter, or nil if the
+// filter is not on a nested list or map.
+func (fltr *Filter) packedCtx() ([]byte, error) {
+	if len(fltr.ctx) == 0 {
+		return nil, nil
 	}
+	return packCDTContextBytes(fltr.ctx)
 }
 
 func (fltr *Filter) estimateSize() (int, error) {
@@ -63,7 +159,7 @@ func (fltr *Filter) write(buf []byte, offset int) (int, error) {
 	offset += len + 1
 
 	// Write particle type.
-	buf[offset] = byte(fltr.begin.GetType())
+	buf[offset] = byte(fltr.valType)
 	offset++
 
 	// Write filter begin.
@@ -0,0 +1,178 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"encoding/json"
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// GeoJSON geometry types supported by the server.
+const (
+	GEO_POINT         = "Point"
+	GEO_POLYGON       = "Polygon"
+	GEO_MULTI_POLYGON = "MultiPolygon"
+	GEO_CIRCLE        = "AeroCircle"
+)
+
+// GeoPoint is a GeoJSON position.
+type GeoPoint struct {
+	Lng float64
+	Lat float64
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (pt GeoPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]float64{pt.Lng, pt.Lat})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (pt *GeoPoint) UnmarshalJSON(data []byte) error {
+	var coords []float64
+	if err := json.Unmarshal(data, &coords); err != nil {
+		return err
+	}
+	if len(coords) < 2 {
+		return fmt.Errorf("invalid GeoJSON position: %s", data)
+	}
+	pt.Lng, pt.Lat = coords[0], coords[1]
+	return nil
+}
+
+// GeoPolygon is a GeoJSON polygon. The first ring is the exterior boundary,
+// the following rings are holes. Each ring is closed: the first and last
+// points are equal.
+type GeoPolygon [][]GeoPoint
+
+// GeoCircle is a circle region, an Aerospike extension to GeoJSON.
+type GeoCircle struct {
+	Center GeoPoint
+	// Radius in meters
+	Radius float64
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c GeoCircle) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{c.Center, c.Radius})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *GeoCircle) UnmarshalJSON(data []byte) error {
+	var coords []json.RawMessage
+	if err := json.Unmarshal(data, &coords); err != nil {
+		return err
+	}
+	if len(coords) != 2 {
+		return fmt.Errorf("invalid AeroCircle coordinates: %s", data)
+	}
+	if err := json.Unmarshal(coords[0], &c.Center); err != nil {
+		return err
+	}
+	return json.Unmarshal(coords[1], &c.Radius)
+}
+
+// geoJSONGeometry is the wire format of a GeoJSON geometry.
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// NewGeoPointValue generates a GeoJSONValue for a point.
+func NewGeoPointValue(lng, lat float64) GeoJSONValue {
+	return newGeoJSONValue(GEO_POINT, GeoPoint{Lng: lng, Lat: lat})
+}
+
+// NewGeoPolygonValue generates a GeoJSONValue for a polygon.
+func NewGeoPolygonValue(polygon GeoPolygon) GeoJSONValue {
+	return newGeoJSONValue(GEO_POLYGON, polygon)
+}
+
+// NewGeoMultiPolygonValue generates a GeoJSONValue for a set of polygons.
+func NewGeoMultiPolygonValue(polygons []GeoPolygon) GeoJSONValue {
+	return newGeoJSONValue(GEO_MULTI_POLYGON, polygons)
+}
+
+// NewGeoCircleValue generates a GeoJSONValue for a circle with the radius in meters.
+func NewGeoCircleValue(lng, lat, radius float64) GeoJSONValue {
+	return newGeoJSONValue(GEO_CIRCLE, GeoCircle{Center: GeoPoint{Lng: lng, Lat: lat}, Radius: radius})
+}
+
+func newGeoJSONValue(geoType string, coordinates interface{}) GeoJSONValue {
+	// marshalling float coordinates can't fail
+	res, _ := json.Marshal(geoJSONGeometry{Type: geoType, Coordinates: coordinates})
+	return GeoJSONValue(res)
+}
+
+// parse decodes the geometry and checks its type.
+// The coordinates are decoded into the value pointed to by coordinates.
+func (vl GeoJSONValue) parse(geoType string, coordinates interface{}) error {
+	var geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal([]byte(vl), &geometry); err != nil {
+		return NewAerospikeError(PARSE_ERROR, "Invalid GeoJSON value: "+err.Error())
+	}
+
+	if geoType != geometry.Type {
+		return NewAerospikeError(PARSE_ERROR, fmt.Sprintf("GeoJSON value is a %s, not a %s", geometry.Type, geoType))
+	}
+
+	if err := json.Unmarshal(geometry.Coordinates, coordinates); err != nil {
+		return NewAerospikeError(PARSE_ERROR, "Invalid GeoJSON coordinates: "+err.Error())
+	}
+	return nil
+}
+
+// GeometryType returns the type of the GeoJSON geometry, e.g. GEO_POINT.
+func (vl GeoJSONValue) GeometryType() (string, error) {
+	var geometry struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(vl), &geometry); err != nil {
+		return "", NewAerospikeError(PARSE_ERROR, "Invalid GeoJSON value: "+err.Error())
+	}
+	return geometry.Type, nil
+}
+
+// Point returns the coordinates of a point geometry.
+func (vl GeoJSONValue) Point() (GeoPoint, error) {
+	var res GeoPoint
+	err := vl.parse(GEO_POINT, &res)
+	return res, err
+}
+
+// Polygon returns the rings of a polygon geometry.
+func (vl GeoJSONValue) Polygon() (GeoPolygon, error) {
+	var res GeoPolygon
+	err := vl.parse(GEO_POLYGON, &res)
+	return res, err
+}
+
+// MultiPolygon returns the polygons of a multi polygon geometry.
+func (vl GeoJSONValue) MultiPolygon() ([]GeoPolygon, error) {
+	var res []GeoPolygon
+	err := vl.parse(GEO_MULTI_POLYGON, &res)
+	return res, err
+}
+
+// Circle returns the center and radius of an AeroCircle geometry.
+func (vl GeoJSONValue) Circle() (GeoCircle, error) {
+	var res GeoCircle
+	err := vl.parse(GEO_CIRCLE, &res)
+	return res, err
+}
@@ -0,0 +1,123 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// ALL tests are isolated by SetName and Key, which are 50 random charachters
+var _ = Describe("Geo Spacial Tests", func() {
+	initTestVars()
+
+	// connection data
+	var ns = "test"
+	var set string
+	var wpolicy = NewWritePolicy(0, 0)
+
+	const size = 20
+	const binName = "GeoBin"
+
+	// use the same client for all
+	client, err := NewClientWithPolicy(clientPolicy, *host, *port)
+	if err != nil {
+		panic(err)
+	}
+
+	// collects all records from the recordset
+	var collect = func(recordset *Recordset) []*Record {
+		res := []*Record{}
+		for r := range recordset.Results() {
+			Expect(r.Err).ToNot(HaveOccurred())
+			res = append(res, r.Record)
+		}
+		return res
+	}
+
+	BeforeEach(func() {
+		set = randString(50)
+	})
+
+	It("must Query points within a region", func() {
+		for i := 0; i < size; i++ {
+			key, err := NewKey(ns, set, i)
+			Expect(err).ToNot(HaveOccurred())
+
+			lng := -122 + (0.1 * float64(i))
+			lat := 37.5 + (0.1 * float64(i))
+			point := NewGeoJSONValue(fmt.Sprintf(`{ "type": "Point", "coordinates": [%f, %f] }`, lng, lat))
+			err = client.PutBins(wpolicy, key, NewBin(binName, point))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		idxTask, err := client.CreateIndex(wpolicy, ns, set, set+binName, binName, GEO2DSPHERE)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(<-idxTask.OnComplete()).ToNot(HaveOccurred())
+		defer client.DropIndex(wpolicy, ns, set, set+binName)
+
+		region := `{ "type": "Polygon", "coordinates": [ [[-122.500000, 37.000000],[-121.000000, 37.000000], [-121.000000, 38.080000],[-122.500000, 38.080000], [-122.500000, 37.000000]] ] }`
+
+		stm := NewStatement(ns, set)
+		stm.Addfilter(NewGeoWithinRegionFilter(binName, region))
+		recordset, err := client.Query(nil, stm)
+		Expect(err).ToNot(HaveOccurred())
+
+		records := collect(recordset)
+		Expect(len(records)).To(Equal(6))
+		for _, rec := range records {
+			Expect(rec.Bins[binName]).To(BeAssignableToTypeOf(GeoJSONValue("")))
+		}
+
+		stm = NewStatement(ns, set)
+		stm.Addfilter(NewGeoWithinRadiusFilter(binName, -122, 37.5, 50000))
+		recordset, err = client.Query(nil, stm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(collect(recordset))).To(BeNumerically(">", 0))
+	})
+
+	It("must Query regions containing a point", func() {
+		for i := 0; i < size; i++ {
+			key, err := NewKey(ns, set, i)
+			Expect(err).ToNot(HaveOccurred())
+
+			lng := -122 + (0.1 * float64(i))
+			lat := 37.5 + (0.1 * float64(i))
+			region := NewGeoJSONValue(fmt.Sprintf(`{ "type": "AeroCircle", "coordinates": [[%f, %f], 3000.0 ] }`, lng, lat))
+			err = client.PutBins(wpolicy, key, NewBin(binName, region))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		idxTask, err := client.CreateIndex(wpolicy, ns, set, set+binName, binName, GEO2DSPHERE)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(<-idxTask.OnComplete()).ToNot(HaveOccurred())
+		defer client.DropIndex(wpolicy, ns, set, set+binName)
+
+		point := `{ "type": "Point", "coordinates": [-122.0, 37.5] }`
+
+		stm := NewStatement(ns, set)
+		stm.Addfilter(NewGeoRegionsContainingPointFilter(binName, point))
+		recordset, err := client.Query(nil, stm)
+		Expect(err).ToNot(HaveOccurred())
+
+		records := collect(recordset)
+		Expect(len(records)).To(Equal(1))
+	})
+
+})
@@ -0,0 +1,163 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Histogram is a snapshot of the distribution of a value in buckets.
+type Histogram struct {
+	// Bounds are the inclusive upper bounds of the buckets, in ascending order.
+	Bounds []int64 `json:"bounds"`
+
+	// Counts are the number of values in each bucket. It has one more element
+	// than Bounds, counting the values greater than the last bound.
+	Counts []int64 `json:"counts"`
+}
+
+// Total returns the number of values in all buckets.
+func (h Histogram) Total() int64 {
+	var total int64
+	for _, c := range h.Counts {
+		total += c
+	}
+	return total
+}
+
+// Diff returns the counts added to the histogram since the prev snapshot of it.
+// If the buckets of the snapshots differ, the histogram is returned unchanged.
+func (h Histogram) Diff(prev Histogram) Histogram {
+	if len(prev.Counts) != len(h.Counts) {
+		return h
+	}
+
+	res := Histogram{
+		Bounds: h.Bounds,
+		Counts: make([]int64, len(h.Counts)),
+	}
+	for i := range h.Counts {
+		res.Counts[i] = h.Counts[i] - prev.Counts[i]
+	}
+	return res
+}
+
+// LatencyType groups commands for latency statistics.
+type LatencyType string
+
+const (
+	// LATENCY_READ includes single record reads, header reads and exists commands.
+	LATENCY_READ LatencyType = "read"
+	// LATENCY_WRITE includes single record writes, deletes, touches, operations and UDFs.
+	LATENCY_WRITE LatencyType = "write"
+	// LATENCY_BATCH includes batch reads and batch exists commands.
+	LATENCY_BATCH LatencyType = "batch"
+	// LATENCY_QUERY includes scans, queries and background query executions.
+	LATENCY_QUERY LatencyType = "query"
+)
+
+// defaultLatencyBuckets are the latency bucket bounds used when
+// ClientPolicy.LatencyBuckets is not set.
+var defaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	4 * time.Millisecond,
+	8 * time.Millisecond,
+	16 * time.Millisecond,
+	32 * time.Millisecond,
+	64 * time.Millisecond,
+	128 * time.Millisecond,
+	256 * time.Millisecond,
+	512 * time.Millisecond,
+	1024 * time.Millisecond,
+}
+
+// LatencyHistogram is a snapshot of the distribution of command latencies.
+type LatencyHistogram struct {
+	// Bounds are the inclusive upper bounds of the buckets, in ascending order.
+	Bounds []time.Duration `json:"bounds"`
+
+	// Counts are the number of commands in each bucket. It has one more element
+	// than Bounds, counting the commands slower than the last bound.
+	Counts []int64 `json:"counts"`
+}
+
+// newLatencyHistogram creates a histogram of latencies in nanoseconds.
+func newLatencyHistogram(bounds []time.Duration) *histogram {
+	nanos := make([]int64, len(bounds))
+	for i := range bounds {
+		nanos[i] = int64(bounds[i])
+	}
+	return newHistogram(nanos...)
+}
+
+// latencySnapshot returns a snapshot of a histogram of latencies in nanoseconds.
+func (h *histogram) latencySnapshot() LatencyHistogram {
+	s := h.snapshot()
+	res := LatencyHistogram{
+		Bounds: make([]time.Duration, len(s.Bounds)),
+		Counts: s.Counts,
+	}
+	for i := range s.Bounds {
+		res.Bounds[i] = time.Duration(s.Bounds[i])
+	}
+	return res
+}
+
+// histogram counts values in buckets concurrently.
+type histogram struct {
+	bounds []int64
+	counts []int64
+}
+
+func newHistogram(bounds ...int64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]int64, len(bounds)+1),
+	}
+}
+
+func (h *histogram) add(value int64) {
+	i := 0
+	for i < len(h.bounds) && value > h.bounds[i] {
+		i++
+	}
+	atomic.AddInt64(&h.counts[i], 1)
+}
+
+// reset clears the counts and returns a snapshot of them. Values added
+// concurrently are either counted in the snapshot or after the reset.
+func (h *histogram) reset() Histogram {
+	res := Histogram{
+		Bounds: append([]int64(nil), h.bounds...),
+		Counts: make([]int64, len(h.counts)),
+	}
+	for i := range h.counts {
+		res.Counts[i] = atomic.SwapInt64(&h.counts[i], 0)
+	}
+	return res
+}
+
+func (h *histogram) snapshot() Histogram {
+	res := Histogram{
+		Bounds: append([]int64(nil), h.bounds...),
+		Counts: make([]int64, len(h.counts)),
+	}
+	for i := range h.counts {
+		res.Counts[i] = atomic.LoadInt64(&h.counts[i])
+	}
+	return res
+}
@@ -0,0 +1,125 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Histogram Test", func() {
+
+	It("should count values in their buckets", func() {
+		h := newHistogram(1, 10, 100)
+		for _, v := range []int64{0, 1, 2, 10, 11, 100, 101, 5000} {
+			h.add(v)
+		}
+
+		s := h.snapshot()
+		Expect(s.Bounds).To(Equal([]int64{1, 10, 100}))
+		Expect(s.Counts).To(Equal([]int64{2, 2, 2, 2}))
+		Expect(s.Total()).To(Equal(int64(8)))
+	})
+
+	It("should count latencies in duration buckets", func() {
+		h := newLatencyHistogram([]time.Duration{time.Millisecond, 10 * time.Millisecond})
+		h.add(int64(500 * time.Microsecond))
+		h.add(int64(5 * time.Millisecond))
+		h.add(int64(time.Second))
+
+		s := h.latencySnapshot()
+		Expect(s.Bounds).To(Equal([]time.Duration{time.Millisecond, 10 * time.Millisecond}))
+		Expect(s.Counts).To(Equal([]int64{1, 1, 1}))
+	})
+
+	It("should account commands in their latency type", func() {
+		Expect(COMMAND_READ_HEADER.latencyType()).To(Equal(LATENCY_READ))
+		Expect(COMMAND_OPERATE.latencyType()).To(Equal(LATENCY_WRITE))
+		Expect(COMMAND_BATCH_GET.latencyType()).To(Equal(LATENCY_BATCH))
+		Expect(COMMAND_SCAN.latencyType()).To(Equal(LATENCY_QUERY))
+	})
+
+	It("should reset the counts and return the snapshot before the reset", func() {
+		h := newHistogram(1, 10)
+		h.add(0)
+		h.add(5)
+
+		Expect(h.reset().Counts).To(Equal([]int64{1, 1, 0}))
+		Expect(h.snapshot().Counts).To(Equal([]int64{0, 0, 0}))
+
+		h.add(50)
+		Expect(h.reset().Counts).To(Equal([]int64{0, 0, 1}))
+	})
+
+	It("should not reset the latency histograms with the node statistics", func() {
+		cluster := &Cluster{clientPolicy: *NewClientPolicy()}
+		node := newNode(cluster, &nodeValidator{name: "A", aliases: []*Host{NewHost("127.0.0.1", 3000)}})
+		node.addLatency(COMMAND_READ, time.Millisecond)
+		node.commandLatency.add(1)
+
+		Expect(node.ResetStats().CommandLatency.Total()).To(Equal(int64(1)))
+		Expect(node.Stats().CommandLatency.Total()).To(Equal(int64(0)))
+
+		var count int64
+		for _, c := range node.LatencyStats()[LATENCY_READ].Counts {
+			count += c
+		}
+		Expect(count).To(Equal(int64(1)))
+	})
+
+	It("should diff the statistics with a previous snapshot", func() {
+		h := newHistogram(1, 10)
+		h.add(0)
+		prevHist := h.snapshot()
+		h.add(0)
+		h.add(5)
+		Expect(h.snapshot().Diff(prevHist).Counts).To(Equal([]int64{1, 1, 0}))
+
+		// histograms with different buckets are not subtracted
+		Expect(h.snapshot().Diff(Histogram{}).Counts).To(Equal([]int64{2, 1, 0}))
+
+		prev := &ClusterStats{
+			TendCount:   10,
+			Connections: 5,
+			Nodes: map[string]NodeStats{
+				"A": {Name: "A", Connections: 5, ConnectionsOpened: 7, TendCount: 10, CommandLatency: prevHist},
+			},
+		}
+		cur := &ClusterStats{
+			TendCount:   15,
+			Connections: 4,
+			Nodes: map[string]NodeStats{
+				"A": {Name: "A", Connections: 3, ConnectionsOpened: 9, TendCount: 15, CommandLatency: h.snapshot()},
+				"B": {Name: "B", Connections: 1, ConnectionsOpened: 1, TendCount: 2},
+			},
+		}
+
+		diff := cur.Diff(prev)
+		Expect(diff.TendCount).To(Equal(5))
+		Expect(diff.Connections).To(Equal(4))
+		Expect(diff.Nodes["A"].Connections).To(Equal(3))
+		Expect(diff.Nodes["A"].ConnectionsOpened).To(Equal(2))
+		Expect(diff.Nodes["A"].TendCount).To(Equal(5))
+		Expect(diff.Nodes["A"].CommandLatency.Counts).To(Equal([]int64{1, 1, 0}))
+		Expect(diff.Nodes["B"]).To(Equal(cur.Nodes["B"]))
+
+		// the snapshots are not modified
+		Expect(cur.TendCount).To(Equal(15))
+		Expect(cur.Nodes["A"].ConnectionsOpened).To(Equal(9))
+	})
+
+})
@@ -0,0 +1,123 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// HLL operation commands.
+const (
+	_HLL_INIT            = 0
+	_HLL_ADD             = 1
+	_HLL_SET_UNION       = 2
+	_HLL_SET_COUNT       = 3
+	_HLL_FOLD            = 4
+	_HLL_COUNT           = 50
+	_HLL_UNION           = 51
+	_HLL_UNION_COUNT     = 52
+	_HLL_INTERSECT_COUNT = 53
+	_HLL_SIMILARITY      = 54
+	_HLL_DESCRIBE        = 55
+)
+
+// newHLLOperation creates a HyperLogLog operation on a HLL bin.
+// HLL modify operations require the server to return a result for each operation.
+func newHLLOperation(opType OperationType, binName string, command int, args ...interface{}) *Operation {
+	op := &Operation{OpType: opType, BinName: binName, BinValue: newCDTOpValue(command, nil, args...)}
+	op.respondAllOps = opType == HLL_MODIFY
+	return op
+}
+
+// HyperLogLog (HLL) operations. Create HLL operations used by the client's Operate command.
+//
+// HyperLogLog operations on HLL items nested in lists/maps are not currently
+// supported by the server.
+
+// HLLInitOp creates a HLL init operation.
+// Server creates a new HLL or resets an existing HLL.
+// If indexBitCount and minHashBitCount are -1, the existing HLL configuration is reused.
+// Server does not return a value.
+//
+// indexBitCount is the number of index bits. Must be between 4 and 16 inclusive.
+// minHashBitCount is the number of min hash bits. Must be between 4 and 51 inclusive.
+func HLLInitOp(policy *HLLPolicy, binName string, indexBitCount, minHashBitCount int) *Operation {
+	return newHLLOperation(HLL_MODIFY, binName, _HLL_INIT, indexBitCount, minHashBitCount, int(policy.flags))
+}
+
+// HLLAddOp creates a HLL add operation.
+// Server adds values to the HLL set. If the HLL bin does not exist, it is
+// created with the specified indexBitCount and minHashBitCount.
+// Server returns the number of entries that caused the HLL to update a register.
+func HLLAddOp(policy *HLLPolicy, binName string, list []Value, indexBitCount, minHashBitCount int) *Operation {
+	return newHLLOperation(HLL_MODIFY, binName, _HLL_ADD, list, indexBitCount, minHashBitCount, int(policy.flags))
+}
+
+// HLLSetUnionOp creates a HLL set union operation.
+// Server sets the union of the specified HLL objects with the HLL bin.
+// Server does not return a value.
+func HLLSetUnionOp(policy *HLLPolicy, binName string, list []HLLValue) *Operation {
+	return newHLLOperation(HLL_MODIFY, binName, _HLL_SET_UNION, list, int(policy.flags))
+}
+
+// HLLRefreshCountOp creates a HLL refresh operation.
+// Server updates the cached count (if stale) and returns the count.
+func HLLRefreshCountOp(binName string) *Operation {
+	return newHLLOperation(HLL_MODIFY, binName, _HLL_SET_COUNT)
+}
+
+// HLLFoldOp creates a HLL fold operation.
+// Server folds indexBitCount to the specified value.
+// This can only be applied when minHashBitCount on the HLL bin is 0.
+// Server does not return a value.
+func HLLFoldOp(binName string, indexBitCount int) *Operation {
+	return newHLLOperation(HLL_MODIFY, binName, _HLL_FOLD, indexBitCount)
+}
+
+// HLLGetCountOp creates a HLL get count operation.
+// Server returns the estimated number of elements in the HLL bin.
+func HLLGetCountOp(binName string) *Operation {
+	return newHLLOperation(HLL_READ, binName, _HLL_COUNT)
+}
+
+// HLLGetUnionOp creates a HLL get union operation.
+// Server returns a HLLValue object that is the union of all specified HLL objects
+// in the list with the HLL bin.
+func HLLGetUnionOp(binName string, list []HLLValue) *Operation {
+	return newHLLOperation(HLL_READ, binName, _HLL_UNION, list)
+}
+
+// HLLGetUnionCountOp creates a HLL get union count operation.
+// Server returns the estimated number of elements that would be contained by
+// the union of these HLL objects.
+func HLLGetUnionCountOp(binName string, list []HLLValue) *Operation {
+	return newHLLOperation(HLL_READ, binName, _HLL_UNION_COUNT, list)
+}
+
+// HLLGetIntersectCountOp creates a HLL get intersect count operation.
+// Server returns the estimated number of elements that would be contained by
+// the intersection of these HLL objects.
+func HLLGetIntersectCountOp(binName string, list []HLLValue) *Operation {
+	return newHLLOperation(HLL_READ, binName, _HLL_INTERSECT_COUNT, list)
+}
+
+// HLLGetSimilarityOp creates a HLL get similarity operation.
+// Server returns the estimated similarity of these HLL objects as a float64.
+func HLLGetSimilarityOp(binName string, list []HLLValue) *Operation {
+	return newHLLOperation(HLL_READ, binName, _HLL_SIMILARITY, list)
+}
+
+// HLLDescribeOp creates a HLL describe operation.
+// Server returns indexBitCount and minHashBitCount used to create the HLL bin
+// in a list of integers. The list size is 2.
+func HLLDescribeOp(binName string) *Operation {
+	return newHLLOperation(HLL_READ, binName, _HLL_DESCRIBE)
+}
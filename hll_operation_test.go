@@ -0,0 +1,130 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("HyperLogLog Operations Test", func() {
+	initTestVars()
+
+	// connection data
+	var client *Client
+	var err error
+	var ns = "test"
+	var set = randString(50)
+	var key *Key
+	var wpolicy = NewWritePolicy(0, 0)
+	var hpolicy = DefaultHLLPolicy()
+	var binName string
+
+	// generates count random values
+	values := func(count int) []Value {
+		res := make([]Value, count)
+		for i := range res {
+			res[i] = NewStringValue(randString(20))
+		}
+		return res
+	}
+
+	BeforeEach(func() {
+		client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+		key, err = NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		binName = randString(10)
+	})
+
+	It("should init, add, count and describe", func() {
+		rec, err := client.Operate(wpolicy, key,
+			HLLInitOp(hpolicy, binName, 10, 0),
+			HLLAddOp(hpolicy, binName, values(100), -1, -1),
+			HLLGetCountOp(binName),
+			HLLDescribeOp(binName),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		results := rec.Bins[binName].([]interface{})
+		Expect(results[0]).To(BeNil())
+		Expect(results[1]).To(BeNumerically(">", 0))
+		Expect(results[2]).To(BeNumerically("~", 100, 10))
+		Expect(results[3]).To(Equal([]interface{}{10, 0}))
+
+		rec, err = client.Get(nil, key, binName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[binName]).To(BeAssignableToTypeOf(HLLValue{}))
+	})
+
+	It("should fold the index bits", func() {
+		rec, err := client.Operate(wpolicy, key,
+			HLLAddOp(hpolicy, binName, values(10), 12, 0),
+			HLLFoldOp(binName, 8),
+			HLLDescribeOp(binName),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[binName].([]interface{})[2]).To(Equal([]interface{}{8, 0}))
+	})
+
+	It("should compute union and intersection counts", func() {
+		other, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		common := values(50)
+		_, err = client.Operate(wpolicy, key, HLLAddOp(hpolicy, binName, append(values(50), common...), 12, 0))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.Operate(wpolicy, other, HLLAddOp(hpolicy, binName, append(values(50), common...), 12, 0))
+		Expect(err).ToNot(HaveOccurred())
+
+		rec, err := client.Get(nil, other, binName)
+		Expect(err).ToNot(HaveOccurred())
+		hll := rec.Bins[binName].(HLLValue)
+
+		rec, err = client.Operate(wpolicy, key,
+			HLLGetUnionCountOp(binName, []HLLValue{hll}),
+			HLLGetIntersectCountOp(binName, []HLLValue{hll}),
+			HLLGetUnionOp(binName, []HLLValue{hll}),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		results := rec.Bins[binName].([]interface{})
+		Expect(results[0]).To(BeNumerically("~", 150, 15))
+		Expect(results[1]).To(BeNumerically("~", 50, 10))
+		Expect(results[2]).To(BeAssignableToTypeOf(HLLValue{}))
+
+		rec, err = client.Operate(wpolicy, key,
+			HLLSetUnionOp(hpolicy, binName, []HLLValue{hll}),
+			HLLRefreshCountOp(binName),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins[binName].([]interface{})[1]).To(BeNumerically("~", 150, 15))
+	})
+
+	It("should honor HLL write flags", func() {
+		_, err := client.Operate(wpolicy, key, HLLInitOp(hpolicy, binName, 10, 0))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = client.Operate(wpolicy, key, HLLInitOp(NewHLLPolicy(HLLWriteFlagsCreateOnly), binName, 10, 0))
+		Expect(err).To(HaveOccurred())
+
+		_, err = client.Operate(wpolicy, key, HLLInitOp(NewHLLPolicy(HLLWriteFlagsCreateOnly|HLLWriteFlagsNoFail), binName, 10, 0))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+})
@@ -0,0 +1,55 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// HLLWriteFlags determines the behavior of HLL write operations.
+// Flags can be combined using bitwise OR.
+type HLLWriteFlags int
+
+const (
+	// HLLWriteFlagsDefault allows create or update.
+	HLLWriteFlagsDefault HLLWriteFlags = 0
+
+	// HLLWriteFlagsCreateOnly means: If the bin already exists, the operation will be denied.
+	// If the bin does not exist, a new bin will be created.
+	HLLWriteFlagsCreateOnly HLLWriteFlags = 1
+
+	// HLLWriteFlagsUpdateOnly means: If the bin already exists, the bin will be overwritten.
+	// If the bin does not exist, the operation will be denied.
+	HLLWriteFlagsUpdateOnly HLLWriteFlags = 2
+
+	// HLLWriteFlagsNoFail means: Do not raise error if operation is denied.
+	HLLWriteFlagsNoFail HLLWriteFlags = 4
+
+	// HLLWriteFlagsAllowFold allows the resulting set to be the minimum of provided index bits.
+	// Also, allow the usage of less precise HLL algorithms when minHash bits
+	// of all participating sets do not match.
+	HLLWriteFlagsAllowFold HLLWriteFlags = 8
+)
+
+// HLLPolicy determines the write flags used in HLL write operations.
+type HLLPolicy struct {
+	flags HLLWriteFlags
+}
+
+// NewHLLPolicy creates a HLL policy with the specified write flags.
+func NewHLLPolicy(flags HLLWriteFlags) *HLLPolicy {
+	return &HLLPolicy{flags: flags}
+}
+
+// DefaultHLLPolicy returns the default HLL policy.
+func DefaultHLLPolicy() *HLLPolicy {
+	return NewHLLPolicy(HLLWriteFlagsDefault)
+}
@@ -0,0 +1,61 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"net"
+)
+
+// HostResolver resolves the hosts of the seeds and of the nodes advertised by the
+// cluster to the addresses the client connects to. It allows environments with
+// split-horizon DNS or service meshes to map the advertised node addresses to
+// reachable ones.
+type HostResolver interface {
+	// ResolveHost returns the addresses to connect to for the host, in order of preference.
+	// The returned hosts are the aliases of the node, and may use different ports.
+	// It is called for IP addresses too, which the default resolution leaves as is.
+	ResolveHost(host *Host) ([]*Host, error)
+}
+
+// HostResolverFunc adapts a function to a HostResolver.
+type HostResolverFunc func(host *Host) ([]*Host, error)
+
+// ResolveHost calls the function.
+func (fn HostResolverFunc) ResolveHost(host *Host) ([]*Host, error) {
+	return fn(host)
+}
+
+// resolveHost returns the aliases of the host with the resolver, or with a DNS lookup
+// of the host name if the resolver is nil. IP addresses do not need a lookup.
+func resolveHost(resolver HostResolver, host *Host) ([]*Host, error) {
+	if resolver != nil {
+		return resolver.ResolveHost(host)
+	}
+
+	if ip := net.ParseIP(host.Name); ip != nil {
+		return []*Host{NewHost(host.Name, host.Port)}, nil
+	}
+
+	addresses, err := net.LookupHost(host.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make([]*Host, len(addresses))
+	for idx, addr := range addresses {
+		aliases[idx] = NewHost(addr, host.Port)
+	}
+	return aliases, nil
+}
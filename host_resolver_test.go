@@ -0,0 +1,84 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("Host Resolver Test", func() {
+	initTestVars()
+
+	It("must use the resolver in preflight checks", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		policy := *clientPolicy
+		policy.HostResolver = HostResolverFunc(func(host *Host) ([]*Host, error) {
+			if host.Name == "node.mesh" {
+				return []*Host{NewHost("127.0.0.1", 1)}, nil
+			}
+			return nil, errors.New("unknown host")
+		})
+
+		reports := Preflight(ctx, &policy, NewHost("node.mesh", 3000), NewHost("127.0.0.1", 3000))
+		Expect(len(reports)).To(Equal(2))
+
+		Expect(reports[0].Addresses).To(Equal([]string{"127.0.0.1"}))
+		last := reports[0].Results[len(reports[0].Results)-1]
+		Expect(last.Stage).To(Equal(PREFLIGHT_CONNECT))
+		Expect(last.Address).To(Equal("127.0.0.1:1"))
+		Expect(last.Err).To(HaveOccurred())
+
+		// IP addresses are resolved too
+		Expect(reports[1].Results[0].Stage).To(Equal(PREFLIGHT_DNS))
+		Expect(reports[1].Results[0].Err).To(HaveOccurred())
+	})
+
+	It("must resolve seeds and cluster nodes with the resolver", func() {
+		var mutex sync.Mutex
+		resolved := map[string]int{}
+
+		policy := *clientPolicy
+		policy.HostResolver = HostResolverFunc(func(h *Host) ([]*Host, error) {
+			mutex.Lock()
+			resolved[h.Name]++
+			mutex.Unlock()
+
+			if h.Name == "seed.mesh" {
+				return []*Host{NewHost(*host, *port)}, nil
+			}
+			return []*Host{h}, nil
+		})
+
+		client, err := NewClientWithPolicy(&policy, "seed.mesh", 3000)
+		Expect(err).ToNot(HaveOccurred())
+		defer client.Close()
+
+		Expect(client.IsConnected()).To(BeTrue())
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		Expect(resolved["seed.mesh"]).To(BeNumerically(">=", 1))
+	})
+})
@@ -0,0 +1,160 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/logger"
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+)
+
+// maximum number of distinct keys counted per hot key interval; the keys first
+// seen after the limit is reached are not counted until the next interval
+const _MAX_HOT_KEY_CANDIDATES = 10000
+
+// HotKey is one of the most used keys of a hot key interval.
+type HotKey struct {
+	// Namespace and SetName are those of the key.
+	Namespace string
+	SetName   string
+
+	// Digest is the digest of the key.
+	Digest []byte
+
+	// Node is the node the last sampled command on the key was sent to.
+	Node *Node
+
+	// Count is the number of sampled commands on the key during the interval.
+	Count int
+}
+
+// HotKeyListener can be implemented by a MetricsListener to receive the most used
+// keys of each MetricsPolicy.HotKeyInterval.
+type HotKeyListener interface {
+	// OnHotKeys is called at the end of each interval with the most used keys,
+	// in descending order of use. It is not called if no command was sampled.
+	OnHotKeys(keys []HotKey)
+}
+
+// hotKeySampler counts the single record commands per key digest.
+type hotKeySampler struct {
+	sampling int
+	top      int
+	counter  *AtomicInt
+
+	mutex  sync.Mutex
+	counts map[[20]byte]*HotKey
+}
+
+func newHotKeySampler(policy *MetricsPolicy) *hotKeySampler {
+	top := policy.HotKeyCount
+	if top <= 0 {
+		top = 10
+	}
+
+	return &hotKeySampler{
+		sampling: policy.HotKeySampling,
+		top:      top,
+		counter:  NewAtomicInt(0),
+		counts:   make(map[[20]byte]*HotKey),
+	}
+}
+
+// sample counts the command on the key if it is sampled.
+func (hs *hotKeySampler) sample(key *Key, node *Node) {
+	if hs.sampling > 1 && hs.counter.IncrementAndGet()%hs.sampling != 0 {
+		return
+	}
+
+	var digest [20]byte
+	copy(digest[:], key.Digest())
+
+	hs.mutex.Lock()
+	defer hs.mutex.Unlock()
+
+	hk := hs.counts[digest]
+	if hk == nil {
+		if len(hs.counts) >= _MAX_HOT_KEY_CANDIDATES {
+			return
+		}
+		hk = &HotKey{
+			Namespace: key.Namespace(),
+			SetName:   key.SetName(),
+			Digest:    digest[:],
+		}
+		hs.counts[digest] = hk
+	}
+	hk.Node = node
+	hk.Count++
+}
+
+// collect returns the most used keys since the last call, and resets the counts.
+func (hs *hotKeySampler) collect() []HotKey {
+	hs.mutex.Lock()
+	counts := hs.counts
+	hs.counts = make(map[[20]byte]*HotKey, len(counts))
+	hs.mutex.Unlock()
+
+	keys := make([]HotKey, 0, len(counts))
+	for _, hk := range counts {
+		keys = append(keys, *hk)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Count != keys[j].Count {
+			return keys[i].Count > keys[j].Count
+		}
+		return bytes.Compare(keys[i].Digest, keys[j].Digest) < 0
+	})
+
+	if len(keys) > hs.top {
+		keys = keys[:hs.top]
+	}
+	return keys
+}
+
+// reportHotKeys reports the most used keys every interval until the cluster is closed.
+func (clstr *Cluster) reportHotKeys(interval time.Duration) {
+	defer clstr.wgTend.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-clstr.tendChannel:
+			return
+		case <-ticker.C:
+			keys := clstr.hotKeys.collect()
+			if len(keys) == 0 {
+				continue
+			}
+
+			if listener, ok := clstr.clientPolicy.MetricsPolicy.Listener.(HotKeyListener); ok {
+				listener.OnHotKeys(keys)
+			}
+
+			if clstr.logEnabled(DEBUG) {
+				hottest := keys[0]
+				clstr.logEvent(DEBUG, SUBSYSTEM_CLUSTER, hottest.Node.GetName(), map[string]interface{}{"namespace": hottest.Namespace, "set": hottest.SetName, "digest": hex.EncodeToString(hottest.Digest), "count": hottest.Count}, "hottest key was used %d times in the last %s", hottest.Count, interval)
+			}
+		}
+	}
+}
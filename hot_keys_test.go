@@ -0,0 +1,78 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hot Key Sampler Test", func() {
+
+	newKey := func(i int) *Key {
+		key, err := NewKey("test", "hot", i)
+		Expect(err).ToNot(HaveOccurred())
+		return key
+	}
+
+	It("should report the most used keys in descending order", func() {
+		hs := newHotKeySampler(&MetricsPolicy{HotKeyCount: 2})
+		node := &Node{name: "A"}
+
+		for i := 1; i <= 4; i++ {
+			key := newKey(i)
+			for j := 0; j < i; j++ {
+				hs.sample(key, node)
+			}
+		}
+
+		keys := hs.collect()
+		Expect(len(keys)).To(Equal(2))
+		Expect(keys[0].Count).To(Equal(4))
+		Expect(keys[0].Digest).To(Equal(newKey(4).Digest()))
+		Expect(keys[0].Namespace).To(Equal("test"))
+		Expect(keys[0].SetName).To(Equal("hot"))
+		Expect(keys[0].Node == node).To(BeTrue())
+		Expect(keys[1].Count).To(Equal(3))
+
+		// the counts are reset after each interval
+		Expect(hs.collect()).To(BeEmpty())
+	})
+
+	It("should only count the sampled commands", func() {
+		hs := newHotKeySampler(&MetricsPolicy{HotKeySampling: 5})
+		key := newKey(1)
+		for i := 0; i < 100; i++ {
+			hs.sample(key, nil)
+		}
+
+		keys := hs.collect()
+		Expect(len(keys)).To(Equal(1))
+		Expect(keys[0].Count).To(Equal(20))
+	})
+
+	It("should bound the number of counted keys", func() {
+		hs := newHotKeySampler(&MetricsPolicy{})
+		for i := 0; i < _MAX_HOT_KEY_CANDIDATES+10; i++ {
+			hs.sample(newKey(i), nil)
+		}
+		Expect(len(hs.counts)).To(Equal(_MAX_HOT_KEY_CANDIDATES))
+
+		// the keys already counted are still counted
+		hs.sample(newKey(0), nil)
+		Expect(hs.collect()[0].Count).To(Equal(2))
+	})
+
+})
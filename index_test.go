@@ -15,6 +15,8 @@
 package aerospike_test
 
 import (
+	"context"
+	"fmt"
 	"math"
 	"math/rand"
 	"time"
@@ -76,6 +78,39 @@ var _ = Describe("Index operations test", func() {
 				Expect(err).To(HaveOccurred())
 			})
 
+			It("must report the progress of an Index creation and wait for it", func() {
+				idxTask, err := client.CreateIndex(wpolicy, ns, set, set+bin1.Name, bin1.Name, STRING)
+				Expect(err).ToNot(HaveOccurred())
+				defer client.DropIndex(wpolicy, ns, set, set+bin1.Name)
+
+				pct, err := idxTask.Progress()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pct).To(BeNumerically(">=", 0))
+				Expect(pct).To(BeNumerically("<=", 100))
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				defer cancel()
+				Expect(idxTask.Wait(ctx)).ToNot(HaveOccurred())
+
+				pct, err = idxTask.Progress()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pct).To(Equal(100))
+			})
+
+			It("must stop waiting for an Index creation when the context is canceled", func() {
+				idxTask, err := client.CreateIndex(wpolicy, ns, set, set+bin2.Name, bin2.Name, STRING)
+				Expect(err).ToNot(HaveOccurred())
+				defer client.DropIndex(wpolicy, ns, set, set+bin2.Name)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				// the task may have completed before the first poll
+				if err := idxTask.Wait(ctx); err != nil {
+					Expect(err).To(Equal(context.Canceled))
+				}
+			})
+
 			It("must drop an Index", func() {
 				idxTask, err := client.CreateIndex(wpolicy, ns, set, set+bin1.Name, bin1.Name, STRING)
 				Expect(err).ToNot(HaveOccurred())
@@ -116,4 +151,84 @@ var _ = Describe("Index operations test", func() {
 		})
 
 	})
+
+	Describe("Complex index queries", func() {
+		// connection data
+		var client *Client
+		var err error
+		var ns = "test"
+		var set string
+		var wpolicy = NewWritePolicy(0, 0)
+
+		const keyCount = 100
+
+		// counts the records returned by a query with the specified filter
+		var count = func(filter *Filter) int {
+			stm := NewStatement(ns, set)
+			stm.Addfilter(filter)
+			recordset, err := client.Query(nil, stm)
+			Expect(err).ToNot(HaveOccurred())
+
+			cnt := 0
+			for res := range recordset.Results() {
+				Expect(res.Err).ToNot(HaveOccurred())
+				cnt++
+			}
+			return cnt
+		}
+
+		BeforeEach(func() {
+			client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+
+			set = randString(50)
+			for i := 0; i < keyCount; i++ {
+				key, err := NewKey(ns, set, i)
+				Expect(err).ToNot(HaveOccurred())
+
+				list := []interface{}{i, i + 1, "value"}
+				mp := map[interface{}]interface{}{fmt.Sprintf("key%d", i): i * 10}
+				nested := map[interface{}]interface{}{"info": map[interface{}]interface{}{"age": i}}
+				err = client.PutBins(wpolicy, key, NewBin("list", list), NewBin("map", mp), NewBin("nested", nested))
+				Expect(err).ToNot(HaveOccurred())
+			}
+		})
+
+		It("must query list elements", func() {
+			idxTask, err := client.CreateComplexIndex(wpolicy, ns, set, set+"list", "list", NUMERIC, ICT_LIST)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.DropIndex(wpolicy, ns, set, set+"list")
+			Expect(<-idxTask.OnComplete()).ToNot(HaveOccurred())
+
+			Expect(count(NewContainsFilter("list", ICT_LIST, 10))).To(Equal(2))
+			Expect(count(NewContainsRangeFilter("list", ICT_LIST, 10, 11))).To(Equal(3))
+		})
+
+		It("must query map keys and values", func() {
+			idxTask, err := client.CreateComplexIndex(wpolicy, ns, set, set+"mapkeys", "map", STRING, ICT_MAPKEYS)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.DropIndex(wpolicy, ns, set, set+"mapkeys")
+			Expect(<-idxTask.OnComplete()).ToNot(HaveOccurred())
+
+			idxTask, err = client.CreateComplexIndex(wpolicy, ns, set, set+"mapvalues", "map", NUMERIC, ICT_MAPVALUES)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.DropIndex(wpolicy, ns, set, set+"mapvalues")
+			Expect(<-idxTask.OnComplete()).ToNot(HaveOccurred())
+
+			Expect(count(NewContainsFilter("map", ICT_MAPKEYS, "key5"))).To(Equal(1))
+			Expect(count(NewContainsRangeFilter("map", ICT_MAPVALUES, 0, 100))).To(Equal(11))
+		})
+
+		It("must query values inside nested maps", func() {
+			ctx := []*CDTContext{CtxMapKey("info"), CtxMapKey("age")}
+			idxTask, err := client.CreateComplexIndex(wpolicy, ns, set, set+"nested", "nested", NUMERIC, ICT_DEFAULT, ctx...)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.DropIndex(wpolicy, ns, set, set+"nested")
+			Expect(<-idxTask.OnComplete()).ToNot(HaveOccurred())
+
+			Expect(count(NewEqualFilter("nested", 7, ctx...))).To(Equal(1))
+			Expect(count(NewRangeFilter("nested", 10, 19, ctx...))).To(Equal(10))
+		})
+
+	})
 })
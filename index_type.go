@@ -14,6 +14,8 @@
 
 package aerospike
 
+import "fmt"
+
 // IndexType the type of the secondary index.
 type IndexType string
 
@@ -23,4 +25,42 @@ const (
 
 	// STRING specifies an index on string values.
 	STRING IndexType = "STRING"
+
+	// GEO2DSPHERE specifies 2-dimensional spherical geospatial index.
+	GEO2DSPHERE IndexType = "GEO2DSPHERE"
+)
+
+// IndexCollectionType is the secondary index collection type.
+type IndexCollectionType int
+
+const (
+	// ICT_DEFAULT is the normal scalar index.
+	ICT_DEFAULT IndexCollectionType = iota
+
+	// ICT_LIST is the index on list elements.
+	ICT_LIST
+
+	// ICT_MAPKEYS is the index on map keys.
+	ICT_MAPKEYS
+
+	// ICT_MAPVALUES is the index on map values.
+	ICT_MAPVALUES
 )
+
+// ictToString converts IndexCollectionType to its server string representation.
+func ictToString(ict IndexCollectionType) string {
+	switch ict {
+
+	case ICT_LIST:
+		return "LIST"
+
+	case ICT_MAPKEYS:
+		return "MAPKEYS"
+
+	case ICT_MAPVALUES:
+		return "MAPVALUES"
+
+	default:
+		panic(fmt.Sprintf("Unknown IndexCollectionType value %v", ict))
+	}
+}
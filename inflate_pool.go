@@ -0,0 +1,229 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+)
+
+// number of messages a scan or query reads ahead of the ones being decoded
+// when its compressed responses are decompressed by the inflate pool
+const _INFLATE_READ_AHEAD = 4
+
+// inflatedMessage is a message of a scan or query response, without its proto header,
+// or the error which occurred reading or decompressing it.
+type inflatedMessage struct {
+	msg []byte
+	err error
+}
+
+// inflatePool decompresses the compressed messages of scans and queries on a
+// bounded number of goroutines, shared by the commands of a cluster.
+// Only set if ClientPolicy.DecompressionWorkers is set.
+type inflatePool struct {
+	jobs      chan func()
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newInflatePool(workers int) *inflatePool {
+	pool := &inflatePool{
+		jobs:   make(chan func()),
+		closed: make(chan struct{}),
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case job := <-pool.jobs:
+					job()
+				case <-pool.closed:
+					return
+				}
+			}
+		}()
+	}
+	return pool
+}
+
+// inflate decompresses the payload of a compressed message on one of the workers,
+// and sends the result on res. If the pool is closed, the payload is decompressed
+// by the calling goroutine.
+func (pool *inflatePool) inflate(payload []byte, res chan<- inflatedMessage) {
+	job := func() {
+		msg, err := inflate(payload)
+		if err != nil {
+			res <- inflatedMessage{err: err}
+			return
+		}
+		res <- inflatedMessage{msg: msg[8:]}
+	}
+
+	select {
+	case pool.jobs <- job:
+	case <-pool.closed:
+		job()
+	}
+}
+
+// close stops the workers once they are done with their current messages.
+func (pool *inflatePool) close() {
+	pool.closeOnce.Do(func() {
+		close(pool.closed)
+	})
+}
+
+// parseInflatedResults parses the response of a scan or query like parseResult, while
+// another goroutine reads the messages from the connection ahead of them and hands
+// the compressed ones to the inflate pool.
+func (cmd *baseMultiCommand) parseInflatedResults(ifc command, conn *Connection, pool *inflatePool) error {
+	messages := make(chan chan inflatedMessage, _INFLATE_READ_AHEAD)
+	stop := make(chan struct{})
+	readerDone := make(chan struct{})
+
+	go func() {
+		defer close(readerDone)
+		readMessages(conn, pool, messages, stop)
+	}()
+
+	defer func() {
+		close(stop)
+
+		select {
+		case <-readerDone:
+		default:
+			// The reader may wait for a message after the last one, which is only
+			// known once it was decompressed. Nothing follows the last message, so
+			// the connection can still be reused once the read is interrupted.
+			if conn.conn == nil {
+				<-readerDone
+				return
+			}
+			conn.conn.SetDeadline(time.Now())
+			<-readerDone
+
+			var deadline time.Time
+			if conn.timeout > 0 {
+				deadline = time.Now().Add(conn.timeout)
+			}
+			conn.conn.SetDeadline(deadline)
+		}
+	}()
+
+	for res := range messages {
+		m := <-res
+		if m.err != nil {
+			return m.err
+		}
+
+		if len(m.msg) == 0 {
+			return nil
+		}
+
+		cmd.inflated = bytes.NewReader(m.msg)
+		status, err := ifc.parseRecordResults(ifc, len(m.msg))
+		cmd.inflated = nil
+		if err != nil || !status {
+			return err
+		}
+	}
+	return nil
+}
+
+// readMessages reads the messages of a response from the connection, and sends them
+// in order on messages as soon as they are read, until the last one was read or stop
+// is closed. The compressed messages are sent once they are decompressed.
+func readMessages(conn *Connection, pool *inflatePool, messages chan<- chan inflatedMessage, stop <-chan struct{}) {
+	defer close(messages)
+
+	header := make([]byte, 8)
+	for {
+		res := make(chan inflatedMessage, 1)
+		select {
+		case messages <- res:
+		case <-stop:
+			return
+		}
+
+		if _, err := conn.Read(header, 8); err != nil {
+			res <- inflatedMessage{err: err}
+			return
+		}
+
+		size := int(Buffer.BytesToInt64(header, 0) & 0xFFFFFFFFFFFF)
+		if size == 0 {
+			res <- inflatedMessage{}
+			return
+		}
+		if size > _MAX_BUFFER_SIZE {
+			res <- inflatedMessage{err: NewAerospikeError(PARSE_ERROR, fmt.Sprintf("Invalid message size: %d", size))}
+			return
+		}
+
+		payload := make([]byte, size)
+		if _, err := conn.Read(payload, size); err != nil {
+			res <- inflatedMessage{err: err}
+			return
+		}
+
+		if isCompressed(header) {
+			pool.inflate(payload, res)
+			continue
+		}
+
+		res <- inflatedMessage{msg: payload}
+		if isLastMessage(payload) {
+			return
+		}
+	}
+}
+
+// isLastMessage returns true if the records of the message end the response,
+// either with the end marker or with an error.
+func isLastMessage(msg []byte) bool {
+	headerSize := int(_MSG_REMAINING_HEADER_SIZE)
+
+	for offset := 0; offset+headerSize <= len(msg); {
+		info3 := int(msg[offset+3])
+		resultCode := msg[offset+5]
+
+		// partitions which are done or unavailable on the node
+		if info3&_INFO3_PARTITION_DONE != 0 {
+			offset += headerSize
+			continue
+		}
+
+		if resultCode != 0 || info3&_INFO3_LAST != 0 {
+			return true
+		}
+
+		fieldCount := int(uint16(Buffer.BytesToInt16(msg, offset+18)))
+		opCount := int(uint16(Buffer.BytesToInt16(msg, offset+20)))
+		offset += headerSize
+
+		// fields and operations are both prefixed by their size
+		for i := 0; i < fieldCount+opCount && offset+4 <= len(msg); i++ {
+			offset += 4 + int(uint32(Buffer.BytesToInt32(msg, offset)))
+		}
+	}
+	return false
+}
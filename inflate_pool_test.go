@@ -0,0 +1,146 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"net"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+)
+
+var _ = Describe("Inflate Pool Test", func() {
+
+	// record returns a record of a scan response with a string bin,
+	// or without bins if name is empty
+	record := func(info3 int, resultCode byte, name, value string) []byte {
+		rec := make([]byte, _MSG_REMAINING_HEADER_SIZE)
+		rec[0] = _MSG_REMAINING_HEADER_SIZE
+		rec[3] = byte(info3)
+		rec[5] = resultCode
+		if name == "" {
+			return rec
+		}
+
+		Buffer.Int16ToBytes(1, rec, 20)
+		op := make([]byte, 8)
+		Buffer.Int32ToBytes(int32(4+len(name)+len(value)), op, 0)
+		op[5] = byte(ParticleType.STRING)
+		op[7] = byte(len(name))
+		rec = append(rec, op...)
+		rec = append(rec, name...)
+		return append(rec, value...)
+	}
+
+	// message returns a proto message holding the records, compressed if requested
+	message := func(compress bool, records ...[]byte) []byte {
+		cmd := &baseCommand{dataBuffer: make([]byte, 8)}
+		for _, rec := range records {
+			cmd.dataBuffer = append(cmd.dataBuffer, rec...)
+		}
+		cmd.dataOffset = len(cmd.dataBuffer)
+		cmd.end()
+
+		if compress {
+			Expect(cmd.compress()).ToNot(HaveOccurred())
+			Expect(isCompressed(cmd.dataBuffer)).To(BeTrue())
+		}
+		return cmd.dataBuffer[:cmd.dataOffset]
+	}
+
+	value := strings.Repeat("aerospike", 30)
+
+	var client, server net.Conn
+	var conn *Connection
+	var pool *inflatePool
+	var recordset *Recordset
+	var cmd *scanCommand
+
+	BeforeEach(func() {
+		client, server = net.Pipe()
+		conn = &Connection{conn: client}
+		pool = newInflatePool(2)
+		recordset = newRecordset(10, 1)
+		cmd = newScanCommand(nil, NewScanPolicy(), "test", "", nil, recordset)
+	})
+
+	AfterEach(func() {
+		pool.close()
+		client.Close()
+		server.Close()
+	})
+
+	// send writes the messages to the connection in the background
+	send := func(messages ...[]byte) {
+		go func() {
+			defer GinkgoRecover()
+			for _, msg := range messages {
+				server.Write(msg)
+			}
+		}()
+	}
+
+	// expectReusable checks that the connection can still be read from
+	expectReusable := func() {
+		send([]byte("next"))
+		buf := make([]byte, 4)
+		_, err := conn.Read(buf, 4)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(buf)).To(Equal("next"))
+	}
+
+	It("should parse compressed messages followed by the end marker", func() {
+		send(
+			message(true, record(0, 0, "a", value), record(0, 0, "b", value)),
+			message(false, record(_INFO3_LAST, 0, "", "")),
+		)
+
+		Expect(cmd.parseInflatedResults(cmd, conn, pool)).ToNot(HaveOccurred())
+		Expect(len(recordset.Records)).To(Equal(2))
+		Expect((<-recordset.Records).Bins).To(Equal(BinMap{"a": value}))
+		Expect((<-recordset.Records).Bins).To(Equal(BinMap{"b": value}))
+
+		expectReusable()
+	})
+
+	It("should stop reading after a compressed last message", func() {
+		send(message(true, record(0, 0, "a", value), record(0, 0, "b", value), record(_INFO3_LAST, 0, "", "")))
+
+		Expect(cmd.parseInflatedResults(cmd, conn, pool)).ToNot(HaveOccurred())
+		Expect(len(recordset.Records)).To(Equal(2))
+
+		expectReusable()
+	})
+
+	It("should return the errors of corrupted messages", func() {
+		msg := message(true, record(0, 0, "a", value))
+		Buffer.Int64ToBytes(0, msg, 8)
+		send(msg)
+
+		Expect(cmd.parseInflatedResults(cmd, conn, pool)).To(HaveOccurred())
+	})
+
+	It("should detect the last message of a response", func() {
+		Expect(isLastMessage(record(0, 0, "a", value))).To(BeFalse())
+		Expect(isLastMessage(append(record(0, 0, "a", value), record(_INFO3_LAST, 0, "", "")...))).To(BeTrue())
+		Expect(isLastMessage(append(record(0, 0, "a", value), record(0, 4, "", "")...))).To(BeTrue())
+		Expect(isLastMessage(record(_INFO3_PARTITION_DONE, 4, "", ""))).To(BeFalse())
+	})
+
+})
@@ -0,0 +1,406 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// kinds of the entries of a write journal
+const (
+	journalPut    byte = 1
+	journalDelete byte = 2
+	journalDone   byte = 3
+)
+
+// size of the header of a journal entry: length, checksum, kind and id
+const journalHeaderSize = 4 + 4 + 1 + 8
+
+// the journal file is compacted once it grows past this size
+const journalCompactSize = 64 * 1024 * 1024
+
+// journalEntry is a write recorded in a journal.
+type journalEntry struct {
+	id      uint64
+	kind    byte
+	payload []byte
+}
+
+// WriteJournal is a file-backed journal of the writes of a client, for applications
+// which must not lose the writes in progress when they crash. Each write is appended
+// to the journal and synced to disk before it is sent to the server, and marked done
+// once the command returned. The writes left pending by a crash are sent again by
+// Replay once the journal is reopened.
+//
+// Writes are replayed with the same bins, so they should be idempotent; writes with
+// generation checks fail on replay if they were applied before the crash.
+// Writes which returned an error are not replayed, since their caller received it.
+// A WriteJournal is safe for concurrent use, but must not be shared by multiple
+// processes.
+type WriteJournal struct {
+	client *Client
+	path   string
+
+	mutex   sync.Mutex
+	file    *os.File
+	size    int64
+	nextId  uint64
+	pending map[uint64]*journalEntry
+}
+
+// NewWriteJournal opens the journal file at path, creating it if necessary, and
+// loads the writes left pending in it. A partially written entry at the end of the
+// file, left by a crash, is discarded.
+func NewWriteJournal(client *Client, path string) (*WriteJournal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	wj := &WriteJournal{
+		client:  client,
+		path:    path,
+		file:    file,
+		nextId:  1,
+		pending: map[uint64]*journalEntry{},
+	}
+
+	if err := wj.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return wj, nil
+}
+
+// load reads the entries of the journal file, and truncates it after the last
+// complete entry.
+func (wj *WriteJournal) load() error {
+	r := bufio.NewReader(wj.file)
+	for {
+		entry, n, err := readJournalEntry(r)
+		if err != nil {
+			break
+		}
+
+		if entry.id >= wj.nextId {
+			wj.nextId = entry.id + 1
+		}
+		if entry.kind == journalDone {
+			delete(wj.pending, entry.id)
+		} else {
+			wj.pending[entry.id] = entry
+		}
+		wj.size += int64(n)
+	}
+
+	if err := wj.file.Truncate(wj.size); err != nil {
+		return err
+	}
+	_, err := wj.file.Seek(wj.size, io.SeekStart)
+	return err
+}
+
+// readJournalEntry reads an entry and returns it with its size in the file.
+func readJournalEntry(r io.Reader) (*journalEntry, int, error) {
+	var header [journalHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, 0, err
+	}
+
+	length := int(binary.BigEndian.Uint32(header[0:]))
+	if length < journalHeaderSize-4 || length > _MAX_BUFFER_SIZE {
+		return nil, 0, NewAerospikeError(PARSE_ERROR, "Invalid journal entry size")
+	}
+
+	data := make([]byte, length)
+	copy(data, header[4:])
+	if _, err := io.ReadFull(r, data[journalHeaderSize-4:]); err != nil {
+		return nil, 0, err
+	}
+
+	if crc32.ChecksumIEEE(data[4:]) != binary.BigEndian.Uint32(data[0:]) {
+		return nil, 0, NewAerospikeError(PARSE_ERROR, "Invalid journal entry checksum")
+	}
+
+	entry := &journalEntry{
+		kind:    data[4],
+		id:      binary.BigEndian.Uint64(data[5:]),
+		payload: data[journalHeaderSize-4:],
+	}
+	return entry, 4 + length, nil
+}
+
+// encode returns the entry as written to the journal file.
+func (entry *journalEntry) encode() []byte {
+	data := make([]byte, journalHeaderSize+len(entry.payload))
+	binary.BigEndian.PutUint32(data[0:], uint32(len(data)-4))
+	data[8] = entry.kind
+	binary.BigEndian.PutUint64(data[9:], entry.id)
+	copy(data[journalHeaderSize:], entry.payload)
+	binary.BigEndian.PutUint32(data[4:], crc32.ChecksumIEEE(data[8:]))
+	return data
+}
+
+// append writes the entry to the journal file, and syncs the file if requested.
+// Must be called with the mutex held.
+func (wj *WriteJournal) append(entry *journalEntry, sync bool) error {
+	data := entry.encode()
+	if _, err := wj.file.Write(data); err != nil {
+		return err
+	}
+	wj.size += int64(len(data))
+
+	if sync {
+		return wj.file.Sync()
+	}
+	return nil
+}
+
+// begin records a pending write in the journal, and returns its id.
+func (wj *WriteJournal) begin(kind byte, key *Key, bins BinMap) (uint64, error) {
+	var userKey interface{}
+	if value := key.Value(); value != nil {
+		userKey = value.GetObject()
+	}
+
+	var binValues interface{}
+	if bins != nil {
+		binValues = map[string]interface{}(bins)
+	}
+
+	payload, err := packAnyArray([]interface{}{key.Namespace(), key.SetName(), userKey, key.Digest(), binValues})
+	if err != nil {
+		return 0, err
+	}
+
+	wj.mutex.Lock()
+	defer wj.mutex.Unlock()
+
+	if wj.file == nil {
+		return 0, NewAerospikeError(PARAMETER_ERROR, "Write journal is closed.")
+	}
+
+	entry := &journalEntry{id: wj.nextId, kind: kind, payload: payload}
+	if err := wj.append(entry, true); err != nil {
+		return 0, err
+	}
+
+	wj.nextId++
+	wj.pending[entry.id] = entry
+	return entry.id, nil
+}
+
+// done marks the write as done. The journal is emptied once no writes are
+// pending, or compacted once it grew too large.
+func (wj *WriteJournal) done(id uint64) error {
+	wj.mutex.Lock()
+	defer wj.mutex.Unlock()
+
+	if wj.file == nil {
+		return nil
+	}
+
+	delete(wj.pending, id)
+	if len(wj.pending) == 0 {
+		if err := wj.file.Truncate(0); err != nil {
+			return err
+		}
+		wj.size = 0
+		_, err := wj.file.Seek(0, io.SeekStart)
+		return err
+	}
+
+	if err := wj.append(&journalEntry{id: id, kind: journalDone}, false); err != nil {
+		return err
+	}
+	if wj.size > journalCompactSize {
+		return wj.compact()
+	}
+	return nil
+}
+
+// compact rewrites the journal file with the pending writes only.
+// Must be called with the mutex held.
+func (wj *WriteJournal) compact() error {
+	tmpPath := wj.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	size := int64(0)
+	for _, entry := range wj.pendingEntries() {
+		data := entry.encode()
+		if _, err := file.Write(data); err != nil {
+			file.Close()
+			return err
+		}
+		size += int64(len(data))
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := os.Rename(tmpPath, wj.path); err != nil {
+		file.Close()
+		return err
+	}
+
+	wj.file.Close()
+	wj.file, wj.size = file, size
+	return nil
+}
+
+// pendingEntries returns the pending writes in the order they were made.
+// Must be called with the mutex held.
+func (wj *WriteJournal) pendingEntries() []*journalEntry {
+	entries := make([]*journalEntry, 0, len(wj.pending))
+	for _, entry := range wj.pending {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+	return entries
+}
+
+// Put writes the bins of the record like Client.Put, after recording the write
+// in the journal.
+// If the policy is nil, the default relevant policy will be used.
+func (wj *WriteJournal) Put(policy *WritePolicy, key *Key, bins BinMap) error {
+	id, err := wj.begin(journalPut, key, bins)
+	if err != nil {
+		return err
+	}
+
+	err = wj.client.Put(policy, key, bins)
+	if doneErr := wj.done(id); err == nil {
+		err = doneErr
+	}
+	return err
+}
+
+// Delete deletes the record like Client.Delete, after recording the delete
+// in the journal.
+// If the policy is nil, the default relevant policy will be used.
+func (wj *WriteJournal) Delete(policy *WritePolicy, key *Key) (bool, error) {
+	id, err := wj.begin(journalDelete, key, nil)
+	if err != nil {
+		return false, err
+	}
+
+	existed, err := wj.client.Delete(policy, key)
+	if doneErr := wj.done(id); err == nil {
+		err = doneErr
+	}
+	return existed, err
+}
+
+// Pending returns the number of writes which were not done yet, either because
+// they are in progress or because they were left pending by a crash.
+func (wj *WriteJournal) Pending() int {
+	wj.mutex.Lock()
+	defer wj.mutex.Unlock()
+
+	return len(wj.pending)
+}
+
+// Replay sends the writes left pending by a crash again, in the order they were
+// made, and returns the number of writes replayed. It stops at the first error,
+// which leaves that write and the following ones pending.
+// Call Replay after NewWriteJournal, before making new writes.
+// If the policy is nil, the default relevant policy will be used.
+func (wj *WriteJournal) Replay(policy *WritePolicy) (int, error) {
+	wj.mutex.Lock()
+	entries := wj.pendingEntries()
+	wj.mutex.Unlock()
+
+	for i, entry := range entries {
+		if err := wj.replay(policy, entry); err != nil {
+			return i, err
+		}
+		if err := wj.done(entry.id); err != nil {
+			return i + 1, err
+		}
+	}
+	return len(entries), nil
+}
+
+// replay sends a pending write again.
+func (wj *WriteJournal) replay(policy *WritePolicy, entry *journalEntry) error {
+	key, bins, err := entry.decode()
+	if err != nil {
+		return err
+	}
+
+	if entry.kind == journalDelete {
+		_, err := wj.client.Delete(policy, key)
+		return err
+	}
+	return wj.client.Put(policy, key, bins)
+}
+
+// decode returns the key and bins of the write recorded by the entry.
+func (entry *journalEntry) decode() (*Key, BinMap, error) {
+	values, err := newUnpacker(entry.payload, 0, len(entry.payload)).UnpackList()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(values) != 5 {
+		return nil, nil, NewAerospikeError(PARSE_ERROR, "Invalid journal entry")
+	}
+
+	namespace, _ := values[0].(string)
+	setName, _ := values[1].(string)
+	digest, _ := values[3].([]byte)
+	key, err := NewKeyWithDigest(namespace, setName, values[2], digest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	binValues, _ := values[4].(map[interface{}]interface{})
+	if binValues == nil {
+		return key, nil, nil
+	}
+
+	bins := make(BinMap, len(binValues))
+	for name, value := range binValues {
+		if s, ok := name.(string); ok {
+			bins[s] = value
+		}
+	}
+	return key, bins, nil
+}
+
+// Close closes the journal file. The writes still pending are kept in it.
+func (wj *WriteJournal) Close() error {
+	wj.mutex.Lock()
+	defer wj.mutex.Unlock()
+
+	if wj.file == nil {
+		return nil
+	}
+
+	err := wj.file.Close()
+	wj.file = nil
+	return err
+}
@@ -0,0 +1,145 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Write Journal Test", func() {
+
+	var dir, path string
+	var key *Key
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "journal")
+		Expect(err).ToNot(HaveOccurred())
+		path = filepath.Join(dir, "writes.journal")
+
+		key, err = NewKey("test", "set", "key")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should keep the pending writes across reopens", func() {
+		wj, err := NewWriteJournal(nil, path)
+		Expect(err).ToNot(HaveOccurred())
+
+		id1, err := wj.begin(journalPut, key, BinMap{"a": 1, "b": "str", "c": []byte{1}, "d": []interface{}{1, "x"}})
+		Expect(err).ToNot(HaveOccurred())
+		id2, err := wj.begin(journalDelete, key, nil)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = wj.begin(journalPut, key, BinMap{"a": 2})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wj.done(id2)).ToNot(HaveOccurred())
+		Expect(wj.Close()).ToNot(HaveOccurred())
+
+		wj, err = NewWriteJournal(nil, path)
+		Expect(err).ToNot(HaveOccurred())
+		defer wj.Close()
+		Expect(wj.Pending()).To(Equal(2))
+
+		entries := wj.pendingEntries()
+		Expect(entries[0].id).To(Equal(id1))
+
+		resKey, bins, err := entries[0].decode()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resKey.Equals(key)).To(BeTrue())
+		Expect(resKey.Value().GetObject()).To(Equal("key"))
+		Expect(bins).To(Equal(BinMap{"a": 1, "b": "str", "c": []byte{1}, "d": []interface{}{1, "x"}}))
+
+		// new writes do not reuse the ids of the pending ones
+		id, err := wj.begin(journalPut, key, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(id > entries[1].id).To(BeTrue())
+	})
+
+	It("should empty the journal once no writes are pending", func() {
+		wj, err := NewWriteJournal(nil, path)
+		Expect(err).ToNot(HaveOccurred())
+		defer wj.Close()
+
+		id, err := wj.begin(journalPut, key, BinMap{"a": 1})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wj.done(id)).ToNot(HaveOccurred())
+
+		info, err := os.Stat(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Size()).To(Equal(int64(0)))
+	})
+
+	It("should discard a partially written entry", func() {
+		wj, err := NewWriteJournal(nil, path)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = wj.begin(journalPut, key, BinMap{"a": 1})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = wj.begin(journalPut, key, BinMap{"a": 2})
+		Expect(err).ToNot(HaveOccurred())
+		size := wj.size
+		Expect(wj.Close()).ToNot(HaveOccurred())
+
+		Expect(os.Truncate(path, size-3)).ToNot(HaveOccurred())
+
+		wj, err = NewWriteJournal(nil, path)
+		Expect(err).ToNot(HaveOccurred())
+		defer wj.Close()
+		Expect(wj.Pending()).To(Equal(1))
+
+		// the torn entry is overwritten by the next one
+		_, err = wj.begin(journalPut, key, BinMap{"a": 3})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wj.Close()).ToNot(HaveOccurred())
+
+		wj, err = NewWriteJournal(nil, path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wj.Pending()).To(Equal(2))
+	})
+
+	It("should compact the journal to the pending writes", func() {
+		wj, err := NewWriteJournal(nil, path)
+		Expect(err).ToNot(HaveOccurred())
+		defer wj.Close()
+
+		ids := []uint64{}
+		for i := 0; i < 10; i++ {
+			id, err := wj.begin(journalPut, key, BinMap{"a": i})
+			Expect(err).ToNot(HaveOccurred())
+			ids = append(ids, id)
+		}
+		for _, id := range ids[:9] {
+			Expect(wj.done(id)).ToNot(HaveOccurred())
+		}
+
+		wj.mutex.Lock()
+		Expect(wj.compact()).ToNot(HaveOccurred())
+		wj.mutex.Unlock()
+
+		reopened, err := NewWriteJournal(nil, path)
+		Expect(err).ToNot(HaveOccurred())
+		defer reopened.Close()
+		Expect(reopened.Pending()).To(Equal(1))
+		Expect(reopened.size).To(Equal(wj.size))
+	})
+
+})
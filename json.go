@@ -0,0 +1,165 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// MarshalJSON implements the json.Marshaler interface.
+// Maps read from the database, which have interface{} keys, are encoded as JSON
+// objects with their keys formatted as strings. Blobs are encoded as base64 strings,
+// and GeoJSON values as the strings holding them.
+func (bm BinMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(toJSONValue(map[string]interface{}(bm)))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The fields of the JSON object become bins: nested objects become maps with
+// string keys, arrays become lists, and numbers become int64 values if they are
+// integers and float64 values otherwise. Null fields are kept as nil values,
+// which delete their bins when written.
+func (bm *BinMap) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var obj map[string]interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		return err
+	}
+
+	*bm = BinMap(fromJSONValue(obj).(map[string]interface{}))
+	return nil
+}
+
+// toJSONValue converts the maps with interface{} keys in the value to maps with
+// string keys, which encoding/json supports.
+func toJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			if s, ok := k.(string); ok {
+				res[s] = toJSONValue(elem)
+			} else {
+				res[fmt.Sprint(k)] = toJSONValue(elem)
+			}
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			res[k] = toJSONValue(elem)
+		}
+		return res
+	case BinMap:
+		return toJSONValue(map[string]interface{}(v))
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, elem := range v {
+			res[i] = toJSONValue(elem)
+		}
+		return res
+	}
+	return value
+}
+
+// fromJSONValue converts the numbers of a decoded JSON value to int64 or float64.
+func fromJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, elem := range v {
+			v[k] = fromJSONValue(elem)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = fromJSONValue(elem)
+		}
+	}
+	return value
+}
+
+// recordJSON is the JSON representation of a record.
+type recordJSON struct {
+	Namespace  string      `json:"namespace,omitempty"`
+	SetName    string      `json:"set,omitempty"`
+	UserKey    interface{} `json:"key,omitempty"`
+	Digest     string      `json:"digest,omitempty"`
+	Generation int         `json:"generation"`
+	Expiration int         `json:"expiration"`
+	Bins       BinMap      `json:"bins"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The record is encoded as an object with its namespace, set, user key if it was
+// sent or stored, hex encoded digest, generation, expiration and bins, encoded
+// like BinMap.MarshalJSON. Lazily decoded records are decoded first.
+func (rc *Record) MarshalJSON() ([]byte, error) {
+	if err := rc.DecodeBins(); err != nil {
+		return nil, err
+	}
+
+	res := recordJSON{
+		Generation: rc.Generation,
+		Expiration: rc.Expiration,
+		Bins:       rc.Bins,
+	}
+
+	if rc.Key != nil {
+		res.Namespace = rc.Key.Namespace()
+		res.SetName = rc.Key.SetName()
+		res.Digest = hex.EncodeToString(rc.Key.Digest())
+		if value := rc.Key.Value(); value != nil {
+			res.UserKey = value.GetObject()
+		}
+	}
+
+	return json.Marshal(&res)
+}
+
+// PutJSON writes the fields of the JSON object in data as the bins of the record,
+// converted like BinMap.UnmarshalJSON: nested objects are stored as maps and arrays
+// as lists.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) PutJSON(policy *WritePolicy, key *Key, data []byte) error {
+	var bins BinMap
+	if err := json.Unmarshal(data, &bins); err != nil {
+		return NewAerospikeError(PARAMETER_ERROR, "Invalid JSON object: "+err.Error())
+	}
+	return clnt.Put(policy, key, bins)
+}
+
+// GetJSON reads the record for the specified key, and returns its bins as
+// a JSON object encoded like BinMap.MarshalJSON. Like Get, it returns nil
+// if the record does not exist, unless the policy's KeyNotFoundAsError is set.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) GetJSON(policy *BasePolicy, key *Key, binNames ...string) ([]byte, error) {
+	rec, err := clnt.Get(policy, key, binNames...)
+	if err != nil || rec == nil {
+		return nil, err
+	}
+	return json.Marshal(rec.Bins)
+}
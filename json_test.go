@@ -0,0 +1,83 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON Test", func() {
+
+	It("should encode maps read from the database as JSON objects", func() {
+		bins := BinMap{
+			"str":  "a",
+			"int":  1,
+			"list": []interface{}{1, "b", map[interface{}]interface{}{"c": 2}},
+			"map":  map[interface{}]interface{}{"d": []interface{}{3}, 4: "e"},
+			"blob": []byte{1, 2},
+		}
+
+		data, err := json.Marshal(bins)
+		Expect(err).ToNot(HaveOccurred())
+
+		var res map[string]interface{}
+		Expect(json.Unmarshal(data, &res)).ToNot(HaveOccurred())
+		Expect(res).To(Equal(map[string]interface{}{
+			"str":  "a",
+			"int":  1.0,
+			"list": []interface{}{1.0, "b", map[string]interface{}{"c": 2.0}},
+			"map":  map[string]interface{}{"d": []interface{}{3.0}, "4": "e"},
+			"blob": "AQI=",
+		}))
+	})
+
+	It("should decode JSON objects to bins", func() {
+		var bins BinMap
+		err := json.Unmarshal([]byte(`{"int":1,"float":1.5,"big":9007199254740993,"obj":{"a":[1,2.5,"x",null]},"null":null}`), &bins)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bins).To(Equal(BinMap{
+			"int":   int64(1),
+			"float": 1.5,
+			"big":   int64(9007199254740993),
+			"obj":   map[string]interface{}{"a": []interface{}{int64(1), 2.5, "x", nil}},
+			"null":  nil,
+		}))
+
+		Expect(json.Unmarshal([]byte(`[1]`), &bins)).To(HaveOccurred())
+	})
+
+	It("should encode records with their key and metadata", func() {
+		key, err := NewKey("test", "set", "k")
+		Expect(err).ToNot(HaveOccurred())
+
+		rec := newRecord(nil, key, BinMap{"a": 1}, 2, 3)
+		data, err := json.Marshal(rec)
+		Expect(err).ToNot(HaveOccurred())
+
+		var res map[string]interface{}
+		Expect(json.Unmarshal(data, &res)).ToNot(HaveOccurred())
+		Expect(res["namespace"]).To(Equal("test"))
+		Expect(res["set"]).To(Equal("set"))
+		Expect(res["key"]).To(Equal("k"))
+		Expect(res["digest"]).To(HaveLen(40))
+		Expect(res["generation"]).To(Equal(2.0))
+		Expect(res["expiration"]).To(Equal(3.0))
+		Expect(res["bins"]).To(Equal(map[string]interface{}{"a": 1.0}))
+	})
+
+})
@@ -60,6 +60,8 @@ func (ky *Key) SetName() string {
 }
 
 // Value returns key's value.
+// For records returned from scans and queries, the value is only available
+// if the record was written with WritePolicy.SendKey set; otherwise it is nil.
 func (ky *Key) Value() Value {
 	return ky.userKey
 }
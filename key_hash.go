@@ -0,0 +1,117 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
+)
+
+// KeyHashBinName is the name of the hidden bin which holds the secondary hash
+// of the user key when WritePolicy.SendKeyHash is set.
+const KeyHashBinName = "_key_hash"
+
+// KeyHashFunc computes a secondary hash of a user key.
+// The input is the set name, followed by the key particle type
+// and the key value, the same data the digest is computed from.
+type KeyHashFunc func(data []byte) int64
+
+// DefaultKeyHash computes the 64 bit FNV-1a hash of the data.
+func DefaultKeyHash(data []byte) int64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return int64(h.Sum64())
+}
+
+// computeKeyHash computes the secondary hash of the user key using the hash function.
+// If the hash function is nil, DefaultKeyHash will be used.
+func computeKeyHash(hashFunc KeyHashFunc, key *Key) (int64, error) {
+	if key.userKey == nil || key.userKey.GetType() == ParticleType.NULL {
+		return 0, NewAerospikeError(PARAMETER_ERROR, "Invalid key: the user key is required to compute the key hash.")
+	}
+
+	if hashFunc == nil {
+		hashFunc = DefaultKeyHash
+	}
+
+	buf := keyBufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	buf.WriteString(key.setName)
+	buf.WriteByte(byte(key.userKey.GetType()))
+	buf.ReadFrom(key.userKey.reader())
+
+	res := hashFunc(buf.Bytes())
+	keyBufPool.Put(buf)
+
+	return res, nil
+}
+
+// keyHashBin returns the hidden bin holding the secondary hash of the user key,
+// or nil if the policy does not require it. An error is returned if the policy
+// requires it but the hash can not be computed, e.g. without a user key.
+func keyHashBin(cluster *Cluster, policy *WritePolicy, key *Key) (*Bin, error) {
+	if policy == nil || !policy.SendKeyHash || policy.SendKey {
+		return nil, nil
+	}
+
+	hash, err := computeKeyHash(cluster.clientPolicy.KeyHash, key)
+	if err != nil {
+		return nil, err
+	}
+	return NewBin(KeyHashBinName, hash), nil
+}
+
+// stripKeyHash removes the hidden key hash bin from the bins.
+// If verify is true, the stored hash is checked against the user key.
+func stripKeyHash(cluster *Cluster, key *Key, bins BinMap, verify bool) error {
+	stored, exists := bins[KeyHashBinName]
+	if !exists {
+		return nil
+	}
+	delete(bins, KeyHashBinName)
+
+	if !verify {
+		return nil
+	}
+
+	// keys created without a user key can't be verified
+	if key.userKey == nil || key.userKey.GetType() == ParticleType.NULL {
+		return nil
+	}
+
+	hash, err := computeKeyHash(cluster.clientPolicy.KeyHash, key)
+	if err != nil {
+		return err
+	}
+
+	var storedHash int64
+	switch v := stored.(type) {
+	case int:
+		storedHash = int64(v)
+	case int64:
+		storedHash = v
+	default:
+		return NewAerospikeError(KEY_MISMATCH, fmt.Sprintf("Invalid key hash bin value: %v", stored))
+	}
+
+	if storedHash != hash {
+		return NewAerospikeError(KEY_MISMATCH, fmt.Sprintf("Key hash mismatch for key %s: stored %d, computed %d", key.String(), storedHash, hash))
+	}
+	return nil
+}
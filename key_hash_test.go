@@ -0,0 +1,58 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Key Hash Test", func() {
+
+	cluster := &Cluster{clientPolicy: *NewClientPolicy()}
+	policy := NewWritePolicy(0, 0)
+	policy.SendKeyHash = true
+
+	It("should add the key hash bin to writes and operations", func() {
+		key, err := NewKey("test", "set", 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		command, err := newWriteCommand(cluster, policy, key, []*Bin{NewBin("a", 1)}, WRITE)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(command.bins[1].Name).To(Equal(KeyHashBinName))
+
+		operate, err := newOperateCommand(cluster, policy, key, []*Operation{PutOp(NewBin("a", 1))})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(operate.operations[1].BinName).To(Equal(KeyHashBinName))
+	})
+
+	It("should fail the writes of keys without user key", func() {
+		key, err := NewKeyWithDigest("test", "set", nil, make([]byte, 20))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = newWriteCommand(cluster, policy, key, []*Bin{NewBin("a", 1)}, WRITE)
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+
+		_, err = newOperateCommand(cluster, policy, key, []*Operation{PutOp(NewBin("a", 1))})
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+
+		// reads do not need the key hash
+		_, err = newOperateCommand(cluster, policy, key, []*Operation{GetOpForBin("a")})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+})
@@ -0,0 +1,157 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/logger"
+)
+
+var _ = Describe("Log Events Test", func() {
+	initTestVars()
+
+	It("must publish structured events to subscribers of the level", func() {
+		var events []*Event
+		unsubscribe := Logger.Subscribe(WARNING, func(evt *Event) {
+			events = append(events, evt)
+		})
+
+		err := errors.New("connection refused")
+		Logger.LogEvent(WARNING, SUBSYSTEM_NODE, "BB9", map[string]interface{}{"error": err}, "failed: %s", err)
+		Logger.Info("not published")
+		unsubscribe()
+		Logger.Warn("not published after unsubscribing")
+
+		Expect(len(events)).To(Equal(1))
+		Expect(events[0].Level).To(Equal(WARNING))
+		Expect(events[0].Subsystem).To(Equal(SUBSYSTEM_NODE))
+		Expect(events[0].Node).To(Equal("BB9"))
+		Expect(events[0].Message).To(Equal("failed: connection refused"))
+		Expect(events[0].Fields["error"]).To(Equal(err))
+		Expect(events[0].String()).To(Equal("[node] node BB9: failed: connection refused"))
+	})
+
+	It("must publish connection failures on the event channel", func() {
+		events, unsubscribe := Logger.SubscribeChan(WARNING, 100)
+		defer unsubscribe()
+
+		_, err := NewClientWithPolicy(clientPolicy, "127.0.0.1", 1)
+		Expect(err).To(HaveOccurred())
+
+		// seeding failures are published synchronously while the client is created
+		Expect(len(events)).To(BeNumerically(">", 0))
+		evt := <-events
+		Expect(evt.Fields["address"]).To(ContainSubstring("127.0.0.1"))
+	})
+
+	It("must convert events to sorted key-value pairs", func() {
+		evt := &Event{
+			Level:     WARNING,
+			Subsystem: SUBSYSTEM_NODE,
+			Node:      "BB9",
+			Message:   "failed",
+			Fields:    map[string]interface{}{"error": "refused", "address": "127.0.0.1:3000"},
+		}
+
+		Expect(evt.KeyValues()).To(Equal([]interface{}{
+			"subsystem", SUBSYSTEM_NODE,
+			"node", "BB9",
+			"address", "127.0.0.1:3000",
+			"error", "refused",
+		}))
+
+		sl := &recordingLogger{}
+		evt.LogTo(sl)
+		Expect(sl.lines).To(Equal([]string{"WARN failed"}))
+	})
+
+	It("must send the events of a client to its own logger", func() {
+		events, unsubscribe := Logger.SubscribeChan(WARNING, 100)
+		defer unsubscribe()
+
+		sl := &recordingLogger{}
+		policy := NewClientPolicy()
+		policy.Logger = sl
+
+		_, err := NewClientWithPolicy(policy, "127.0.0.1", 1)
+		Expect(err).To(HaveOccurred())
+
+		seedFailures := 0
+		for _, line := range sl.lines {
+			if strings.HasPrefix(line, "WARN Seed 127.0.0.1:1 failed") {
+				seedFailures++
+			}
+		}
+		Expect(seedFailures).To(BeNumerically(">", 0))
+
+		// only the dial failures of NewConnection, which is not bound to a client, go to the global Logger
+		for len(events) > 0 {
+			evt := <-events
+			Expect(evt.Subsystem).To(Equal(SUBSYSTEM_NODE))
+			Expect(evt.Node).To(Equal(""))
+		}
+	})
+
+	It("must not build the events of the levels the logger does not log", func() {
+		Expect(Logger.Enabled(DEBUG)).To(BeFalse())
+		unsubscribe := Logger.Subscribe(DEBUG, func(evt *Event) {})
+		Expect(Logger.Enabled(DEBUG)).To(BeTrue())
+		unsubscribe()
+
+		sl := &levelLogger{level: WARNING}
+		policy := NewClientPolicy()
+		policy.Logger = sl
+
+		_, err := NewClientWithPolicy(policy, "127.0.0.1", 1)
+		Expect(err).To(HaveOccurred())
+
+		Expect(len(sl.lines)).To(BeNumerically(">", 0))
+		for _, line := range sl.lines {
+			Expect(strings.HasPrefix(line, "WARN ") || strings.HasPrefix(line, "ERROR ")).To(BeTrue())
+		}
+	})
+})
+
+type recordingLogger struct {
+	mutex sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) log(level string, msg string) {
+	l.mutex.Lock()
+	l.lines = append(l.lines, level+" "+msg)
+	l.mutex.Unlock()
+}
+
+func (l *recordingLogger) Debug(msg string, keyvals ...interface{}) { l.log("DEBUG", msg) }
+func (l *recordingLogger) Info(msg string, keyvals ...interface{})  { l.log("INFO", msg) }
+func (l *recordingLogger) Warn(msg string, keyvals ...interface{})  { l.log("WARN", msg) }
+func (l *recordingLogger) Error(msg string, keyvals ...interface{}) { l.log("ERROR", msg) }
+
+// levelLogger records the events of its level and above, and reports the
+// other levels as disabled.
+type levelLogger struct {
+	recordingLogger
+	level LogPriority
+}
+
+func (l *levelLogger) Enabled(level LogPriority) bool { return level >= l.level }
@@ -0,0 +1,160 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"time"
+)
+
+// Subsystems of the client which emit log events.
+const (
+	SUBSYSTEM_CLIENT  = "client"
+	SUBSYSTEM_CLUSTER = "cluster"
+	SUBSYSTEM_NODE    = "node"
+	SUBSYSTEM_COMMAND = "command"
+)
+
+// Event is a structured log record of the client.
+type Event struct {
+	Time  time.Time
+	Level LogPriority
+
+	// Subsystem that emitted the event, e.g. SUBSYSTEM_CLUSTER.
+	// Empty for unstructured log messages.
+	Subsystem string
+
+	// Node is the name or address of the server node the event relates to, if any.
+	Node string
+
+	// Message is the formatted log message.
+	Message string
+
+	// Fields hold additional event specific data, e.g. the error or the address.
+	Fields map[string]interface{}
+}
+
+// String implements the Stringer interface.
+func (evt *Event) String() string {
+	if evt.Node != "" {
+		return fmt.Sprintf("[%s] node %s: %s", evt.Subsystem, evt.Node, evt.Message)
+	}
+	if evt.Subsystem != "" {
+		return fmt.Sprintf("[%s] %s", evt.Subsystem, evt.Message)
+	}
+	return evt.Message
+}
+
+// EventHandler receives log events. Handlers are called synchronously from the
+// goroutine that emitted the event, and must not block.
+type EventHandler func(evt *Event)
+
+type subscription struct {
+	level   LogPriority
+	handler EventHandler
+}
+
+// Subscribe registers a handler for the log events of the specified level and above,
+// independently of the level set by SetLevel.
+// Call the returned function to unsubscribe.
+func (lgr *logger) Subscribe(level LogPriority, handler EventHandler) (unsubscribe func()) {
+	sub := &subscription{level: level, handler: handler}
+
+	lgr.mutex.Lock()
+	lgr.subscriptions = append(lgr.subscriptions, sub)
+	lgr.mutex.Unlock()
+
+	return func() {
+		lgr.mutex.Lock()
+		defer lgr.mutex.Unlock()
+
+		// copy on write, so that events being published are not affected
+		res := make([]*subscription, 0, len(lgr.subscriptions))
+		for _, s := range lgr.subscriptions {
+			if s != sub {
+				res = append(res, s)
+			}
+		}
+		lgr.subscriptions = res
+	}
+}
+
+// SubscribeChan returns a channel receiving the log events of the specified level
+// and above. Events are dropped if the channel buffer is full, so that the client
+// is never blocked by a slow consumer. Call the returned function to unsubscribe;
+// the channel is not closed, since events may still be in flight.
+func (lgr *logger) SubscribeChan(level LogPriority, size int) (events <-chan *Event, unsubscribe func()) {
+	ch := make(chan *Event, size)
+	unsubscribe = lgr.Subscribe(level, func(evt *Event) {
+		select {
+		case ch <- evt:
+		default:
+		}
+	})
+	return ch, unsubscribe
+}
+
+// Enabled returns true if the events of the level are printed or published,
+// so that callers can skip building the events which would be dropped.
+func (lgr *logger) Enabled(level LogPriority) bool {
+	lgr.mutex.RLock()
+	defer lgr.mutex.RUnlock()
+
+	if lgr.level <= level {
+		return true
+	}
+	for _, sub := range lgr.subscriptions {
+		if sub.level <= level {
+			return true
+		}
+	}
+	return false
+}
+
+// LogEvent logs a structured event. The message is printed if the log level allows it,
+// and the event is published to the subscribers of the level.
+func (lgr *logger) LogEvent(level LogPriority, subsystem string, node string, fields map[string]interface{}, format string, v ...interface{}) {
+	lgr.mutex.RLock()
+	printer := lgr.Logger
+	print := lgr.level <= level
+	subscriptions := lgr.subscriptions
+	lgr.mutex.RUnlock()
+
+	// avoid formatting the message if nobody is interested
+	var evt *Event
+	newEvent := func() *Event {
+		if evt == nil {
+			evt = &Event{
+				Time:      time.Now(),
+				Level:     level,
+				Subsystem: subsystem,
+				Node:      node,
+				Message:   fmt.Sprintf(format, v...),
+				Fields:    fields,
+			}
+		}
+		return evt
+	}
+
+	for _, sub := range subscriptions {
+		if sub.level <= level {
+			sub.handler(newEvent())
+		}
+	}
+
+	if print {
+		printer.Printf("%s", newEvent().String())
+	}
+}
@@ -40,6 +40,9 @@ type logger struct {
 
 	level LogPriority
 	mutex sync.RWMutex
+
+	// subscribers of log events; copied on write
+	subscriptions []*subscription
 }
 
 // Logger is the default logger instance
@@ -84,28 +87,20 @@ func (lgr *logger) LogAtLevel(level LogPriority, format string, v ...interface{}
 
 // Debug logs a message if log level allows to do so.
 func (lgr *logger) Debug(format string, v ...interface{}) {
-	if lgr.level <= DEBUG {
-		lgr.Logger.Printf(format, v...)
-	}
+	lgr.LogEvent(DEBUG, "", "", nil, format, v...)
 }
 
 // Info logs a message if log level allows to do so.
 func (lgr *logger) Info(format string, v ...interface{}) {
-	if lgr.level <= INFO {
-		lgr.Logger.Printf(format, v...)
-	}
+	lgr.LogEvent(INFO, "", "", nil, format, v...)
 }
 
 // Warn logs a message if log level allows to do so.
 func (lgr *logger) Warn(format string, v ...interface{}) {
-	if lgr.level <= WARNING {
-		lgr.Logger.Printf(format, v...)
-	}
+	lgr.LogEvent(WARNING, "", "", nil, format, v...)
 }
 
 // Error logs a message if log level allows to do so.
 func (lgr *logger) Error(format string, v ...interface{}) {
-	if lgr.level <= ERR {
-		lgr.Logger.Printf(format, v...)
-	}
+	lgr.LogEvent(ERR, "", "", nil, format, v...)
 }
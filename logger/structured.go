@@ -0,0 +1,73 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"sort"
+)
+
+// StructuredLogger is a leveled logger which receives a message and its context
+// as alternating key-value pairs. It is implemented by *slog.Logger, and can be
+// easily adapted to zap, logrus, etc.
+// Set ClientPolicy.Logger to send the log events of a client to it.
+type StructuredLogger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// LevelEnabler may be implemented by a StructuredLogger to report whether it
+// logs the events of a level, so that the client skips building the others.
+type LevelEnabler interface {
+	Enabled(level LogPriority) bool
+}
+
+// KeyValues returns the subsystem, node and fields of the event as alternating
+// key-value pairs. Fields are sorted by key.
+func (evt *Event) KeyValues() []interface{} {
+	res := make([]interface{}, 0, 4+2*len(evt.Fields))
+	if evt.Subsystem != "" {
+		res = append(res, "subsystem", evt.Subsystem)
+	}
+	if evt.Node != "" {
+		res = append(res, "node", evt.Node)
+	}
+
+	keys := make([]string, 0, len(evt.Fields))
+	for k := range evt.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		res = append(res, k, evt.Fields[k])
+	}
+	return res
+}
+
+// LogTo sends the event to the structured logger at the level of the event.
+func (evt *Event) LogTo(sl StructuredLogger) {
+	switch {
+	case evt.Level <= DEBUG:
+		sl.Debug(evt.Message, evt.KeyValues()...)
+	case evt.Level == INFO:
+		sl.Info(evt.Message, evt.KeyValues()...)
+	case evt.Level == WARNING:
+		sl.Warn(evt.Message, evt.KeyValues()...)
+	case evt.Level < OFF:
+		sl.Error(evt.Message, evt.KeyValues()...)
+	}
+}
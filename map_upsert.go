@@ -0,0 +1,73 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// expiration which leaves the TTL of an updated record unchanged; requires server version 3.10.1+
+const _TTL_DONT_UPDATE int32 = -2
+
+// maximum number of create and update attempts of MapUpsert, in case the record
+// is created or deleted concurrently in between
+const _MAP_UPSERT_ATTEMPTS = 3
+
+// MapUpsert applies the map operations to the map bin of the record, creating
+// the record and the map bin with the order of the map policy if they do not exist.
+// The record's TTL is set to the policy's Expiration only when the record is created;
+// updates leave the TTL of existing records unchanged.
+//
+// Each attempt is a single atomic Operate call: the record is first created with
+// CREATE_ONLY, and if it already exists, it is updated with UPDATE_ONLY.
+// The order of an existing map bin is set to the order of the map policy.
+// The returned flag is true if the record was created. The RecordExistsAction
+// of the policy is ignored.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) MapUpsert(policy *WritePolicy, key *Key, binName string, mapPolicy *MapPolicy, operations ...*Operation) (*Record, bool, error) {
+	policy = clnt.getUsableWritePolicy(policy)
+	if mapPolicy == nil {
+		mapPolicy = DefaultMapPolicy()
+	}
+
+	ops := make([]*Operation, 0, len(operations)+1)
+	ops = append(ops, MapCreateOp(binName, mapPolicy.attributes))
+	ops = append(ops, operations...)
+
+	// do not modify the caller's policy
+	createPolicy := *policy
+	createPolicy.RecordExistsAction = CREATE_ONLY
+
+	updatePolicy := *policy
+	updatePolicy.RecordExistsAction = UPDATE_ONLY
+	updatePolicy.Expiration = _TTL_DONT_UPDATE
+
+	var err error
+	for attempt := 0; attempt < _MAP_UPSERT_ATTEMPTS; attempt++ {
+		var rec *Record
+		rec, err = clnt.Operate(&createPolicy, key, ops...)
+		if ae, ok := err.(AerospikeError); !ok || ae.ResultCode() != KEY_EXISTS_ERROR {
+			return rec, err == nil, err
+		}
+
+		rec, err = clnt.Operate(&updatePolicy, key, ops...)
+		if ae, ok := err.(AerospikeError); !ok || ae.ResultCode() != KEY_NOT_FOUND_ERROR {
+			return rec, false, err
+		}
+
+		// the record was deleted in between; create it again
+	}
+	return nil, false, err
+}
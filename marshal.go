@@ -15,160 +15,490 @@
 package aerospike
 
 import (
+	"bytes"
+	"fmt"
 	"math"
 	"reflect"
 	"strings"
 	"sync"
 	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
 )
 
 const (
 	aerospikeTag = "as"
 	keyTag       = "key"
+
+	// maximum bin name length; longer names are truncated or rejected by the server
+	maxBinNameLength = 15
+)
+
+// AerospikeMarshaler is implemented by types which control their own bin
+// representation in PutObject, for example to encrypt a field or to store an enum
+// as a string. MarshalAerospike returns the value to store, which must be of a
+// type supported by NewValue.
+type AerospikeMarshaler interface {
+	MarshalAerospike() (interface{}, error)
+}
+
+// AerospikeUnmarshaler is implemented by types which decode their own bin
+// representation in GetObject. UnmarshalAerospike receives the bin value as it
+// was read from the server, and must be implemented with a pointer receiver.
+type AerospikeUnmarshaler interface {
+	UnmarshalAerospike(value interface{}) error
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*AerospikeMarshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*AerospikeUnmarshaler)(nil)).Elem()
 )
 
-func valueToInterface(f reflect.Value) interface{} {
+// marshalerOf returns the AerospikeMarshaler of the value, if its type or a pointer to it implements it.
+func marshalerOf(f reflect.Value) (AerospikeMarshaler, bool) {
+	if f.Type().Implements(marshalerType) {
+		if (f.Kind() == reflect.Ptr || f.Kind() == reflect.Interface) && f.IsNil() {
+			return nil, false
+		}
+		return f.Interface().(AerospikeMarshaler), true
+	}
+
+	if f.CanAddr() && reflect.PtrTo(f.Type()).Implements(marshalerType) {
+		return f.Addr().Interface().(AerospikeMarshaler), true
+	}
+	return nil, false
+}
+
+func valueToInterface(f reflect.Value) (interface{}, error) {
+	if m, ok := marshalerOf(f); ok {
+		return m.MarshalAerospike()
+	}
+
 	// get to the core value
 	for f.Kind() == reflect.Ptr {
 		if f.IsNil() {
-			return nil
+			return nil, nil
 		}
 		f = reflect.Indirect(f)
+
+		if m, ok := marshalerOf(f); ok {
+			return m.MarshalAerospike()
+		}
 	}
 
 	switch f.Kind() {
 	case reflect.Uint64:
-		return int64(f.Uint())
+		return int64(f.Uint()), nil
 	case reflect.Float64, reflect.Float32:
-		return int(math.Float64bits(f.Float()))
+		return int(math.Float64bits(f.Float())), nil
 	case reflect.Struct:
 		if f.Type().PkgPath() == "time" && f.Type().Name() == "Time" {
-			return f.Interface().(time.Time).UTC().UnixNano()
+			return f.Interface().(time.Time).UTC().UnixNano(), nil
 		} else {
 			return structToMap(f)
 		}
 	case reflect.Bool:
-		if f.Bool() == true {
-			return int64(1)
-		}
-		return int64(0)
+		return f.Bool(), nil
 	case reflect.Map:
 		if f.IsNil() {
-			return nil
+			return nil, nil
 		}
 
 		newMap := make(map[interface{}]interface{}, f.Len())
 		for _, mk := range f.MapKeys() {
-			newMap[valueToInterface(mk)] = valueToInterface(f.MapIndex(mk))
+			k, err := valueToInterface(mk)
+			if err != nil {
+				return nil, err
+			}
+			if newMap[k], err = valueToInterface(f.MapIndex(mk)); err != nil {
+				return nil, err
+			}
 		}
 
-		return f.Interface()
+		return f.Interface(), nil
 	case reflect.Slice, reflect.Array:
 		if f.Kind() == reflect.Slice && f.IsNil() {
-			return nil
+			return nil, nil
 		}
 
 		// convert to primitives recursively
+		var err error
 		newSlice := make([]interface{}, f.Len(), f.Cap())
 		for i := 0; i < len(newSlice); i++ {
-			newSlice[i] = valueToInterface(f.Index(i))
+			if newSlice[i], err = valueToInterface(f.Index(i)); err != nil {
+				return nil, err
+			}
 		}
 
-		return newSlice
+		return newSlice, nil
 	case reflect.Interface:
 		if f.IsNil() {
-			return nil
+			return nil, nil
 		}
-		return f.Interface()
+		return f.Interface(), nil
 	default:
-		return f.Interface()
+		return f.Interface(), nil
+	}
+}
+
+// fieldTag holds the options of an `as` struct tag, e.g. `as:"name,omitempty,msgpack"`.
+type fieldTag struct {
+	// bin name; empty if the field should not be persisted
+	name string
+	// skip the field on write if it holds the zero value of its type
+	omitEmpty bool
+	// store the field as a msgpack encoded blob instead of a native bin value
+	msgpack bool
+	// store time.Time and time.Duration fields in milliseconds instead of nanoseconds
+	millis bool
+	// store the fields of an embedded struct in the bins of its parent instead of a nested map
+	flatten bool
+}
+
+func parseFieldTag(f reflect.StructField) fieldTag {
+	tag := strings.Trim(f.Tag.Get(aerospikeTag), " ")
+
+	// if tag is -, the field should not be persisted
+	if tag == "-" {
+		return fieldTag{}
+	}
+
+	opts := strings.Split(tag, ",")
+	res := fieldTag{name: strings.Trim(opts[0], " ")}
+	if res.name == "" {
+		res.name = f.Name
+	}
+
+	for _, opt := range opts[1:] {
+		switch strings.Trim(opt, " ") {
+		case "omitempty":
+			res.omitEmpty = true
+		case "msgpack":
+			res.msgpack = true
+		case "millis":
+			res.millis = true
+		case "flatten":
+			res.flatten = true
+		}
+	}
+
+	return res
+}
+
+// objectField is a struct field persisted in a bin.
+type objectField struct {
+	tag fieldTag
+
+	// index sequence of the field, as for reflect.Value.FieldByIndex
+	index []int
+
+	// field name, qualified with the names of the flattened structs it belongs to
+	name string
+}
+
+// objectFieldSet holds the persisted fields of a struct type, and their positions by bin name.
+type objectFieldSet struct {
+	fields []objectField
+	bins   map[string]int
+}
+
+var objectFieldSets = struct {
+	sets  map[reflect.Type]*objectFieldSet
+	mutex sync.RWMutex
+}{sets: map[reflect.Type]*objectFieldSet{}}
+
+// objectFieldsOf returns the persisted fields of the struct type. The fields of embedded
+// structs tagged with the flatten option take the place of the embedded struct, so they
+// are stored in the bins of the parent. Other embedded structs are stored as nested maps
+// like any other struct field. The result is cached per type.
+func objectFieldsOf(objType reflect.Type) *objectFieldSet {
+	objectFieldSets.mutex.RLock()
+	set, exists := objectFieldSets.sets[objType]
+	objectFieldSets.mutex.RUnlock()
+	if exists {
+		return set
+	}
+
+	set = &objectFieldSet{bins: map[string]int{}}
+	set.fields = appendObjectFields(nil, objType, nil, "", map[reflect.Type]bool{objType: true})
+	for i, f := range set.fields {
+		if _, exists := set.bins[f.tag.name]; !exists {
+			set.bins[f.tag.name] = i
+		}
 	}
+
+	objectFieldSets.mutex.Lock()
+	objectFieldSets.sets[objType] = set
+	objectFieldSets.mutex.Unlock()
+
+	return set
 }
 
-func fieldAlias(f reflect.StructField) string {
-	alias := f.Tag.Get(aerospikeTag)
-	if alias != "" {
-		alias = strings.Trim(alias, " ")
+func appendObjectFields(fields []objectField, objType reflect.Type, index []int, prefix string, parents map[reflect.Type]bool) []objectField {
+	for i := 0; i < objType.NumField(); i++ {
+		f := objType.Field(i)
+		tag := parseFieldTag(f)
+		if tag.name == "" {
+			continue
+		}
+
+		// copy the index, so the slices of sibling fields do not share their backing array
+		fieldIndex := append(index[:len(index):len(index)], i)
+
+		if tag.flatten && f.Anonymous {
+			embedded := f.Type
+			isPtr := embedded.Kind() == reflect.Ptr
+			if isPtr {
+				embedded = embedded.Elem()
+			}
+
+			// pointers to unexported structs cannot be allocated when read back;
+			// recursive embedding cannot be flattened
+			if embedded.Kind() == reflect.Struct && (f.PkgPath == "" || !isPtr) && !parents[embedded] {
+				parents[embedded] = true
+				fields = appendObjectFields(fields, embedded, fieldIndex, prefix+f.Name+".", parents)
+				delete(parents, embedded)
+				continue
+			}
+		}
 
-		// if tag is -, the field should not be persisted
-		if alias == "-" {
-			return ""
+		// skip unexported fields
+		if f.PkgPath != "" {
+			continue
 		}
-		return alias
-	} else {
-		return f.Name
+
+		fields = append(fields, objectField{tag: tag, index: fieldIndex, name: prefix + f.Name})
 	}
+	return fields
 }
 
-func structToMap(s reflect.Value) map[string]interface{} {
+// objectFieldValue returns the field of the struct value with the index sequence.
+// Nil pointers to the flattened structs the field belongs to are allocated if alloc is set;
+// otherwise false is returned, as the field has no value.
+func objectFieldValue(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// isTimeType reports whether the type, or the type it points to, is time.Time or time.Duration.
+func isTimeType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == timeType || t == durationType
+}
+
+// isEmptyValue reports whether the field holds the zero value for the
+// purposes of the omitempty tag option. Structs are never considered empty,
+// except the zero time.Time.
+func isEmptyValue(v reflect.Value) bool {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).IsZero()
+	}
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// fieldToInterface converts the struct field to a bin value according to its tag.
+// A nil result means the field should not be persisted.
+func fieldToInterface(f reflect.Value, tag fieldTag) (interface{}, error) {
+	if tag.name == "" || (tag.omitEmpty && isEmptyValue(f)) {
+		return nil, nil
+	}
+
+	value, err := valueToInterface(f)
+	if err != nil || value == nil {
+		return value, err
+	}
+
+	// times and durations are in nanoseconds
+	if tag.millis && isTimeType(f.Type()) {
+		switch nanos := value.(type) {
+		case int64:
+			value = nanos / int64(time.Millisecond)
+		case time.Duration:
+			value = int64(nanos / time.Millisecond)
+		}
+	}
+
+	if tag.msgpack {
+		blob, err := packFieldBlob(tag.name, value)
+		if err != nil {
+			return nil, err
+		}
+		return blob, nil
+	}
+	return value, nil
+}
+
+// packFieldBlob encodes the value as a msgpack blob for fields tagged with the msgpack option.
+// Like NewValue, it will panic if the value type is not supported.
+func packFieldBlob(binName string, value interface{}) ([]byte, error) {
+	packer := newPacker()
+	if err := packer.PackObject(value); err != nil {
+		return nil, NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Failed to pack the value of bin `%s`: %s", binName, err))
+	}
+	return packer.buffer.Bytes(), nil
+}
+
+// validateObjectBins checks that the fields of the struct type map to distinct bin names.
+// Tag collisions, or names which are equal after truncation to the maximum bin name
+// length, would silently overwrite each other's bins.
+// The result is cached per type.
+func validateObjectBins(objType reflect.Type) error {
+	for objType.Kind() == reflect.Ptr {
+		objType = objType.Elem()
+	}
+
+	objectBinErrors.mutex.RLock()
+	err, exists := objectBinErrors.errors[objType]
+	objectBinErrors.mutex.RUnlock()
+	if exists {
+		return err
+	}
+
+	if objType.Kind() == reflect.Struct {
+		err = binNameCollisions(objType)
+	}
+
+	objectBinErrors.mutex.Lock()
+	objectBinErrors.errors[objType] = err
+	objectBinErrors.mutex.Unlock()
+
+	return err
+}
+
+func binNameCollisions(objType reflect.Type) error {
+	var names []string
+	fields := map[string][]string{}
+
+	for _, f := range objectFieldsOf(objType).fields {
+		name := f.tag.name
+		if len(name) > maxBinNameLength {
+			name = name[:maxBinNameLength]
+		}
+
+		if _, exists := fields[name]; !exists {
+			names = append(names, name)
+		}
+		fields[name] = append(fields[name], f.name)
+	}
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		if len(fields[name]) > 1 {
+			if buf.Len() > 0 {
+				buf.WriteString("; ")
+			}
+			fmt.Fprintf(&buf, "bin `%s` from fields %s", name, strings.Join(fields[name], ", "))
+		}
+	}
+
+	if buf.Len() > 0 {
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Duplicate bin names in type %s: %s", objType, buf.String()))
+	}
+	return nil
+}
+
+var objectBinErrors = struct {
+	errors map[reflect.Type]error
+	mutex  sync.RWMutex
+}{errors: map[reflect.Type]error{}}
+
+func structToMap(s reflect.Value) (map[string]interface{}, error) {
 	if !s.IsValid() {
-		return nil
+		return nil, nil
 	}
 
 	// map tags
 	cacheObjectTags(s)
 
-	typeOfT := s.Type()
-	numFields := s.NumField()
+	fields := objectFieldsOf(s.Type()).fields
 
 	var binMap map[string]interface{}
-	for i := 0; i < numFields; i++ {
-		// skip unexported fields
-		if typeOfT.Field(i).PkgPath != "" {
+	for _, f := range fields {
+		fv, ok := objectFieldValue(s, f.index, false)
+		if !ok {
 			continue
 		}
 
-		binValue := valueToInterface(s.Field(i))
+		binValue, err := fieldToInterface(fv, f.tag)
+		if err != nil {
+			return nil, err
+		}
 
 		if binValue != nil {
 			if binMap == nil {
-				binMap = make(map[string]interface{}, numFields)
+				binMap = make(map[string]interface{}, len(fields))
 			}
 
-			alias := fieldAlias(typeOfT.Field(i))
-			if alias == "" {
-				continue
-			}
-
-			binMap[alias] = binValue
+			binMap[f.tag.name] = binValue
 		}
 	}
 
-	return binMap
+	return binMap, nil
 }
 
-func marshal(v interface{}) []*Bin {
+func marshal(v interface{}) ([]*Bin, error) {
 	s := reflect.Indirect(reflect.ValueOf(v).Elem())
-	typeOfT := s.Type()
 
 	// map tags
 	cacheObjectTags(s)
 
-	numFields := s.NumField()
-	bins := binPool.Get(numFields).([]*Bin)
+	fields := objectFieldsOf(s.Type()).fields
+	bins := binPool.Get(len(fields)).([]*Bin)
 
 	binCount := 0
-	for i := 0; i < numFields; i++ {
-		// skip unexported fields
-		if typeOfT.Field(i).PkgPath != "" {
+	for _, f := range fields {
+		fv, ok := objectFieldValue(s, f.index, false)
+		if !ok {
 			continue
 		}
 
-		binValue := valueToInterface(s.Field(i))
+		binValue, err := fieldToInterface(fv, f.tag)
+		if err != nil {
+			binPool.Put(bins)
+			return nil, err
+		}
 
 		if binValue != nil {
-			alias := fieldAlias(typeOfT.Field(i))
-			if alias == "" {
-				continue
-			}
-
-			bins[binCount].Name = alias
+			bins[binCount].Name = f.tag.name
 			bins[binCount].Value = NewValue(binValue)
 			binCount++
 		}
 	}
 
-	return bins[:binCount]
+	return bins[:binCount], nil
 }
 
 type SyncMap struct {
@@ -225,14 +555,12 @@ func cacheObjectTags(obj reflect.Value) {
 			continue
 		}
 
-		tag := strings.Trim(f.Tag.Get(aerospikeTag), " ")
-		if tag != "-" {
-			if tag != "" {
-				mapping[tag] = f.Name
-				fields = append(fields, tag)
-			} else {
-				fields = append(fields, f.Name)
+		tag := parseFieldTag(f)
+		if tag.name != "" {
+			if tag.name != f.Name {
+				mapping[tag.name] = f.Name
 			}
+			fields = append(fields, tag.name)
 		}
 	}
 
@@ -0,0 +1,88 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"errors"
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type marshalerTestColor int
+
+var marshalerTestColors = []string{"red", "green", "blue"}
+
+func (c marshalerTestColor) MarshalAerospike() (interface{}, error) {
+	if int(c) >= len(marshalerTestColors) {
+		return nil, errors.New("invalid color")
+	}
+	return marshalerTestColors[c], nil
+}
+
+func (c *marshalerTestColor) UnmarshalAerospike(value interface{}) error {
+	for i, name := range marshalerTestColors {
+		if name == value {
+			*c = marshalerTestColor(i)
+			return nil
+		}
+	}
+	return errors.New("invalid color")
+}
+
+type marshalerTestObject struct {
+	Color   marshalerTestColor
+	Colors  []marshalerTestColor
+	Favored *marshalerTestColor `as:"favored"`
+	Missing *marshalerTestColor `as:"missing"`
+}
+
+var _ = Describe("Marshaler Test", func() {
+
+	It("should store and read fields with their own representation", func() {
+		blue := marshalerTestColor(2)
+		obj := &marshalerTestObject{Color: 1, Colors: []marshalerTestColor{2, 0}, Favored: &blue}
+
+		bins, err := marshal(obj)
+		Expect(err).ToNot(HaveOccurred())
+		binMap := BinMap{}
+		for _, bin := range bins {
+			binMap[bin.Name] = bin.Value.GetObject()
+		}
+		binPool.Put(bins)
+
+		Expect(binMap).To(Equal(BinMap{
+			"Color":   "green",
+			"Colors":  []interface{}{"blue", "red"},
+			"favored": "blue",
+		}))
+
+		res := &marshalerTestObject{}
+		for name, value := range binMap {
+			Expect(setObjectField(reflect.ValueOf(res).Elem(), name, value)).ToNot(HaveOccurred())
+		}
+		Expect(res).To(Equal(obj))
+	})
+
+	It("should return the errors of the marshalers", func() {
+		_, err := marshal(&marshalerTestObject{Color: 5})
+		Expect(err).To(HaveOccurred())
+
+		err = setObjectField(reflect.ValueOf(&marshalerTestObject{}).Elem(), "Color", "purple")
+		Expect(err).To(HaveOccurred())
+	})
+
+})
@@ -0,0 +1,217 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"encoding/hex"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/logger"
+)
+
+// CommandType identifies the kind of a database command in metrics.
+type CommandType string
+
+const (
+	COMMAND_READ          CommandType = "read"
+	COMMAND_READ_HEADER   CommandType = "read_header"
+	COMMAND_WRITE         CommandType = "write"
+	COMMAND_DELETE        CommandType = "delete"
+	COMMAND_TOUCH         CommandType = "touch"
+	COMMAND_EXISTS        CommandType = "exists"
+	COMMAND_OPERATE       CommandType = "operate"
+	COMMAND_UDF           CommandType = "udf"
+	COMMAND_BATCH_GET     CommandType = "batch_get"
+	COMMAND_BATCH_EXISTS  CommandType = "batch_exists"
+	COMMAND_SCAN          CommandType = "scan"
+	COMMAND_QUERY         CommandType = "query"
+	COMMAND_QUERY_EXECUTE CommandType = "query_execute"
+	COMMAND_UNKNOWN       CommandType = "unknown"
+)
+
+// latencyType returns the latency type the command type is accounted in.
+func (ct CommandType) latencyType() LatencyType {
+	switch ct {
+	case COMMAND_READ, COMMAND_READ_HEADER, COMMAND_EXISTS:
+		return LATENCY_READ
+	case COMMAND_WRITE, COMMAND_DELETE, COMMAND_TOUCH, COMMAND_OPERATE, COMMAND_UDF:
+		return LATENCY_WRITE
+	case COMMAND_BATCH_GET, COMMAND_BATCH_EXISTS:
+		return LATENCY_BATCH
+	case COMMAND_SCAN, COMMAND_QUERY, COMMAND_QUERY_EXECUTE:
+		return LATENCY_QUERY
+	default:
+		return ""
+	}
+}
+
+// MetricsListener receives the metrics of a client. It can be used to export
+// counters and latency histograms per node and command type to a monitoring
+// system like Prometheus.
+// The methods are called synchronously from the goroutines running the commands
+// and the cluster tend goroutine, and must be safe for concurrent use and return quickly.
+type MetricsListener interface {
+	// OnCommand is called when a command sent to node completes.
+	// Latency includes the retries; err is nil if the command was successful.
+	OnCommand(node *Node, commandType CommandType, latency time.Duration, err error)
+
+	// OnConnectionOpened is called when a new connection to node is opened.
+	OnConnectionOpened(node *Node)
+
+	// OnConnectionClosed is called when a connection to node is closed.
+	OnConnectionClosed(node *Node)
+}
+
+// MetricsPolicy determines how the metrics of a client are reported.
+// Set ClientPolicy.MetricsPolicy to enable metrics for a client.
+type MetricsPolicy struct {
+	// Listener receives the metrics.
+	Listener MetricsListener
+
+	// HotKeyInterval is the interval at which the most used keys of single record
+	// commands are reported to the Listener, if it implements HotKeyListener.
+	// Default (0) disables hot key detection.
+	HotKeyInterval time.Duration
+
+	// HotKeyCount is the number of keys reported per interval. Default (0) is 10.
+	HotKeyCount int
+
+	// HotKeySampling determines that one in HotKeySampling single record commands
+	// is counted for hot key detection. Default (0) counts all of them.
+	HotKeySampling int
+}
+
+// NewMetricsPolicy generates a new MetricsPolicy which reports the metrics to the listener.
+func NewMetricsPolicy(listener MetricsListener) *MetricsPolicy {
+	return &MetricsPolicy{
+		Listener: listener,
+	}
+}
+
+// metricsListener returns the metrics listener of the node's client, or nil.
+func (nd *Node) metricsListener() MetricsListener {
+	if nd.cluster == nil || nd.cluster.clientPolicy.MetricsPolicy == nil {
+		return nil
+	}
+	return nd.cluster.clientPolicy.MetricsPolicy.Listener
+}
+
+// commandTypeOf returns the type of the command for metrics.
+func commandTypeOf(ifc command) CommandType {
+	switch ifc.(type) {
+	case *readCommand:
+		return COMMAND_READ
+	case *readHeaderCommand:
+		return COMMAND_READ_HEADER
+	case *writeCommand:
+		return COMMAND_WRITE
+	case *deleteCommand:
+		return COMMAND_DELETE
+	case *touchCommand:
+		return COMMAND_TOUCH
+	case *existsCommand:
+		return COMMAND_EXISTS
+	case *operateCommand:
+		return COMMAND_OPERATE
+	case *executeCommand:
+		return COMMAND_UDF
+	case *batchCommandGet:
+		return COMMAND_BATCH_GET
+	case *batchCommandExists:
+		return COMMAND_BATCH_EXISTS
+	case *scanCommand:
+		return COMMAND_SCAN
+	case *queryRecordCommand:
+		return COMMAND_QUERY
+	case *serverCommand:
+		return COMMAND_QUERY_EXECUTE
+	default:
+		return COMMAND_UNKNOWN
+	}
+}
+
+// SlowCommand describes a command which took longer than the SlowLogThreshold of its policy.
+type SlowCommand struct {
+	// Node is the node the last attempt of the command was sent to.
+	Node *Node
+
+	// CommandType is the type of the command.
+	CommandType CommandType
+
+	// Digest is the digest of the command's key, or nil for commands on several keys.
+	Digest []byte
+
+	// Attempts is the number of times the command was sent to a node.
+	Attempts int
+
+	// Latency is the total duration of the command, retries included.
+	Latency time.Duration
+
+	// LastAttemptLatency is the duration of the last attempt from sending the command
+	// to parsing its result, or zero if nothing was sent.
+	LastAttemptLatency time.Duration
+
+	// Err is the error the command failed with, or nil.
+	Err error
+}
+
+// SlowCommandListener can be implemented by a MetricsListener to receive the commands
+// which took longer than the SlowLogThreshold of their policies.
+type SlowCommandListener interface {
+	// OnSlowCommand is called when a slow command completes.
+	OnSlowCommand(cmd *SlowCommand)
+}
+
+// reportSlowCommand logs the slow command and reports it to the metrics listener.
+func (nd *Node) reportSlowCommand(sc *SlowCommand) {
+	if listener, ok := nd.metricsListener().(SlowCommandListener); ok {
+		listener.OnSlowCommand(sc)
+	}
+
+	if nd.cluster == nil {
+		return
+	}
+	fields := map[string]interface{}{
+		"command":      string(sc.CommandType),
+		"attempts":     sc.Attempts,
+		"latency":      sc.Latency,
+		"last_attempt": sc.LastAttemptLatency,
+	}
+	if sc.Digest != nil {
+		fields["digest"] = hex.EncodeToString(sc.Digest)
+	}
+	if sc.Err != nil {
+		fields["error"] = sc.Err
+	}
+	nd.cluster.logEvent(WARNING, SUBSYSTEM_COMMAND, nd.GetName(), fields, "slow %s command took %s in %d attempt(s)", sc.CommandType, sc.Latency, sc.Attempts)
+}
+
+// commandDigest returns the digest of the key of a single record command, or nil.
+func commandDigest(ifc command) []byte {
+	if key := commandKeyOf(ifc); key != nil {
+		return key.Digest()
+	}
+	return nil
+}
+
+// commandKeyOf returns the key of a single record command, or nil.
+func commandKeyOf(ifc command) *Key {
+	if kc, ok := ifc.(interface {
+		commandKey() *Key
+	}); ok {
+		return kc.commandKey()
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+const (
+	migrationIDBin        = "id"
+	migrationAppliedBin   = "applied"
+	migrationOwnerBin     = "owner"
+	migrationLockKeyValue = "__migration_lock"
+)
+
+// MigrationStep is a single schema change applied as part of a Migration.
+// Steps should be idempotent, since a migration that fails halfway
+// will be run again from its first step.
+type MigrationStep func(clnt *Client, policy *WritePolicy) error
+
+// Migration is an ordered list of steps identified by a unique ID.
+// Once all steps succeed, the ID is recorded in the database and the
+// migration will not be applied again.
+type Migration struct {
+	// ID uniquely identifies the migration. It is used as the user key
+	// of the record marking the migration as applied.
+	ID string
+
+	// Steps are applied in order.
+	Steps []MigrationStep
+}
+
+// Migrator applies migrations in the order they were added and keeps
+// track of applied migrations in a set on the server.
+type Migrator struct {
+	client     *Client
+	namespace  string
+	setName    string
+	migrations []*Migration
+
+	// Policy is used for all commands sent by the migrator and its steps.
+	// If nil, the client's default write policy is used.
+	Policy *WritePolicy
+
+	// LockTimeout determines how long the migration lock is held before it
+	// expires on its own, in case a migrator dies while holding it.
+	LockTimeout time.Duration //= 10 minutes
+}
+
+// NewMigrator creates a migrator which records applied migrations
+// in the given namespace and set.
+func NewMigrator(client *Client, namespace string, setName string) *Migrator {
+	return &Migrator{
+		client:      client,
+		namespace:   namespace,
+		setName:     setName,
+		LockTimeout: 10 * time.Minute,
+	}
+}
+
+// Add appends a migration to the list of migrations.
+// Migrations are applied in the order they are added.
+func (mgr *Migrator) Add(id string, steps ...MigrationStep) *Migrator {
+	mgr.migrations = append(mgr.migrations, &Migration{ID: id, Steps: steps})
+	return mgr
+}
+
+// IsApplied returns true if the migration with the given ID has been applied.
+func (mgr *Migrator) IsApplied(id string) (bool, error) {
+	key, err := NewKey(mgr.namespace, mgr.setName, id)
+	if err != nil {
+		return false, err
+	}
+	return mgr.client.Exists(&mgr.policy().BasePolicy, key)
+}
+
+// Pending returns the IDs of the migrations which have not been applied yet.
+func (mgr *Migrator) Pending() ([]string, error) {
+	res := []string{}
+	for _, m := range mgr.migrations {
+		applied, err := mgr.IsApplied(m.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !applied {
+			res = append(res, m.ID)
+		}
+	}
+	return res, nil
+}
+
+// Run applies all pending migrations in order and returns the IDs of the
+// migrations applied by this call. Run stops at the first failing migration.
+// A lock record prevents concurrent migrators from running at the same time;
+// if the lock is held by another migrator, a KEY_EXISTS_ERROR is returned.
+func (mgr *Migrator) Run() ([]string, error) {
+	policy := mgr.policy()
+
+	lockKey, err := NewKey(mgr.namespace, mgr.setName, migrationLockKeyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	// the owner tells this lock apart from the lock of another migrator,
+	// taken after this one expired
+	owner := fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano())
+
+	lockPolicy := *policy
+	lockPolicy.RecordExistsAction = CREATE_ONLY
+	lockPolicy.Expiration = int32(mgr.LockTimeout / time.Second)
+	rec, err := mgr.client.Operate(&lockPolicy, lockKey,
+		PutOp(NewBin(migrationAppliedBin, time.Now().Unix())),
+		PutOp(NewBin(migrationOwnerBin, owner)),
+		GetHeaderOp(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer mgr.unlock(policy, lockKey, owner, rec.Generation)
+
+	applied := []string{}
+	for _, m := range mgr.migrations {
+		key, err := NewKey(mgr.namespace, mgr.setName, m.ID)
+		if err != nil {
+			return applied, err
+		}
+
+		exists, err := mgr.client.Exists(&policy.BasePolicy, key)
+		if err != nil {
+			return applied, err
+		}
+		if exists {
+			continue
+		}
+
+		for i, step := range m.Steps {
+			if err := step(mgr.client, policy); err != nil {
+				return applied, fmt.Errorf("Migration `%s` failed at step %d: %s", m.ID, i+1, err.Error())
+			}
+		}
+
+		recPolicy := *policy
+		recPolicy.Expiration = 0
+		if err := mgr.client.PutBins(&recPolicy, key,
+			NewBin(migrationIDBin, m.ID),
+			NewBin(migrationAppliedBin, time.Now().Unix()),
+		); err != nil {
+			return applied, err
+		}
+		applied = append(applied, m.ID)
+	}
+
+	return applied, nil
+}
+
+// unlock deletes the lock if it is still held by the owner with the generation
+// it was taken with. A lock which expired while the migrations were running
+// may have been taken by another migrator, and is left alone.
+func (mgr *Migrator) unlock(policy *WritePolicy, lockKey *Key, owner string, generation int) {
+	rec, err := mgr.client.Get(&policy.BasePolicy, lockKey, migrationOwnerBin)
+	if err != nil || rec == nil || rec.Bins[migrationOwnerBin] != owner {
+		return
+	}
+
+	deletePolicy := *policy
+	deletePolicy.GenerationPolicy = EXPECT_GEN_EQUAL
+	deletePolicy.Generation = int32(generation)
+	mgr.client.Delete(&deletePolicy, lockKey)
+}
+
+func (mgr *Migrator) policy() *WritePolicy {
+	return mgr.client.getUsableWritePolicy(mgr.Policy)
+}
+
+// CreateIndexStep returns a step which creates a secondary index and waits
+// until it is built. An already existing index is not considered an error.
+func CreateIndexStep(namespace string, setName string, indexName string, binName string, indexType IndexType) MigrationStep {
+	return func(clnt *Client, policy *WritePolicy) error {
+		task, err := clnt.CreateIndex(policy, namespace, setName, indexName, binName, indexType)
+		if err != nil {
+			if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == INDEX_FOUND {
+				return nil
+			}
+			return err
+		}
+		return <-task.OnComplete()
+	}
+}
+
+// DropIndexStep returns a step which drops a secondary index.
+func DropIndexStep(namespace string, setName string, indexName string) MigrationStep {
+	return func(clnt *Client, policy *WritePolicy) error {
+		return clnt.DropIndex(policy, namespace, setName, indexName)
+	}
+}
+
+// RegisterUDFStep returns a step which registers a UDF package and waits
+// until it is available on all nodes.
+func RegisterUDFStep(udfBody []byte, serverPath string, language Language) MigrationStep {
+	return func(clnt *Client, policy *WritePolicy) error {
+		task, err := clnt.RegisterUDF(policy, udfBody, serverPath, language)
+		if err != nil {
+			return err
+		}
+		return <-task.OnComplete()
+	}
+}
+
+// RemoveUDFStep returns a step which removes a UDF package and waits
+// until it is removed from all nodes.
+func RemoveUDFStep(udfName string) MigrationStep {
+	return func(clnt *Client, policy *WritePolicy) error {
+		task, err := clnt.RemoveUDF(policy, udfName)
+		if err != nil {
+			return err
+		}
+		return <-task.OnComplete()
+	}
+}
+
+// TruncateSetStep returns a step which deletes all records in a set.
+// Records are scanned and deleted one by one.
+func TruncateSetStep(namespace string, setName string) MigrationStep {
+	return func(clnt *Client, policy *WritePolicy) error {
+		scanPolicy := NewScanPolicy()
+		scanPolicy.IncludeBinData = false
+
+		recordset, err := clnt.ScanAll(scanPolicy, namespace, setName)
+		if err != nil {
+			return err
+		}
+		defer recordset.Close()
+
+		for res := range recordset.Results() {
+			if res.Err != nil {
+				return res.Err
+			}
+			if _, err := clnt.Delete(policy, res.Record.Key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// ALL tests are isolated by SetName and Key, which are 50 random charachters
+var _ = Describe("Migration Test", func() {
+	initTestVars()
+
+	// connection data
+	var client *Client
+	var err error
+	var ns = "test"
+	var set string
+	var dataSet string
+	var wpolicy = NewWritePolicy(0, 0)
+
+	BeforeEach(func() {
+		client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+
+		set = randString(50)
+		dataSet = randString(50)
+	})
+
+	It("must apply pending migrations only once", func() {
+		key, err := NewKey(ns, dataSet, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+		err = client.PutBins(wpolicy, key, NewBin("bin1", 1))
+		Expect(err).ToNot(HaveOccurred())
+
+		indexName := dataSet + "_idx"
+		migrator := NewMigrator(client, ns, set).
+			Add("001_create_index", CreateIndexStep(ns, dataSet, indexName, "bin1", NUMERIC)).
+			Add("002_register_udf", RegisterUDFStep([]byte(udfEcho), "migration_echo.lua", LUA)).
+			Add("003_truncate", TruncateSetStep(ns, dataSet))
+
+		pending, err := migrator.Pending()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pending).To(Equal([]string{"001_create_index", "002_register_udf", "003_truncate"}))
+
+		applied, err := migrator.Run()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied).To(Equal([]string{"001_create_index", "002_register_udf", "003_truncate"}))
+
+		exists, err := client.Exists(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+
+		applied, err = migrator.Add("004_drop_index", DropIndexStep(ns, dataSet, indexName)).Run()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied).To(Equal([]string{"004_drop_index"}))
+
+		pending, err = migrator.Pending()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pending).To(BeEmpty())
+	})
+
+	It("must stop at the first failing migration", func() {
+		migrator := NewMigrator(client, ns, set).
+			Add("001_fail", func(*Client, *WritePolicy) error { return NewAerospikeError(PARAMETER_ERROR) }).
+			Add("002_never_applied", DropIndexStep(ns, dataSet, "none"))
+
+		applied, err := migrator.Run()
+		Expect(err).To(HaveOccurred())
+		Expect(applied).To(BeEmpty())
+
+		pending, err := migrator.Pending()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pending).To(Equal([]string{"001_fail", "002_never_applied"}))
+	})
+
+	It("must not run concurrently with another migrator", func() {
+		lockKey, err := NewKey(ns, set, "__migration_lock")
+		Expect(err).ToNot(HaveOccurred())
+		err = client.PutBins(wpolicy, lockKey, NewBin("applied", 0))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = NewMigrator(client, ns, set).Add("001_noop").Run()
+		Expect(err).To(HaveOccurred())
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(KEY_EXISTS_ERROR))
+
+		_, err = client.Delete(wpolicy, lockKey)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("must not release the lock of another migrator", func() {
+		lockKey, err := NewKey(ns, set, "__migration_lock")
+		Expect(err).ToNot(HaveOccurred())
+
+		// the lock expired during the migration, and was taken by another migrator
+		takeOver := func(clnt *Client, policy *WritePolicy) error {
+			if _, err := clnt.Delete(policy, lockKey); err != nil {
+				return err
+			}
+			return clnt.PutBins(policy, lockKey, NewBin("applied", 0), NewBin("owner", "other"))
+		}
+
+		_, err = NewMigrator(client, ns, set).Add("001_take_over", takeOver).Run()
+		Expect(err).ToNot(HaveOccurred())
+
+		exists, err := client.Exists(nil, lockKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+
+		_, err = client.Delete(wpolicy, lockKey)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+})
@@ -34,6 +34,24 @@ type MultiPolicy struct {
 
 	// Blocks until on-going migrations are over
 	WaitUntilMigrationsAreOver bool //=false
+
+	// PartitionFilter determines the partitions to read, and tracks their progress.
+	// If set, the scan or query is sent to the master node of each partition,
+	// and can be resumed by passing the same filter again.
+	// Default (nil) reads all partitions of all nodes.
+	PartitionFilter *PartitionFilter
+
+	// MaxRecords limits the number of records returned by a partition scan or query.
+	// The limit is divided evenly between the nodes, so fewer records may be returned.
+	// Pass the same PartitionFilter again to receive the next page.
+	// Only used when PartitionFilter is set. Default (0) is no limit.
+	MaxRecords int64
+
+	// LazyBinDecoding keeps the raw bins of the returned records and decodes each bin
+	// only when it is accessed by Record.Bin, or all of them by Record.DecodeBins.
+	// Until then, Record.Bins only holds the decoded bins. It saves the decoding cost
+	// of the bins which are never accessed, e.g. when only one bin of many is used.
+	LazyBinDecoding bool //= false
 }
 
 // NewMultiPolicy initializes a MultiPolicy instance with default values.
@@ -15,6 +15,8 @@
 package aerospike
 
 import (
+	"context"
+	"net"
 	"strconv"
 	"strings"
 	"sync"
@@ -35,41 +37,131 @@ type Node struct {
 	cluster *Cluster
 	name    string
 	host    *Host
+
+	// guarded by mutex, since the address may be switched to an alias by
+	// any goroutine dialing the node; use GetAddress and setAddress
 	aliases []*Host
 	address string
 
-	connections     *AtomicQueue //ArrayBlockingQueue<*Connection>
+	connections *AtomicQueue //ArrayBlockingQueue<*Connection>
+	// dedicated connection of the cluster tend goroutine, kept out of the pool
+	// so that an exhausted pool can not block tending.
+	// Only accessed from the tend goroutine.
+	tendConnection  *Connection
 	connectionCount *AtomicInt
 	health          *AtomicInt //AtomicInteger
+	latency         *AtomicInt // smoothed info round trip time in nanoseconds
+
+	// size of the connection pool, and the earliest time in nanoseconds
+	// when the next stale connection may be replaced after a reconfiguration
+	connectionQueueSize *AtomicInt
+	nextDrain           *AtomicInt
+
+	// connections shared by single record commands if ClientPolicy.PipelinedConnections
+	// is set, opened on first use and used in turn. A slot being dialed has a channel,
+	// closed once the dial is over.
+	pipelines     []*pipelinedConnection
+	pipelineDials []chan struct{}
+	pipelineIndex int
+	pipelineMutex sync.Mutex
+
+	// limits the rate of commands sent to the node if ClientPolicy.MaxCommandsPerSecondPerNode is set
+	rateLimiter *rateLimiter
+
+	// total number of connections opened to and closed from the node
+	connectionsOpened *AtomicInt
+	connectionsClosed *AtomicInt
+
+	// age of pooled connections when they are reused, in seconds
+	connectionAges *histogram
+	// number of times connections were reused, recorded when they are closed
+	connectionReuses *histogram
+	// round trip time of commands sent to the node, in milliseconds
+	commandLatency *histogram
+	// round trip time of commands sent to the node by latency type, in nanoseconds
+	latencies map[LatencyType]*histogram
+
+	// total number of refreshes during cluster tend, and the failed ones
+	tendCount  *AtomicInt
+	tendErrors *AtomicInt
+
+	// number of consecutive failed refreshes, and the number of tends which skip
+	// the node before it is refreshed again if ClientPolicy.MaxRefreshBackoff is set.
+	// Only accessed from the tend goroutine.
+	refreshFailures int
+	skipRefreshes   int
+
+	// server build version, as reported when the node was added
+	build string
 
 	partitionGeneration int
 	refreshCount        int
 	referenceCount      int
 	responded           bool
 	useNewInfo          bool
+	supportsCompression bool
 	active              *AtomicBool
 	mutex               sync.RWMutex
+
+	// server version 4.7+ runs background scans and queries with operations
+	supportsBackgroundOps bool
+
+	// server version 6.0+ queries partitions instead of whole nodes
+	supportsPartitionQuery bool
 }
 
 // NewNode initializes a server node with connection parameters.
 func newNode(cluster *Cluster, nv *nodeValidator) *Node {
+	latencyBuckets := cluster.clientPolicy.LatencyBuckets
+	if len(latencyBuckets) == 0 {
+		latencyBuckets = defaultLatencyBuckets
+	}
+
+	var limiter *rateLimiter
+	if cluster.clientPolicy.MaxCommandsPerSecondPerNode > 0 {
+		limiter = newRateLimiter(cluster.clientPolicy.MaxCommandsPerSecondPerNode)
+	}
+
 	return &Node{
 		cluster:    cluster,
 		name:       nv.name,
 		aliases:    nv.aliases,
 		address:    nv.address,
 		useNewInfo: nv.useNewInfo,
+		build:      nv.build,
+
+		supportsCompression: nv.supportsCompression,
+		rateLimiter:         limiter,
+
+		supportsBackgroundOps:  nv.supportsBackgroundOps,
+		supportsPartitionQuery: nv.supportsPartitionQuery,
 
 		// Assign host to first IP alias because the server identifies nodes
 		// by IP address (not hostname).
 		host:                nv.aliases[0],
 		connections:         NewAtomicQueue(cluster.clientPolicy.ConnectionQueueSize),
 		connectionCount:     NewAtomicInt(0),
+		connectionQueueSize: NewAtomicInt(cluster.clientPolicy.ConnectionQueueSize),
+		nextDrain:           NewAtomicInt(0),
 		health:              NewAtomicInt(_FULL_HEALTH),
+		latency:             NewAtomicInt(int(nv.latency)),
+		connectionsOpened:   NewAtomicInt(0),
+		connectionsClosed:   NewAtomicInt(0),
+		connectionAges:      newHistogram(1, 10, 60, 300, 900, 3600),
+		connectionReuses:    newHistogram(0, 1, 10, 100, 1000, 10000),
+		commandLatency:      newHistogram(1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024),
+		tendCount:           NewAtomicInt(0),
+		tendErrors:          NewAtomicInt(0),
 		partitionGeneration: -1,
 		referenceCount:      0,
 		responded:           false,
 		active:              NewAtomicBool(true),
+		latencies: map[LatencyType]*histogram{
+			LATENCY_READ:  newLatencyHistogram(latencyBuckets),
+			LATENCY_WRITE: newLatencyHistogram(latencyBuckets),
+			LATENCY_BATCH: newLatencyHistogram(latencyBuckets),
+			LATENCY_QUERY: newLatencyHistogram(latencyBuckets),
+		},
 	}
 }
 
@@ -79,17 +171,20 @@ func (nd *Node) Refresh() ([]*Host, error) {
 
 	nd.refreshCount++
 
-	conn, err := nd.GetConnection(1 * time.Second)
+	conn, err := nd.getTendConnection(1 * time.Second)
 	if err != nil {
 		return nil, err
 	}
 
+	// only the round trip on the wire, without dialing a new tend connection
+	start := time.Now()
 	infoMap, err := RequestInfo(conn, "node", "partition-generation", "services")
 	if err != nil {
-		conn.Close()
+		nd.closeTendConnection()
 		nd.DecreaseHealth()
 		return nil, err
 	}
+	nd.updateLatency(time.Now().Sub(start))
 
 	if err := nd.verifyNodeName(infoMap); err != nil {
 		return nil, err
@@ -102,12 +197,52 @@ func (nd *Node) Refresh() ([]*Host, error) {
 	}
 
 	if err := nd.updatePartitions(conn, infoMap); err != nil {
+		// the partition map may not have been read completely
+		nd.closeTendConnection()
 		return nil, err
 	}
-	nd.PutConnection(conn)
 	return friends, nil
 }
 
+// getTendConnection returns the dedicated connection of the tend goroutine,
+// opening a new one if necessary.
+func (nd *Node) getTendConnection(timeout time.Duration) (*Connection, error) {
+	if conn := nd.tendConnection; conn != nil && conn.IsConnected() && !nd.isStale(conn) {
+		if err := conn.SetTimeout(timeout); err == nil {
+			return conn, nil
+		}
+		nd.closeTendConnection()
+	}
+
+	conn, err := nd.dial(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	conn.generation = nd.cluster.configGeneration.Get()
+
+	user, password := nd.cluster.getCredentials()
+	if err := conn.Authenticate(user, password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err := conn.SetTimeout(timeout); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	nd.tendConnection = conn
+	return conn, nil
+}
+
+// closeTendConnection closes the dedicated connection of the tend goroutine, if any.
+func (nd *Node) closeTendConnection() {
+	if nd.tendConnection != nil {
+		nd.tendConnection.Close()
+		nd.tendConnection = nil
+	}
+}
+
 func (nd *Node) verifyNodeName(infoMap map[string]string) error {
 	infoName, exists := infoMap["node"]
 
@@ -176,11 +311,13 @@ func (nd *Node) updatePartitions(conn *Connection, infoMap map[string]string) er
 	generation, _ := strconv.Atoi(genString)
 
 	if nd.partitionGeneration != generation {
-		Logger.Info("Node %s partition generation %d changed", nd.GetName(), generation)
+		nd.cluster.logEvent(INFO, SUBSYSTEM_NODE, nd.GetName(), nil, "Node %s partition generation %d changed", nd.GetName(), generation)
 		if err := nd.cluster.updatePartitions(conn, nd); err != nil {
 			return err
 		}
+		nd.mutex.Lock()
 		nd.partitionGeneration = generation
+		nd.mutex.Unlock()
 	}
 
 	return nil
@@ -189,14 +326,27 @@ func (nd *Node) updatePartitions(conn *Connection, infoMap map[string]string) er
 // GetConnection gets a connection to the node.
 // If no pooled connection is available, a new connection will be created.
 func (nd *Node) GetConnection(timeout time.Duration) (conn *Connection, err error) {
+	return nd.GetConnectionContext(context.Background(), timeout)
+}
+
+// GetConnectionContext gets a connection to the node like GetConnection.
+// If a new connection is being created when the context is done, its dial and
+// authentication are aborted and the context's error is returned.
+func (nd *Node) GetConnectionContext(ctx context.Context, timeout time.Duration) (conn *Connection, err error) {
 	tBegin := time.Now()
 	pollTries := 0
 L:
 	for timeout == 0 || time.Now().Sub(tBegin) <= timeout {
-		if t := nd.connections.Poll(); t != nil {
-			conn = t.(*Connection)
+		if t := nd.pollConnection(); t != nil {
+			conn = t
+			if nd.retireStale(conn) {
+				// replace the stale connection with a new one
+				continue
+			}
 			if conn.IsConnected() {
 				if err := conn.SetTimeout(timeout); err == nil {
+					conn.reuses++
+					nd.connectionAges.add(int64(time.Since(conn.created) / time.Second))
 					return conn, nil
 				}
 			}
@@ -204,7 +354,7 @@ L:
 		}
 
 		// if connection count is limited and enough connections are already created, don't create a new one
-		if nd.cluster.clientPolicy.LimitConnectionsToQueueSize && nd.connectionCount.Get() >= nd.cluster.clientPolicy.ConnectionQueueSize {
+		if nd.cluster.clientPolicy.LimitConnectionsToQueueSize && nd.connectionCount.Get() >= nd.connectionQueueSize.Get() {
 			// will avoid an infinite loop
 			if timeout != 0 || pollTries < 10 {
 				// 10 reteies, each waits for 100us for a total of 1 milliseconds
@@ -215,12 +365,19 @@ L:
 			break L
 		}
 
-		if conn, err = NewConnection(nd.address, nd.cluster.clientPolicy.Timeout); err != nil {
+		if err = ctx.Err(); err != nil {
 			return nil, err
 		}
 
+		if conn, err = nd.dial(ctx); err != nil {
+			return nil, err
+		}
+		conn.node = nd
+		conn.generation = nd.cluster.configGeneration.Get()
+
 		// need to authenticate
-		if conn.Authenticate(nd.cluster.user, nd.cluster.password); err != nil {
+		user, password := nd.cluster.getCredentials()
+		if err = conn.AuthenticateContext(ctx, user, password); err != nil {
 			// Socket not authenticated. Do not put back into pool.
 			conn.Close()
 
@@ -234,21 +391,262 @@ L:
 		}
 
 		nd.connectionCount.IncrementAndGet()
+		nd.connectionsOpened.IncrementAndGet()
+		if listener := nd.metricsListener(); listener != nil {
+			listener.OnConnectionOpened(nd)
+		}
 		return conn, nil
 	}
 	return nil, NewAerospikeError(NO_AVAILABLE_CONNECTIONS_TO_NODE)
 }
 
+// dial opens a new connection to the node. If ClientPolicy.DialAllAddresses is set
+// and the current address of the node fails, its aliases are tried in order.
+func (nd *Node) dial(ctx context.Context) (*Connection, error) {
+	timeout := nd.cluster.clientPolicy.Timeout
+	address := nd.GetAddress()
+
+	conn, err := NewConnectionContext(ctx, address, timeout)
+	if err == nil || ctx.Err() != nil || !nd.cluster.clientPolicy.DialAllAddresses {
+		return conn, err
+	}
+
+	for _, alias := range nd.GetAliases() {
+		aliasAddress := net.JoinHostPort(alias.Name, strconv.Itoa(alias.Port))
+		if aliasAddress == address {
+			continue
+		}
+
+		if conn, aliasErr := NewConnectionContext(ctx, aliasAddress, timeout); aliasErr == nil {
+			nd.cluster.logEvent(INFO, SUBSYSTEM_NODE, nd.name, map[string]interface{}{"address": aliasAddress, "error": err}, "Switched node address from %s to %s", address, aliasAddress)
+			nd.setAddress(aliasAddress)
+			return conn, nil
+		}
+	}
+
+	return nil, err
+}
+
 // PutConnection puts back a connection to the pool.
 // If connection pool is full, the connection will be
 // closed and discarded.
 func (nd *Node) PutConnection(conn *Connection) {
-	if !nd.active.Get() || !nd.connections.Offer(conn) {
+	if !nd.active.Get() || !nd.offerConnection(conn) {
 		nd.connectionCount.DecrementAndGet()
 		conn.Close()
 	}
 }
 
+// pollConnection takes a connection from the pool, or returns nil if the pool is empty.
+func (nd *Node) pollConnection() *Connection {
+	nd.mutex.RLock()
+	defer nd.mutex.RUnlock()
+
+	if conn := nd.connections.Poll(); conn != nil {
+		return conn.(*Connection)
+	}
+	return nil
+}
+
+// offerConnection puts the connection back in the pool unless the pool is full.
+func (nd *Node) offerConnection(conn *Connection) bool {
+	// the pool is not replaced while the connection is offered
+	nd.mutex.RLock()
+	defer nd.mutex.RUnlock()
+
+	return nd.connections.Offer(conn)
+}
+
+// isStale returns true if the connection was opened before the last reconfiguration.
+func (nd *Node) isStale(conn *Connection) bool {
+	return conn.generation < nd.cluster.configGeneration.Get()
+}
+
+// retireStale closes the connection if it is stale and the drain interval allows
+// replacing a connection, so that the connections are replaced gradually after
+// a reconfiguration. Returns true if the connection was closed.
+func (nd *Node) retireStale(conn *Connection) bool {
+	if !nd.isStale(conn) {
+		return false
+	}
+
+	now := int(time.Now().UnixNano())
+	next := nd.nextDrain.Get()
+	if now < next || !nd.nextDrain.CompareAndSet(next, now+int(nd.cluster.drainInterval.Get())) {
+		return false
+	}
+
+	nd.connectionCount.DecrementAndGet()
+	conn.Close()
+	return true
+}
+
+// resizeConnectionPool replaces the connection pool with one of the size.
+// The idle connections are moved to the new pool; those exceeding its size are closed.
+func (nd *Node) resizeConnectionPool(size int) {
+	if nd.connectionQueueSize.Get() == size {
+		return
+	}
+
+	nd.mutex.Lock()
+	old := nd.connections
+	nd.connections = NewAtomicQueue(size)
+	nd.connectionQueueSize.Set(size)
+	nd.mutex.Unlock()
+
+	for conn := old.Poll(); conn != nil; conn = old.Poll() {
+		nd.PutConnection(conn.(*Connection))
+	}
+}
+
+// NodeStats is a snapshot of the statistics of a node.
+type NodeStats struct {
+	// Name and Address identify the node.
+	Name    string `json:"name"`
+	Address string `json:"address"`
+
+	// Connections is the number of open connections to the node,
+	// both pooled and in use.
+	Connections int `json:"connections"`
+
+	// ConnectionsOpened and ConnectionsClosed are the total number of
+	// connections opened to and closed from the node.
+	ConnectionsOpened int `json:"connections_opened"`
+	ConnectionsClosed int `json:"connections_closed"`
+
+	// TendCount is the number of times the node was refreshed by the
+	// cluster tend goroutine, and TendErrors the number of failed refreshes.
+	TendCount  int `json:"tend_count"`
+	TendErrors int `json:"tend_errors"`
+
+	// PartitionGeneration is the generation of the partition map of the
+	// node, as last seen by the client.
+	PartitionGeneration int `json:"partition_generation"`
+
+	// Build is the server version of the node, as reported when it joined the cluster.
+	Build string `json:"build"`
+
+	// Features are the optional protocol features used with the node:
+	// "new-info" for the partition info protocol of servers 2.6.6+,
+	// "compression" and "background-ops".
+	Features []string `json:"features"`
+
+	// CommandLatency is the distribution of the round trip time of the
+	// commands sent to the node, in milliseconds.
+	CommandLatency Histogram `json:"command_latency"`
+
+	// ConnectionAges is the distribution of the age of pooled connections
+	// in seconds, recorded each time a connection is reused.
+	ConnectionAges Histogram `json:"connection_ages"`
+
+	// ConnectionReuses is the distribution of the number of times connections
+	// were reused, recorded when they are closed.
+	ConnectionReuses Histogram `json:"connection_reuses"`
+}
+
+// Stats returns a snapshot of the statistics of the node.
+func (nd *Node) Stats() NodeStats {
+	nd.mutex.RLock()
+	address := nd.address
+	partitionGeneration := nd.partitionGeneration
+	nd.mutex.RUnlock()
+
+	return NodeStats{
+		Name:                nd.name,
+		Address:             address,
+		Connections:         nd.connectionCount.Get(),
+		ConnectionsOpened:   nd.connectionsOpened.Get(),
+		ConnectionsClosed:   nd.connectionsClosed.Get(),
+		TendCount:           nd.tendCount.Get(),
+		TendErrors:          nd.tendErrors.Get(),
+		PartitionGeneration: partitionGeneration,
+		Build:               nd.build,
+		Features:            nd.features(),
+		CommandLatency:      nd.commandLatency.snapshot(),
+		ConnectionAges:      nd.connectionAges.snapshot(),
+		ConnectionReuses:    nd.connectionReuses.snapshot(),
+	}
+}
+
+// ResetStats returns a snapshot of the statistics of the node like Stats, and
+// resets its counters and distributions. Each counter is read and reset
+// atomically, so that no event is lost between two calls. Connections and
+// PartitionGeneration are current values and are not reset, nor are the
+// histograms returned by LatencyStats, which are not part of NodeStats.
+func (nd *Node) ResetStats() NodeStats {
+	nd.mutex.RLock()
+	address := nd.address
+	partitionGeneration := nd.partitionGeneration
+	nd.mutex.RUnlock()
+
+	return NodeStats{
+		Name:                nd.name,
+		Address:             address,
+		Connections:         nd.connectionCount.Get(),
+		ConnectionsOpened:   nd.connectionsOpened.GetAndSet(0),
+		ConnectionsClosed:   nd.connectionsClosed.GetAndSet(0),
+		TendCount:           nd.tendCount.GetAndSet(0),
+		TendErrors:          nd.tendErrors.GetAndSet(0),
+		PartitionGeneration: partitionGeneration,
+		Build:               nd.build,
+		Features:            nd.features(),
+		CommandLatency:      nd.commandLatency.reset(),
+		ConnectionAges:      nd.connectionAges.reset(),
+		ConnectionReuses:    nd.connectionReuses.reset(),
+	}
+}
+
+// features returns the optional protocol features used with the node.
+func (nd *Node) features() []string {
+	var res []string
+	if nd.useNewInfo {
+		res = append(res, "new-info")
+	}
+	if nd.supportsCompression {
+		res = append(res, "compression")
+	}
+	if nd.supportsBackgroundOps {
+		res = append(res, "background-ops")
+	}
+	if nd.supportsPartitionQuery {
+		res = append(res, "partition-query")
+	}
+	return res
+}
+
+// Diff returns the statistics of the node accumulated since the prev snapshot,
+// so that periodic reporters can compute rates. Counters and distributions are
+// subtracted; Connections and PartitionGeneration are the current values.
+func (ns NodeStats) Diff(prev NodeStats) NodeStats {
+	res := ns
+	res.ConnectionsOpened -= prev.ConnectionsOpened
+	res.ConnectionsClosed -= prev.ConnectionsClosed
+	res.TendCount -= prev.TendCount
+	res.TendErrors -= prev.TendErrors
+	res.CommandLatency = ns.CommandLatency.Diff(prev.CommandLatency)
+	res.ConnectionAges = ns.ConnectionAges.Diff(prev.ConnectionAges)
+	res.ConnectionReuses = ns.ConnectionReuses.Diff(prev.ConnectionReuses)
+	return res
+}
+
+// LatencyStats returns a snapshot of the latency histograms of the commands
+// sent to the node, by latency type. The buckets are determined by
+// ClientPolicy.LatencyBuckets. Compare them across nodes to detect a slow node.
+func (nd *Node) LatencyStats() map[LatencyType]LatencyHistogram {
+	res := make(map[LatencyType]LatencyHistogram, len(nd.latencies))
+	for lt, h := range nd.latencies {
+		res[lt] = h.latencySnapshot()
+	}
+	return res
+}
+
+// addLatency records the latency of a command of the type.
+func (nd *Node) addLatency(commandType CommandType, latency time.Duration) {
+	if h := nd.latencies[commandType.latencyType()]; h != nil {
+		h.add(int64(latency))
+	}
+}
+
 // RestoreHealth marks the node as healthy.
 func (nd *Node) RestoreHealth() {
 	// There can be cases where health is full, but active is false.
@@ -261,6 +659,20 @@ func (nd *Node) DecreaseHealth() {
 	nd.health.DecrementAndGet()
 }
 
+// GetLatency returns the smoothed round trip time of info requests to the node.
+// It is measured on node discovery and re-evaluated on every cluster tend.
+func (nd *Node) GetLatency() time.Duration {
+	return time.Duration(nd.latency.Get())
+}
+
+// updateLatency adds a sample to the exponentially weighted moving average of the node latency.
+func (nd *Node) updateLatency(sample time.Duration) {
+	if old := nd.latency.Get(); old > 0 {
+		sample = (time.Duration(old)*3 + sample) / 4
+	}
+	nd.latency.Set(int(sample))
+}
+
 // IsUnhealthy checks if the node is unhealthy.
 func (nd *Node) IsUnhealthy() bool {
 	return nd.health.Get() <= 0
@@ -290,6 +702,22 @@ func (nd *Node) GetAliases() []*Host {
 	return aliases
 }
 
+// GetAddress returns the address used to open connections to the node.
+func (nd *Node) GetAddress() string {
+	nd.mutex.RLock()
+	address := nd.address
+	nd.mutex.RUnlock()
+
+	return address
+}
+
+// Sets node address
+func (nd *Node) setAddress(address string) {
+	nd.mutex.Lock()
+	nd.address = address
+	nd.mutex.Unlock()
+}
+
 // Sets node aliases
 func (nd *Node) setAliases(aliases []*Host) {
 	nd.mutex.Lock()
@@ -314,6 +742,8 @@ func (nd *Node) AddAlias(aliasToAdd *Host) {
 func (nd *Node) Close() {
 	nd.active.Set(false)
 	nd.closeConnections()
+	nd.closePipelines()
+	nd.closeTendConnection()
 }
 
 // String implements stringer interface
@@ -322,8 +752,8 @@ func (nd *Node) String() string {
 }
 
 func (nd *Node) closeConnections() {
-	for conn := nd.connections.Poll(); conn != nil; conn = nd.connections.Poll() {
-		conn.(*Connection).Close()
+	for conn := nd.pollConnection(); conn != nil; conn = nd.pollConnection() {
+		conn.Close()
 	}
 }
 
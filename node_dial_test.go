@@ -0,0 +1,204 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Node Dial Test", func() {
+
+	var listener net.Listener
+	var node *Node
+
+	BeforeEach(func() {
+		var err error
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+
+		// a port nobody listens to
+		closed, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		closedAddress := closed.Addr().String()
+		closed.Close()
+
+		_, port, _ := net.SplitHostPort(listener.Addr().String())
+		p, _ := strconv.Atoi(port)
+
+		node = &Node{
+			cluster: &Cluster{
+				clientPolicy:     *NewClientPolicy(),
+				configGeneration: NewAtomicInt(0),
+				drainInterval:    NewAtomicInt(0),
+			},
+			address:             closedAddress,
+			aliases:             []*Host{NewHost("127.0.0.1", p)},
+			active:              NewAtomicBool(true),
+			connections:         NewAtomicQueue(4),
+			connectionCount:     NewAtomicInt(0),
+			connectionQueueSize: NewAtomicInt(4),
+			nextDrain:           NewAtomicInt(0),
+		}
+		node.cluster.clientPolicy.Timeout = time.Second
+	})
+
+	AfterEach(func() {
+		listener.Close()
+	})
+
+	It("should fail when the node address cannot be dialed", func() {
+		_, err := node.dial(context.Background())
+		Expect(err).To(HaveOccurred())
+		Expect(node.GetAddress()).ToNot(Equal(listener.Addr().String()))
+	})
+
+	It("should dial the aliases and remember the working address", func() {
+		node.cluster.clientPolicy.DialAllAddresses = true
+
+		conn, err := node.dial(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		defer conn.Close()
+
+		Expect(node.GetAddress()).To(Equal(listener.Addr().String()))
+	})
+
+	It("should switch the address while other goroutines read it", func() {
+		node.cluster.clientPolicy.DialAllAddresses = true
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if conn, err := node.dial(context.Background()); err == nil {
+					conn.Close()
+				}
+			}()
+		}
+		for i := 0; i < 100; i++ {
+			node.GetAddress()
+		}
+		wg.Wait()
+
+		Expect(node.GetAddress()).To(Equal(listener.Addr().String()))
+	})
+
+	It("should not open new connections once the context is done", func() {
+		node.setAddress(listener.Addr().String())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := node.GetConnectionContext(ctx, time.Second)
+		Expect(err).To(Equal(context.Canceled))
+		Expect(node.connectionCount.Get()).To(Equal(0))
+	})
+
+	It("should abort the authentication when the context is done", func() {
+		node.setAddress(listener.Addr().String())
+		node.cluster.user = "user"
+		node.cluster.password = []byte("password")
+		node.cluster.clientPolicy.Timeout = 10 * time.Second
+		node.connectionsClosed = NewAtomicInt(0)
+		node.connectionReuses = newHistogram(0, 1)
+
+		// accept the connection but never respond to the authentication
+		go func() {
+			if conn, err := listener.Accept(); err == nil {
+				defer conn.Close()
+				time.Sleep(5 * time.Second)
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := node.GetConnectionContext(ctx, 10*time.Second)
+		Expect(err).To(Equal(context.DeadlineExceeded))
+		Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+		Expect(node.connectionCount.Get()).To(Equal(0))
+		Expect(node.connectionsClosed.Get()).To(Equal(1))
+	})
+
+	It("should reuse the dedicated tend connection until it is closed", func() {
+		node.cluster.clientPolicy.DialAllAddresses = true
+
+		conn, err := node.getTendConnection(time.Second)
+		Expect(err).ToNot(HaveOccurred())
+
+		again, err := node.getTendConnection(time.Second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(again == conn).To(BeTrue())
+
+		node.closeTendConnection()
+		Expect(conn.IsConnected()).To(BeFalse())
+
+		again, err = node.getTendConnection(time.Second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(again == conn).To(BeFalse())
+		node.closeTendConnection()
+	})
+
+	It("should replace the tend connection after a reconfiguration", func() {
+		node.cluster.clientPolicy.DialAllAddresses = true
+
+		conn, err := node.getTendConnection(time.Second)
+		Expect(err).ToNot(HaveOccurred())
+
+		node.cluster.configGeneration.IncrementAndGet()
+
+		again, err := node.getTendConnection(time.Second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(again == conn).To(BeFalse())
+		node.closeTendConnection()
+	})
+
+	It("should retire one stale connection per drain interval", func() {
+		node.cluster.drainInterval.Set(int(time.Hour))
+		stale := []*Connection{{}, {}}
+		node.connectionCount.Set(len(stale))
+
+		node.cluster.configGeneration.IncrementAndGet()
+
+		Expect(node.retireStale(stale[0])).To(BeTrue())
+		Expect(node.retireStale(stale[1])).To(BeFalse())
+		Expect(node.retireStale(&Connection{generation: 1})).To(BeFalse())
+		Expect(node.connectionCount.Get()).To(Equal(1))
+	})
+
+	It("should keep the idle connections which fit in the resized pool", func() {
+		for i := 0; i < 3; i++ {
+			node.PutConnection(&Connection{})
+		}
+		node.connectionCount.Set(3)
+
+		node.resizeConnectionPool(2)
+		Expect(node.connectionCount.Get()).To(Equal(2))
+		Expect(node.pollConnection()).ToNot(BeNil())
+		Expect(node.pollConnection()).ToNot(BeNil())
+		Expect(node.pollConnection()).To(BeNil())
+	})
+
+})
@@ -15,6 +15,7 @@
 package aerospike_test
 
 import (
+	"sync"
 	"time"
 
 	. "github.com/aerospike/aerospike-client-go"
@@ -23,6 +24,41 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+type countingMetricsListener struct {
+	mutex          sync.Mutex
+	commands       map[CommandType]int
+	opened, closed int
+}
+
+func (l *countingMetricsListener) OnCommand(node *Node, commandType CommandType, latency time.Duration, err error) {
+	l.mutex.Lock()
+	l.commands[commandType]++
+	l.mutex.Unlock()
+}
+
+func (l *countingMetricsListener) OnConnectionOpened(node *Node) {
+	l.mutex.Lock()
+	l.opened++
+	l.mutex.Unlock()
+}
+
+func (l *countingMetricsListener) OnConnectionClosed(node *Node) {
+	l.mutex.Lock()
+	l.closed++
+	l.mutex.Unlock()
+}
+
+type slowCommandListener struct {
+	countingMetricsListener
+	slow []*SlowCommand
+}
+
+func (l *slowCommandListener) OnSlowCommand(cmd *SlowCommand) {
+	l.mutex.Lock()
+	l.slow = append(l.slow, cmd)
+	l.mutex.Unlock()
+}
+
 // ALL tests are isolated by SetName and Key, which are 50 random charachters
 var _ = Describe("Aerospike", func() {
 	initTestVars()
@@ -98,4 +134,121 @@ var _ = Describe("Aerospike", func() {
 
 		})
 	})
+
+	Describe("Node Latency", func() {
+
+		It("must measure and re-evaluate node latency", func() {
+			clientPolicy := NewClientPolicy()
+			clientPolicy.LatencyAwareSeeds = true
+			clientPolicy.PreferClosestNode = true
+			clientPolicy.TendInterval = 10 * time.Millisecond
+
+			client, err := NewClientWithPolicy(clientPolicy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			// wait for a few tends to re-evaluate latencies
+			time.Sleep(100 * time.Millisecond)
+
+			for _, node := range client.GetNodes() {
+				Expect(node.GetLatency()).To(BeNumerically(">", 0))
+			}
+
+			// commands not bound to a partition go to the closest node
+			_, err = client.ListUDF(nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("must track command latencies by type in the configured buckets", func() {
+			clientPolicy := NewClientPolicy()
+			clientPolicy.LatencyBuckets = []time.Duration{time.Millisecond, 10 * time.Millisecond}
+
+			client, err := NewClientWithPolicy(clientPolicy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+			err = client.PutBins(nil, key, NewBin("bin", 1))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = client.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+
+			counts := map[LatencyType]int64{}
+			for _, node := range client.GetNodes() {
+				for lt, h := range node.LatencyStats() {
+					Expect(h.Bounds).To(Equal(clientPolicy.LatencyBuckets))
+					for _, c := range h.Counts {
+						counts[lt] += c
+					}
+				}
+			}
+			Expect(counts).To(Equal(map[LatencyType]int64{LATENCY_READ: 1, LATENCY_WRITE: 1, LATENCY_BATCH: 0, LATENCY_QUERY: 0}))
+		})
+
+	})
+
+	Describe("Node Metrics", func() {
+
+		It("must report commands and connections to the metrics listener", func() {
+			listener := &countingMetricsListener{commands: map[CommandType]int{}}
+
+			clientPolicy := NewClientPolicy()
+			clientPolicy.MetricsPolicy = NewMetricsPolicy(listener)
+
+			client, err := NewClientWithPolicy(clientPolicy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.PutBins(nil, key, NewBin("bin", 1))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = client.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+
+			client.Close()
+
+			listener.mutex.Lock()
+			defer listener.mutex.Unlock()
+
+			Expect(listener.commands).To(Equal(map[CommandType]int{COMMAND_WRITE: 1, COMMAND_READ: 1}))
+			Expect(listener.opened).To(BeNumerically(">", 0))
+			Expect(listener.closed).To(Equal(listener.opened))
+		})
+
+		It("must report the commands slower than the policy's threshold", func() {
+			listener := &slowCommandListener{countingMetricsListener: countingMetricsListener{commands: map[CommandType]int{}}}
+
+			clientPolicy := NewClientPolicy()
+			clientPolicy.MetricsPolicy = NewMetricsPolicy(listener)
+
+			client, err := NewClientWithPolicy(clientPolicy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.PutBins(nil, key, NewBin("bin", 1))
+			Expect(err).ToNot(HaveOccurred())
+
+			policy := NewWritePolicy(0, 0)
+			policy.SlowLogThreshold = time.Nanosecond
+			err = client.PutBins(policy, key, NewBin("bin", 2))
+			Expect(err).ToNot(HaveOccurred())
+
+			listener.mutex.Lock()
+			defer listener.mutex.Unlock()
+
+			Expect(len(listener.slow)).To(Equal(1))
+			slow := listener.slow[0]
+			Expect(slow.CommandType).To(Equal(COMMAND_WRITE))
+			Expect(slow.Digest).To(Equal(key.Digest()))
+			Expect(slow.Attempts).To(Equal(1))
+			Expect(slow.Latency).To(BeNumerically(">=", slow.LastAttemptLatency))
+			Expect(slow.Err).ToNot(HaveOccurred())
+		})
+
+	})
 })
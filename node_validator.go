@@ -18,6 +18,7 @@ import (
 	"net"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	. "github.com/aerospike/aerospike-client-go/logger"
@@ -31,6 +32,21 @@ type nodeValidator struct {
 	address    string
 	useNewInfo bool //= true
 	cluster    *Cluster
+
+	// server build version
+	build string
+
+	// set if the node advertises the compression feature
+	supportsCompression bool
+
+	// server version 4.7+ runs background scans and queries with operations
+	supportsBackgroundOps bool
+
+	// set if the node advertises the partition query feature (server 6.0+)
+	supportsPartitionQuery bool
+
+	// connect and info round trip time
+	latency time.Duration
 }
 
 // Generates a node validator
@@ -52,33 +68,29 @@ func newNodeValidator(cluster *Cluster, host *Host, timeout time.Duration) (*nod
 }
 
 func (ndv *nodeValidator) setAliases(host *Host) error {
-	// IP addresses do not need a lookup
-	ip := net.ParseIP(host.Name)
-	if ip != nil {
-		aliases := make([]*Host, 1)
-		aliases[0] = NewHost(host.Name, host.Port)
-		ndv.aliases = aliases
-	} else {
-		addresses, err := net.LookupHost(host.Name)
-		if err != nil {
-			Logger.Error("HostLookup failed with error: ", err)
-			return err
-		}
-		aliases := make([]*Host, len(addresses))
-		for idx, addr := range addresses {
-			aliases[idx] = NewHost(addr, host.Port)
-		}
-		ndv.aliases = aliases
+	aliases, err := resolveHost(ndv.cluster.clientPolicy.HostResolver, host)
+	if err != nil {
+		ndv.cluster.logEvent(ERR, SUBSYSTEM_NODE, "", map[string]interface{}{"address": host.String(), "error": err}, "HostLookup failed with error: %s", err)
+		return err
+	}
+	if len(aliases) == 0 {
+		return NewAerospikeError(INVALID_NODE_ERROR, "No addresses resolved for host "+host.String())
 	}
-	Logger.Debug("Node Validator has %d nodes.", len(ndv.aliases))
+	ndv.aliases = aliases
+	ndv.cluster.logEvent(DEBUG, SUBSYSTEM_NODE, "", nil, "Node Validator has %d nodes.", len(ndv.aliases))
 	return nil
 }
 
 func (ndv *nodeValidator) setAddress(timeout time.Duration) error {
-	for _, alias := range ndv.aliases {
+	for i, alias := range ndv.aliases {
 		address := net.JoinHostPort(alias.Name, strconv.Itoa(alias.Port))
 		conn, err := NewConnection(address, time.Second)
 		if err != nil {
+			// try the next address of the host if allowed
+			if ndv.cluster.clientPolicy.DialAllAddresses && (i < len(ndv.aliases)-1 || ndv.address != "") {
+				ndv.cluster.logEvent(WARNING, SUBSYSTEM_NODE, "", map[string]interface{}{"address": address, "error": err}, "Dial %s failed: %s", address, err)
+				continue
+			}
 			return err
 		}
 
@@ -86,7 +98,8 @@ func (ndv *nodeValidator) setAddress(timeout time.Duration) error {
 
 		// need to authenticate
 		// need to authenticate
-		if conn.Authenticate(ndv.cluster.user, ndv.cluster.password); err != nil {
+		user, password := ndv.cluster.getCredentials()
+		if conn.Authenticate(user, password); err != nil {
 			// Socket not authenticated. Do not put back into pool.
 			conn.Close()
 
@@ -97,22 +110,40 @@ func (ndv *nodeValidator) setAddress(timeout time.Duration) error {
 			return err
 		}
 
-		infoMap, err := RequestInfo(conn, "node", "build")
+		// only the round trip on the wire, like Node.Refresh
+		start := time.Now()
+		infoMap, err := RequestInfo(conn, "node", "build", "features")
 		if err != nil {
 			return err
 		}
+		latency := time.Now().Sub(start)
+
 		if nodeName, exists := infoMap["node"]; exists {
 			ndv.name = nodeName
 			ndv.address = address
+			ndv.latency = latency
 
 			// Check new info protocol support for >= 2.6.6 build
 			if buildVersion, exists := infoMap["build"]; exists {
+				ndv.build = buildVersion
 				v1, v2, v3, err := parseVersionString(buildVersion)
 				if err != nil {
-					Logger.Error(err.Error())
+					ndv.cluster.logEvent(ERR, SUBSYSTEM_NODE, "", map[string]interface{}{"address": address}, "%s", err)
 					return err
 				}
 				ndv.useNewInfo = v1 > 2 || (v1 == 2 && (v2 > 6 || (v2 == 6 && v3 >= 6)))
+				ndv.supportsBackgroundOps = v1 > 4 || (v1 == 4 && v2 >= 7)
+			}
+
+			ndv.supportsCompression = false
+			ndv.supportsPartitionQuery = false
+			for _, feature := range strings.Split(infoMap["features"], ";") {
+				switch feature {
+				case "compression":
+					ndv.supportsCompression = true
+				case "pquery":
+					ndv.supportsPartitionQuery = true
+				}
 			}
 		}
 	}
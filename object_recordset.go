@@ -0,0 +1,114 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"reflect"
+	"sync"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// ObjectRecordset is the result of ScanAllObjects and QueryObjects.
+// The records are decoded into new structs and sent on the object channel passed
+// to them, which is closed once all records have been sent, the scan or query was
+// aborted by an error, or the recordset was closed.
+type ObjectRecordset struct {
+	recordset *Recordset
+
+	// closed by Close; the recordset is also closed when all records were received
+	cancelled  chan struct{}
+	cancelOnce sync.Once
+
+	// closed after the object channel, once err is set
+	done chan struct{}
+	err  error
+}
+
+// objectChanOf validates that objChan is a channel of pointers to structs
+// which records can be sent on.
+func objectChanOf(objChan interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(objChan)
+	if rv.Kind() != reflect.Chan || rv.IsNil() || rv.Type().ChanDir()&reflect.SendDir == 0 {
+		return rv, NewAerospikeError(PARAMETER_ERROR, "Object channel must be a non-nil channel of pointers to structs.")
+	}
+
+	elemType := rv.Type().Elem()
+	if elemType.Kind() != reflect.Ptr || elemType.Elem().Kind() != reflect.Struct {
+		return rv, NewAerospikeError(PARAMETER_ERROR, "Object channel must be a non-nil channel of pointers to structs.")
+	}
+	return rv, nil
+}
+
+// newObjectRecordset decodes the records of the recordset into the object channel.
+func newObjectRecordset(recordset *Recordset, objChan reflect.Value) *ObjectRecordset {
+	ors := &ObjectRecordset{
+		recordset: recordset,
+		cancelled: make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go func() {
+		defer close(ors.done)
+		defer objChan.Close()
+
+		structType := objChan.Type().Elem().Elem()
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectSend, Chan: objChan},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ors.cancelled)},
+		}
+
+		for res := range recordset.Results() {
+			if ors.err != nil {
+				// aborted; discard the rest
+				continue
+			}
+
+			err := res.Err
+			if err == nil {
+				obj := reflect.New(structType)
+				if err = UnmarshalBins(res.Record.Bins, obj.Interface()); err == nil {
+					// the consumer may stop receiving once it closed the recordset
+					cases[0].Send = obj
+					reflect.Select(cases)
+					continue
+				}
+			}
+
+			ors.err = err
+			// closing waits for the node goroutines; keep draining the results meanwhile
+			go recordset.Close()
+		}
+	}()
+
+	return ors
+}
+
+// Close cancels the scan or query. The object channel is closed shortly after;
+// records not received yet are discarded.
+func (ors *ObjectRecordset) Close() {
+	ors.cancelOnce.Do(func() {
+		close(ors.cancelled)
+	})
+	ors.recordset.Close()
+}
+
+// Err waits until the object channel is closed and returns the error which
+// aborted the scan or query, returned either by a node or by decoding a record.
+// It returns nil if all records were sent, or the recordset was closed.
+func (ors *ObjectRecordset) Err() error {
+	<-ors.done
+	return ors.err
+}
@@ -0,0 +1,98 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"errors"
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type objectRecordsetTest struct {
+	Name  string `as:"name"`
+	Count int    `as:"count"`
+}
+
+var _ = Describe("Object Recordset Test", func() {
+
+	It("should validate the object channel", func() {
+		_, err := objectChanOf(make(chan *objectRecordsetTest))
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, objChan := range []interface{}{
+			nil,
+			(chan *objectRecordsetTest)(nil),
+			make(<-chan *objectRecordsetTest),
+			make(chan objectRecordsetTest),
+			make(chan *int),
+			[]*objectRecordsetTest{},
+		} {
+			_, err := objectChanOf(objChan)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
+	It("should decode the records and close the channel", func() {
+		rs := newRecordset(10, 1)
+		objChan := make(chan *objectRecordsetTest, 10)
+		ors := newObjectRecordset(rs, reflect.ValueOf(objChan))
+
+		rs.Records <- &Record{Bins: BinMap{"name": "a", "count": 1}}
+		rs.Records <- &Record{Bins: BinMap{"name": "b", "count": 2}}
+		rs.signalEnd()
+
+		res := []objectRecordsetTest{}
+		for obj := range objChan {
+			res = append(res, *obj)
+		}
+		Expect(res).To(Equal([]objectRecordsetTest{{"a", 1}, {"b", 2}}))
+		Expect(ors.Err()).ToNot(HaveOccurred())
+	})
+
+	It("should abort on the first error", func() {
+		rs := newRecordset(10, 1)
+		objChan := make(chan *objectRecordsetTest, 10)
+		ors := newObjectRecordset(rs, reflect.ValueOf(objChan))
+
+		rs.Errors <- errors.New("node failed")
+		rs.Records <- &Record{Bins: BinMap{"name": "a"}}
+		rs.signalEnd()
+
+		for range objChan {
+		}
+
+		Expect(ors.Err()).To(MatchError("node failed"))
+		Expect(rs.IsActive()).To(BeFalse())
+	})
+
+	It("should close the channel when the recordset is closed", func() {
+		rs := newRecordset(10, 1)
+		objChan := make(chan *objectRecordsetTest)
+		ors := newObjectRecordset(rs, reflect.ValueOf(objChan))
+
+		rs.Records <- &Record{Bins: BinMap{"name": "a"}}
+		go func() {
+			rs.signalEnd()
+		}()
+		ors.Close()
+
+		for range objChan {
+		}
+		Expect(ors.Err()).ToNot(HaveOccurred())
+	})
+
+})
@@ -19,20 +19,100 @@ type operateCommand struct {
 
 	policy     *WritePolicy
 	operations []*Operation
+
+	// if true, the server returns a result for every operation
+	respondAllOps bool
+}
+
+// hasWriteOperation returns true if any of the operations modify the record.
+func hasWriteOperation(operations []*Operation) bool {
+	for _, op := range operations {
+		switch op.OpType {
+		case READ, CDT_READ, BIT_READ, HLL_READ:
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// boolOpsAsIntegers returns a copy of operations with boolean write values converted to integers.
+// The original slice is returned if it doesn't contain boolean write values.
+func boolOpsAsIntegers(operations []*Operation) []*Operation {
+	var res []*Operation
+	for i, op := range operations {
+		if bv, ok := op.BinValue.(BoolValue); ok && op.OpType == WRITE {
+			if res == nil {
+				res = make([]*Operation, len(operations))
+				copy(res, operations)
+			}
+			newOp := *op
+			newOp.BinValue = bv.asInteger()
+			res[i] = &newOp
+		}
+	}
+
+	if res == nil {
+		return operations
+	}
+	return res
 }
 
-func newOperateCommand(cluster *Cluster, policy *WritePolicy, key *Key, operations []*Operation) *operateCommand {
+// binResults is used internally to collect multiple results for the same bin.
+type binResults []interface{}
+
+func newOperateCommand(cluster *Cluster, policy *WritePolicy, key *Key, operations []*Operation) (*operateCommand, error) {
+	readCommand := newReadCommand(cluster, policy, key, nil)
+	readCommand.isOperation = true
+
+	if policy.BoolAsInteger {
+		operations = boolOpsAsIntegers(operations)
+	}
+
+	if hasWriteOperation(operations) {
+		bin, err := keyHashBin(cluster, policy, key)
+		if err != nil {
+			return nil, err
+		}
+		if bin != nil {
+			operations = append(operations[:len(operations):len(operations)], PutOp(bin))
+		}
+	}
+
 	return &operateCommand{
-		readCommand: newReadCommand(cluster, policy, key, nil),
+		readCommand: readCommand,
 		policy:      policy,
 		operations:  operations,
-	}
+	}, nil
 }
 
 func (cmd *operateCommand) writeBuffer(ifc command) error {
-	return cmd.setOperate(cmd.policy, cmd.key, cmd.operations)
+	return cmd.setOperate(cmd.policy, cmd.key, cmd.operations, cmd.respondAllOps)
+}
+
+// operationResults matches the results returned by the server to the
+// requested operations. Requires respondAllOps to be set.
+func (cmd *operateCommand) operationResults(operations []*Operation) OpResults {
+	res := make(OpResults, len(operations))
+	for i, op := range operations {
+		res[i] = &OperationResult{Operation: op}
+		if i < len(cmd.opValues) {
+			res[i].Value = cmd.opValues[i]
+		}
+	}
+	return res
+}
+
+func (cmd *operateCommand) getNode(ifc command) (*Node, error) {
+	if hasWriteOperation(cmd.operations) {
+		return cmd.cluster.GetNode(cmd.partition)
+	}
+	return cmd.readCommand.getNode(ifc)
 }
 
 func (cmd *operateCommand) Execute() error {
+	if hasWriteOperation(cmd.operations) {
+		cmd.policy.ReadYourWrites.Track(cmd.key)
+	}
 	return cmd.execute(cmd)
 }
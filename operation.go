@@ -20,11 +20,18 @@ type OperationType byte
 const (
 	READ OperationType = 1
 	// READ_HEADER OperationType = 1
-	WRITE   OperationType = 2
-	ADD     OperationType = 5
-	APPEND  OperationType = 9
-	PREPEND OperationType = 10
-	TOUCH   OperationType = 11
+	WRITE      OperationType = 2
+	CDT_READ   OperationType = 3
+	CDT_MODIFY OperationType = 4
+	ADD        OperationType = 5
+	APPEND     OperationType = 9
+	PREPEND    OperationType = 10
+	TOUCH      OperationType = 11
+	BIT_READ   OperationType = 12
+	BIT_MODIFY OperationType = 13
+	DELETE     OperationType = 14
+	HLL_READ   OperationType = 15
+	HLL_MODIFY OperationType = 16
 )
 
 // Operation contasins operation definition.
@@ -42,6 +49,47 @@ type Operation struct {
 
 	// will be true ONLY for GetHeader() operation
 	headerOnly bool
+
+	// will be true for operations which require the server
+	// to return a result for each operation in the command
+	respondAllOps bool
+}
+
+// OperationResult contains the value returned by the server for a single
+// operation of an OperateWithResults command. It has no result code, since
+// the server only returns one for the whole command.
+type OperationResult struct {
+	// Operation is the operation that produced the result.
+	Operation *Operation
+
+	// Value is the value returned by the server for the operation.
+	Value interface{}
+}
+
+// OpResults are the results of the operations of an OperateWithResults command,
+// in the order of the operations.
+type OpResults []*OperationResult
+
+// Values returns the values of the results in the order of the operations.
+func (or OpResults) Values() []interface{} {
+	res := make([]interface{}, len(or))
+	for i, r := range or {
+		res[i] = r.Value
+	}
+	return res
+}
+
+// ForBin returns the values of the results of the operations on the bin,
+// in the order of the operations. Each operation has its own value, even
+// if several operations read or modify the same bin.
+func (or OpResults) ForBin(binName string) []interface{} {
+	var res []interface{}
+	for _, r := range or {
+		if r.Operation.BinName == binName {
+			res = append(res, r.Value)
+		}
+	}
+	return res
 }
 
 // GetOpForBin creates read bin database operation.
@@ -83,3 +131,8 @@ func AddOp(bin *Bin) *Operation {
 func TouchOp() *Operation {
 	return &Operation{OpType: TOUCH, BinValue: NewNullValue()}
 }
+
+// DeleteOp creates delete record database operation.
+func DeleteOp() *Operation {
+	return &Operation{OpType: DELETE, BinValue: NewNullValue()}
+}
@@ -19,8 +19,10 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"sort"
 	"time"
 
+	. "github.com/aerospike/aerospike-client-go/types"
 	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
 	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
 )
@@ -54,6 +56,32 @@ func packAnyMap(val map[interface{}]interface{}) ([]byte, error) {
 	return packer.buffer.Bytes(), nil
 }
 
+// MarshalMsgPack returns the value packed in the MessagePack format the server
+// uses for list and map bins, e.g. to store those bins in files.
+func MarshalMsgPack(value interface{}) ([]byte, error) {
+	packer := newPacker()
+	if err := packer.PackObject(value); err != nil {
+		return nil, err
+	}
+	return packer.buffer.Bytes(), nil
+}
+
+// UnmarshalMsgPack decodes a value packed by MarshalMsgPack, or a list or map bin
+// as sent by the server. Lists are returned as []interface{} and maps as
+// map[interface{}]interface{}.
+func UnmarshalMsgPack(data []byte) (value interface{}, err error) {
+	if len(data) == 0 {
+		return nil, NewAerospikeError(PARSE_ERROR, "Empty MessagePack value")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			value, err = nil, NewAerospikeError(PARSE_ERROR, fmt.Sprintf("Invalid MessagePack value: %v", r))
+		}
+	}()
+	return newUnpacker(data, 0, len(data)).unpackObject()
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 
 func newPacker() *packer {
@@ -107,6 +135,82 @@ func (pckr *packer) PackMap(theMap map[interface{}]interface{}) error {
 	return nil
 }
 
+// PackOrderedMap packs the map with the extension header the server uses to
+// store the order of the map. Keys are sorted as the server expects them.
+func (pckr *packer) PackOrderedMap(theMap map[interface{}]interface{}, order MapOrderType) error {
+	if order == MapOrderUnordered {
+		return pckr.PackMap(theMap)
+	}
+
+	keys := make(orderedMapKeys, 0, len(theMap))
+	for k := range theMap {
+		key, err := newOrderedMapKey(k)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	sort.Sort(keys)
+
+	pckr.PackMapBegin(len(theMap) + 1)
+	pckr.PackAByte(0xc7) // ext 8
+	pckr.PackAByte(0)
+	pckr.PackAByte(byte(order))
+	pckr.PackNil()
+
+	for _, k := range keys {
+		if err := pckr.PackObject(k.key); err != nil {
+			return err
+		}
+		if err := pckr.PackObject(theMap[k.key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderedMapKey holds a map key with the values used to sort it:
+// integers come before strings, which come before byte arrays.
+type orderedMapKey struct {
+	key   interface{}
+	class int
+	num   int64
+	bytes []byte
+}
+
+func newOrderedMapKey(key interface{}) (orderedMapKey, error) {
+	switch k := key.(type) {
+	case string:
+		return orderedMapKey{key: key, class: 1, bytes: []byte(k)}, nil
+	case []byte:
+		return orderedMapKey{key: key, class: 2, bytes: k}, nil
+	}
+
+	v := reflect.ValueOf(key)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return orderedMapKey{key: key, num: v.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return orderedMapKey{key: key, num: int64(v.Uint())}, nil
+	}
+	return orderedMapKey{}, NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Unsupported ordered map key type: %T", key))
+}
+
+type orderedMapKeys []orderedMapKey
+
+func (keys orderedMapKeys) Len() int      { return len(keys) }
+func (keys orderedMapKeys) Swap(i, j int) { keys[i], keys[j] = keys[j], keys[i] }
+func (keys orderedMapKeys) Less(i, j int) bool {
+	a, b := keys[i], keys[j]
+	if a.class != b.class {
+		return a.class < b.class
+	}
+	if a.class == 0 {
+		return a.num < b.num
+	}
+	return bytes.Compare(a.bytes, b.bytes) < 0
+}
+
 func (pckr *packer) PackMapBegin(size int) {
 	if size < 16 {
 		pckr.PackAByte(0x80 | byte(size))
@@ -201,6 +305,11 @@ func (pckr *packer) PackObject(obj interface{}) error {
 		return pckr.PackMap(obj.(map[interface{}]interface{}))
 	}
 
+	// check for registered converters
+	if cv := convertValue(obj); cv != nil {
+		return cv.pack(pckr)
+	}
+
 	// check for array and map
 	rv := reflect.ValueOf(obj)
 	switch reflect.TypeOf(obj).Kind() {
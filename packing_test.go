@@ -20,6 +20,8 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go/types"
 )
 
 func testPackingFor(v interface{}) interface{} {
@@ -405,4 +407,18 @@ var _ = Describe("Packing Test", func() {
 			Expect(testPackingFor(vStr)).To(Equal(retStr))
 		})
 	})
+
+	Context("CDT Operations", func() {
+
+		It("should fail to write the operations whose arguments cannot be packed", func() {
+			op := ListSizeOp("bin", CtxListIndex(0), nil)
+			_, err := op.BinValue.write(make([]byte, 100), 0)
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+
+			// the error of a nested value is kept
+			op = ListAppendOp("bin", op.BinValue)
+			_, err = op.BinValue.write(make([]byte, 100), 0)
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+		})
+	})
 })
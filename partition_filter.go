@@ -0,0 +1,403 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// version of the cursor encoding
+const partitionCursorVersion = 1
+
+// flags of a partition in the cursor encoding
+const (
+	cursorPartitionDone   = 1 << 0
+	cursorPartitionDigest = 1 << 1
+)
+
+// PartitionStatus holds the progress of a partition scan or query.
+type PartitionStatus struct {
+	// Id is the partition id.
+	Id int
+
+	// Digest is the digest of the last record received from the partition.
+	// The next scan or query of the partition resumes after this record.
+	Digest []byte
+
+	// BVal is the secondary index value of the last record received from
+	// the partition by a query with a filter.
+	BVal int64
+
+	// Done is set when all the records of the partition have been received.
+	Done bool
+}
+
+// PartitionFilter determines the partitions which are read by scans and queries,
+// and tracks their progress.
+//
+// Set a PartitionFilter on ScanPolicy or QueryPolicy to read only the specified
+// partitions. After the returned Recordset is exhausted, the filter holds the
+// progress of each partition; passing the same filter to a new scan or query
+// resumes it until IsDone returns true. Combined with MultiPolicy.MaxRecords,
+// this allows paginating results. Disjoint partition ranges can be read by
+// different workers in parallel.
+//
+// A PartitionFilter must not be used by more than one scan or query at a time.
+// Partition scans and queries require server version 4.9+ (queries 6.0+).
+type PartitionFilter struct {
+	begin      int
+	count      int
+	partitions []*PartitionStatus
+}
+
+// NewPartitionFilterAll creates a partition filter which reads all partitions.
+func NewPartitionFilterAll() *PartitionFilter {
+	return newPartitionFilter(0, _PARTITIONS, nil)
+}
+
+// NewPartitionFilterById creates a partition filter which reads a single partition.
+func NewPartitionFilterById(partitionId int) *PartitionFilter {
+	return newPartitionFilter(partitionId, 1, nil)
+}
+
+// NewPartitionFilterByRange creates a partition filter which reads count partitions
+// starting from the begin partition id.
+func NewPartitionFilterByRange(begin int, count int) *PartitionFilter {
+	return newPartitionFilter(begin, count, nil)
+}
+
+// NewPartitionFilterAfterKey creates a partition filter which reads the partition
+// of the key, starting after the key's digest. Records are returned in digest order
+// within a partition, so this can be used to resume a partition scan from the last
+// record received.
+func NewPartitionFilterAfterKey(key *Key) *PartitionFilter {
+	return newPartitionFilter(NewPartitionByKey(key).PartitionId, 1, key.Digest())
+}
+
+func newPartitionFilter(begin int, count int, digest []byte) *PartitionFilter {
+	pf := &PartitionFilter{begin: begin, count: count}
+	if begin < 0 || count <= 0 || begin+count > _PARTITIONS {
+		// invalid ranges are reported when the filter is used
+		return pf
+	}
+
+	pf.partitions = make([]*PartitionStatus, count)
+	for i := range pf.partitions {
+		pf.partitions[i] = &PartitionStatus{Id: begin + i}
+	}
+	pf.partitions[0].Digest = digest
+	return pf
+}
+
+// SplitPartitionFilters splits all partitions into n disjoint partition filters of
+// contiguous ranges with balanced partition counts. Each filter can be handed to a
+// different worker or process to scan its own slice of the namespace.
+func SplitPartitionFilters(n int) ([]*PartitionFilter, error) {
+	if n <= 0 || n > _PARTITIONS {
+		return nil, NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Invalid number of partition filters: %d", n))
+	}
+
+	res := make([]*PartitionFilter, n)
+	begin := 0
+	for i := range res {
+		count := _PARTITIONS / n
+		if i < _PARTITIONS%n {
+			count++
+		}
+		res[i] = NewPartitionFilterByRange(begin, count)
+		begin += count
+	}
+	return res, nil
+}
+
+// SplitPartitionFiltersByRecords splits all partitions into n disjoint partition filters
+// of contiguous ranges with balanced estimated record counts. The estimates are usually
+// taken from Recordset.PartitionStats of a previous scan. Partitions missing from stats
+// are assumed to hold a single record.
+func SplitPartitionFiltersByRecords(stats []PartitionStats, n int) ([]*PartitionFilter, error) {
+	if n <= 0 || n > _PARTITIONS {
+		return nil, NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Invalid number of partition filters: %d", n))
+	}
+
+	var weights [_PARTITIONS]int64
+	for i := range weights {
+		weights[i] = 1
+	}
+	for _, st := range stats {
+		if st.PartitionId >= 0 && st.PartitionId < _PARTITIONS && st.Records > 0 {
+			weights[st.PartitionId] = st.Records
+		}
+	}
+
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+
+	res := make([]*PartitionFilter, 0, n)
+	begin := 0
+	var sum int64
+	for i := 0; i < _PARTITIONS && len(res) < n-1; i++ {
+		sum += weights[i]
+
+		// cut when the running sum reaches the next share of the total,
+		// or when the remaining partitions are only enough for one per filter
+		remaining := n - len(res) - 1
+		if sum*int64(n) >= total*int64(len(res)+1) || _PARTITIONS-(i+1) == remaining {
+			res = append(res, NewPartitionFilterByRange(begin, i+1-begin))
+			begin = i + 1
+		}
+	}
+	res = append(res, NewPartitionFilterByRange(begin, _PARTITIONS-begin))
+	return res, nil
+}
+
+// Begin returns the first partition id of the filter.
+func (pf *PartitionFilter) Begin() int {
+	return pf.begin
+}
+
+// Count returns the number of partitions of the filter.
+func (pf *PartitionFilter) Count() int {
+	return pf.count
+}
+
+// Partitions returns the status of each partition of the filter.
+func (pf *PartitionFilter) Partitions() []*PartitionStatus {
+	return pf.partitions
+}
+
+// IsDone returns true if all the records of all the partitions have been received.
+func (pf *PartitionFilter) IsDone() bool {
+	for _, ps := range pf.partitions {
+		if !ps.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeCursor serializes the progress of the filter, so that a scan or query can
+// be resumed with DecodeCursor after the process is restarted. Only partitions
+// which are done or have been partially read are included in the cursor.
+func (pf *PartitionFilter) EncodeCursor() []byte {
+	var entries []*PartitionStatus
+	for _, ps := range pf.partitions {
+		if ps.Done || ps.Digest != nil {
+			entries = append(entries, ps)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(partitionCursorVersion)
+	binary.Write(&buf, binary.BigEndian, uint16(pf.begin))
+	binary.Write(&buf, binary.BigEndian, uint16(pf.count))
+	binary.Write(&buf, binary.BigEndian, uint16(len(entries)))
+
+	for _, ps := range entries {
+		binary.Write(&buf, binary.BigEndian, uint16(ps.Id))
+		if ps.Done {
+			buf.WriteByte(cursorPartitionDone)
+			continue
+		}
+
+		buf.WriteByte(cursorPartitionDigest)
+		buf.Write(ps.Digest)
+		binary.Write(&buf, binary.BigEndian, ps.BVal)
+	}
+
+	return buf.Bytes()
+}
+
+// DecodeCursor restores the partition range and the progress of the filter
+// from a cursor created by EncodeCursor.
+func (pf *PartitionFilter) DecodeCursor(cursor []byte) error {
+	invalid := func(reason string) error {
+		return NewAerospikeError(PARSE_ERROR, "Invalid partition filter cursor: "+reason)
+	}
+
+	buf := bytes.NewReader(cursor)
+	version, err := buf.ReadByte()
+	if err != nil {
+		return invalid("empty cursor")
+	}
+	if version != partitionCursorVersion {
+		return invalid(fmt.Sprintf("unsupported version %d", version))
+	}
+
+	var header struct{ Begin, Count, Entries uint16 }
+	if err := binary.Read(buf, binary.BigEndian, &header); err != nil {
+		return invalid("truncated header")
+	}
+
+	res := newPartitionFilter(int(header.Begin), int(header.Count), nil)
+	if err := res.validate(); err != nil {
+		return err
+	}
+
+	for i := 0; i < int(header.Entries); i++ {
+		var id uint16
+		if err := binary.Read(buf, binary.BigEndian, &id); err != nil {
+			return invalid("truncated partition")
+		}
+		flags, err := buf.ReadByte()
+		if err != nil {
+			return invalid("truncated partition")
+		}
+
+		idx := int(id) - res.begin
+		if idx < 0 || idx >= res.count {
+			return invalid(fmt.Sprintf("partition %d out of range", id))
+		}
+		ps := res.partitions[idx]
+
+		if flags&cursorPartitionDone != 0 {
+			ps.Done = true
+		}
+
+		if flags&cursorPartitionDigest != 0 {
+			ps.Digest = make([]byte, _DIGEST_SIZE)
+			if _, err := io.ReadFull(buf, ps.Digest); err != nil {
+				return invalid("truncated digest")
+			}
+			if err := binary.Read(buf, binary.BigEndian, &ps.BVal); err != nil {
+				return invalid("truncated digest")
+			}
+		}
+	}
+
+	*pf = *res
+	return nil
+}
+
+func (pf *PartitionFilter) validate() error {
+	if pf.partitions == nil {
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Invalid partition range: begin %d, count %d", pf.begin, pf.count))
+	}
+	return nil
+}
+
+// assign groups the unfinished partitions of the filter by the nodes of
+// their replicas chosen by the replica policy.
+// The record limit is divided evenly between the nodes.
+func (pf *PartitionFilter) assign(cluster *Cluster, namespace string, replica ReplicaPolicy, maxRecords int64) ([]*nodePartitions, error) {
+	if err := pf.validate(); err != nil {
+		return nil, err
+	}
+	return pf.assignPartitions(cluster, namespace, replica, pf.partitions, maxRecords)
+}
+
+// assignPartitions groups the unfinished partitions of the list by the nodes of their replicas.
+func (pf *PartitionFilter) assignPartitions(cluster *Cluster, namespace string, replica ReplicaPolicy, partitions []*PartitionStatus, maxRecords int64) ([]*nodePartitions, error) {
+	var list []*nodePartitions
+	for _, ps := range partitions {
+		if ps.Done {
+			continue
+		}
+
+		node, err := cluster.getScanNode(NewPartition(namespace, ps.Id), replica)
+		if err != nil {
+			return nil, err
+		}
+
+		var np *nodePartitions
+		for _, item := range list {
+			if item.node == node {
+				np = item
+				break
+			}
+		}
+		if np == nil {
+			np = &nodePartitions{node: node, filter: pf}
+			list = append(list, np)
+		}
+
+		if ps.Digest == nil {
+			np.full = append(np.full, ps)
+		} else {
+			np.partial = append(np.partial, ps)
+		}
+	}
+
+	if maxRecords > 0 && len(list) > 0 {
+		max := maxRecords / int64(len(list))
+		if max == 0 {
+			max = 1
+		}
+		for _, np := range list {
+			np.maxRecords = max
+		}
+	}
+
+	return list, nil
+}
+
+// nodePartitions holds the partitions of a partition filter assigned to a node.
+type nodePartitions struct {
+	node   *Node
+	filter *PartitionFilter
+
+	// partitions to be read from the beginning
+	full []*PartitionStatus
+	// partitions to be read after their last digest
+	partial []*PartitionStatus
+
+	maxRecords int64
+
+	// if set, the secondary index values of the partial partitions are sent
+	sendBVal bool
+}
+
+func (np *nodePartitions) status(partitionId int) *PartitionStatus {
+	idx := partitionId - np.filter.begin
+	if idx < 0 || idx >= len(np.filter.partitions) {
+		return nil
+	}
+	return np.filter.partitions[idx]
+}
+
+// unfinished returns the partitions assigned to the node which are not done.
+func (np *nodePartitions) unfinished() []*PartitionStatus {
+	var res []*PartitionStatus
+	for _, list := range [][]*PartitionStatus{np.full, np.partial} {
+		for _, ps := range list {
+			if !ps.Done {
+				res = append(res, ps)
+			}
+		}
+	}
+	return res
+}
+
+// setDigest records the last record received from a partition.
+func (np *nodePartitions) setDigest(key *Key, bval int64) {
+	if ps := np.status(NewPartitionByKey(key).PartitionId); ps != nil {
+		ps.Digest = key.Digest()
+		ps.BVal = bval
+	}
+}
+
+// partitionDone marks the partition as done. Partitions which were unavailable
+// on the node remain unfinished, and will be read again on the next scan or query.
+func (np *nodePartitions) partitionDone(partitionId int, resultCode ResultCode) {
+	if ps := np.status(partitionId); ps != nil && resultCode == 0 {
+		ps.Done = true
+	}
+}
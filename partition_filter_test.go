@@ -0,0 +1,100 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("Partition Filter Test", func() {
+	initTestVars()
+
+	It("must restore the progress of a filter from its cursor", func() {
+		key, err := NewKey("test", "set", "key")
+		Expect(err).ToNot(HaveOccurred())
+
+		filter := NewPartitionFilterByRange(100, 50)
+		partitions := filter.Partitions()
+		partitions[0].Done = true
+		partitions[3].Digest = key.Digest()
+		partitions[3].BVal = -42
+
+		res := &PartitionFilter{}
+		Expect(res.DecodeCursor(filter.EncodeCursor())).ToNot(HaveOccurred())
+
+		Expect(res.Begin()).To(Equal(100))
+		Expect(res.Count()).To(Equal(50))
+		Expect(res.Partitions()).To(Equal(partitions))
+		Expect(res.IsDone()).To(BeFalse())
+	})
+
+	It("must reject invalid cursors", func() {
+		cursor := NewPartitionFilterAll().EncodeCursor()
+
+		res := &PartitionFilter{}
+		Expect(res.DecodeCursor(nil)).To(HaveOccurred())
+		Expect(res.DecodeCursor(cursor[:3])).To(HaveOccurred())
+
+		key, err := NewKey("test", "set", "key")
+		Expect(err).ToNot(HaveOccurred())
+		filter := NewPartitionFilterAfterKey(key)
+		cursor = filter.EncodeCursor()
+		Expect(res.DecodeCursor(cursor[:len(cursor)-10])).To(HaveOccurred())
+	})
+
+	It("must split all partitions into balanced ranges", func() {
+		filters, err := SplitPartitionFilters(3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(filters)).To(Equal(3))
+
+		begin := 0
+		for _, filter := range filters {
+			Expect(filter.Begin()).To(Equal(begin))
+			Expect(filter.Count()).To(BeNumerically(">=", 1365))
+			Expect(filter.Count()).To(BeNumerically("<=", 1366))
+			begin += filter.Count()
+		}
+		Expect(begin).To(Equal(4096))
+
+		_, err = SplitPartitionFilters(0)
+		Expect(err).To(HaveOccurred())
+		_, err = SplitPartitionFilters(4097)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("must split all partitions into ranges balanced by records", func() {
+		// the first partition holds about half the records
+		stats := []PartitionStats{{PartitionId: 0, Records: 4095}}
+
+		filters, err := SplitPartitionFiltersByRecords(stats, 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(filters)).To(Equal(2))
+		Expect(filters[0].Begin()).To(Equal(0))
+		Expect(filters[0].Count()).To(Equal(1))
+		Expect(filters[1].Begin()).To(Equal(1))
+		Expect(filters[1].Count()).To(Equal(4095))
+
+		filters, err = SplitPartitionFiltersByRecords(nil, 4096)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(filters)).To(Equal(4096))
+		for i, filter := range filters {
+			Expect(filter.Begin()).To(Equal(i))
+			Expect(filter.Count()).To(Equal(1))
+		}
+	})
+})
@@ -0,0 +1,89 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// PartitionMap is a snapshot of the partition map of a cluster: the nodes
+// holding each partition of each namespace, as known by the client.
+// It can be used for data locality aware work placement, e.g. to process the
+// records of a partition range on a host close to their master node.
+// A snapshot is not updated when the cluster changes; take a new one after
+// nodes are added or removed, or partitions migrate.
+type PartitionMap struct {
+	// Masters holds the master node of each partition by namespace, indexed by
+	// partition id. An entry is nil if the master is unknown or not active.
+	Masters map[string][]*Node
+
+	// Proles holds a prole replica node of each partition by namespace, indexed
+	// by partition id. It is only populated if ClientPolicy.RequestProleReplicas is set.
+	Proles map[string][]*Node
+}
+
+// PartitionsByNode returns the ids of the partitions of the namespace by the
+// name of their master node, in ascending order.
+func (pm *PartitionMap) PartitionsByNode(namespace string) map[string][]int {
+	res := make(map[string][]int)
+	for partitionId, node := range pm.Masters[namespace] {
+		if node != nil {
+			res[node.GetName()] = append(res[node.GetName()], partitionId)
+		}
+	}
+	return res
+}
+
+// PartitionMapSnapshot returns a copy of the current partition map of the cluster.
+func (clstr *Cluster) PartitionMapSnapshot() *PartitionMap {
+	return &PartitionMap{
+		Masters: copyPartitionMap(clstr.getPartitions()),
+		Proles:  copyPartitionMap(clstr.getProlePartitions()),
+	}
+}
+
+// copyPartitionMap copies the partition map, leaving out the inactive nodes.
+func copyPartitionMap(pmap map[string][]*Node) map[string][]*Node {
+	res := make(map[string][]*Node, len(pmap))
+	for namespace, nodes := range pmap {
+		copied := make([]*Node, len(nodes))
+		for i, node := range nodes {
+			if node != nil && node.IsActive() {
+				copied[i] = node
+			}
+		}
+		res[namespace] = copied
+	}
+	return res
+}
+
+// PartitionMapSnapshot returns a copy of the current partition map of the cluster.
+func (clnt *Client) PartitionMapSnapshot() *PartitionMap {
+	return clnt.cluster.PartitionMapSnapshot()
+}
+
+// GetNodeForKey returns the master node of the key's partition, which writes and
+// default reads of the key are sent to.
+// Unlike commands, which fall back to a random node, an INVALID_NODE_ERROR is
+// returned if the master node of the partition is unknown or not active.
+func (clnt *Client) GetNodeForKey(key *Key) (*Node, error) {
+	partition := NewPartitionByKey(key)
+	if nodes, exists := clnt.cluster.getPartitions()[partition.Namespace]; exists {
+		if node := nodes[partition.PartitionId]; node != nil && node.IsActive() {
+			return node, nil
+		}
+	}
+	return nil, NewAerospikeError(INVALID_NODE_ERROR, "No active master node for the key's partition")
+}
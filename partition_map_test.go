@@ -0,0 +1,124 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+)
+
+var _ = Describe("Partition Map Test", func() {
+
+	var client *Client
+	var nodeA, nodeB *Node
+
+	BeforeEach(func() {
+		nodeA = &Node{name: "A", active: NewAtomicBool(true)}
+		nodeB = &Node{name: "B", active: NewAtomicBool(true)}
+
+		masters := make([]*Node, _PARTITIONS)
+		for i := range masters {
+			if i%2 == 0 {
+				masters[i] = nodeA
+			} else {
+				masters[i] = nodeB
+			}
+		}
+
+		client = &Client{cluster: &Cluster{
+			partitionWriteMap: map[string][]*Node{"test": masters},
+			partitionProleMap: map[string][]*Node{},
+		}}
+	})
+
+	It("should route keys to the master of their partition", func() {
+		key, err := NewKey("test", "set", "key")
+		Expect(err).ToNot(HaveOccurred())
+
+		node, err := client.GetNodeForKey(key)
+		Expect(err).ToNot(HaveOccurred())
+		if NewPartitionByKey(key).PartitionId%2 == 0 {
+			Expect(node == nodeA).To(BeTrue())
+		} else {
+			Expect(node == nodeB).To(BeTrue())
+		}
+
+		key, err = NewKey("other", "set", "key")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.GetNodeForKey(key)
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(INVALID_NODE_ERROR))
+	})
+
+	It("should snapshot the partitions of the active nodes", func() {
+		pm := client.PartitionMapSnapshot()
+		Expect(len(pm.Masters["test"])).To(Equal(_PARTITIONS))
+		Expect(pm.Masters["test"][0] == nodeA).To(BeTrue())
+
+		byNode := pm.PartitionsByNode("test")
+		Expect(len(byNode["A"])).To(Equal(_PARTITIONS / 2))
+		Expect(byNode["B"][:3]).To(Equal([]int{1, 3, 5}))
+
+		// the snapshot is not affected by later changes
+		nodeB.active.Set(false)
+		Expect(pm.Masters["test"][1] == nodeB).To(BeTrue())
+
+		pm = client.PartitionMapSnapshot()
+		Expect(pm.Masters["test"][1]).To(BeNil())
+		Expect(pm.PartitionsByNode("test")).ToNot(HaveKey("B"))
+	})
+
+	It("should assign the partitions of scans to the replicas of the replica policy", func() {
+		// A is the prole of B's partitions and vice versa
+		proles := make([]*Node, _PARTITIONS)
+		for i := range proles {
+			if i%2 == 0 {
+				proles[i] = nodeB
+			} else {
+				proles[i] = nodeA
+			}
+		}
+		client.cluster.partitionProleMap = map[string][]*Node{"test": proles}
+
+		idsByNode := func(replica ReplicaPolicy) map[string][]int {
+			list, err := NewPartitionFilterByRange(0, 6).assign(client.cluster, "test", replica, 0)
+			Expect(err).ToNot(HaveOccurred())
+
+			res := map[string][]int{}
+			for _, np := range list {
+				for _, ps := range np.full {
+					res[np.node.name] = append(res[np.node.name], ps.Id)
+				}
+			}
+			return res
+		}
+
+		Expect(idsByNode(MASTER)).To(Equal(map[string][]int{"A": {0, 2, 4}, "B": {1, 3, 5}}))
+		Expect(idsByNode(PROLES)).To(Equal(map[string][]int{"A": {1, 3, 5}, "B": {0, 2, 4}}))
+
+		// the even partitions are read from their masters and the odd ones from their proles
+		Expect(idsByNode(MASTER_PROLES)).To(Equal(map[string][]int{"A": {0, 1, 2, 3, 4, 5}}))
+
+		// inactive proles fall back to the masters
+		nodeB.active.Set(false)
+		list, err := NewPartitionFilterByRange(0, 2).assign(client.cluster, "test", PROLES, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(list)).To(Equal(1))
+		Expect(list[0].node == nodeA).To(BeTrue())
+	})
+
+})
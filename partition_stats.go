@@ -0,0 +1,54 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"sync/atomic"
+)
+
+// PartitionStats holds the number of records and bytes received from a partition.
+type PartitionStats struct {
+	PartitionId int
+	Records     int64
+	Bytes       int64
+}
+
+// partitionStats collects per partition statistics of a scan.
+// Counters are updated concurrently by the node goroutines.
+type partitionStats struct {
+	records [_PARTITIONS]int64
+	bytes   [_PARTITIONS]int64
+}
+
+func (ps *partitionStats) add(key *Key, size int) {
+	partitionId := NewPartitionByKey(key).PartitionId
+	atomic.AddInt64(&ps.records[partitionId], 1)
+	atomic.AddInt64(&ps.bytes[partitionId], int64(size))
+}
+
+// list returns the statistics of the partitions which returned records.
+func (ps *partitionStats) list() []PartitionStats {
+	var res []PartitionStats
+	for i := 0; i < _PARTITIONS; i++ {
+		if records := atomic.LoadInt64(&ps.records[i]); records > 0 {
+			res = append(res, PartitionStats{
+				PartitionId: i,
+				Records:     records,
+				Bytes:       atomic.LoadInt64(&ps.bytes[i]),
+			})
+		}
+	}
+	return res
+}
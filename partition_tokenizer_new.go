@@ -29,11 +29,11 @@ type partitionTokenizerNew struct {
 	offset int
 }
 
-func newPartitionTokenizerNew(conn *Connection) (*partitionTokenizerNew, error) {
+func newPartitionTokenizerNew(conn *Connection, replicasName string) (*partitionTokenizerNew, error) {
 	pt := &partitionTokenizerNew{}
 
 	// Use low-level info methods and parse byte array directly for maximum performance.
-	// Send format:    replicas-master\n
+	// Send format:    replicas-master\n (or replicas-prole\n)
 	// Receive format: replicas-master\t<ns1>:<base 64 encoded bitmap>;<ns2>:<base 64 encoded bitmap>... \n
 	infoMap, err := RequestInfo(conn, replicasName)
 	if err != nil {
@@ -30,11 +30,11 @@ type partitionTokenizerOld struct {
 	offset int
 }
 
-func newPartitionTokenizerOld(conn *Connection) (*partitionTokenizerOld, error) {
+func newPartitionTokenizerOld(conn *Connection, replicasName string) (*partitionTokenizerOld, error) {
 	pt := &partitionTokenizerOld{}
 
 	// Use low-level info methods and parse byte array directly for maximum performance.
-	// Send format:    replicas-master\n
+	// Send format:    replicas-master\n (or replicas-prole\n)
 	// Receive format: replicas-master\t<ns1>:<base 64 encoded bitmap>;<ns2>:<base 64 encoded bitmap>... \n
 	infoMap, err := RequestInfo(conn, replicasName)
 	if err != nil {
@@ -78,7 +78,9 @@ func (pt *partitionTokenizerOld) UpdatePartition(nmap map[string][]*Node, node *
 			nodeArray := make([]*Node, _PARTITIONS)
 			amap[partition.Namespace] = nodeArray
 		}
-		Logger.Debug(partition.String() + "," + node.name)
+		if node.cluster.logEnabled(DEBUG) {
+			node.cluster.logEvent(DEBUG, SUBSYSTEM_CLUSTER, node.name, nil, "%s,%s", partition.String(), node.name)
+		}
 		nodeArray[partition.PartitionId] = node
 	}
 
@@ -0,0 +1,500 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+)
+
+// errPipelineClosed is returned for the commands of a pipelined connection which was closed.
+var errPipelineClosed = errors.New("Pipelined connection closed.")
+
+// pipelinedConnection shares a connection between the single record commands of a node.
+// Commands are written one after the other without waiting for the previous responses.
+// The server responds in the order of the commands, so each command reads its response
+// when its turn comes, in the order the commands were written. A command which times
+// out gives up its turn, and its response is skipped when it comes.
+type pipelinedConnection struct {
+	node *Node
+	conn *Connection
+
+	// underlying connection, to interrupt pending reads and writes
+	netConn net.Conn
+
+	// the bytes read of the current response, to skip the rest of it if abandoned
+	response *responseConn
+
+	// time given to the response of an abandoned command to be skipped once its turn comes
+	skipTimeout time.Duration
+
+	// serializes the writes of the commands
+	writeMutex sync.Mutex
+
+	mutex sync.Mutex
+	turn  *sync.Cond
+
+	// turns of the next written command and of the command reading its response
+	nextTicket int
+	serving    int
+
+	// turns of the commands which gave up waiting for their response
+	abandoned map[int]bool
+
+	// number of commands writing or reading, and of responses being skipped
+	active int
+
+	// set once the connection is broken; the commands which did not read their response fail with it
+	err error
+
+	// set if the connection is to be closed once idle
+	draining bool
+	closed   bool
+}
+
+func newPipelinedConnection(node *Node, conn *Connection) *pipelinedConnection {
+	pc := &pipelinedConnection{
+		node:      node,
+		conn:      conn,
+		netConn:   conn.conn,
+		response:  &responseConn{Conn: conn.conn},
+		abandoned: map[int]bool{},
+	}
+	conn.conn = pc.response
+	pc.turn = sync.NewCond(&pc.mutex)
+	return pc
+}
+
+// send writes the command and returns its turn to read the response.
+func (pc *pipelinedConnection) send(buf []byte, deadline time.Time) (int, error) {
+	pc.writeMutex.Lock()
+	defer pc.writeMutex.Unlock()
+
+	pc.mutex.Lock()
+	if pc.err != nil {
+		err := pc.err
+		pc.mutex.Unlock()
+		return 0, err
+	}
+	pc.active++
+	pc.mutex.Unlock()
+
+	err := pc.netConn.SetWriteDeadline(deadline)
+	if err == nil {
+		_, err = pc.conn.Write(buf)
+	}
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	ticket := pc.nextTicket
+	if err != nil {
+		pc.failLocked(err)
+	} else {
+		pc.nextTicket++
+	}
+	pc.leaveLocked()
+	return ticket, err
+}
+
+// receive waits for the turn of the command and reads its response with parse.
+// If the connection breaks before, the error which broke it is returned.
+// If the deadline passes or the context is done first, the command gives up its
+// turn; its response is skipped without failing the commands after it.
+func (pc *pipelinedConnection) receive(ticket int, deadline time.Time, ctx context.Context, parse func(conn *Connection) error) error {
+	pc.mutex.Lock()
+	pc.active++
+	if pc.serving != ticket && pc.err == nil {
+		stop := pc.wakeUpOn(deadline, ctx.Done())
+		for pc.serving != ticket && pc.err == nil && ctx.Err() == nil && (deadline.IsZero() || time.Now().Before(deadline)) {
+			pc.turn.Wait()
+		}
+		stop()
+	}
+	if pc.err != nil {
+		err := pc.err
+		pc.leaveLocked()
+		pc.mutex.Unlock()
+		return err
+	}
+	if pc.serving != ticket {
+		pc.abandoned[ticket] = true
+		pc.leaveLocked()
+		pc.mutex.Unlock()
+		return timeoutError(ctx)
+	}
+	pc.mutex.Unlock()
+
+	pc.response.read = 0
+	err := pc.netConn.SetReadDeadline(deadline)
+	if err == nil {
+		stop := pc.interruptReadOn(ctx.Done())
+		err = parse(pc.conn)
+		stop()
+	}
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	if err != nil && !responseConsumed(err) {
+		if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == TIMEOUT && pc.err == nil {
+			// skip the rest of the response before passing the turn on
+			pc.active++
+			go pc.skip()
+			pc.leaveLocked()
+			return timeoutError(ctx)
+		}
+		pc.failLocked(err)
+	}
+	pc.advanceLocked()
+	pc.leaveLocked()
+	return err
+}
+
+// wakeUpOn wakes up the commands waiting for their turn once the deadline passes or
+// cancelled is closed, until stop is called.
+func (pc *pipelinedConnection) wakeUpOn(deadline time.Time, cancelled <-chan struct{}) (stop func()) {
+	if deadline.IsZero() && cancelled == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		var expired <-chan time.Time
+		if !deadline.IsZero() {
+			timer := time.NewTimer(deadline.Sub(time.Now()))
+			defer timer.Stop()
+			expired = timer.C
+		}
+
+		select {
+		case <-expired:
+		case <-cancelled:
+		case <-done:
+			return
+		}
+
+		pc.mutex.Lock()
+		pc.turn.Broadcast()
+		pc.mutex.Unlock()
+	}()
+	return func() { close(done) }
+}
+
+// interruptReadOn interrupts the read of the response once cancelled is closed, until
+// stop is called. The read deadline is not changed anymore once stop returns.
+func (pc *pipelinedConnection) interruptReadOn(cancelled <-chan struct{}) (stop func()) {
+	if cancelled == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-cancelled:
+			pc.netConn.SetReadDeadline(time.Now())
+		case <-done:
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+// skip reads and discards the rest of the current response, then passes the turn on.
+// The connection fails if the response does not come in time.
+func (pc *pipelinedConnection) skip() {
+	var deadline time.Time
+	if pc.skipTimeout > 0 {
+		deadline = time.Now().Add(pc.skipTimeout)
+	}
+	err := pc.netConn.SetReadDeadline(deadline)
+	if err == nil {
+		err = pc.response.skip()
+	}
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	if err != nil {
+		pc.failLocked(errToTimeoutErr(err))
+	}
+	pc.advanceLocked()
+	pc.leaveLocked()
+}
+
+// advanceLocked passes the turn to the next command, skipping its response if it was abandoned.
+func (pc *pipelinedConnection) advanceLocked() {
+	pc.serving++
+	if pc.abandoned[pc.serving] {
+		delete(pc.abandoned, pc.serving)
+		if pc.err == nil {
+			pc.response.read = 0
+			pc.active++
+			go pc.skip()
+		}
+	}
+	pc.turn.Broadcast()
+}
+
+// isBroken returns true if the connection can not be used for new commands.
+func (pc *pipelinedConnection) isBroken() bool {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	return pc.err != nil || pc.draining
+}
+
+// drain closes the connection once the responses of the commands written so far are read.
+func (pc *pipelinedConnection) drain() {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	pc.draining = true
+	pc.closeIfIdleLocked()
+}
+
+// close breaks the connection; the commands waiting for their responses fail.
+func (pc *pipelinedConnection) close() {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	pc.failLocked(errPipelineClosed)
+	pc.closeIfIdleLocked()
+}
+
+func (pc *pipelinedConnection) failLocked(err error) {
+	if pc.err == nil {
+		pc.err = err
+		// unblock the pending reads and writes
+		pc.netConn.SetDeadline(time.Now())
+		pc.turn.Broadcast()
+	}
+}
+
+func (pc *pipelinedConnection) leaveLocked() {
+	pc.active--
+	pc.closeIfIdleLocked()
+}
+
+// closeIfIdleLocked closes the connection if it is broken or draining and no
+// command is using it, nor waits for a response.
+func (pc *pipelinedConnection) closeIfIdleLocked() {
+	if pc.closed || pc.active > 0 || (pc.err == nil && !pc.draining) {
+		return
+	}
+	if pc.err == nil && pc.serving != pc.nextTicket {
+		return
+	}
+
+	pc.closed = true
+	if pc.err == nil {
+		pc.err = errPipelineClosed
+	}
+	pc.node.connectionCount.DecrementAndGet()
+	pc.conn.Close()
+}
+
+// responseConn counts the bytes read of the current response and keeps its proto
+// header, so that the rest of the response can be skipped.
+type responseConn struct {
+	net.Conn
+
+	read   int
+	header [8]byte
+}
+
+func (rc *responseConn) Read(b []byte) (int, error) {
+	n, err := rc.Conn.Read(b)
+	if rc.read < len(rc.header) {
+		copy(rc.header[rc.read:], b[:n])
+	}
+	rc.read += n
+	return n, err
+}
+
+// skip reads and discards the rest of the response.
+func (rc *responseConn) skip() error {
+	if rc.read < len(rc.header) {
+		rest := make([]byte, len(rc.header)-rc.read)
+		if _, err := io.ReadFull(rc, rest); err != nil {
+			return err
+		}
+	}
+
+	size := Buffer.BytesToInt64(rc.header[:], 0) & 0xFFFFFFFFFFFF
+	_, err := io.CopyN(ioutil.Discard, rc, int64(len(rc.header))+size-int64(rc.read))
+	return err
+}
+
+// responseConsumed returns true if the error was returned by the server, in which
+// case the whole response was read and the connection can still be used.
+func responseConsumed(err error) bool {
+	ae, ok := err.(AerospikeError)
+	return ok && ae.ResultCode() > 0 && ae.ResultCode() != TIMEOUT
+}
+
+// timeoutError returns the error of a command which gave up waiting: the context's
+// error if it was cancelled, a timeout otherwise.
+func timeoutError(ctx context.Context) error {
+	if err := ctx.Err(); err == context.Canceled {
+		return err
+	}
+	return NewAerospikeError(TIMEOUT)
+}
+
+// getPipelinedConnection returns one of the pipelined connections of the node,
+// opening it if needed. Connections opened before a reconfiguration are replaced.
+// Waiting for the connection and opening it give up at the deadline, or once the
+// context is done.
+func (nd *Node) getPipelinedConnection(ctx context.Context, deadline time.Time) (*pipelinedConnection, error) {
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	nd.pipelineMutex.Lock()
+	if nd.pipelines == nil {
+		size := nd.cluster.clientPolicy.PipelinedConnections
+		nd.pipelines = make([]*pipelinedConnection, size)
+		nd.pipelineDials = make([]chan struct{}, size)
+	}
+
+	i := nd.pipelineIndex % len(nd.pipelines)
+	nd.pipelineIndex++
+
+	// wait for the connection being opened in the slot, if any
+	for nd.pipelineDials != nil && nd.pipelineDials[i] != nil {
+		dialing := nd.pipelineDials[i]
+		nd.pipelineMutex.Unlock()
+		select {
+		case <-dialing:
+		case <-ctx.Done():
+			return nil, timeoutError(ctx)
+		}
+		nd.pipelineMutex.Lock()
+	}
+	if nd.pipelines == nil {
+		nd.pipelineMutex.Unlock()
+		return nil, errPipelineClosed
+	}
+
+	if pc := nd.pipelines[i]; pc != nil && !pc.isBroken() {
+		if !nd.isStale(pc.conn) {
+			nd.pipelineMutex.Unlock()
+			return pc, nil
+		}
+		pc.drain()
+	}
+
+	// if connection count is limited and enough connections are already created, don't create a new one
+	if nd.cluster.clientPolicy.LimitConnectionsToQueueSize && nd.connectionCount.Get() >= nd.connectionQueueSize.Get() {
+		nd.pipelineMutex.Unlock()
+		return nil, NewAerospikeError(NO_AVAILABLE_CONNECTIONS_TO_NODE)
+	}
+
+	// open the connection without holding the lock; the other commands of the slot wait for it
+	dialing := make(chan struct{})
+	nd.pipelineDials[i] = dialing
+	nd.pipelineMutex.Unlock()
+
+	pc, err := nd.openPipelinedConnection(ctx)
+
+	nd.pipelineMutex.Lock()
+	defer nd.pipelineMutex.Unlock()
+
+	close(dialing)
+	if nd.pipelineDials == nil || nd.pipelineDials[i] != dialing {
+		// the pipelined connections were closed meanwhile
+		if err == nil {
+			pc.close()
+		}
+		return nil, errPipelineClosed
+	}
+	nd.pipelineDials[i] = nil
+
+	if err != nil {
+		return nil, err
+	}
+	nd.pipelines[i] = pc
+	return pc, nil
+}
+
+// openPipelinedConnection opens and authenticates a connection for a pipelined connection.
+func (nd *Node) openPipelinedConnection(ctx context.Context) (*pipelinedConnection, error) {
+	conn, err := nd.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn.node = nd
+	conn.generation = nd.cluster.configGeneration.Get()
+
+	user, password := nd.cluster.getCredentials()
+	if err := conn.AuthenticateContext(ctx, user, password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// deadlines are set per read and write
+	if err := conn.SetTimeout(0); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	nd.connectionCount.IncrementAndGet()
+	nd.connectionsOpened.IncrementAndGet()
+	if listener := nd.metricsListener(); listener != nil {
+		listener.OnConnectionOpened(nd)
+	}
+
+	pc := newPipelinedConnection(nd, conn)
+	pc.skipTimeout = nd.cluster.clientPolicy.Timeout
+	return pc, nil
+}
+
+// closePipelines closes the pipelined connections of the node.
+func (nd *Node) closePipelines() {
+	nd.pipelineMutex.Lock()
+	defer nd.pipelineMutex.Unlock()
+
+	for _, pc := range nd.pipelines {
+		if pc != nil {
+			pc.close()
+		}
+	}
+	nd.pipelines = nil
+	nd.pipelineDials = nil
+}
+
+// isSingleRecordCommand returns true for the commands on a single key, which
+// may be sent on pipelined connections.
+func isSingleRecordCommand(ifc command) bool {
+	_, ok := ifc.(interface {
+		commandKey() *Key
+	})
+	return ok
+}
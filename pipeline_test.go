@@ -0,0 +1,178 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pipelined Connection Test", func() {
+
+	var server, client net.Conn
+	var pipeline *pipelinedConnection
+
+	// receives the delay of the first response, if any
+	var delay chan time.Duration
+
+	BeforeEach(func() {
+		server, client = net.Pipe()
+		delay = make(chan time.Duration, 1)
+		node := &Node{connectionCount: NewAtomicInt(1)}
+		pipeline = newPipelinedConnection(node, &Connection{conn: client})
+
+		// echo the 8 byte commands in order; responses are buffered like in a socket
+		// the goroutines outlive the test, so they keep their own pipe
+		server, delay := server, delay
+		responses := make(chan []byte, 100)
+		go func() {
+			defer close(responses)
+			for {
+				buf := make([]byte, 8)
+				if _, err := io.ReadFull(server, buf); err != nil {
+					return
+				}
+				responses <- buf
+			}
+		}()
+		go func() {
+			for buf := range responses {
+				select {
+				case d := <-delay:
+					time.Sleep(d)
+				default:
+				}
+				if _, err := server.Write(buf); err != nil {
+					return
+				}
+			}
+		}()
+	})
+
+	AfterEach(func() {
+		server.Close()
+		pipeline.close()
+	})
+
+	It("should match the responses to the commands in the order they were sent", func() {
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				msg := bytes.Repeat([]byte{byte(i)}, 8)
+				ticket, err := pipeline.send(msg, time.Now().Add(time.Second))
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				errs <- pipeline.receive(ticket, time.Now().Add(time.Second), context.Background(), func(conn *Connection) error {
+					res := make([]byte, 8)
+					if _, err := conn.Read(res, 8); err != nil {
+						return err
+					}
+					if !bytes.Equal(res, msg) {
+						return errors.New("response of another command")
+					}
+					return nil
+				})
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(pipeline.isBroken()).To(BeFalse())
+	})
+
+	It("should fail the pending commands when the connection breaks", func() {
+		ticket, err := pipeline.send(make([]byte, 8), time.Now().Add(time.Second))
+		Expect(err).ToNot(HaveOccurred())
+		next, err := pipeline.send(make([]byte, 8), time.Now().Add(time.Second))
+		Expect(err).ToNot(HaveOccurred())
+
+		broken := errors.New("broken")
+		err = pipeline.receive(ticket, time.Now().Add(time.Second), context.Background(), func(conn *Connection) error { return broken })
+		Expect(err).To(Equal(broken))
+
+		err = pipeline.receive(next, time.Now().Add(time.Second), context.Background(), func(conn *Connection) error { return nil })
+		Expect(err).To(Equal(broken))
+		Expect(pipeline.isBroken()).To(BeTrue())
+		Expect(pipeline.node.connectionCount.Get()).To(Equal(0))
+	})
+
+	It("should skip the responses of the commands which timed out without failing the commands behind them", func() {
+		delay <- 300 * time.Millisecond
+
+		// 8 byte responses made of an empty proto header, to be skipped
+		timeouts := []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, 2 * time.Second, 2 * time.Second, 2 * time.Second}
+		tickets := make([]int, len(timeouts))
+		for i := range timeouts {
+			ticket, err := pipeline.send([]byte{byte(i), 0, 0, 0, 0, 0, 0, 0}, time.Now().Add(time.Second))
+			Expect(err).ToNot(HaveOccurred())
+			tickets[i] = ticket
+		}
+
+		begin := time.Now()
+		var wg sync.WaitGroup
+		errs := make([]error, len(timeouts))
+		for i, timeout := range timeouts {
+			wg.Add(1)
+			go func(i int, timeout time.Duration) {
+				defer wg.Done()
+
+				errs[i] = pipeline.receive(tickets[i], time.Now().Add(timeout), context.Background(), func(conn *Connection) error {
+					res := make([]byte, 8)
+					if _, err := conn.Read(res, 8); err != nil {
+						return err
+					}
+					if res[0] != byte(i) {
+						return errors.New("response of another command")
+					}
+					return nil
+				})
+			}(i, timeout)
+		}
+		wg.Wait()
+
+		// the first command timed out reading its response, the second one waiting for its turn
+		for _, err := range errs[:2] {
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(TIMEOUT))
+		}
+		for _, err := range errs[2:] {
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(time.Since(begin)).To(BeNumerically("<", time.Second))
+		Expect(pipeline.isBroken()).To(BeFalse())
+	})
+
+})
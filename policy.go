@@ -31,6 +31,9 @@ type BasePolicy struct {
 
 	// Priority of request relative to other transactions.
 	// Currently, only used for scans.
+	//
+	// Deprecated: Servers 4.7+ ignore the priority.
+	// Use ScanPolicy.RecordsPerSecond to throttle scans instead.
 	Priority Priority //= Priority.DEFAULT;
 
 	// How replicas should be consulted in a read operation to provide the desired
@@ -38,6 +41,21 @@ type BasePolicy struct {
 	// read operation.
 	ConsistencyLevel ConsistencyLevel //= CONSISTENCY_ONE
 
+	// ReplicaPolicy determines which partition replica a single record read
+	// is sent to. Writes are always sent to the master.
+	ReplicaPolicy ReplicaPolicy //= MASTER
+
+	// ReadYourWrites, if set, tracks the keys written with this policy and
+	// routes their reads to the master for the session's window, regardless
+	// of ReplicaPolicy. Share the same session between read and write policies.
+	ReadYourWrites *ReadYourWrites
+
+	// FilterExpression is evaluated by the server against each record.
+	// Records for which the expression is false are skipped by scans and queries,
+	// and single record commands on them fail with a FILTERED_OUT error.
+	// Batch commands ignore it.
+	FilterExpression *Expression
+
 	// Timeout specifies transaction timeout.
 	// This timeout is used to set the socket timeout and is also sent to the
 	// server along with the transaction in the wire protocol.
@@ -53,6 +71,37 @@ type BasePolicy struct {
 	// SleepBetweenReplies determines duration to sleep between retries if a transaction fails and the
 	// timeout was not exceeded.  Enter zero to skip sleep.
 	SleepBetweenRetries time.Duration //= 500ms;
+
+	// VerifyKeyHash determines if the secondary hash of the user key stored by
+	// WritePolicy.SendKeyHash should be verified on reads. If the hashes do not match,
+	// a KEY_MISMATCH error will be returned.
+	// The hidden bin is always removed from the records returned by single record reads.
+	VerifyKeyHash bool
+
+	// KeyNotFoundAsError determines if Get and GetHeader return ErrKeyNotFound
+	// when the record does not exist, instead of a nil record and a nil error.
+	KeyNotFoundAsError bool //= false
+
+	// UseCompression compresses the commands larger than 128 bytes sent to the nodes
+	// which advertise the compression feature, and allows them to compress their
+	// responses, e.g. the records of batch, scan and query commands.
+	// It reduces the bandwidth at the cost of CPU time on both sides.
+	UseCompression bool //= false
+
+	// SlowLogThreshold, if set, reports the commands which take longer than the threshold,
+	// retries included, to the client's logger and to the metrics listener if it implements
+	// SlowCommandListener. Scans and queries are reported per node.
+	// Default to no reporting (0).
+	SlowLogThreshold time.Duration
+
+	// ClusterDownPolicy determines what single record commands do when no node
+	// is available to send them to: retry, fail immediately or wait for the
+	// cluster to come back for up to ClusterDownGracePeriod.
+	ClusterDownPolicy ClusterDownPolicy //= CLUSTER_DOWN_RETRY
+
+	// ClusterDownGracePeriod is the longest time a command waits for a node
+	// when ClusterDownPolicy is CLUSTER_DOWN_WAIT.
+	ClusterDownGracePeriod time.Duration //= 1 second
 }
 
 // NewPolicy generates a new BasePolicy instance with default values.
@@ -60,9 +109,13 @@ func NewPolicy() *BasePolicy {
 	return &BasePolicy{
 		Priority:            DEFAULT,
 		ConsistencyLevel:    CONSISTENCY_ONE,
+		ReplicaPolicy:       MASTER,
 		Timeout:             0 * time.Millisecond,
 		MaxRetries:          2,
 		SleepBetweenRetries: 500 * time.Millisecond,
+
+		ClusterDownPolicy:      CLUSTER_DOWN_RETRY,
+		ClusterDownGracePeriod: time.Second,
 	}
 }
 
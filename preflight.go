@@ -0,0 +1,287 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// PreflightStage is a stage of the connectivity check of a seed host.
+type PreflightStage int
+
+const (
+	// PREFLIGHT_DNS resolves the seed host name into addresses.
+	PREFLIGHT_DNS PreflightStage = iota
+
+	// PREFLIGHT_CONNECT opens a TCP connection to an address.
+	PREFLIGHT_CONNECT
+
+	// PREFLIGHT_TLS performs the TLS handshake. This client does not support TLS,
+	// so the stage is always skipped.
+	PREFLIGHT_TLS
+
+	// PREFLIGHT_AUTH authenticates the user of the client policy.
+	// Skipped if the client policy does not require authentication.
+	PREFLIGHT_AUTH
+
+	// PREFLIGHT_INFO requests the node name and build from the server.
+	PREFLIGHT_INFO
+)
+
+// String implements the Stringer interface.
+func (stage PreflightStage) String() string {
+	switch stage {
+	case PREFLIGHT_DNS:
+		return "dns"
+	case PREFLIGHT_CONNECT:
+		return "connect"
+	case PREFLIGHT_TLS:
+		return "tls"
+	case PREFLIGHT_AUTH:
+		return "auth"
+	case PREFLIGHT_INFO:
+		return "info"
+	}
+	return "unknown"
+}
+
+// PreflightResult is the outcome of a single stage of a preflight check.
+type PreflightResult struct {
+	Stage PreflightStage
+
+	// Address is the resolved address the stage was run against.
+	// Empty for the DNS stage.
+	Address string
+
+	// Duration is the time the stage took.
+	Duration time.Duration
+
+	// Skipped is set if the stage was not required.
+	Skipped bool
+
+	// Err is the error of the stage, or nil if the stage succeeded.
+	Err error
+}
+
+// PreflightReport is the outcome of the preflight check of a seed host.
+type PreflightReport struct {
+	Seed *Host
+
+	// Addresses are the addresses the seed host name resolved to,
+	// with ClientPolicy.HostResolver if set.
+	Addresses []string
+
+	// Results of each stage, in the order they were run.
+	// The connection stages are run for each address.
+	Results []*PreflightResult
+
+	// NodeName and Build are reported by the first reachable address.
+	NodeName string
+	Build    string
+}
+
+// OK returns true if at least one address of the seed passed all stages.
+func (rpt *PreflightReport) OK() bool {
+	return rpt.NodeName != ""
+}
+
+// Err returns the first error encountered, or nil if the seed is reachable.
+func (rpt *PreflightReport) Err() error {
+	if rpt.OK() {
+		return nil
+	}
+	for _, res := range rpt.Results {
+		if res.Err != nil {
+			return fmt.Errorf("%s: %s failed: %s", rpt.Seed, res.Stage, res.Err)
+		}
+	}
+	return fmt.Errorf("%s: no address passed all checks", rpt.Seed)
+}
+
+// String implements the Stringer interface and returns a human readable diagnosis.
+func (rpt *PreflightReport) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "seed %s:", rpt.Seed)
+	if rpt.OK() {
+		fmt.Fprintf(&buf, " OK (node %s, build %s)", rpt.NodeName, rpt.Build)
+	} else {
+		buf.WriteString(" FAILED")
+	}
+
+	for _, res := range rpt.Results {
+		buf.WriteString("\n  ")
+		buf.WriteString(res.Stage.String())
+		if res.Address != "" {
+			fmt.Fprintf(&buf, " %s", res.Address)
+		}
+		switch {
+		case res.Skipped:
+			buf.WriteString(": skipped")
+		case res.Err != nil:
+			fmt.Fprintf(&buf, ": %s after %s", res.Err, res.Duration)
+		default:
+			fmt.Fprintf(&buf, ": ok in %s", res.Duration)
+		}
+	}
+	return buf.String()
+}
+
+// Preflight checks the connectivity of each seed host of the client.
+// See the Preflight function for details.
+func (clnt *Client) Preflight(ctx context.Context) []*PreflightReport {
+	return Preflight(ctx, &clnt.cluster.clientPolicy, clnt.cluster.getSeeds()...)
+}
+
+// Preflight checks the connectivity of each host, without creating a client.
+// For each host, it resolves the host name, connects to every resolved address,
+// authenticates and requests the node info, and reports the outcome and duration
+// of every stage. Hosts are checked concurrently; the reports are returned in the
+// order of the hosts. The context limits the duration of the whole check.
+// If the policy is nil, the default client policy will be used.
+func Preflight(ctx context.Context, policy *ClientPolicy, hosts ...*Host) []*PreflightReport {
+	if policy == nil {
+		policy = NewClientPolicy()
+	}
+
+	reports := make([]*PreflightReport, len(hosts))
+
+	var wg sync.WaitGroup
+	wg.Add(len(hosts))
+	for i, host := range hosts {
+		go func(i int, host *Host) {
+			defer wg.Done()
+			reports[i] = preflightHost(ctx, policy, host)
+		}(i, host)
+	}
+	wg.Wait()
+
+	return reports
+}
+
+func preflightHost(ctx context.Context, policy *ClientPolicy, host *Host) *PreflightReport {
+	rpt := &PreflightReport{Seed: host}
+
+	start := time.Now()
+	var hosts []*Host
+	if policy.HostResolver != nil {
+		var err error
+		hosts, err = policy.HostResolver.ResolveHost(host)
+		rpt.Results = append(rpt.Results, &PreflightResult{Stage: PREFLIGHT_DNS, Duration: time.Since(start), Err: err})
+		if err != nil {
+			return rpt
+		}
+	} else if ip := net.ParseIP(host.Name); ip != nil {
+		// IP addresses do not need a lookup
+		hosts = []*Host{host}
+		rpt.Results = append(rpt.Results, &PreflightResult{Stage: PREFLIGHT_DNS, Skipped: true})
+	} else {
+		addresses, err := net.DefaultResolver.LookupHost(ctx, host.Name)
+		rpt.Results = append(rpt.Results, &PreflightResult{Stage: PREFLIGHT_DNS, Duration: time.Since(start), Err: err})
+		if err != nil {
+			return rpt
+		}
+		for _, addr := range addresses {
+			hosts = append(hosts, NewHost(addr, host.Port))
+		}
+	}
+
+	for _, h := range hosts {
+		rpt.Addresses = append(rpt.Addresses, h.Name)
+
+		address := net.JoinHostPort(h.Name, strconv.Itoa(h.Port))
+		if nodeName, build, ok := preflightAddress(ctx, policy, address, rpt); ok && !rpt.OK() {
+			rpt.NodeName = nodeName
+			rpt.Build = build
+		}
+	}
+	return rpt
+}
+
+// preflightAddress runs the connection stages against the address, appending the
+// results to the report. Returns the node name and build if all stages passed.
+func preflightAddress(ctx context.Context, policy *ClientPolicy, address string, rpt *PreflightReport) (string, string, bool) {
+	record := func(stage PreflightStage, start time.Time, err error) bool {
+		rpt.Results = append(rpt.Results, &PreflightResult{Stage: stage, Address: address, Duration: time.Since(start), Err: err})
+		return err == nil
+	}
+
+	start := time.Now()
+	dialer := net.Dialer{Timeout: policy.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", address)
+	if !record(PREFLIGHT_CONNECT, start, err) {
+		return "", "", false
+	}
+	conn := &Connection{conn: netConn}
+	defer conn.Close()
+
+	// abort the remaining stages when the context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			netConn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := conn.SetTimeout(policy.Timeout); err != nil {
+		record(PREFLIGHT_CONNECT, start, err)
+		return "", "", false
+	}
+
+	rpt.Results = append(rpt.Results, &PreflightResult{Stage: PREFLIGHT_TLS, Address: address, Skipped: true})
+
+	if policy.RequiresAuthentication() {
+		start = time.Now()
+		password, err := hashPassword(policy.Password)
+		if err == nil {
+			err = conn.Authenticate(policy.User, password)
+		}
+		if !record(PREFLIGHT_AUTH, start, ctxErr(ctx, err)) {
+			return "", "", false
+		}
+	} else {
+		rpt.Results = append(rpt.Results, &PreflightResult{Stage: PREFLIGHT_AUTH, Address: address, Skipped: true})
+	}
+
+	start = time.Now()
+	infoMap, err := RequestInfo(conn, "node", "build")
+	if err == nil && infoMap["node"] == "" {
+		err = NewAerospikeError(INVALID_NODE_ERROR, "node name not returned by server")
+	}
+	if !record(PREFLIGHT_INFO, start, ctxErr(ctx, err)) {
+		return "", "", false
+	}
+
+	return infoMap["node"], infoMap["build"], true
+}
+
+// ctxErr returns the context error instead of the connection error if the
+// connection was closed because the context is done.
+func ctxErr(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
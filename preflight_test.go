@@ -0,0 +1,59 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("Preflight Test", func() {
+	initTestVars()
+
+	It("must report the failed stage of unreachable seeds", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		reports := Preflight(ctx, clientPolicy, NewHost("127.0.0.1", 1), NewHost("invalid.host.name.", 3000))
+		Expect(len(reports)).To(Equal(2))
+
+		Expect(reports[0].OK()).To(BeFalse())
+		Expect(reports[0].Err()).To(HaveOccurred())
+		last := reports[0].Results[len(reports[0].Results)-1]
+		Expect(last.Stage).To(Equal(PREFLIGHT_CONNECT))
+		Expect(last.Err).To(HaveOccurred())
+
+		Expect(reports[1].OK()).To(BeFalse())
+		Expect(reports[1].Results[0].Stage).To(Equal(PREFLIGHT_DNS))
+		Expect(reports[1].Results[0].Err).To(HaveOccurred())
+	})
+
+	It("must report the node of reachable seeds", func() {
+		client, err := NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+		defer client.Close()
+
+		reports := client.Preflight(context.Background())
+		Expect(len(reports)).To(Equal(1))
+		Expect(reports[0].OK()).To(BeTrue())
+		Expect(reports[0].Err()).ToNot(HaveOccurred())
+		Expect(reports[0].NodeName).ToNot(BeEmpty())
+	})
+})
@@ -37,6 +37,8 @@ func (cmd *queryCommand) getPolicy(ifc command) Policy {
 
 func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 	var functionArgBuffer []byte
+	var packedCtx []byte
+	var packedExp []byte
 
 	fieldCount := 0
 	filterSize := 0
@@ -59,7 +61,25 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 		fieldCount++
 	}
 
+	if cmd.partitions != nil {
+		fieldCount += cmd.estimatePartitionsSize(cmd.partitions)
+	}
+
 	if len(cmd.statement.Filters) > 0 {
+		// only one filter is supported by the server on a secondary index lookup
+		if cmd.statement.Filters[0].IndexCollectionType() != ICT_DEFAULT {
+			cmd.dataOffset += int(_FIELD_HEADER_SIZE) + 1
+			fieldCount++
+		}
+
+		if packedCtx, err = cmd.statement.Filters[0].packedCtx(); err != nil {
+			return err
+		}
+		if packedCtx != nil {
+			cmd.dataOffset += int(_FIELD_HEADER_SIZE) + len(packedCtx)
+			fieldCount++
+		}
+
 		cmd.dataOffset += int(_FIELD_HEADER_SIZE)
 		filterSize++ // num filters
 
@@ -72,13 +92,22 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 		}
 		cmd.dataOffset += filterSize
 		fieldCount++
-	} else {
+	} else if cmd.partitions == nil {
 		// Calling query with no filters is more efficiently handled by a primary index scan.
 		// Estimate scan options size.
+		// Partition queries do not send the scan options; their priority is deprecated.
 		cmd.dataOffset += (2 + int(_FIELD_HEADER_SIZE))
 		fieldCount++
 	}
 
+	if packedExp, err = cmd.policy.FilterExpression.pack(); err != nil {
+		return err
+	}
+	if packedExp != nil {
+		cmd.dataOffset += int(_FIELD_HEADER_SIZE) + len(packedExp)
+		fieldCount++
+	}
+
 	if len(cmd.statement.BinNames) > 0 {
 		cmd.dataOffset += int(_FIELD_HEADER_SIZE)
 		binNameSize++ // num bin names
@@ -112,12 +141,22 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 		cmd.dataOffset += int(_FIELD_HEADER_SIZE) + len(functionArgBuffer)
 		fieldCount += 4
 	}
+
+	for _, op := range cmd.statement.operations {
+		cmd.estimateOperationSizeForOperation(op)
+	}
+
 	if err := cmd.sizeBuffer(); err != nil {
 		return nil
 	}
 
-	readAttr := _INFO1_READ
-	cmd.writeHeader(cmd.policy.GetBasePolicy(), readAttr, 0, fieldCount, 0)
+	if len(cmd.statement.operations) > 0 {
+		// background query applying the operations to each record
+		cmd.writeHeader(cmd.policy.GetBasePolicy(), 0, _INFO2_WRITE, fieldCount, len(cmd.statement.operations))
+	} else {
+		readAttr := _INFO1_READ
+		cmd.writeHeader(cmd.policy.GetBasePolicy(), readAttr, 0, fieldCount, 0)
+	}
 
 	if cmd.statement.Namespace != "" {
 		cmd.writeFieldString(cmd.statement.Namespace, NAMESPACE)
@@ -131,7 +170,21 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 		cmd.writeFieldString(cmd.statement.SetName, TABLE)
 	}
 
+	if cmd.partitions != nil {
+		cmd.writePartitions(cmd.partitions)
+	}
+
 	if len(cmd.statement.Filters) > 0 {
+		if idxType := cmd.statement.Filters[0].IndexCollectionType(); idxType != ICT_DEFAULT {
+			cmd.writeFieldHeader(1, INDEX_TYPE)
+			cmd.dataBuffer[cmd.dataOffset] = byte(idxType)
+			cmd.dataOffset++
+		}
+
+		if packedCtx != nil {
+			cmd.writeFieldBytes(packedCtx, INDEX_CONTEXT)
+		}
+
 		cmd.writeFieldHeader(filterSize, INDEX_RANGE)
 		cmd.dataBuffer[cmd.dataOffset] = byte(len(cmd.statement.Filters))
 		cmd.dataOffset++
@@ -142,7 +195,7 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 				return err
 			}
 		}
-	} else {
+	} else if cmd.partitions == nil {
 		// Calling query with no filters is more efficiently handled by a primary index scan.
 		cmd.writeFieldHeader(2, SCAN_OPTIONS)
 		priority := byte(cmd.policy.Priority)
@@ -153,6 +206,10 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 		cmd.dataOffset++
 	}
 
+	if packedExp != nil {
+		cmd.writeFieldBytes(packedExp, FILTER_EXP)
+	}
+
 	if len(cmd.statement.BinNames) > 0 {
 		cmd.writeFieldHeader(binNameSize, QUERY_BINLIST)
 		cmd.dataBuffer[cmd.dataOffset] = byte(len(cmd.statement.BinNames))
@@ -183,6 +240,12 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 		cmd.writeFieldString(cmd.statement.functionName, UDF_FUNCTION)
 		cmd.writeFieldBytes(functionArgBuffer, UDF_ARGLIST)
 	}
+
+	for _, op := range cmd.statement.operations {
+		if err := cmd.writeOperationForOperation(op); err != nil {
+			return err
+		}
+	}
 	cmd.end()
 
 	return nil
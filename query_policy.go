@@ -17,12 +17,19 @@ package aerospike
 // QueryPolicy encapsulates parameters for policy attributes used in query operations.
 type QueryPolicy struct {
 	*MultiPolicy
+
+	// PartitionRetries determines how many times the partitions which were not
+	// completed by a node, e.g. because they were migrating, are queried again
+	// on their current master nodes. Partitions are not retried if MaxRecords is set,
+	// since the remaining partitions are then expected to be read by the next query.
+	PartitionRetries int //= 5
 }
 
 // NewQueryPolicy generates a new QueryPolicy instance with default values.
 func NewQueryPolicy() *QueryPolicy {
 	res := &QueryPolicy{
-		MultiPolicy: NewMultiPolicy(),
+		MultiPolicy:      NewMultiPolicy(),
+		PartitionRetries: 5,
 	}
 
 	// Retry policy must be one-shot for queries
@@ -38,17 +38,22 @@ func (cmd *queryRecordCommand) parseRecordResults(ifc command, receiveSize int)
 
 	for cmd.dataOffset < receiveSize {
 		if err := cmd.readBytes(int(_MSG_REMAINING_HEADER_SIZE)); err != nil {
-			cmd.recordset.Errors <- newNodeError(cmd.node, err)
+			cmd.reportError(err)
 			return false, err
 		}
 		resultCode := ResultCode(cmd.dataBuffer[5] & 0xFF)
 
+		// partitions which are done or unavailable on the node
+		if cmd.partitionDone(int(cmd.dataBuffer[3]), resultCode) {
+			continue
+		}
+
 		if resultCode != 0 {
 			if resultCode == KEY_NOT_FOUND_ERROR {
 				return false, nil
 			}
 			err := NewAerospikeError(resultCode)
-			cmd.recordset.Errors <- newNodeError(cmd.node, err)
+			cmd.reportError(err)
 			return false, err
 		}
 
@@ -66,16 +71,30 @@ func (cmd *queryRecordCommand) parseRecordResults(ifc command, receiveSize int)
 
 		key, err := cmd.parseKey(fieldCount)
 		if err != nil {
-			cmd.recordset.Errors <- newNodeError(cmd.node, err)
+			cmd.reportError(err)
 			return false, err
 		}
 
+		if cmd.partitions != nil {
+			cmd.partitions.setDigest(key, cmd.bval)
+		}
+
 		// Parse bins.
 		var bins BinMap
+		var rawBins []rawBin
+		var rawData []byte
+
+		if cmd.policy.LazyBinDecoding {
+			if rawBins, rawData, err = cmd.readRawBins(opCount); err != nil {
+				cmd.reportError(err)
+				return false, err
+			}
+			opCount = 0
+		}
 
 		for i := 0; i < opCount; i++ {
 			if err := cmd.readBytes(8); err != nil {
-				cmd.recordset.Errors <- newNodeError(cmd.node, err)
+				cmd.reportError(err)
 				return false, err
 			}
 
@@ -84,19 +103,19 @@ func (cmd *queryRecordCommand) parseRecordResults(ifc command, receiveSize int)
 			nameSize := int(cmd.dataBuffer[7])
 
 			if err := cmd.readBytes(nameSize); err != nil {
-				cmd.recordset.Errors <- newNodeError(cmd.node, err)
+				cmd.reportError(err)
 				return false, err
 			}
 			name := string(cmd.dataBuffer[:nameSize])
 
 			particleBytesSize := int((opSize - (4 + nameSize)))
 			if err = cmd.readBytes(particleBytesSize); err != nil {
-				cmd.recordset.Errors <- newNodeError(cmd.node, err)
+				cmd.reportError(err)
 				return false, err
 			}
 			value, err := bytesToParticle(particleType, cmd.dataBuffer, 0, particleBytesSize)
 			if err != nil {
-				cmd.recordset.Errors <- newNodeError(cmd.node, err)
+				cmd.reportError(err)
 				return false, err
 			}
 
@@ -108,9 +127,12 @@ func (cmd *queryRecordCommand) parseRecordResults(ifc command, receiveSize int)
 
 		// If the channel is full and it blocks, we don't want this command to
 		// block forever, or panic in case the channel is closed in the meantime.
+		rec := newRecord(cmd.node, key, bins, generation, expiration)
+		rec.rawBins, rec.rawData = rawBins, rawData
+
 		select {
 		// send back the result on the async channel
-		case cmd.recordset.Records <- newRecord(cmd.node, key, bins, generation, expiration):
+		case cmd.recordset.Records <- rec:
 		case <-cmd.recordset.cancelled:
 			return false, NewAerospikeError(SCAN_TERMINATED)
 		}
@@ -119,6 +141,15 @@ func (cmd *queryRecordCommand) parseRecordResults(ifc command, receiveSize int)
 	return true, nil
 }
 
+// reportError sends the error on the recordset. The retryable errors of
+// partition queries are reported by the caller once its retries are exhausted.
+func (cmd *queryRecordCommand) reportError(err error) {
+	if cmd.partitions != nil && isRetryableQueryError(err) {
+		return
+	}
+	cmd.recordset.Errors <- newNodeError(cmd.node, err)
+}
+
 func (cmd *queryRecordCommand) Execute() error {
 	defer cmd.recordset.signalEnd()
 	return cmd.execute(cmd)
@@ -127,6 +127,36 @@ var _ = Describe("Query operations", func() {
 		Expect(len(keys)).To(Equal(0))
 	})
 
+	It("must Query partition ranges and get all records back", func() {
+		for _, filter := range []*PartitionFilter{NewPartitionFilterByRange(0, 1024), NewPartitionFilterByRange(1024, 3072)} {
+			policy := NewQueryPolicy()
+			policy.PartitionFilter = filter
+
+			stm := NewStatement(ns, set)
+			recordset, err := client.Query(policy, stm)
+			Expect(err).ToNot(HaveOccurred())
+
+			checkResults(recordset, 0)
+			Expect(filter.IsDone()).To(BeTrue())
+		}
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must Query a range by partitions with retries disabled and get all records back", func() {
+		policy := NewQueryPolicy()
+		policy.PartitionRetries = 0
+
+		stm := NewStatement(ns, set)
+		stm.Addfilter(NewRangeFilter(bin3.Name, 0, math.MaxInt16))
+		recordset, err := client.Query(policy, stm)
+		Expect(err).ToNot(HaveOccurred())
+
+		checkResults(recordset, 0)
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
 	It("must Cancel Query abruptly", func() {
 		stm := NewStatement(ns, set)
 		recordset, err := client.Query(nil, stm)
@@ -189,6 +219,57 @@ var _ = Describe("Query operations", func() {
 		Expect(cnt).To(BeNumerically(">", 0))
 	})
 
+	It("must delete the records matching a filter expression in the background", func() {
+		stm := NewStatement(ns, set)
+		exp := ExpLess(ExpIntBin(bin3.Name), ExpIntVal(math.MaxInt16/2))
+
+		task, err := client.QueryDelete(nil, stm, exp)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(<-task.OnComplete()).ToNot(HaveOccurred())
+
+		recordset, err := client.ScanAll(nil, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		cnt := 0
+		for rec := range recordset.Records {
+			Expect(rec.Bins[bin3.Name]).To(BeNumerically(">=", math.MaxInt16/2))
+			cnt++
+		}
+		Expect(cnt).To(BeNumerically("<", keyCount))
+
+		// the statement is not modified, and still reads the records
+		recordset, err = client.Query(nil, stm)
+		Expect(err).ToNot(HaveOccurred())
+
+		read := 0
+		for res := range recordset.Results() {
+			Expect(res.Err).ToNot(HaveOccurred())
+			read++
+		}
+		Expect(read).To(Equal(cnt))
+	})
+
+	It("must delete the records matching a filter expression with DeleteWhere", func() {
+		exp := ExpLess(ExpIntBin(bin3.Name), ExpIntVal(math.MaxInt16/2))
+
+		task, err := client.DeleteWhere(nil, ns, set, nil, exp, 1000)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(<-task.OnComplete()).ToNot(HaveOccurred())
+
+		recordset, err := client.ScanAll(nil, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		cnt := 0
+		for rec := range recordset.Records {
+			Expect(rec.Bins[bin3.Name]).To(BeNumerically(">=", math.MaxInt16/2))
+			cnt++
+		}
+		Expect(cnt).To(BeNumerically("<", keyCount))
+		if !task.IsServerSide() {
+			Expect(task.Deleted()).To(Equal(keyCount - cnt))
+		}
+	})
+
 	It("must Query specific equality filters and get only relevant records back", func() {
 		// save a record with requested value
 		key, err := NewKey(ns, set, randString(50))
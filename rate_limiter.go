@@ -0,0 +1,81 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// rateLimiter is a token bucket limiting the commands sent to a node.
+// The bucket holds up to a second worth of tokens, so short bursts are allowed.
+// Commands which find the bucket empty reserve a future token and wait for it,
+// so that waiting commands are served in arrival order.
+type rateLimiter struct {
+	mutex    sync.Mutex
+	rate     float64 // tokens per second
+	tokens   float64 // may be negative when tokens are reserved
+	lastFill time.Time
+}
+
+func newRateLimiter(perSecond int) *rateLimiter {
+	return &rateLimiter{
+		rate:     float64(perSecond),
+		tokens:   float64(perSecond),
+		lastFill: time.Now(),
+	}
+}
+
+// reserve takes a token, and returns how long to wait before it is available.
+// If the token would not be available within maxWait, nothing is reserved and
+// false is returned.
+func (rl *rateLimiter) reserve(now time.Time, maxWait time.Duration) (time.Duration, bool) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	if elapsed := now.Sub(rl.lastFill); elapsed > 0 {
+		rl.tokens += elapsed.Seconds() * rl.rate
+		if rl.tokens > rl.rate {
+			rl.tokens = rl.rate
+		}
+		rl.lastFill = now
+	}
+
+	var wait time.Duration
+	if rl.tokens < 1 {
+		wait = time.Duration((1 - rl.tokens) / rl.rate * float64(time.Second))
+		if wait > maxWait {
+			return 0, false
+		}
+	}
+
+	rl.tokens--
+	return wait, true
+}
+
+// wait blocks until a token is available, or fails with ErrRateLimited if it
+// would not be available within maxWait.
+func (rl *rateLimiter) wait(maxWait time.Duration) error {
+	wait, ok := rl.reserve(time.Now(), maxWait)
+	if !ok {
+		return ErrRateLimited
+	}
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	return nil
+}
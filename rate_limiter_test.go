@@ -0,0 +1,71 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+var _ = Describe("Rate Limiter Test", func() {
+
+	It("should allow a burst of a second worth of commands", func() {
+		rl := newRateLimiter(10)
+		now := rl.lastFill
+
+		for i := 0; i < 10; i++ {
+			wait, ok := rl.reserve(now, 0)
+			Expect(ok).To(BeTrue())
+			Expect(wait).To(Equal(time.Duration(0)))
+		}
+
+		_, ok := rl.reserve(now, 0)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should queue commands up to the maximum wait", func() {
+		rl := newRateLimiter(10)
+		now := rl.lastFill
+		rl.tokens = 0
+
+		wait, ok := rl.reserve(now, time.Second)
+		Expect(ok).To(BeTrue())
+		Expect(wait).To(Equal(100 * time.Millisecond))
+
+		// the previous command reserved the next token
+		wait, ok = rl.reserve(now, time.Second)
+		Expect(ok).To(BeTrue())
+		Expect(wait).To(Equal(200 * time.Millisecond))
+
+		_, ok = rl.reserve(now, 250*time.Millisecond)
+		Expect(ok).To(BeFalse())
+
+		// tokens are refilled over time
+		wait, ok = rl.reserve(now.Add(300*time.Millisecond), 0)
+		Expect(ok).To(BeTrue())
+		Expect(wait).To(Equal(time.Duration(0)))
+	})
+
+	It("should fail with ErrRateLimited when the wait is too long", func() {
+		rl := newRateLimiter(1)
+		Expect(rl.wait(0)).ToNot(HaveOccurred())
+		Expect(rl.wait(0)).To(Equal(ErrRateLimited))
+	})
+
+})
@@ -17,7 +17,6 @@ package aerospike
 import (
 	"math"
 	"reflect"
-	"strings"
 	"time"
 
 	. "github.com/aerospike/aerospike-client-go/logger"
@@ -35,8 +34,10 @@ type readCommand struct {
 
 	// pointer to the object that's going to be unmarshalled
 	object interface{}
-	// mapping of aliases for the object fields
-	objectMappings map[string]map[string]string
+	// if true, multiple results for the same bin will be collected in a list
+	isOperation bool
+	// results of an operate command in the order they were returned
+	opValues []interface{}
 }
 
 func newReadCommand(cluster *Cluster, policy Policy, key *Key, binNames []string) *readCommand {
@@ -51,15 +52,27 @@ func (cmd *readCommand) getPolicy(ifc command) Policy {
 	return cmd.policy
 }
 
+func (cmd *readCommand) getNode(ifc command) (*Node, error) {
+	return cmd.getReadNode(cmd.policy.GetBasePolicy())
+}
+
 func (cmd *readCommand) writeBuffer(ifc command) error {
 	return cmd.setRead(cmd.policy.GetBasePolicy(), cmd.key, cmd.binNames)
 }
 
 func (cmd *readCommand) parseResult(ifc command, conn *Connection) error {
-	// Read header.
-	_, err := conn.Read(cmd.dataBuffer, int(_MSG_TOTAL_HEADER_SIZE))
+	// Read proto header.
+	_, err := conn.Read(cmd.dataBuffer, 8)
+	compressed := err == nil && isCompressed(cmd.dataBuffer)
+	if compressed {
+		// the whole decompressed message replaces the buffer
+		err = cmd.readCompressed(conn)
+	} else if err == nil {
+		// Read the rest of the header.
+		_, err = conn.Read(cmd.dataBuffer[8:], int(_MSG_TOTAL_HEADER_SIZE)-8)
+	}
 	if err != nil {
-		Logger.Warn("parse result error: " + err.Error())
+		cmd.node.cluster.logEvent(WARNING, SUBSYSTEM_COMMAND, cmd.node.GetName(), map[string]interface{}{"error": err}, "parse result error: %s", err)
 		return err
 	}
 
@@ -75,13 +88,20 @@ func (cmd *readCommand) parseResult(ifc command, conn *Connection) error {
 	receiveSize := int((sz & 0xFFFFFFFFFFFF) - int64(headerLength))
 
 	// Read remaining message bytes.
-	if receiveSize > 0 {
+	if receiveSize > 0 && compressed {
+		// the remaining bytes follow the header in the decompressed message
+		bodyOffset := int(_MSG_TOTAL_HEADER_SIZE)
+		if bodyOffset+receiveSize > len(cmd.dataBuffer) {
+			return NewAerospikeError(PARSE_ERROR, "Invalid size for compressed message body")
+		}
+		copy(cmd.dataBuffer, cmd.dataBuffer[bodyOffset:bodyOffset+receiveSize])
+	} else if receiveSize > 0 {
 		if err = cmd.sizeBufferSz(receiveSize); err != nil {
 			return err
 		}
 		_, err = conn.Read(cmd.dataBuffer, receiveSize)
 		if err != nil {
-			Logger.Warn("parse result error: " + err.Error())
+			cmd.node.cluster.logEvent(WARNING, SUBSYSTEM_COMMAND, cmd.node.GetName(), map[string]interface{}{"error": err}, "parse result error: %s", err)
 			return err
 		}
 
@@ -95,7 +115,7 @@ func (cmd *readCommand) parseResult(ifc command, conn *Connection) error {
 		if resultCode == UDF_BAD_RESPONSE {
 			cmd.record, _ = cmd.parseRecord(opCount, fieldCount, generation, expiration)
 			err := cmd.handleUdfError(resultCode)
-			Logger.Warn("UDF execution error: " + err.Error())
+			cmd.node.cluster.logEvent(WARNING, SUBSYSTEM_COMMAND, cmd.node.GetName(), map[string]interface{}{"error": err}, "UDF execution error: %s", err)
 			return err
 		}
 
@@ -114,6 +134,8 @@ func (cmd *readCommand) parseResult(ifc command, conn *Connection) error {
 			return err
 		}
 	} else {
+		// keep the record header for GetObjectWithHeader
+		cmd.record = newRecord(cmd.node, cmd.key, nil, generation, expiration)
 		cmd.parseObject(opCount, fieldCount, generation, expiration)
 	}
 
@@ -162,9 +184,37 @@ func (cmd *readCommand) parseRecord(
 		if bins == nil {
 			bins = make(BinMap, opCount)
 		}
+
+		if cmd.isOperation {
+			cmd.opValues = append(cmd.opValues, value)
+		}
+
+		// for operate commands, multiple operations on the same bin
+		// return their results in the order of the operations
+		if prev, exists := bins[name]; exists && cmd.isOperation {
+			if prevList, ok := prev.(binResults); ok {
+				bins[name] = append(prevList, value)
+			} else {
+				bins[name] = binResults{prev, value}
+			}
+			continue
+		}
 		bins[name] = value
 	}
 
+	if err := stripKeyHash(cmd.cluster, cmd.key, bins, cmd.policy.GetBasePolicy().VerifyKeyHash); err != nil {
+		return nil, err
+	}
+
+	// convert collected results to plain lists for the user
+	if cmd.isOperation {
+		for name, value := range bins {
+			if list, ok := value.(binResults); ok {
+				bins[name] = []interface{}(list)
+			}
+		}
+	}
+
 	return newRecord(cmd.node, cmd.key, bins, generation, expiration), nil
 }
 
@@ -194,8 +244,6 @@ func (cmd *readCommand) parseObject(
 
 		// map tags
 		cacheObjectTags(rv)
-
-		cmd.objectMappings = objectMappings.objectMappings //getMapping(rv.Type().Name())
 	}
 
 	for i := 0; i < opCount; i++ {
@@ -207,7 +255,7 @@ func (cmd *readCommand) parseObject(
 
 		particleBytesSize := int(opSize - (4 + nameSize))
 		value, _ := bytesToParticle(particleType, cmd.dataBuffer, receiveOffset, particleBytesSize)
-		if err := cmd.setObjectField(rv, name, value); err != nil {
+		if err := setObjectField(rv, name, value); err != nil {
 			return err
 		}
 
@@ -225,21 +273,110 @@ func (cmd *readCommand) Execute() error {
 	return cmd.execute(cmd)
 }
 
-func (cmd *readCommand) setObjectField(obj reflect.Value, fieldName string, value interface{}) error {
-	// find the name based on tag mapping
+// setObjectField sets the field of the object which the bin is mapped to.
+// Bins without a matching field are ignored.
+func setObjectField(obj reflect.Value, binName string, value interface{}) error {
+	// find the field based on tag mapping
 	iobj := reflect.Indirect(obj)
-	if name, exists := cmd.objectMappings[iobj.Type().Name()][fieldName]; exists {
-		fieldName = name
+	set := objectFieldsOf(iobj.Type())
+	idx, exists := set.bins[binName]
+	if !exists {
+		return nil
+	}
+
+	f := set.fields[idx]
+	fv, _ := objectFieldValue(iobj, f.index, true)
+	return setFieldValue(fv, f.tag, value)
+}
+
+// UnmarshalBins sets the fields of the struct pointed to by obj from the bins, with the
+// same mapping as GetObject, e.g. for the records returned by queries and scans.
+// Bins without a matching field are ignored.
+func UnmarshalBins(bins BinMap, obj interface{}) error {
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return NewAerospikeError(PARAMETER_ERROR, "UnmarshalBins requires a non-nil pointer to a struct.")
 	}
-	f := iobj.FieldByName(fieldName)
-	setValue(f, value)
 
+	for name, value := range bins {
+		if err := setObjectField(rv.Elem(), name, value); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// setStructFields sets the fields of the struct value from the map of bin values.
+func setStructFields(v reflect.Value, valMap map[interface{}]interface{}) error {
+	for _, f := range objectFieldsOf(v.Type()).fields {
+		if valMap[f.tag.name] == nil {
+			continue
+		}
+
+		fv, ok := objectFieldValue(v, f.index, true)
+		if !ok {
+			continue
+		}
+		if err := setFieldValue(fv, f.tag, valMap[f.tag.name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setFieldValue sets the struct field from the bin value, decoding
+// the msgpack blob first if the field is tagged with the msgpack option,
+// and times stored in milliseconds if tagged with the millis option.
+func setFieldValue(f reflect.Value, tag fieldTag, value interface{}) error {
+	if tag.msgpack {
+		if blob, ok := value.([]byte); ok {
+			var err error
+			if value, err = newUnpacker(blob, 0, len(blob)).unpackObject(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if tag.millis && isTimeType(f.Type()) {
+		if millis, ok := value.(int); ok {
+			value = millis * int(time.Millisecond)
+		}
+	}
+	return setValue(f, value)
+}
+
+// unmarshalValue decodes the value with the AerospikeUnmarshaler of the field, if its type
+// or a pointer to it implements it. Nil pointer fields are allocated first.
+// It returns false if the field does not implement AerospikeUnmarshaler.
+func unmarshalValue(f reflect.Value, value interface{}) (bool, error) {
+	if f.Kind() == reflect.Ptr && f.Type().Implements(unmarshalerType) {
+		if f.IsNil() {
+			f.Set(reflect.New(f.Type().Elem()))
+		}
+		return true, f.Interface().(AerospikeUnmarshaler).UnmarshalAerospike(value)
+	}
+
+	if f.CanAddr() && reflect.PtrTo(f.Type()).Implements(unmarshalerType) {
+		return true, f.Addr().Interface().(AerospikeUnmarshaler).UnmarshalAerospike(value)
+	}
+	return false, nil
+}
+
+// valueToBool converts a native boolean or a legacy integer bin value to bool.
+func valueToBool(value interface{}) bool {
+	if b, ok := value.(bool); ok {
+		return b
+	}
+	return value.(int) == 1
+}
+
 func setValue(f reflect.Value, value interface{}) error {
 	// find the name based on tag mapping
 	if f.CanSet() {
+		if ok, err := unmarshalValue(f, value); ok {
+			return err
+		}
+
 		switch f.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			f.SetInt(int64(value.(int)))
@@ -261,7 +398,7 @@ func setValue(f reflect.Value, value interface{}) error {
 			}
 			f.Set(rv)
 		case reflect.Bool:
-			f.SetBool(value.(int) == 1)
+			f.SetBool(valueToBool(value))
 		case reflect.Interface:
 			if value != nil {
 				f.Set(reflect.ValueOf(value))
@@ -353,7 +490,7 @@ func setValue(f reflect.Value, value interface{}) error {
 				}
 				f.Set(rv)
 			case reflect.Bool:
-				tempV := bool(value.(int) == 1)
+				tempV := valueToBool(value)
 				rv := reflect.ValueOf(&tempV)
 				if rv.Type() != f.Type() {
 					rv = rv.Convert(f.Type())
@@ -382,23 +519,8 @@ func setValue(f reflect.Value, value interface{}) error {
 						if f.IsNil() {
 							newObjPtr = reflect.New(f.Type().Elem())
 						}
-						theStruct := newObjPtr.Elem().Type()
-						numFields := newObjPtr.Elem().NumField()
-						for i := 0; i < numFields; i++ {
-							// skip unexported fields
-							if theStruct.Field(i).PkgPath != "" {
-								continue
-							}
-
-							alias := theStruct.Field(i).Name
-							tag := strings.Trim(theStruct.Field(i).Tag.Get(aerospikeTag), " ")
-							if tag != "" {
-								alias = tag
-							}
-
-							if valMap[alias] != nil {
-								setValue(reflect.Indirect(newObjPtr).FieldByName(alias), valMap[alias])
-							}
+						if err := setStructFields(newObjPtr.Elem(), valMap); err != nil {
+							return err
 						}
 
 						// set the field
@@ -415,7 +537,9 @@ func setValue(f reflect.Value, value interface{}) error {
 			}
 
 			for i := 0; i < theArray.Len(); i++ {
-				setValue(f.Index(i), theArray.Index(i).Interface())
+				if err := setValue(f.Index(i), theArray.Index(i).Interface()); err != nil {
+					return err
+				}
 			}
 		case reflect.Map:
 			theMap := value.(map[interface{}]interface{})
@@ -449,23 +573,8 @@ func setValue(f reflect.Value, value interface{}) error {
 
 			valMap := value.(map[interface{}]interface{})
 			// iteraste over struct fields and recursively fill them up
-			typeOfT := f.Type()
-			numFields := f.NumField()
-			for i := 0; i < numFields; i++ {
-				// skip unexported fields
-				if typeOfT.Field(i).PkgPath != "" {
-					continue
-				}
-
-				alias := typeOfT.Field(i).Name
-				tag := strings.Trim(typeOfT.Field(i).Tag.Get(aerospikeTag), " ")
-				if tag != "" {
-					alias = tag
-				}
-
-				if valMap[alias] != nil {
-					setValue(f.FieldByName(typeOfT.Field(i).Name), valMap[alias])
-				}
+			if err := setStructFields(f, valMap); err != nil {
+				return err
 			}
 
 			// set the field
@@ -39,6 +39,10 @@ func (cmd *readHeaderCommand) getPolicy(ifc command) Policy {
 	return cmd.policy
 }
 
+func (cmd *readHeaderCommand) getNode(ifc command) (*Node, error) {
+	return cmd.getReadNode(cmd.policy.GetBasePolicy())
+}
+
 func (cmd *readHeaderCommand) writeBuffer(ifc command) error {
 	return cmd.setReadHeader(cmd.policy.GetBasePolicy(), cmd.key)
 }
@@ -0,0 +1,96 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"sync"
+	"time"
+)
+
+// ReadYourWrites remembers the keys written through policies it is attached to,
+// and routes subsequent reads for those keys to the master partition for a
+// configurable window, regardless of BasePolicy.ReplicaPolicy.
+// This avoids stale reads from prole replicas right after a write.
+//
+// A ReadYourWrites instance represents a session; share it between the policies
+// used by a goroutine or a logical user session. It is safe for concurrent use.
+type ReadYourWrites struct {
+	window time.Duration
+
+	mutex     sync.Mutex
+	keys      map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewReadYourWrites creates a new session which will route reads of written keys
+// to the master partition for the specified window after the write.
+func NewReadYourWrites(window time.Duration) *ReadYourWrites {
+	return &ReadYourWrites{
+		window:    window,
+		keys:      make(map[string]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+// Window returns the duration during which reads of written keys are routed to the master.
+func (rw *ReadYourWrites) Window() time.Duration {
+	return rw.window
+}
+
+// Track marks the key as written. Reads of the key will be routed to the master
+// until the window has passed. Writes through policies with this session attached
+// are tracked automatically.
+func (rw *ReadYourWrites) Track(key *Key) {
+	if rw == nil {
+		return
+	}
+
+	now := time.Now()
+
+	rw.mutex.Lock()
+	rw.keys[string(key.Digest())] = now.Add(rw.window)
+
+	// remove expired entries once per window to keep the session bounded
+	if now.Sub(rw.lastSweep) > rw.window {
+		for digest, expiration := range rw.keys {
+			if now.After(expiration) {
+				delete(rw.keys, digest)
+			}
+		}
+		rw.lastSweep = now
+	}
+	rw.mutex.Unlock()
+}
+
+// IsRecent returns true if the key was written within the window.
+func (rw *ReadYourWrites) IsRecent(key *Key) bool {
+	if rw == nil {
+		return false
+	}
+
+	rw.mutex.Lock()
+	expiration, exists := rw.keys[string(key.Digest())]
+	rw.mutex.Unlock()
+
+	return exists && time.Now().Before(expiration)
+}
+
+// Reset forgets all tracked keys.
+func (rw *ReadYourWrites) Reset() {
+	rw.mutex.Lock()
+	rw.keys = make(map[string]time.Time)
+	rw.lastSweep = time.Now()
+	rw.mutex.Unlock()
+}
@@ -0,0 +1,82 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("Read Your Writes Test", func() {
+	initTestVars()
+
+	var ns = "test"
+	var set = randString(50)
+
+	It("must remember written keys for the window", func() {
+		session := NewReadYourWrites(50 * time.Millisecond)
+
+		key, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+		other, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		session.Track(key)
+		Expect(session.IsRecent(key)).To(BeTrue())
+		Expect(session.IsRecent(other)).To(BeFalse())
+
+		time.Sleep(100 * time.Millisecond)
+		Expect(session.IsRecent(key)).To(BeFalse())
+
+		session.Track(key)
+		session.Reset()
+		Expect(session.IsRecent(key)).To(BeFalse())
+	})
+
+	It("must read the latest write when reads are distributed across replicas", func() {
+		cpolicy := *clientPolicy
+		cpolicy.RequestProleReplicas = true
+
+		client, err := NewClientWithPolicy(&cpolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+		defer client.Close()
+
+		session := NewReadYourWrites(time.Second)
+
+		wpolicy := NewWritePolicy(0, 0)
+		wpolicy.ReadYourWrites = session
+
+		rpolicy := NewPolicy()
+		rpolicy.ReplicaPolicy = MASTER_PROLES
+		rpolicy.ReadYourWrites = session
+
+		key, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := 0; i < 10; i++ {
+			err = client.Put(wpolicy, key, BinMap{"bin": i})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(session.IsRecent(key)).To(BeTrue())
+
+			rec, err := client.Get(rpolicy, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins["bin"]).To(Equal(i))
+		}
+	})
+})
@@ -29,6 +29,8 @@ type Record struct {
 	Node *Node
 
 	// Bins is the map of requested name/value bins.
+	// For records read with MultiPolicy.LazyBinDecoding, it only holds the bins
+	// decoded by Bin or DecodeBins.
 	Bins BinMap
 
 	// Generation shows record modification count.
@@ -37,6 +39,17 @@ type Record struct {
 	// Expiration is TTL (Time-To-Live).
 	// Number of seconds until record expires.
 	Expiration int
+
+	// raw bins which are not decoded yet, and the buffer holding their particles
+	rawBins []rawBin
+	rawData []byte
+}
+
+// rawBin locates the particle of an undecoded bin in the raw data of a record.
+type rawBin struct {
+	name         string
+	particleType int
+	offset, size int
 }
 
 func newRecord(node *Node, key *Key, bins BinMap, generation int, expiration int) *Record {
@@ -56,8 +69,72 @@ func newRecord(node *Node, key *Key, bins BinMap, generation int, expiration int
 	return r
 }
 
+// Bin returns the value of the bin, or nil if the record has no such bin.
+// For records read with MultiPolicy.LazyBinDecoding, the bin is decoded on the
+// first access and kept in Bins.
+// Lazily decoded records are not safe for concurrent use.
+func (rc *Record) Bin(name string) (interface{}, error) {
+	if value, exists := rc.Bins[name]; exists {
+		return value, nil
+	}
+
+	for i := range rc.rawBins {
+		if rc.rawBins[i].name == name {
+			return rc.decodeBin(i)
+		}
+	}
+	return nil, nil
+}
+
+// DecodeBins decodes all the bins of a record read with MultiPolicy.LazyBinDecoding
+// into Bins, and releases its raw data. It does nothing for other records.
+func (rc *Record) DecodeBins() error {
+	for i := range rc.rawBins {
+		if _, exists := rc.Bins[rc.rawBins[i].name]; exists {
+			continue
+		}
+		if _, err := rc.decodeBin(i); err != nil {
+			return err
+		}
+	}
+
+	rc.rawBins = nil
+	rc.rawData = nil
+	return nil
+}
+
+func (rc *Record) decodeBin(i int) (interface{}, error) {
+	rb := &rc.rawBins[i]
+	value, err := bytesToParticle(rb.particleType, rc.rawData, rb.offset, rb.size)
+	if err != nil {
+		return nil, err
+	}
+
+	rc.Bins[rb.name] = value
+	return value, nil
+}
+
 // String implements the Stringer interface.
-// Returns string representation of record.
+// Returns string representation of record. The bins which are not decoded
+// yet are decoded into a copy of Bins, so that the record is not modified.
 func (rc *Record) String() string {
-	return fmt.Sprintf("%v %v", *rc.Key, rc.Bins)
+	if len(rc.rawBins) == 0 {
+		return fmt.Sprintf("%v %v", *rc.Key, rc.Bins)
+	}
+
+	bins := make(BinMap, len(rc.Bins)+len(rc.rawBins))
+	for name, value := range rc.Bins {
+		bins[name] = value
+	}
+	for _, rb := range rc.rawBins {
+		if _, exists := bins[rb.name]; exists {
+			continue
+		}
+		value, err := bytesToParticle(rb.particleType, rc.rawData, rb.offset, rb.size)
+		if err != nil {
+			value = fmt.Sprintf("<%s>", err)
+		}
+		bins[rb.name] = value
+	}
+	return fmt.Sprintf("%v %v", *rc.Key, bins)
 }
@@ -0,0 +1,69 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
+)
+
+var _ = Describe("Record Test", func() {
+
+	newLazyRecord := func() *Record {
+		rec := newRecord(nil, nil, nil, 1, 0)
+		rec.rawBins = []rawBin{
+			{name: "int", particleType: ParticleType.INTEGER, offset: 0, size: 8},
+			{name: "str", particleType: ParticleType.STRING, offset: 8, size: 5},
+		}
+		rec.rawData = append([]byte{0, 0, 0, 0, 0, 0, 0, 42}, "hello"...)
+		return rec
+	}
+
+	It("should decode the bins of a lazy record on access", func() {
+		rec := newLazyRecord()
+		Expect(len(rec.Bins)).To(Equal(0))
+
+		value, err := rec.Bin("str")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(Equal("hello"))
+		Expect(rec.Bins).To(Equal(BinMap{"str": "hello"}))
+
+		value, err = rec.Bin("missing")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(value).To(BeNil())
+	})
+
+	It("should decode all the bins of a lazy record", func() {
+		rec := newLazyRecord()
+
+		Expect(rec.DecodeBins()).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(BinMap{"int": 42, "str": "hello"}))
+		Expect(rec.rawData).To(BeNil())
+	})
+
+	It("should format a lazy record without decoding its bins", func() {
+		rec := newLazyRecord()
+		key, err := NewKey("test", "set", 1)
+		Expect(err).ToNot(HaveOccurred())
+		rec.Key = key
+
+		Expect(rec.String()).To(ContainSubstring("map[int:42 str:hello]"))
+		Expect(len(rec.Bins)).To(Equal(0))
+		Expect(len(rec.rawBins)).To(Equal(2))
+	})
+
+})
@@ -15,14 +15,20 @@
 package aerospike
 
 import (
+	"context"
 	"sync"
 
 	. "github.com/aerospike/aerospike-client-go/types/atomic"
 )
 
-type result struct {
+// Result is a single result of a Scan or Query returned by Recordset.Results.
+// It holds either a Record or an error.
+type Result struct {
+	// Record is the record received, if Err is nil.
 	Record *Record
-	Err    error
+
+	// Err is the error returned by a node, if any.
+	Err error
 }
 
 // Recordset encapsulates the result of Scan and Query commands.
@@ -41,6 +47,16 @@ type Recordset struct {
 
 	active    *AtomicBool
 	cancelled chan struct{}
+
+	// ctx is cancelled with the recordset, to abort the connections being opened
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// per partition statistics; only collected if requested by the scan policy
+	stats *partitionStats
+
+	// called once the recordset is closed
+	onClose func()
 }
 
 // NewRecordset generates a new RecordSet instance.
@@ -52,6 +68,7 @@ func newRecordset(recSize, goroutines int) *Recordset {
 		goroutines: NewAtomicInt(goroutines),
 		cancelled:  make(chan struct{}),
 	}
+	rs.ctx, rs.cancel = context.WithCancel(context.Background())
 	rs.wgGoroutines.Add(goroutines)
 
 	return rs
@@ -67,6 +84,7 @@ func (rcs *Recordset) IsActive() bool {
 // results back from the recordset, and doesn't require the user to write the
 // ugly select in their code.
 // Result embeds A Record and an error reference.
+// All errors sent by the nodes are delivered on the channel before it is closed.
 //
 // Example:
 //
@@ -80,29 +98,49 @@ func (rcs *Recordset) IsActive() bool {
 //     fmt.Println(res.Record.Bins)
 //   }
 // }
-func (rcs *Recordset) Results() <-chan *result {
-	res := make(chan *result, len(rcs.Records))
+func (rcs *Recordset) Results() <-chan *Result {
+	res := make(chan *Result, len(rcs.Records))
 
 	go func() {
-	L:
+		// always close the channel on return
+		defer close(res)
+
 		for {
 			select {
-			case r := <-rcs.Records:
+			case r, ok := <-rcs.Records:
+				if !ok {
+					// Errors is closed right after Records; deliver the remaining ones
+					for e := range rcs.Errors {
+						if e != nil {
+							res <- &Result{Record: nil, Err: e}
+						}
+					}
+					return
+				}
 				if r != nil {
-					res <- &result{Record: r, Err: nil}
-				} else {
-					close(res)
-					break L
+					res <- &Result{Record: r, Err: nil}
 				}
-			case e := <-rcs.Errors:
-				if e != nil {
-					res <- &result{Record: nil, Err: e}
+			case e, ok := <-rcs.Errors:
+				if ok && e != nil {
+					res <- &Result{Record: nil, Err: e}
 				}
 			}
 		}
 	}()
 
-	return (<-chan *result)(res)
+	return (<-chan *Result)(res)
+}
+
+// PartitionStats returns the number of records and bytes received from each partition
+// which returned records, in partition id order. It returns nil if the statistics were
+// not requested with ScanPolicy.CollectPartitionStats.
+// The statistics are complete once the recordset is closed, which happens after all
+// records have been received, or the scan was cancelled.
+func (rcs *Recordset) PartitionStats() []PartitionStats {
+	if rcs.stats == nil {
+		return nil
+	}
+	return rcs.stats.list()
 }
 
 // Close all streams from different nodes.
@@ -111,15 +149,36 @@ func (rcs *Recordset) Close() {
 	if rcs.active.CompareAndToggle(true) {
 		// this will broadcast to all commands listening to the channel
 		close(rcs.cancelled)
+		rcs.cancel()
 
 		// wait till all goroutines are done
 		rcs.wgGoroutines.Wait()
 
 		close(rcs.Records)
 		close(rcs.Errors)
+
+		if rcs.onClose != nil {
+			rcs.onClose()
+		}
 	}
 }
 
+// closeOnDone closes the recordset and discards the records left in it
+// when the context is done before the recordset is closed.
+func (rcs *Recordset) closeOnDone(ctx context.Context) {
+	go func() {
+		select {
+		case <-ctx.Done():
+			rcs.Close()
+
+			// unblock consumers waiting on records which are not wanted anymore
+			for range rcs.Records {
+			}
+		case <-rcs.cancelled:
+		}
+	}()
+}
+
 func (rcs *Recordset) signalEnd() {
 	rcs.wgGoroutines.Done()
 	if rcs.goroutines.DecrementAndGet() == 0 {
@@ -0,0 +1,68 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recordset Test", func() {
+
+	It("should deliver records and all node errors through Results", func() {
+		rs := newRecordset(10, 2)
+		rs.Records <- &Record{}
+		rs.Errors <- errors.New("node 1 failed")
+		rs.Errors <- errors.New("node 2 failed")
+		rs.signalEnd()
+		rs.signalEnd()
+
+		records, errs := 0, 0
+		for res := range rs.Results() {
+			if res.Err != nil {
+				errs++
+			} else {
+				Expect(res.Record).ToNot(BeNil())
+				records++
+			}
+		}
+
+		Expect(records).To(Equal(1))
+		Expect(errs).To(Equal(2))
+	})
+
+	It("should close and drain the recordset when the context is cancelled", func() {
+		rs := newRecordset(10, 1)
+		rs.Records <- &Record{}
+
+		// simulate a node goroutine stopping on cancellation
+		go func() {
+			<-rs.cancelled
+			rs.signalEnd()
+		}()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		rs.closeOnDone(ctx)
+		cancel()
+
+		// the channel is closed; the record may already have been discarded
+		for range rs.Records {
+		}
+		Expect(rs.IsActive()).To(BeFalse())
+	})
+})
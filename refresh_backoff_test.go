@@ -0,0 +1,57 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Refresh Backoff Test", func() {
+
+	var cluster *Cluster
+	var node *Node
+
+	BeforeEach(func() {
+		cluster = &Cluster{clientPolicy: ClientPolicy{TendInterval: time.Second, MaxRefreshBackoff: 10 * time.Second}}
+		node = &Node{cluster: cluster}
+	})
+
+	It("should double the delay with each consecutive failure up to the maximum", func() {
+		expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+		for i, delay := range expected {
+			Expect(cluster.backOffRefresh(node)).To(Equal(delay))
+			Expect(node.refreshFailures).To(Equal(i + 1))
+			Expect(node.skipRefreshes).To(Equal(int(delay/time.Second) - 1))
+		}
+	})
+
+	It("should not back off when disabled", func() {
+		cluster.clientPolicy.MaxRefreshBackoff = 0
+		Expect(cluster.backOffRefresh(node)).To(Equal(time.Duration(0)))
+		Expect(cluster.backOffRefresh(node)).To(Equal(time.Duration(0)))
+		Expect(node.refreshFailures).To(Equal(2))
+		Expect(node.skipRefreshes).To(Equal(0))
+	})
+
+	It("should not skip tends when the maximum is below the tend interval", func() {
+		cluster.clientPolicy.MaxRefreshBackoff = 100 * time.Millisecond
+		Expect(cluster.backOffRefresh(node)).To(Equal(100 * time.Millisecond))
+		Expect(node.skipRefreshes).To(Equal(0))
+	})
+
+})
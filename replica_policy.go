@@ -0,0 +1,39 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// ReplicaPolicy defines which partition replicas are consulted in a read operation.
+type ReplicaPolicy int
+
+const (
+	// MASTER reads from the node containing the key's master partition.
+	// This is the most recommended option since the master is always
+	// up to date with the latest writes.
+	MASTER ReplicaPolicy = iota
+
+	// MASTER_PROLES distributes reads across the nodes containing the key's
+	// master and replicated (prole) partitions in round-robin fashion.
+	// Reads from proles may return stale data while a write is being replicated.
+	// Requires ClientPolicy.RequestProleReplicas to be set; otherwise
+	// all reads go to the master.
+	// Partition scans alternate between the master and the prole of each partition.
+	MASTER_PROLES
+
+	// PROLES reads from the node containing the key's replicated (prole)
+	// partition, and from the master only if no prole is available.
+	// Use it to shift heavy scan IO off the masters serving latency-sensitive
+	// traffic. Requires ClientPolicy.RequestProleReplicas to be set.
+	PROLES
+)
@@ -50,7 +50,7 @@ func (cmd *scanCommand) getPolicy(ifc command) Policy {
 }
 
 func (cmd *scanCommand) writeBuffer(ifc command) error {
-	return cmd.setScan(cmd.policy, &cmd.namespace, &cmd.setName, cmd.binNames)
+	return cmd.setScan(cmd.policy, &cmd.namespace, &cmd.setName, cmd.binNames, cmd.partitions)
 }
 
 func (cmd *scanCommand) parseRecordResults(ifc command, receiveSize int) (bool, error) {
@@ -58,12 +58,18 @@ func (cmd *scanCommand) parseRecordResults(ifc command, receiveSize int) (bool,
 	cmd.dataOffset = 0
 
 	for cmd.dataOffset < receiveSize {
+		recordOffset := cmd.dataOffset
 		if err := cmd.readBytes(int(_MSG_REMAINING_HEADER_SIZE)); err != nil {
 			cmd.recordset.Errors <- newNodeError(cmd.node, err)
 			return false, err
 		}
 		resultCode := ResultCode(cmd.dataBuffer[5] & 0xFF)
 
+		// partitions which are done or unavailable on the node
+		if cmd.partitionDone(int(cmd.dataBuffer[3]), resultCode) {
+			continue
+		}
+
 		if resultCode != 0 {
 			if resultCode == KEY_NOT_FOUND_ERROR {
 				return false, nil
@@ -91,8 +97,22 @@ func (cmd *scanCommand) parseRecordResults(ifc command, receiveSize int) (bool,
 			return false, err
 		}
 
+		if cmd.partitions != nil {
+			cmd.partitions.setDigest(key, cmd.bval)
+		}
+
 		// Parse bins.
 		var bins BinMap
+		var rawBins []rawBin
+		var rawData []byte
+
+		if cmd.policy.LazyBinDecoding {
+			if rawBins, rawData, err = cmd.readRawBins(opCount); err != nil {
+				cmd.recordset.Errors <- newNodeError(cmd.node, err)
+				return false, err
+			}
+			opCount = 0
+		}
 
 		for i := 0; i < opCount; i++ {
 			if err := cmd.readBytes(8); err != nil {
@@ -128,11 +148,18 @@ func (cmd *scanCommand) parseRecordResults(ifc command, receiveSize int) (bool,
 			bins[name] = value
 		}
 
+		if cmd.recordset.stats != nil {
+			cmd.recordset.stats.add(key, cmd.dataOffset-recordOffset)
+		}
+
 		// If the channel is full and it blocks, we don't want this command to
 		// block forever, or panic in case the channel is closed in the meantime.
+		rec := newRecord(cmd.node, key, bins, generation, expiration)
+		rec.rawBins, rec.rawData = rawBins, rawData
+
 		select {
 		// send back the result on the async channel
-		case cmd.recordset.Records <- newRecord(cmd.node, key, bins, generation, expiration):
+		case cmd.recordset.Records <- rec:
 		case <-cmd.recordset.cancelled:
 			return false, NewAerospikeError(SCAN_TERMINATED)
 		}
@@ -31,6 +31,22 @@ type ScanPolicy struct {
 
 	// FailOnClusterChange determines scan termination if cluster is in fluctuating state.
 	FailOnClusterChange bool
+
+	// RecordsPerSecond limits the number of records returned per second by each node.
+	// Use it to keep background scans from starving latency-sensitive traffic.
+	// It replaces the coarse BasePolicy.Priority, which newer servers ignore.
+	// Default (0) is no limit. Requires server version 4.7+.
+	RecordsPerSecond int
+
+	// CollectPartitionStats determines if the number of records and bytes received
+	// from each partition are counted. Use Recordset.PartitionStats to retrieve them
+	// after the scan is complete, e.g. to detect data skew and hot partitions.
+	CollectPartitionStats bool //= false
+
+	// CallbackWorkers determines the number of goroutines which invoke the
+	// callback of Client.ScanAllFunc concurrently.
+	// Default is 1, meaning records are processed one at a time.
+	CallbackWorkers int //= 1
 }
 
 // NewScanPolicy creates a new ScanPolicy instance with default values.
@@ -41,6 +57,7 @@ func NewScanPolicy() *ScanPolicy {
 		ConcurrentNodes:     true,
 		IncludeBinData:      true,
 		FailOnClusterChange: true,
+		CallbackWorkers:     1,
 	}
 	// Retry policy must be one-shot for scans.
 	res.MaxRetries = 0
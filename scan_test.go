@@ -15,10 +15,15 @@
 package aerospike_test
 
 import (
+	"context"
+	"errors"
 	"math"
 	"math/rand"
+	"sync"
+	"time"
 
 	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -157,6 +162,127 @@ var _ = Describe("Scan operations", func() {
 		Expect(len(keys)).To(Equal(0))
 	})
 
+	It("must Scan and decode the bins of the records lazily", func() {
+		scanPolicy := NewScanPolicy()
+		scanPolicy.LazyBinDecoding = true
+
+		recordset, err := client.ScanAll(scanPolicy, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		for res := range recordset.Results() {
+			Expect(res.Err).NotTo(HaveOccurred())
+			Expect(len(res.Record.Bins)).To(Equal(0))
+
+			value, err := res.Record.Bin(bin1.Name)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(value).To(Equal(bin1.Value.GetObject()))
+			Expect(res.Record.Bins).To(Equal(BinMap{bin1.Name: bin1.Value.GetObject()}))
+
+			Expect(res.Record.DecodeBins()).ToNot(HaveOccurred())
+			Expect(res.Record.Bins[bin2.Name]).To(Equal(bin2.Value.GetObject()))
+
+			delete(keys, string(res.Record.Key.Digest()))
+		}
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must Scan with throttling and get all records back", func() {
+		Expect(len(keys)).To(Equal(keyCount))
+
+		scanPolicy := NewScanPolicy()
+		scanPolicy.RecordsPerSecond = keyCount / 2
+
+		start := time.Now()
+		recordset, err := client.ScanAll(scanPolicy, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		checkResults(recordset, 0)
+
+		Expect(len(keys)).To(Equal(0))
+		Expect(time.Since(start)).To(BeNumerically(">=", time.Second/time.Duration(len(client.GetNodes()))))
+	})
+
+	It("must Scan and collect per partition statistics", func() {
+		Expect(len(keys)).To(Equal(keyCount))
+
+		scanPolicy := NewScanPolicy()
+		scanPolicy.CollectPartitionStats = true
+
+		recordset, err := client.ScanAll(scanPolicy, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		checkResults(recordset, 0)
+		Expect(len(keys)).To(Equal(0))
+
+		var records int64
+		lastPartitionId := -1
+		for _, stats := range recordset.PartitionStats() {
+			Expect(stats.PartitionId).To(BeNumerically(">", lastPartitionId))
+			Expect(stats.Bytes).To(BeNumerically(">", stats.Records*100))
+			lastPartitionId = stats.PartitionId
+			records += stats.Records
+		}
+		Expect(records).To(Equal(int64(keyCount)))
+	})
+
+	It("must cancel a Scan when its context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		recordset, err := client.ScanAllContext(ctx, nil, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		cnt := 0
+		for range recordset.Records {
+			cnt++
+			if cnt == 10 {
+				cancel()
+			}
+		}
+		Expect(cnt).To(BeNumerically("<", keyCount))
+		Expect(recordset.IsActive()).To(BeFalse())
+
+		_, err = client.ScanAllContext(ctx, nil, ns, set)
+		Expect(err).To(Equal(context.Canceled))
+	})
+
+	It("must Scan with a callback from multiple workers", func() {
+		Expect(len(keys)).To(Equal(keyCount))
+
+		scanPolicy := NewScanPolicy()
+		scanPolicy.CallbackWorkers = 4
+
+		var mutex sync.Mutex
+		err := client.ScanAllFunc(scanPolicy, ns, set, func(rec *Record) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			_, exists := keys[string(rec.Key.Digest())]
+			Expect(exists).To(BeTrue())
+			delete(keys, string(rec.Key.Digest()))
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must abort a callback Scan when the callback returns an error", func() {
+		Expect(len(keys)).To(Equal(keyCount))
+
+		errStop := errors.New("stop")
+		counter := 0
+		err := client.ScanAllFunc(nil, ns, set, func(rec *Record) error {
+			counter++
+			if counter == keyCount/2 {
+				return errStop
+			}
+			return nil
+		})
+		Expect(err).To(Equal(errStop))
+		Expect(counter).To(Equal(keyCount / 2))
+	})
+
 	It("must Cancel Scan", func() {
 		Expect(len(keys)).To(Equal(keyCount))
 
@@ -168,4 +294,136 @@ var _ = Describe("Scan operations", func() {
 		Expect(len(keys)).To(BeNumerically("<=", keyCount/2))
 	})
 
+	It("must Scan partition ranges in parallel and get all records back", func() {
+		Expect(len(keys)).To(Equal(keyCount))
+
+		filters := []*PartitionFilter{
+			NewPartitionFilterByRange(0, 2048),
+			NewPartitionFilterByRange(2048, 2048),
+		}
+
+		for _, filter := range filters {
+			scanPolicy := NewScanPolicy()
+			scanPolicy.PartitionFilter = filter
+
+			recordset, err := client.ScanAll(scanPolicy, ns, set)
+			Expect(err).ToNot(HaveOccurred())
+
+			checkResults(recordset, 0)
+			Expect(filter.IsDone()).To(BeTrue())
+		}
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must paginate a partition Scan with MaxRecords", func() {
+		Expect(len(keys)).To(Equal(keyCount))
+
+		scanPolicy := NewScanPolicy()
+		scanPolicy.PartitionFilter = NewPartitionFilterAll()
+		scanPolicy.MaxRecords = keyCount / 4
+
+		for pages := 0; !scanPolicy.PartitionFilter.IsDone(); pages++ {
+			Expect(pages).To(BeNumerically("<=", keyCount))
+
+			recordset, err := client.ScanAll(scanPolicy, ns, set)
+			Expect(err).ToNot(HaveOccurred())
+
+			counter := 0
+			for res := range recordset.Results() {
+				Expect(res.Err).NotTo(HaveOccurred())
+				delete(keys, string(res.Record.Key.Digest()))
+				counter++
+			}
+			Expect(counter).To(BeNumerically("<=", keyCount/4))
+		}
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must Scan only the records updated since a point in time", func() {
+		// server clocks have millisecond resolution
+		time.Sleep(10 * time.Millisecond)
+		since := time.Now()
+		time.Sleep(10 * time.Millisecond)
+
+		updated := map[string]bool{}
+		for _, key := range keys {
+			err := client.PutBins(wpolicy, key, bin1, bin2)
+			Expect(err).ToNot(HaveOccurred())
+			updated[string(key.Digest())] = true
+			if len(updated) == 10 {
+				break
+			}
+		}
+
+		recordset, err := client.IncrementalScan(nil, ns, set, since)
+		Expect(err).ToNot(HaveOccurred())
+
+		cnt := 0
+		for rec := range recordset.Records {
+			Expect(updated[string(rec.Key.Digest())]).To(BeTrue())
+			cnt++
+		}
+		Expect(cnt).To(Equal(len(updated)))
+	})
+
+	It("must limit the number of concurrent Scans", func() {
+		cpolicy := *clientPolicy
+		cpolicy.MaxConcurrentScans = 1
+		limitedClient, err := NewClientWithPolicy(&cpolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+		defer limitedClient.Close()
+
+		recordset, err := limitedClient.ScanAll(nil, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = limitedClient.ScanAll(nil, ns, set)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(COMMAND_REJECTED))
+
+		recordset.Close()
+
+		recordset, err = limitedClient.ScanAll(nil, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+		checkResults(recordset, 0)
+	})
+
+	It("must return a parameter error for invalid partition ranges", func() {
+		scanPolicy := NewScanPolicy()
+		scanPolicy.PartitionFilter = NewPartitionFilterByRange(4000, 100)
+
+		_, err := client.ScanAll(scanPolicy, ns, set)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("must Scan and return the stored integer user keys with their original type", func() {
+		intSet := randString(50)
+		intKeys := make(map[string]*Key, 100)
+		for i := 0; i < 100; i++ {
+			key, err := NewKey(ns, intSet, i)
+			Expect(err).ToNot(HaveOccurred())
+
+			intKeys[string(key.Digest())] = key
+			err = client.PutBins(wpolicy, key, bin1)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		recordset, err := client.ScanAll(nil, ns, intSet)
+		Expect(err).ToNot(HaveOccurred())
+
+		for res := range recordset.Results() {
+			Expect(res.Err).NotTo(HaveOccurred())
+			key, exists := intKeys[string(res.Record.Key.Digest())]
+
+			Expect(exists).To(Equal(true))
+			Expect(res.Record.Key.Value()).NotTo(BeNil())
+			Expect(res.Record.Key.Value().GetObject()).To(Equal(key.Value().GetObject()))
+
+			delete(intKeys, string(res.Record.Key.Digest()))
+		}
+
+		Expect(len(intKeys)).To(Equal(0))
+	})
+
 })
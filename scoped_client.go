@@ -0,0 +1,162 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// ScopedNamespace is a handle of the client bound to a namespace.
+// It is created with Client.Namespace.
+type ScopedNamespace struct {
+	client    *Client
+	namespace string
+}
+
+// Namespace returns a handle of the client bound to the namespace.
+// Use its Set method to bind it to a set as well:
+//
+//	users := client.Namespace("test").Set("users")
+//	rec, err := users.Get(nil, "user-1")
+func (clnt *Client) Namespace(namespace string) *ScopedNamespace {
+	return &ScopedNamespace{client: clnt, namespace: namespace}
+}
+
+// Name returns the namespace the handle is bound to.
+func (sn *ScopedNamespace) Name() string {
+	return sn.namespace
+}
+
+// Set returns a handle of the client bound to the set of the namespace.
+// An empty set name binds the handle to the records without a set.
+func (sn *ScopedNamespace) Set(setName string) *ScopedSet {
+	return &ScopedSet{client: sn.client, namespace: sn.namespace, setName: setName}
+}
+
+// ScopedSet is a handle of the client bound to a namespace and a set.
+// Its commands take the user key only, and are executed by the client with
+// the key of the namespace and set; the policies are used as by the client.
+// It is created with ScopedNamespace.Set.
+type ScopedSet struct {
+	client    *Client
+	namespace string
+	setName   string
+}
+
+// Namespace returns the namespace the handle is bound to.
+func (ss *ScopedSet) Namespace() string {
+	return ss.namespace
+}
+
+// SetName returns the set the handle is bound to.
+func (ss *ScopedSet) SetName() string {
+	return ss.setName
+}
+
+// Key returns the key of the user key in the namespace and set of the handle.
+func (ss *ScopedSet) Key(userKey interface{}) (*Key, error) {
+	return NewKey(ss.namespace, ss.setName, userKey)
+}
+
+// Get reads the record of the user key like Client.Get.
+func (ss *ScopedSet) Get(policy *BasePolicy, userKey interface{}, binNames ...string) (*Record, error) {
+	key, err := ss.Key(userKey)
+	if err != nil {
+		return nil, err
+	}
+	return ss.client.Get(policy, key, binNames...)
+}
+
+// GetHeader reads the generation and expiration of the record of the user key like Client.GetHeader.
+func (ss *ScopedSet) GetHeader(policy *BasePolicy, userKey interface{}) (*Record, error) {
+	key, err := ss.Key(userKey)
+	if err != nil {
+		return nil, err
+	}
+	return ss.client.GetHeader(policy, key)
+}
+
+// GetObject reads the record of the user key into the object like Client.GetObject.
+func (ss *ScopedSet) GetObject(policy *BasePolicy, userKey interface{}, obj interface{}) error {
+	key, err := ss.Key(userKey)
+	if err != nil {
+		return err
+	}
+	return ss.client.GetObject(policy, key, obj)
+}
+
+// Exists determines if the record of the user key exists like Client.Exists.
+func (ss *ScopedSet) Exists(policy *BasePolicy, userKey interface{}) (bool, error) {
+	key, err := ss.Key(userKey)
+	if err != nil {
+		return false, err
+	}
+	return ss.client.Exists(policy, key)
+}
+
+// Put writes the bins of the record of the user key like Client.Put.
+func (ss *ScopedSet) Put(policy *WritePolicy, userKey interface{}, binMap BinMap) error {
+	key, err := ss.Key(userKey)
+	if err != nil {
+		return err
+	}
+	return ss.client.Put(policy, key, binMap)
+}
+
+// PutBins writes the bins of the record of the user key like Client.PutBins.
+func (ss *ScopedSet) PutBins(policy *WritePolicy, userKey interface{}, bins ...*Bin) error {
+	key, err := ss.Key(userKey)
+	if err != nil {
+		return err
+	}
+	return ss.client.PutBins(policy, key, bins...)
+}
+
+// PutObject writes the object as the record of the user key like Client.PutObject.
+func (ss *ScopedSet) PutObject(policy *WritePolicy, userKey interface{}, obj interface{}) error {
+	key, err := ss.Key(userKey)
+	if err != nil {
+		return err
+	}
+	return ss.client.PutObject(policy, key, obj)
+}
+
+// Delete deletes the record of the user key like Client.Delete.
+func (ss *ScopedSet) Delete(policy *WritePolicy, userKey interface{}) (bool, error) {
+	key, err := ss.Key(userKey)
+	if err != nil {
+		return false, err
+	}
+	return ss.client.Delete(policy, key)
+}
+
+// Touch resets the expiration of the record of the user key like Client.Touch.
+func (ss *ScopedSet) Touch(policy *WritePolicy, userKey interface{}) error {
+	key, err := ss.Key(userKey)
+	if err != nil {
+		return err
+	}
+	return ss.client.Touch(policy, key)
+}
+
+// Operate performs the operations on the record of the user key like Client.Operate.
+func (ss *ScopedSet) Operate(policy *WritePolicy, userKey interface{}, operations ...*Operation) (*Record, error) {
+	key, err := ss.Key(userKey)
+	if err != nil {
+		return nil, err
+	}
+	return ss.client.Operate(policy, key, operations...)
+}
+
+// ScanAll reads all the records of the set like Client.ScanAll.
+func (ss *ScopedSet) ScanAll(policy *ScanPolicy, binNames ...string) (*Recordset, error) {
+	return ss.client.ScanAll(policy, ss.namespace, ss.setName, binNames...)
+}
@@ -0,0 +1,79 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("Scoped Client Test", func() {
+	initTestVars()
+
+	var ns = "test"
+	var client *Client
+	var err error
+
+	BeforeEach(func() {
+		client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("must execute the commands in the namespace and set of the handle", func() {
+		set := randString(50)
+		users := client.Namespace(ns).Set(set)
+		Expect(users.Namespace()).To(Equal(ns))
+		Expect(users.SetName()).To(Equal(set))
+
+		userKey := randString(50)
+		err = users.Put(nil, userKey, BinMap{"name": "Alice"})
+		Expect(err).ToNot(HaveOccurred())
+
+		// the record is stored with the key of the namespace and set
+		key, err := NewKey(ns, set, userKey)
+		Expect(err).ToNot(HaveOccurred())
+		rec, err := client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(BinMap{"name": "Alice"}))
+
+		rec, err = users.Get(nil, userKey, "name")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(BinMap{"name": "Alice"}))
+
+		// other sets are not affected
+		exists, err := client.Namespace(ns).Set(randString(50)).Exists(nil, userKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+
+		existed, err := users.Delete(nil, userKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(existed).To(BeTrue())
+
+		exists, err = users.Exists(nil, userKey)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("must reject invalid user keys", func() {
+		_, err := client.Namespace(ns).Set(randString(50)).Get(nil, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,239 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sessions stores web sessions in Aerospike with sliding expiration.
+//
+// Session data is any struct supported by Client.PutObject. Each session is a
+// record keyed by a random session id, which expires when it has not been
+// refreshed for the store's TTL. Loads refresh the expiration according to the
+// store's TouchPolicy, and saves are checked against the generation of the
+// loaded session, so that concurrent requests cannot silently overwrite each
+// other's changes.
+package sessions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// TouchPolicy determines when loading a session refreshes its expiration.
+type TouchPolicy int
+
+const (
+	// TOUCH_NEVER does not refresh sessions on load; call Refresh explicitly.
+	TOUCH_NEVER TouchPolicy = iota
+
+	// TOUCH_ALWAYS refreshes the session on every load.
+	TOUCH_ALWAYS
+
+	// TOUCH_WHEN_HALF_EXPIRED refreshes the session on load once half of its TTL
+	// has elapsed, which saves most writes while keeping the expiration sliding.
+	TOUCH_WHEN_HALF_EXPIRED
+)
+
+// ErrSessionNotFound is returned when a session does not exist or has expired.
+var ErrSessionNotFound = NewAerospikeError(KEY_NOT_FOUND_ERROR, "Session not found")
+
+// ErrSessionConflict is returned by Save when the session was modified or
+// refreshed since it was loaded. Load it again and reapply the changes.
+var ErrSessionConflict = NewAerospikeError(GENERATION_ERROR, "Session was modified concurrently")
+
+// Session identifies a stored session, and the version of it which was loaded.
+type Session struct {
+	// ID is the session id, to be sent to the client, e.g. in a cookie.
+	ID string
+
+	// Generation is the generation of the session record when it was loaded or saved.
+	Generation int
+
+	// Expiration is the time left until the session expires.
+	Expiration time.Duration
+}
+
+// Store creates, loads and saves the sessions of a set.
+type Store struct {
+	client *Client
+
+	// Namespace and SetName locate the session records.
+	Namespace string
+	SetName   string
+
+	// TTL is how long a session lives without being refreshed.
+	TTL time.Duration
+
+	// TouchPolicy determines when Load refreshes the expiration of a session.
+	TouchPolicy TouchPolicy //= TOUCH_WHEN_HALF_EXPIRED
+
+	// IDLength is the number of random bytes of generated session ids.
+	// Session ids are hex encoded, and twice as long.
+	IDLength int //= 16
+}
+
+// NewStore generates a session Store keeping the sessions of the set alive
+// for ttl after their last refresh.
+func NewStore(client *Client, namespace string, setName string, ttl time.Duration) *Store {
+	return &Store{
+		client:      client,
+		Namespace:   namespace,
+		SetName:     setName,
+		TTL:         ttl,
+		TouchPolicy: TOUCH_WHEN_HALF_EXPIRED,
+		IDLength:    16,
+	}
+}
+
+// Create stores the session data under a new random session id.
+func (st *Store) Create(data interface{}) (*Session, error) {
+	idBytes := make([]byte, st.IDLength)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(idBytes)
+
+	key, err := NewKey(st.Namespace, st.SetName, id)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := st.writePolicy()
+	policy.RecordExistsAction = CREATE_ONLY
+	if err := st.client.PutObject(policy, key, data); err != nil {
+		return nil, err
+	}
+
+	return &Session{ID: id, Generation: 1, Expiration: st.TTL}, nil
+}
+
+// Load reads the session data into the provided object, and refreshes the
+// session's expiration according to the TouchPolicy.
+// ErrSessionNotFound is returned if the session does not exist or has expired.
+func (st *Store) Load(id string, data interface{}) (*Session, error) {
+	key, err := NewKey(st.Namespace, st.SetName, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := st.client.GetObjectWithHeader(nil, key, data)
+	if err != nil {
+		if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == KEY_NOT_FOUND_ERROR {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	session := &Session{
+		ID:         id,
+		Generation: rec.Generation,
+		Expiration: time.Duration(rec.Expiration) * time.Second,
+	}
+
+	if st.shouldTouch(session.Expiration) {
+		// only refresh the version just read, so that the generation stays in step
+		policy := st.writePolicy()
+		policy.GenerationPolicy = EXPECT_GEN_EQUAL
+		policy.Generation = int32(session.Generation)
+
+		err := st.client.Touch(policy, key)
+		if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == GENERATION_ERROR {
+			// modified concurrently; Save will report the conflict
+			return session, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		session.Generation++
+		session.Expiration = st.TTL
+	}
+
+	return session, nil
+}
+
+// Save replaces the session data, if the session was not modified or refreshed
+// since it was loaded; otherwise ErrSessionConflict is returned.
+// Saving refreshes the session's expiration.
+func (st *Store) Save(session *Session, data interface{}) error {
+	key, err := NewKey(st.Namespace, st.SetName, session.ID)
+	if err != nil {
+		return err
+	}
+
+	policy := st.writePolicy()
+	policy.RecordExistsAction = REPLACE_ONLY
+	policy.GenerationPolicy = EXPECT_GEN_EQUAL
+	policy.Generation = int32(session.Generation)
+
+	if err := st.client.PutObject(policy, key, data); err != nil {
+		if ae, ok := err.(AerospikeError); ok {
+			switch ae.ResultCode() {
+			case GENERATION_ERROR:
+				return ErrSessionConflict
+			case KEY_NOT_FOUND_ERROR:
+				return ErrSessionNotFound
+			}
+		}
+		return err
+	}
+
+	session.Generation++
+	session.Expiration = st.TTL
+	return nil
+}
+
+// Refresh resets the expiration of the session to the store's TTL, without
+// reading or checking its data.
+// ErrSessionNotFound is returned if the session does not exist or has expired.
+func (st *Store) Refresh(id string) error {
+	key, err := NewKey(st.Namespace, st.SetName, id)
+	if err != nil {
+		return err
+	}
+
+	err = st.client.Touch(st.writePolicy(), key)
+	if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == KEY_NOT_FOUND_ERROR {
+		return ErrSessionNotFound
+	}
+	return err
+}
+
+// Destroy removes the session. Destroying a missing session is not an error.
+func (st *Store) Destroy(id string) error {
+	key, err := NewKey(st.Namespace, st.SetName, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = st.client.Delete(nil, key)
+	return err
+}
+
+// writePolicy returns a new write policy setting the session TTL.
+func (st *Store) writePolicy() *WritePolicy {
+	return NewWritePolicy(0, int32(st.TTL/time.Second))
+}
+
+// shouldTouch tells if a session with the expiration left is refreshed on load.
+func (st *Store) shouldTouch(expiration time.Duration) bool {
+	switch st.TouchPolicy {
+	case TOUCH_ALWAYS:
+		return true
+	case TOUCH_WHEN_HALF_EXPIRED:
+		return expiration < st.TTL/2
+	default:
+		return false
+	}
+}
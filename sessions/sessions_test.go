@@ -0,0 +1,124 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/sessions"
+)
+
+var host = flag.String("h", "127.0.0.1", "Aerospike server seed hostnames or IP addresses")
+var port = flag.Int("p", 3000, "Aerospike server seed hostname or IP address port number.")
+
+func TestSessions(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Aerospike Client Library Sessions Suite")
+}
+
+type cart struct {
+	User  string
+	Items int
+}
+
+var _ = Describe("Sessions Test", func() {
+
+	var client *Client
+	var store *Store
+	var err error
+
+	BeforeEach(func() {
+		flag.Parse()
+		client, err = NewClient(*host, *port)
+		Expect(err).ToNot(HaveOccurred())
+		store = NewStore(client, "test", "sessions", time.Hour)
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("must create, load, save and destroy sessions", func() {
+		session, err := store.Create(&cart{User: "alice"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(session.ID)).To(Equal(32))
+
+		var c cart
+		loaded, err := store.Load(session.ID, &c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c).To(Equal(cart{User: "alice"}))
+		Expect(loaded.Generation).To(Equal(session.Generation))
+
+		c.Items = 3
+		err = store.Save(loaded, &c)
+		Expect(err).ToNot(HaveOccurred())
+
+		var saved cart
+		_, err = store.Load(session.ID, &saved)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(saved.Items).To(Equal(3))
+
+		err = store.Refresh(session.ID)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = store.Destroy(session.ID)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = store.Load(session.ID, &c)
+		Expect(err).To(Equal(ErrSessionNotFound))
+		Expect(store.Refresh(session.ID)).To(Equal(ErrSessionNotFound))
+	})
+
+	It("must reject saving a stale session", func() {
+		session, err := store.Create(&cart{User: "bob"})
+		Expect(err).ToNot(HaveOccurred())
+
+		var first, second cart
+		s1, err := store.Load(session.ID, &first)
+		Expect(err).ToNot(HaveOccurred())
+		s2, err := store.Load(session.ID, &second)
+		Expect(err).ToNot(HaveOccurred())
+
+		first.Items = 1
+		Expect(store.Save(s1, &first)).ToNot(HaveOccurred())
+
+		second.Items = 2
+		Expect(store.Save(s2, &second)).To(Equal(ErrSessionConflict))
+	})
+
+	It("must refresh sessions on load according to the touch policy", func() {
+		store.TTL = 10 * time.Second
+		store.TouchPolicy = TOUCH_ALWAYS
+
+		session, err := store.Create(&cart{User: "carol"})
+		Expect(err).ToNot(HaveOccurred())
+
+		var c cart
+		loaded, err := store.Load(session.ID, &c)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(loaded.Generation).To(Equal(session.Generation + 1))
+		Expect(loaded.Expiration).To(Equal(10 * time.Second))
+
+		// the refreshed session can still be saved
+		Expect(store.Save(loaded, &c)).ToNot(HaveOccurred())
+	})
+
+})
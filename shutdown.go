@@ -0,0 +1,103 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// interval between the checks of the commands in progress while draining
+const drainCheckInterval = 10 * time.Millisecond
+
+// CloseGracefully waits until the commands in progress have finished or the
+// timeout has expired, and closes the client. A timeout of zero or less closes
+// the client at once, like Close.
+// Commands started while draining are waited for as well, so the application
+// should stop issuing new ones first.
+// It returns a TIMEOUT error if commands were still in progress when the client
+// was closed.
+func (clnt *Client) CloseGracefully(timeout time.Duration) error {
+	defer clnt.Close()
+
+	deadline := time.Now().Add(timeout)
+	for clnt.cluster.commandsInProgress.Get() > 0 {
+		if !time.Now().Before(deadline) {
+			return NewAerospikeError(TIMEOUT, "Client was closed with commands in progress.")
+		}
+		time.Sleep(drainCheckInterval)
+	}
+	return nil
+}
+
+// ShutdownHook closes a client gracefully when the process receives a signal.
+// It is returned by Client.CloseOnSignal.
+type ShutdownHook struct {
+	signals  chan os.Signal
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan error
+}
+
+// CloseOnSignal registers for the signals, SIGINT and SIGTERM if none are
+// passed, and closes the client with CloseGracefully and the timeout when the
+// first of them is received.
+// The signals are not relayed to the default handlers anymore, so the
+// application must wait on Done and exit by itself.
+func (clnt *Client) CloseOnSignal(timeout time.Duration, signals ...os.Signal) *ShutdownHook {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+
+	hook := &ShutdownHook{
+		signals: make(chan os.Signal, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan error, 1),
+	}
+	signal.Notify(hook.signals, signals...)
+
+	go func() {
+		defer close(hook.done)
+		defer signal.Stop(hook.signals)
+
+		select {
+		case <-hook.signals:
+			hook.done <- clnt.CloseGracefully(timeout)
+		case <-hook.stop:
+		}
+	}()
+
+	return hook
+}
+
+// Done returns a channel which receives the result of CloseGracefully once the
+// client was closed on a signal. The channel is closed without a value if the
+// hook was stopped first.
+func (hook *ShutdownHook) Done() <-chan error {
+	return hook.done
+}
+
+// Stop unregisters the signals without closing the client.
+// It has no effect if the client is being closed already.
+func (hook *ShutdownHook) Stop() {
+	hook.stopOnce.Do(func() {
+		close(hook.stop)
+	})
+}
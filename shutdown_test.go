@@ -0,0 +1,83 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+var _ = Describe("Shutdown Test", func() {
+
+	var client *Client
+
+	BeforeEach(func() {
+		client = &Client{cluster: &Cluster{tendChannel: make(chan struct{})}}
+	})
+
+	It("should close at once without commands in progress", func() {
+		Expect(client.CloseGracefully(time.Second)).ToNot(HaveOccurred())
+	})
+
+	It("should wait for the commands in progress", func() {
+		client.cluster.commandsInProgress.IncrementAndGet()
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			client.cluster.commandsInProgress.DecrementAndGet()
+		}()
+
+		begin := time.Now()
+		Expect(client.CloseGracefully(time.Second)).ToNot(HaveOccurred())
+		Expect(time.Since(begin) >= 50*time.Millisecond).To(BeTrue())
+	})
+
+	It("should close with a timeout error after the deadline", func() {
+		client.cluster.commandsInProgress.IncrementAndGet()
+
+		err := client.CloseGracefully(20 * time.Millisecond)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(TIMEOUT))
+	})
+
+	It("should close the client on a signal", func() {
+		hook := client.CloseOnSignal(time.Second, os.Interrupt)
+
+		p, err := os.FindProcess(os.Getpid())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.Signal(os.Interrupt)).ToNot(HaveOccurred())
+
+		select {
+		case err := <-hook.Done():
+			Expect(err).ToNot(HaveOccurred())
+		case <-time.After(time.Second):
+			Fail("client was not closed on the signal")
+		}
+	})
+
+	It("should not close the client once stopped", func() {
+		hook := client.CloseOnSignal(time.Second)
+		hook.Stop()
+		hook.Stop()
+
+		_, ok := <-hook.Done()
+		Expect(ok).To(BeFalse())
+	})
+
+})
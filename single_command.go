@@ -35,10 +35,24 @@ func newSingleCommand(cluster *Cluster, key *Key) *singleCommand {
 	}
 }
 
+// commandKey returns the key of the command.
+func (cmd *singleCommand) commandKey() *Key {
+	return cmd.key
+}
+
 func (cmd *singleCommand) getNode(ifc command) (*Node, error) {
 	return cmd.cluster.GetNode(cmd.partition)
 }
 
+// getReadNode returns the node to read the record from according to the policy.
+// Recently written keys are always read from the master.
+func (cmd *singleCommand) getReadNode(policy *BasePolicy) (*Node, error) {
+	if policy.ReplicaPolicy == MASTER || policy.ReadYourWrites.IsRecent(cmd.key) {
+		return cmd.cluster.GetNode(cmd.partition)
+	}
+	return cmd.cluster.getReadNode(cmd.partition, policy.ReplicaPolicy)
+}
+
 func (cmd *singleCommand) emptySocket(conn *Connection) error {
 	// There should not be any more bytes.
 	// Empty the socket to be safe.
@@ -49,6 +49,9 @@ type Statement struct {
 
 	// determines if the query should return data
 	returnData bool
+
+	// operations applied to each record by a background query
+	operations []*Operation
 }
 
 // NewStatement initializes a new Statement instance.
@@ -0,0 +1,268 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type tagTestInner struct {
+	Label string `as:"label"`
+	Score int
+}
+
+type tagTestObject struct {
+	Skipped int               `as:"-"`
+	Name    string            `as:"name,omitempty"`
+	Count   int               `as:" count , omitempty "`
+	Inner   tagTestInner      `as:"inner,msgpack"`
+	Values  []int             `as:",msgpack"`
+	Attrs   map[string]string `as:"attrs,msgpack,omitempty"`
+	Created time.Time         `as:"created,millis,omitempty"`
+}
+
+type EmbedTestBase struct {
+	ID      string `as:"id"`
+	Version int
+}
+
+type EmbedTestAudit struct {
+	CreatedBy string `as:"created_by"`
+}
+
+type embedTestHidden struct {
+	Secret string `as:"secret"`
+}
+
+type embedTestObject struct {
+	EmbedTestBase   `as:",flatten"`
+	*EmbedTestAudit `as:",flatten"`
+	embedTestHidden `as:",flatten"`
+	Nested          EmbedTestBase
+	Name            string `as:"name"`
+}
+
+type EmbedTestNested struct {
+	EmbedTestBase
+	Name string `as:"name"`
+}
+
+type embedTestColliding struct {
+	EmbedTestBase `as:",flatten"`
+	Other         string `as:"id"`
+}
+
+type timeTestObject struct {
+	Nanos     time.Time
+	Millis    time.Time      `as:"millis,millis"`
+	MillisP   *time.Time     `as:"millisp,millis"`
+	Zero      time.Time      `as:"zero,omitempty"`
+	Timeout   time.Duration  `as:"timeout,millis"`
+	TimeoutP  *time.Duration `as:"timeoutp,millis"`
+	Precision time.Duration
+}
+
+var _ = Describe("Struct Tag Test", func() {
+
+	It("should parse the bin name and the tag options", func() {
+		typ := reflect.TypeOf(tagTestObject{})
+
+		field := func(name string) reflect.StructField {
+			f, _ := typ.FieldByName(name)
+			return f
+		}
+
+		Expect(parseFieldTag(field("Skipped"))).To(Equal(fieldTag{}))
+		Expect(parseFieldTag(field("Name"))).To(Equal(fieldTag{name: "name", omitEmpty: true}))
+		Expect(parseFieldTag(field("Count"))).To(Equal(fieldTag{name: "count", omitEmpty: true}))
+		Expect(parseFieldTag(field("Inner"))).To(Equal(fieldTag{name: "inner", msgpack: true}))
+		Expect(parseFieldTag(field("Values"))).To(Equal(fieldTag{name: "Values", msgpack: true}))
+		Expect(parseFieldTag(field("Attrs"))).To(Equal(fieldTag{name: "attrs", omitEmpty: true, msgpack: true}))
+		Expect(parseFieldTag(field("Created"))).To(Equal(fieldTag{name: "created", omitEmpty: true, millis: true}))
+	})
+
+	It("should omit empty fields and round trip msgpack blobs", func() {
+		obj := &tagTestObject{
+			Skipped: 1,
+			Count:   2,
+			Inner:   tagTestInner{Label: "a", Score: 7},
+			Values:  []int{1, 2, 3},
+		}
+
+		bins, err := marshal(obj)
+		Expect(err).ToNot(HaveOccurred())
+		binMap := BinMap{}
+		for _, bin := range bins {
+			binMap[bin.Name] = bin.Value.GetObject()
+		}
+		binPool.Put(bins)
+
+		Expect(len(binMap)).To(Equal(3))
+		Expect(binMap).To(HaveKey("count"))
+		Expect(binMap["inner"]).To(BeAssignableToTypeOf([]byte{}))
+		Expect(binMap["Values"]).To(BeAssignableToTypeOf([]byte{}))
+
+		res := &tagTestObject{}
+		for name, value := range binMap {
+			Expect(setObjectField(reflect.ValueOf(res).Elem(), name, value)).ToNot(HaveOccurred())
+		}
+
+		obj.Skipped = 0
+		Expect(res).To(Equal(obj))
+	})
+
+	It("should fail to marshal msgpack fields which cannot be packed", func() {
+		type object struct {
+			Value Value `as:"value,msgpack"`
+		}
+
+		// a CDT operation value with a nil context cannot be packed
+		_, err := marshal(&object{Value: ListSizeOp("bin", nil).BinValue})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should map times and durations to integers", func() {
+		now := time.Unix(1500000000, 123456789)
+		timeout := 1500 * time.Millisecond
+		obj := &timeTestObject{
+			Nanos:     now,
+			Millis:    now,
+			MillisP:   &now,
+			Timeout:   timeout,
+			TimeoutP:  &timeout,
+			Precision: time.Nanosecond,
+		}
+
+		bins, err := marshal(obj)
+		Expect(err).ToNot(HaveOccurred())
+		binMap := BinMap{}
+		for _, bin := range bins {
+			binMap[bin.Name] = bin.Value.GetObject()
+		}
+		binPool.Put(bins)
+
+		Expect(binMap).To(Equal(BinMap{
+			"Nanos":     now.UnixNano(),
+			"millis":    int64(1500000000123),
+			"millisp":   int64(1500000000123),
+			"timeout":   int64(1500),
+			"timeoutp":  int64(1500),
+			"Precision": int64(1),
+		}))
+
+		res := &timeTestObject{}
+		for name, value := range binMap {
+			// integers are read back as int
+			value = int(value.(int64))
+			Expect(setObjectField(reflect.ValueOf(res).Elem(), name, value)).ToNot(HaveOccurred())
+		}
+
+		Expect(res.Nanos.Equal(now)).To(BeTrue())
+		Expect(res.Millis.Equal(now.Truncate(time.Millisecond))).To(BeTrue())
+		Expect(res.MillisP.Equal(now.Truncate(time.Millisecond))).To(BeTrue())
+		Expect(res.Zero.IsZero()).To(BeTrue())
+		Expect(res.Timeout).To(Equal(timeout))
+		Expect(*res.TimeoutP).To(Equal(timeout))
+		Expect(res.Precision).To(Equal(time.Nanosecond))
+	})
+
+	It("should flatten embedded structs tagged with flatten into the parent bins", func() {
+		obj := &embedTestObject{
+			EmbedTestBase:   EmbedTestBase{ID: "a", Version: 2},
+			EmbedTestAudit:  &EmbedTestAudit{CreatedBy: "me"},
+			embedTestHidden: embedTestHidden{Secret: "s"},
+			Nested:          EmbedTestBase{ID: "b"},
+			Name:            "n",
+		}
+
+		set := objectFieldsOf(reflect.TypeOf(*obj))
+		names := []string{}
+		for _, f := range set.fields {
+			names = append(names, f.name)
+		}
+		Expect(names).To(Equal([]string{
+			"EmbedTestBase.ID", "EmbedTestBase.Version", "EmbedTestAudit.CreatedBy",
+			"embedTestHidden.Secret", "Nested", "Name",
+		}))
+
+		bins, err := marshal(obj)
+		Expect(err).ToNot(HaveOccurred())
+		binMap := BinMap{}
+		for _, bin := range bins {
+			binMap[bin.Name] = bin.Value.GetObject()
+		}
+		binPool.Put(bins)
+
+		Expect(len(binMap)).To(Equal(6))
+		Expect(binMap["id"]).To(Equal("a"))
+		Expect(binMap["created_by"]).To(Equal("me"))
+		Expect(binMap["secret"]).To(Equal("s"))
+		Expect(binMap).To(HaveKey("Nested"))
+
+		res := &embedTestObject{}
+		for name, value := range binMap {
+			if v, ok := value.(int64); ok {
+				value = int(v)
+			}
+			if m, ok := value.(map[string]interface{}); ok {
+				// maps are read back with interface keys
+				im := map[interface{}]interface{}{}
+				for k, v := range m {
+					im[k] = v
+				}
+				value = im
+			}
+			Expect(setObjectField(reflect.ValueOf(res).Elem(), name, value)).ToNot(HaveOccurred())
+		}
+		Expect(res).To(Equal(obj))
+
+		// nil flattened pointers are neither written nor allocated
+		obj.EmbedTestAudit = nil
+		bins, err = marshal(obj)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(bins)).To(Equal(5))
+		binPool.Put(bins)
+	})
+
+	It("should store embedded structs without the flatten option as nested maps", func() {
+		bins, err := marshal(&EmbedTestNested{EmbedTestBase: EmbedTestBase{ID: "a"}, Name: "n"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(bins)).To(Equal(2))
+		Expect(bins[0].Name).To(Equal("EmbedTestBase"))
+		binPool.Put(bins)
+	})
+
+	It("should report collisions of flattened fields", func() {
+		err := validateObjectBins(reflect.TypeOf(embedTestColliding{}))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("EmbedTestBase.ID, Other"))
+	})
+
+	It("should unmarshal bins into a struct pointer", func() {
+		res := &tagTestObject{}
+		err := UnmarshalBins(BinMap{"name": "n", "count": 3}, res)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Name).To(Equal("n"))
+		Expect(res.Count).To(Equal(3))
+
+		Expect(UnmarshalBins(BinMap{}, *res)).To(HaveOccurred())
+		Expect(UnmarshalBins(BinMap{}, (*tagTestObject)(nil))).To(HaveOccurred())
+	})
+
+})
@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"time"
 )
 
@@ -26,6 +27,28 @@ type Task interface {
 	OnComplete() chan error
 }
 
+// ProgressTask is a Task which reports its progress, and can be waited for
+// with a context. It is implemented by the tasks of this package; Task is
+// left unchanged so that other implementations keep satisfying it.
+type ProgressTask interface {
+	Task
+
+	// Progress returns the percentage of the task completed, from 0 to 100.
+	Progress() (int, error)
+
+	// Wait blocks until the task is completed, an error is encountered,
+	// or the context is canceled.
+	Wait(ctx context.Context) error
+}
+
+var (
+	_ ProgressTask = &ExecuteTask{}
+	_ ProgressTask = &IndexTask{}
+	_ ProgressTask = &RegisterTask{}
+	_ ProgressTask = &RemoveTask{}
+	_ ProgressTask = &DeleteWhereTask{}
+)
+
 // BaseTask is used to poll for server task completion.
 type BaseTask struct {
 	cluster        *Cluster
@@ -74,3 +97,34 @@ func (btsk *BaseTask) onComplete(ifc Task) chan error {
 
 	return btsk.onCompleteChan
 }
+
+// wait polls the task every <interval> until IsDone() returns true or error,
+// or the context is canceled.
+func (btsk *BaseTask) wait(ctx context.Context, ifc Task) error {
+	const interval = 1 * time.Second
+	for {
+		done, err := ifc.IsDone()
+		if err != nil {
+			return err
+		} else if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// progressOf reports a task which can only tell if it is done as 0% or 100% complete.
+func progressOf(ifc Task) (int, error) {
+	done, err := ifc.IsDone()
+	if err != nil {
+		return 0, err
+	} else if done {
+		return 100, nil
+	}
+	return 0, nil
+}
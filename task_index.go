@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"regexp"
 	"strconv"
 	"strings"
@@ -37,40 +38,55 @@ func NewIndexTask(cluster *Cluster, namespace string, indexName string) *IndexTa
 	}
 }
 
+var loadPctRegexp = regexp.MustCompile(`\.*load_pct=(\d+)\.*`)
+
 // IsDone queries all nodes for task completion status.
 func (tski *IndexTask) IsDone() (bool, error) {
+	pct, err := tski.Progress()
+	if err != nil {
+		return false, err
+	}
+	return pct == 100, nil
+}
+
+// Progress queries all nodes for the index build status and returns the lowest
+// percentage of the index loaded on a node.
+func (tski *IndexTask) Progress() (int, error) {
 	command := "sindex/" + tski.namespace + "/" + tski.indexName
 	nodes := tski.cluster.GetNodes()
-	complete := false
-
-	r := regexp.MustCompile(`\.*load_pct=(\d+)\.*`)
+	progress := 100
+	found := false
 
 	for _, node := range nodes {
 		responseMap, err := RequestNodeInfo(node, command)
 		if err != nil {
-			return false, err
+			return 0, err
 		}
 
 		for _, response := range responseMap {
+			found = true
+
 			find := "load_pct="
 			index := strings.Index(response, find)
 
 			if index < 0 {
-				complete = true
 				continue
 			}
 
-			matchRes := r.FindStringSubmatch(response)
+			matchRes := loadPctRegexp.FindStringSubmatch(response)
 			// we know it exists and is a valid number
 			pct, _ := strconv.Atoi(matchRes[1])
 
-			if pct >= 0 && pct < 100 {
-				return false, nil
+			if pct >= 0 && pct < progress {
+				progress = pct
 			}
-			complete = true
 		}
 	}
-	return complete, nil
+
+	if !found {
+		return 0, nil
+	}
+	return progress, nil
 }
 
 // OnComplete returns a channel that will be closed as soon as the task is finished.
@@ -78,3 +94,9 @@ func (tski *IndexTask) IsDone() (bool, error) {
 func (tski *IndexTask) OnComplete() chan error {
 	return tski.onComplete(tski)
 }
+
+// Wait blocks until the index is built on all nodes, an error is encountered,
+// or the context is canceled.
+func (tski *IndexTask) Wait(ctx context.Context) error {
+	return tski.wait(ctx, tski)
+}
@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"strings"
 )
 
@@ -63,3 +64,14 @@ func (tskr *RemoveTask) IsDone() (bool, error) {
 func (tskr *RemoveTask) OnComplete() chan error {
 	return tskr.onComplete(tskr)
 }
+
+// Progress returns 100 if the task is completed on all nodes, and 0 otherwise.
+func (tskr *RemoveTask) Progress() (int, error) {
+	return progressOf(tskr)
+}
+
+// Wait blocks until the task is completed on all nodes, an error is encountered,
+// or the context is canceled.
+func (tskr *RemoveTask) Wait(ctx context.Context) error {
+	return tskr.wait(ctx, tskr)
+}
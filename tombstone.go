@@ -0,0 +1,195 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/logger"
+	. "github.com/aerospike/aerospike-client-go/types"
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+)
+
+// DefaultTombstoneBinName is the default name of the bin marking a record as deleted by Tombstones.
+const DefaultTombstoneBinName = "_tombstone"
+
+// Tombstones simulates durable deletes on servers without support for them,
+// e.g. the Community Edition. Instead of removing a record, Delete replaces it
+// with a tombstone bin holding the time until which the tombstone is retained,
+// so that a previous version of the record cannot be resurrected from disk
+// by a cold restart in the meantime.
+// The reads of Tombstones report tombstoned records as not found; use
+// FilterExpression to skip them in scans and queries. The expired tombstones
+// are removed by Cleanup, or periodically by StartCleanup.
+type Tombstones struct {
+	client *Client
+
+	// BinName is the name of the tombstone bin. It must not be used by the records.
+	BinName string //= DefaultTombstoneBinName
+
+	// Retention is how long a tombstone is kept before Cleanup removes it.
+	Retention time.Duration
+}
+
+// NewTombstones generates a Tombstones helper for the client, which retains
+// the tombstones for the retention duration.
+func NewTombstones(client *Client, retention time.Duration) *Tombstones {
+	return &Tombstones{
+		client:    client,
+		BinName:   DefaultTombstoneBinName,
+		Retention: retention,
+	}
+}
+
+// Delete replaces the record with a tombstone, whether the record exists or not.
+// The expiration of the policy applies to the tombstone; set it to -1 for
+// namespaces which do not expire records.
+// If the policy is nil, the default relevant policy will be used.
+func (ts *Tombstones) Delete(policy *WritePolicy, key *Key) error {
+	policy = ts.client.getUsableWritePolicy(policy)
+
+	// do not modify the caller's policy
+	wp := *policy
+	wp.RecordExistsAction = REPLACE
+
+	expiry := time.Now().Add(ts.Retention).Unix()
+	return ts.client.PutBins(&wp, key, NewBin(ts.BinName, expiry))
+}
+
+// Get reads the record like Client.Get, reporting tombstoned records as not found.
+// If the policy is nil, the default relevant policy will be used.
+func (ts *Tombstones) Get(policy *BasePolicy, key *Key, binNames ...string) (*Record, error) {
+	policy = ts.client.getUsablePolicy(policy)
+
+	// the tombstone bin is needed to tell if the record was deleted
+	if len(binNames) > 0 {
+		binNames = append(binNames[:len(binNames):len(binNames)], ts.BinName)
+	}
+
+	rec, err := ts.client.Get(policy, key, binNames...)
+	if err != nil || rec == nil {
+		return rec, err
+	}
+
+	if ts.IsTombstone(rec) {
+		if policy.KeyNotFoundAsError {
+			return nil, ErrKeyNotFound
+		}
+		return nil, nil
+	}
+
+	delete(rec.Bins, ts.BinName)
+	return rec, nil
+}
+
+// Exists returns true if the record exists and is not tombstoned.
+// If the policy is nil, the default relevant policy will be used.
+func (ts *Tombstones) Exists(policy *BasePolicy, key *Key) (bool, error) {
+	policy = ts.client.getUsablePolicy(policy)
+
+	// do not modify the caller's policy
+	bp := *policy
+	bp.KeyNotFoundAsError = false
+
+	rec, err := ts.Get(&bp, key, ts.BinName)
+	return rec != nil, err
+}
+
+// IsTombstone returns true if the record was deleted by Tombstones.
+func (ts *Tombstones) IsTombstone(rec *Record) bool {
+	if rec == nil {
+		return false
+	}
+	_, exists := rec.Bins[ts.BinName]
+	return exists
+}
+
+// FilterExpression returns an expression which filters out the tombstoned records.
+// Set it as the FilterExpression of scan and query policies to skip them.
+// Requires server version 5.2+.
+func (ts *Tombstones) FilterExpression() *Expression {
+	return ExpNot(ExpBinExists(ts.BinName))
+}
+
+// Cleanup removes the tombstones of the set whose retention expired and returns
+// how many were removed. A tombstone replaced by a new record in the meantime
+// is not removed.
+// The policy's FilterExpression, if any, is combined with the tombstone filter.
+// Requires server version 5.2+.
+// If the policy is nil, the default relevant policy will be used.
+func (ts *Tombstones) Cleanup(policy *ScanPolicy, namespace string, setName string) (int, error) {
+	policy = ts.client.getUsableScanPolicy(policy)
+
+	// do not modify the caller's policy
+	sp := *policy
+	sp.IncludeBinData = false
+	expired := ExpLessEq(ExpIntBin(ts.BinName), ExpIntVal(time.Now().Unix()))
+	if sp.FilterExpression != nil {
+		sp.FilterExpression = ExpAnd(sp.FilterExpression, expired)
+	} else {
+		sp.FilterExpression = expired
+	}
+
+	removed := NewAtomicInt(0)
+	err := ts.client.ScanAllFunc(&sp, namespace, setName, func(rec *Record) error {
+		wp := NewWritePolicy(int32(rec.Generation), 0)
+		wp.GenerationPolicy = EXPECT_GEN_EQUAL
+		wp.Timeout = sp.Timeout
+
+		existed, err := ts.client.Delete(wp, rec.Key)
+		if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == GENERATION_ERROR {
+			// the record was written again since the scan
+			return nil
+		}
+		if existed {
+			removed.IncrementAndGet()
+		}
+		return err
+	})
+	return removed.Get(), err
+}
+
+// StartCleanup runs Cleanup on the set every interval in the background,
+// until the returned function is called. Cleanup errors are logged.
+// If the policy is nil, the default relevant policy will be used.
+func (ts *Tombstones) StartCleanup(policy *ScanPolicy, namespace string, setName string, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				removed, err := ts.Cleanup(policy, namespace, setName)
+				if err != nil {
+					ts.client.cluster.logEvent(WARNING, SUBSYSTEM_CLIENT, "", map[string]interface{}{"namespace": namespace, "set": setName, "error": err}, "Tombstone cleanup failed: %s", err)
+					continue
+				}
+				if ts.client.cluster.logEnabled(DEBUG) {
+					ts.client.cluster.logEvent(DEBUG, SUBSYSTEM_CLIENT, "", map[string]interface{}{"namespace": namespace, "set": setName, "removed": removed}, "Removed %d expired tombstones", removed)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+var _ = Describe("Tombstones Test", func() {
+	initTestVars()
+
+	var ns = "test"
+	var set = randString(50)
+	var client *Client
+	var err error
+
+	BeforeEach(func() {
+		client, err = NewClientWithPolicy(clientPolicy, *host, *port)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("must report tombstoned records as not found", func() {
+		tombstones := NewTombstones(client, 0)
+
+		key, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = client.PutBins(nil, key, NewBin("bin", 1), NewBin("other", 2))
+		Expect(err).ToNot(HaveOccurred())
+
+		rec, err := tombstones.Get(nil, key, "bin")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec.Bins).To(Equal(BinMap{"bin": 1}))
+
+		err = tombstones.Delete(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+
+		rec, err = tombstones.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rec).To(BeNil())
+
+		exists, err := tombstones.Exists(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+
+		// the tombstone replaced all the bins of the record
+		rec, err = client.Get(nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tombstones.IsTombstone(rec)).To(BeTrue())
+		Expect(len(rec.Bins)).To(Equal(1))
+	})
+
+	It("must remove the expired tombstones only", func() {
+		tombstones := NewTombstones(client, 0)
+
+		deleted, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+		live, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = tombstones.Delete(nil, deleted)
+		Expect(err).ToNot(HaveOccurred())
+		err = client.PutBins(nil, live, NewBin("bin", 1))
+		Expect(err).ToNot(HaveOccurred())
+
+		removed, err := tombstones.Cleanup(nil, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(Equal(1))
+
+		exists, err := client.Exists(nil, deleted)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+
+		exists, err = tombstones.Exists(nil, live)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
+})
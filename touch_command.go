@@ -62,5 +62,6 @@ func (cmd *touchCommand) parseResult(ifc command, conn *Connection) error {
 }
 
 func (cmd *touchCommand) Execute() error {
+	cmd.policy.ReadYourWrites.Track(cmd.key)
 	return cmd.execute(cmd)
 }
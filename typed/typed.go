@@ -0,0 +1,132 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.23
+
+// Package typed is a generic facade over the object mapping of the client,
+// so that application code reads and writes its own struct types instead of
+// asserting the values of BinMaps.
+//
+// Records are mapped to T like Client.PutObject and Client.GetObject map them,
+// including the `as` struct tags; the field metadata of each type is cached.
+// T must be a struct type.
+//
+//	user, err := typed.Get[User](client, nil, key)
+//
+//	for user, err := range typed.Query[User](client, nil, stmt) {
+//		...
+//	}
+package typed
+
+import (
+	"iter"
+
+	. "github.com/aerospike/aerospike-client-go"
+)
+
+// Get reads the record of the key into a new T.
+// If the policy is nil, the default relevant policy will be used.
+func Get[T any](client ClientIfc, policy *BasePolicy, key *Key) (*T, error) {
+	obj := new(T)
+	if err := client.GetObject(policy, key, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Put writes the object as the bins of the record of the key.
+// If the policy is nil, the default relevant policy will be used.
+func Put[T any](client ClientIfc, policy *WritePolicy, key *Key, obj *T) error {
+	return client.PutObject(policy, key, obj)
+}
+
+// BatchGet reads the records of the keys in one batch request.
+// The returned objects are in positional order with the keys;
+// the object of a record which does not exist is nil.
+// If the policy is nil, the default relevant policy will be used.
+func BatchGet[T any](client ClientIfc, policy *BasePolicy, keys []*Key) ([]*T, error) {
+	records, err := client.BatchGet(policy, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*T, len(records))
+	for i, rec := range records {
+		if rec == nil {
+			continue
+		}
+
+		res[i] = new(T)
+		if err := UnmarshalBins(rec.Bins, res[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Query executes the query and returns an iterator over its records mapped to T.
+// Errors returned by the nodes are yielded with a nil object, and iteration
+// continues with the records of the other nodes. Stopping the iteration early
+// cancels the query.
+// If the policy is nil, the default relevant policy will be used.
+func Query[T any](client ClientIfc, policy *QueryPolicy, statement *Statement) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		recordset, err := client.Query(policy, statement)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		yieldRecords(recordset, yield)
+	}
+}
+
+// ScanAll scans the namespace and set and returns an iterator over the records
+// mapped to T, like Query.
+// If the policy is nil, the default relevant policy will be used.
+func ScanAll[T any](client ClientIfc, policy *ScanPolicy, namespace string, setName string) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		recordset, err := client.ScanAll(policy, namespace, setName)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		yieldRecords(recordset, yield)
+	}
+}
+
+// yieldRecords yields the results of the recordset mapped to T,
+// and closes the recordset if the iteration is stopped early.
+func yieldRecords[T any](recordset *Recordset, yield func(*T, error) bool) {
+	results := recordset.Results()
+	for res := range results {
+		var obj *T
+		err := res.Err
+		if err == nil {
+			obj = new(T)
+			if err = UnmarshalBins(res.Record.Bins, obj); err != nil {
+				obj = nil
+			}
+		}
+
+		if !yield(obj, err) {
+			// unblock the results goroutine until the recordset is closed
+			go func() {
+				for range results {
+				}
+			}()
+			recordset.Close()
+			return
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.23
+
+package typed_test
+
+import (
+	"flag"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/typed"
+)
+
+var host = flag.String("h", "127.0.0.1", "Aerospike server seed hostnames or IP addresses")
+var port = flag.Int("p", 3000, "Aerospike server seed hostname or IP address port number.")
+
+func TestTyped(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Aerospike Client Library Typed Suite")
+}
+
+type user struct {
+	Name  string `as:"name"`
+	Age   int    `as:"age"`
+	Email string `as:"email,omitempty"`
+}
+
+var _ = Describe("Typed Test", func() {
+
+	var client *Client
+	var err error
+	var ns = "test"
+	var set = "typed" + strconv.Itoa(rand.Int())
+
+	BeforeEach(func() {
+		flag.Parse()
+		client, err = NewClient(*host, *port)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("must put and get objects", func() {
+		key, err := NewKey(ns, set, "alice")
+		Expect(err).ToNot(HaveOccurred())
+
+		err = typed.Put(client, nil, key, &user{Name: "alice", Age: 30})
+		Expect(err).ToNot(HaveOccurred())
+
+		u, err := typed.Get[user](client, nil, key)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*u).To(Equal(user{Name: "alice", Age: 30}))
+	})
+
+	It("must batch get objects and return nil for missing records", func() {
+		key1, _ := NewKey(ns, set, "bob")
+		key2, _ := NewKey(ns, set, "missing")
+
+		err = typed.Put(client, nil, key1, &user{Name: "bob", Age: 40})
+		Expect(err).ToNot(HaveOccurred())
+
+		users, err := typed.BatchGet[user](client, nil, []*Key{key1, key2})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(users)).To(Equal(2))
+		Expect(users[0].Name).To(Equal("bob"))
+		Expect(users[1] == nil).To(BeTrue())
+	})
+
+	It("must scan objects and stop early", func() {
+		for i := 0; i < 10; i++ {
+			key, _ := NewKey(ns, set, i)
+			err = typed.Put(client, nil, key, &user{Name: "user" + strconv.Itoa(i), Age: i})
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		cnt := 0
+		for u, err := range typed.ScanAll[user](client, nil, ns, set) {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(u.Name).ToNot(BeEmpty())
+			cnt++
+		}
+		Expect(cnt >= 10).To(BeTrue())
+
+		cnt = 0
+		for _, err := range typed.ScanAll[user](client, nil, ns, set) {
+			Expect(err).ToNot(HaveOccurred())
+			cnt++
+			if cnt == 3 {
+				break
+			}
+		}
+		Expect(cnt).To(Equal(3))
+	})
+
+	It("must query objects", func() {
+		stmt := NewStatement(ns, set)
+		cnt := 0
+		for u, err := range typed.Query[user](client, nil, stmt) {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(u).ToNot(BeNil())
+			cnt++
+		}
+		Expect(cnt > 0).To(BeTrue())
+	})
+})
@@ -45,3 +45,13 @@ func NewAerospikeError(code ResultCode, messages ...string) error {
 	err := errors.New(strings.Join(messages, " "))
 	return AerospikeError{error: err, resultCode: code}
 }
+
+// ErrKeyNotFound is returned by single record reads when the record does not exist
+// and the policy's KeyNotFoundAsError is set.
+// Its result code is KEY_NOT_FOUND_ERROR.
+var ErrKeyNotFound = NewAerospikeError(KEY_NOT_FOUND_ERROR)
+
+// ErrRateLimited is returned when a command is not sent to a node because it
+// would exceed the node's command rate limit set by the client policy.
+// Its result code is COMMAND_REJECTED.
+var ErrRateLimited = NewAerospikeError(COMMAND_REJECTED, "Node command rate limit exceeded.")
@@ -33,8 +33,9 @@ const (
 	// RTA_LIST        = 14
 	// RTA_DICT        = 15
 	// RTA_APPEND_DICT = 16
-	// RTA_APPEND_LIST = 17
-	// LUA_BLOB        = 18
-	MAP  = 19
-	LIST = 20
+	BOOL    = 17
+	HLL     = 18
+	MAP     = 19
+	LIST    = 20
+	GEOJSON = 23
 )
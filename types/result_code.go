@@ -14,11 +14,21 @@
 
 package types
 
+import (
+	"strconv"
+)
+
 // ResultCode signifies the database operation error codes.
 // The positive numbers align with the server side file proto.h.
 type ResultCode int
 
 const (
+	// The replica of a verified write did not have the written version of the record.
+	REPLICA_DIVERGENCE ResultCode = -10
+
+	// The record read is older than the write of a consistency token.
+	STALE_READ ResultCode = -9
+
 	// There were no connections available to the node in the pool, and the pool was limited
 	NO_AVAILABLE_CONNECTIONS_TO_NODE ResultCode = -8
 
@@ -115,6 +125,22 @@ const (
 	// Operation not allowed at this time.
 	FAIL_FORBIDDEN ResultCode = 22
 
+	// Collection element was not found.
+	ELEMENT_NOT_FOUND ResultCode = 23
+
+	// Collection element already exists.
+	ELEMENT_EXISTS ResultCode = 24
+
+	// The feature is only available on Enterprise servers, e.g. durable deletes.
+	ENTERPRISE_ONLY ResultCode = 25
+
+	// Operation can not be applied to the current bin value.
+	OP_NOT_APPLICABLE ResultCode = 26
+
+	// The command was not applied because the policy's filter expression
+	// evaluated to false for the record.
+	FILTERED_OUT ResultCode = 27
+
 	// There are no more records left for query.
 	QUERY_END ResultCode = 50
 
@@ -203,6 +229,169 @@ const (
 	QUERY_GENERIC ResultCode = 213
 )
 
+// String returns the name of the result code, e.g. "GENERATION_ERROR".
+// Unknown result codes are formatted as "ResultCode(<code>)".
+func (rc ResultCode) String() string {
+	if name, exists := resultCodeNames[rc]; exists {
+		return name
+	}
+	return "ResultCode(" + strconv.Itoa(int(rc)) + ")"
+}
+
+// Description returns the description of the result code, as used for error messages.
+func (rc ResultCode) Description() string {
+	return ResultCodeToString(rc)
+}
+
+// Category returns the category of the result code.
+func (rc ResultCode) Category() ResultCodeCategory {
+	switch {
+	case rc < OK:
+		return CATEGORY_CLIENT
+	case rc == OK:
+		return CATEGORY_OK
+	}
+
+	switch rc {
+	case SECURITY_NOT_SUPPORTED,
+		SECURITY_NOT_ENABLED,
+		SECURITY_SCHEME_NOT_SUPPORTED,
+		INVALID_USER,
+		USER_ALREADY_EXISTS,
+		INVALID_PASSWORD,
+		EXPIRED_PASSWORD,
+		FORBIDDEN_PASSWORD,
+		INVALID_CREDENTIAL,
+		INVALID_ROLE,
+		ROLE_ALREADY_EXISTS,
+		INVALID_PRIVILEGE,
+		NOT_AUTHENTICATED,
+		ROLE_VIOLATION:
+		return CATEGORY_SECURITY
+
+	case SERVER_MEM_ERROR,
+		DEVICE_OVERLOAD,
+		RECORD_TOO_BIG,
+		INDEX_OOM:
+		return CATEGORY_DEVICE
+
+	default:
+		return CATEGORY_SERVER
+	}
+}
+
+// ResultCodeCategory groups result codes by their origin.
+type ResultCodeCategory int
+
+const (
+	// CATEGORY_OK is the category of the OK result code.
+	CATEGORY_OK ResultCodeCategory = iota
+
+	// CATEGORY_CLIENT contains the errors which originate in the client.
+	CATEGORY_CLIENT
+
+	// CATEGORY_SERVER contains the errors returned by the server,
+	// which are not security or device errors.
+	CATEGORY_SERVER
+
+	// CATEGORY_SECURITY contains the authentication and authorization errors.
+	CATEGORY_SECURITY
+
+	// CATEGORY_DEVICE contains the errors caused by exhausted server resources,
+	// like memory and storage devices.
+	CATEGORY_DEVICE
+)
+
+// String returns the name of the category, e.g. "server".
+func (cat ResultCodeCategory) String() string {
+	switch cat {
+	case CATEGORY_OK:
+		return "ok"
+	case CATEGORY_CLIENT:
+		return "client"
+	case CATEGORY_SERVER:
+		return "server"
+	case CATEGORY_SECURITY:
+		return "security"
+	case CATEGORY_DEVICE:
+		return "device"
+	default:
+		return "ResultCodeCategory(" + strconv.Itoa(int(cat)) + ")"
+	}
+}
+
+var resultCodeNames = map[ResultCode]string{
+	REPLICA_DIVERGENCE:               "REPLICA_DIVERGENCE",
+	STALE_READ:                       "STALE_READ",
+	NO_AVAILABLE_CONNECTIONS_TO_NODE: "NO_AVAILABLE_CONNECTIONS_TO_NODE",
+	TYPE_NOT_SUPPORTED:               "TYPE_NOT_SUPPORTED",
+	COMMAND_REJECTED:                 "COMMAND_REJECTED",
+	QUERY_TERMINATED:                 "QUERY_TERMINATED",
+	SCAN_TERMINATED:                  "SCAN_TERMINATED",
+	INVALID_NODE_ERROR:               "INVALID_NODE_ERROR",
+	PARSE_ERROR:                      "PARSE_ERROR",
+	SERIALIZE_ERROR:                  "SERIALIZE_ERROR",
+	OK:                               "OK",
+	SERVER_ERROR:                     "SERVER_ERROR",
+	KEY_NOT_FOUND_ERROR:              "KEY_NOT_FOUND_ERROR",
+	GENERATION_ERROR:                 "GENERATION_ERROR",
+	PARAMETER_ERROR:                  "PARAMETER_ERROR",
+	KEY_EXISTS_ERROR:                 "KEY_EXISTS_ERROR",
+	BIN_EXISTS_ERROR:                 "BIN_EXISTS_ERROR",
+	CLUSTER_KEY_MISMATCH:             "CLUSTER_KEY_MISMATCH",
+	SERVER_MEM_ERROR:                 "SERVER_MEM_ERROR",
+	TIMEOUT:                          "TIMEOUT",
+	NO_XDS:                           "NO_XDS",
+	SERVER_NOT_AVAILABLE:             "SERVER_NOT_AVAILABLE",
+	BIN_TYPE_ERROR:                   "BIN_TYPE_ERROR",
+	RECORD_TOO_BIG:                   "RECORD_TOO_BIG",
+	KEY_BUSY:                         "KEY_BUSY",
+	SCAN_ABORT:                       "SCAN_ABORT",
+	UNSUPPORTED_FEATURE:              "UNSUPPORTED_FEATURE",
+	BIN_NOT_FOUND:                    "BIN_NOT_FOUND",
+	DEVICE_OVERLOAD:                  "DEVICE_OVERLOAD",
+	KEY_MISMATCH:                     "KEY_MISMATCH",
+	INVALID_NAMESPACE:                "INVALID_NAMESPACE",
+	BIN_NAME_TOO_LONG:                "BIN_NAME_TOO_LONG",
+	FAIL_FORBIDDEN:                   "FAIL_FORBIDDEN",
+	ELEMENT_NOT_FOUND:                "ELEMENT_NOT_FOUND",
+	ELEMENT_EXISTS:                   "ELEMENT_EXISTS",
+	ENTERPRISE_ONLY:                  "ENTERPRISE_ONLY",
+	OP_NOT_APPLICABLE:                "OP_NOT_APPLICABLE",
+	FILTERED_OUT:                     "FILTERED_OUT",
+	QUERY_END:                        "QUERY_END",
+	SECURITY_NOT_SUPPORTED:           "SECURITY_NOT_SUPPORTED",
+	SECURITY_NOT_ENABLED:             "SECURITY_NOT_ENABLED",
+	SECURITY_SCHEME_NOT_SUPPORTED:    "SECURITY_SCHEME_NOT_SUPPORTED",
+	INVALID_COMMAND:                  "INVALID_COMMAND",
+	INVALID_FIELD:                    "INVALID_FIELD",
+	ILLEGAL_STATE:                    "ILLEGAL_STATE",
+	INVALID_USER:                     "INVALID_USER",
+	USER_ALREADY_EXISTS:              "USER_ALREADY_EXISTS",
+	INVALID_PASSWORD:                 "INVALID_PASSWORD",
+	EXPIRED_PASSWORD:                 "EXPIRED_PASSWORD",
+	FORBIDDEN_PASSWORD:               "FORBIDDEN_PASSWORD",
+	INVALID_CREDENTIAL:               "INVALID_CREDENTIAL",
+	INVALID_ROLE:                     "INVALID_ROLE",
+	ROLE_ALREADY_EXISTS:              "ROLE_ALREADY_EXISTS",
+	INVALID_PRIVILEGE:                "INVALID_PRIVILEGE",
+	NOT_AUTHENTICATED:                "NOT_AUTHENTICATED",
+	ROLE_VIOLATION:                   "ROLE_VIOLATION",
+	UDF_BAD_RESPONSE:                 "UDF_BAD_RESPONSE",
+	LARGE_ITEM_NOT_FOUND:             "LARGE_ITEM_NOT_FOUND",
+	INDEX_FOUND:                      "INDEX_FOUND",
+	INDEX_NOTFOUND:                   "INDEX_NOTFOUND",
+	INDEX_OOM:                        "INDEX_OOM",
+	INDEX_NOTREADABLE:                "INDEX_NOTREADABLE",
+	INDEX_GENERIC:                    "INDEX_GENERIC",
+	INDEX_NAME_MAXLEN:                "INDEX_NAME_MAXLEN",
+	INDEX_MAXCOUNT:                   "INDEX_MAXCOUNT",
+	QUERY_ABORTED:                    "QUERY_ABORTED",
+	QUERY_QUEUEFULL:                  "QUERY_QUEUEFULL",
+	QUERY_TIMEOUT:                    "QUERY_TIMEOUT",
+	QUERY_GENERIC:                    "QUERY_GENERIC",
+}
+
 // Should connection be put back into pool.
 func KeepConnection(resultCode int) bool {
 	switch ResultCode(resultCode) {
@@ -229,6 +418,12 @@ func KeepConnection(resultCode int) bool {
 // Return result code as a string.
 func ResultCodeToString(resultCode ResultCode) string {
 	switch ResultCode(resultCode) {
+	case REPLICA_DIVERGENCE:
+		return "Replica does not have the written record"
+
+	case STALE_READ:
+		return "Record is older than the consistency token"
+
 	case NO_AVAILABLE_CONNECTIONS_TO_NODE:
 		return "No available connections to the node. Connection Pool was empty, and limited to certain number of connections."
 
@@ -322,6 +517,21 @@ func ResultCodeToString(resultCode ResultCode) string {
 	case FAIL_FORBIDDEN:
 		return "Operation not allowed at this time"
 
+	case ELEMENT_NOT_FOUND:
+		return "Element not found"
+
+	case ELEMENT_EXISTS:
+		return "Element already exists"
+
+	case ENTERPRISE_ONLY:
+		return "Enterprise only"
+
+	case OP_NOT_APPLICABLE:
+		return "Operation not applicable"
+
+	case FILTERED_OUT:
+		return "Record filtered out by the filter expression"
+
 	case QUERY_END:
 		return "Query end"
 
@@ -0,0 +1,44 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types_test
+
+import (
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Result Code Test", func() {
+
+	It("should format result codes by name", func() {
+		Expect(GENERATION_ERROR.String()).To(Equal("GENERATION_ERROR"))
+		Expect(fmt.Sprintf("%v", TIMEOUT)).To(Equal("TIMEOUT"))
+		Expect(ResultCode(12345).String()).To(Equal("ResultCode(12345)"))
+		Expect(KEY_NOT_FOUND_ERROR.Description()).To(Equal("Key not found"))
+	})
+
+	It("should categorize result codes", func() {
+		Expect(OK.Category()).To(Equal(CATEGORY_OK))
+		Expect(TIMEOUT.Category()).To(Equal(CATEGORY_SERVER))
+		Expect(PARSE_ERROR.Category()).To(Equal(CATEGORY_CLIENT))
+		Expect(NOT_AUTHENTICATED.Category()).To(Equal(CATEGORY_SECURITY))
+		Expect(DEVICE_OVERLOAD.Category()).To(Equal(CATEGORY_DEVICE))
+		Expect(CATEGORY_SECURITY.String()).To(Equal("security"))
+	})
+
+})
@@ -0,0 +1,57 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "sync"
+
+// SyncBufferPool implements a buffer pool backed by a sync.Pool.
+// Unlike BufferPool, it does not limit the number of pooled buffers; unused
+// buffers are released by the garbage collector. Buffers larger than the
+// max buffer size are not pooled to prevent memory bloat.
+type SyncBufferPool struct {
+	pool sync.Pool
+
+	initBufSize int
+	maxBufSize  int
+}
+
+// NewSyncBufferPool creates a new buffer pool backed by a sync.Pool.
+// New buffers will be created with size and capacity of initBufferSize.
+// If cap(buffer) is larger than maxBufferSize when it is put back in the pool,
+// it will be thrown away.
+func NewSyncBufferPool(initBufferSize, maxBufferSize int) *SyncBufferPool {
+	return &SyncBufferPool{
+		initBufSize: initBufferSize,
+		maxBufSize:  maxBufferSize,
+	}
+}
+
+// Get returns a buffer from the pool. If the pool is empty, a new buffer of
+// size initBufSize will be created and returned.
+func (bp *SyncBufferPool) Get() []byte {
+	if buf, ok := bp.pool.Get().([]byte); ok {
+		return buf[:cap(buf)]
+	}
+	return make([]byte, bp.initBufSize)
+}
+
+// Put will put the buffer back in the pool, unless cap(buf) is smaller than
+// initBufSize or bigger than maxBufSize, in which case it will be thrown away.
+func (bp *SyncBufferPool) Put(buf []byte) {
+	if cap(buf) < bp.initBufSize || cap(buf) > bp.maxBufSize {
+		return
+	}
+	bp.pool.Put(buf)
+}
@@ -0,0 +1,47 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types_test
+
+import (
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sync Buffer Pool Test", func() {
+
+	It("should create buffers of the initial size", func() {
+		pool := NewSyncBufferPool(1024, 4096)
+		Expect(len(pool.Get())).To(Equal(1024))
+	})
+
+	It("should return pooled buffers with their full capacity", func() {
+		pool := NewSyncBufferPool(1024, 4096)
+
+		pool.Put(make([]byte, 10, 2048))
+		buf := pool.Get()
+		Expect(len(buf)).To(BeNumerically(">=", 1024))
+		Expect(len(buf)).To(Equal(cap(buf)))
+	})
+
+	It("should not pool buffers larger than the max size", func() {
+		pool := NewSyncBufferPool(1024, 4096)
+
+		pool.Put(make([]byte, 8192))
+		Expect(len(pool.Get())).To(Equal(1024))
+	})
+
+})
@@ -1,5 +1,10 @@
 package aerospike
 
+import (
+	"crypto/sha1"
+	"encoding/hex"
+)
+
 // UDF carries information about UDFs on the server
 type UDF struct {
 	// Filename of the UDF
@@ -9,3 +14,11 @@ type UDF struct {
 	// Language of UDF
 	Language Language
 }
+
+// UDFHash returns the hash digest the server reports in UDF.Hash for a package
+// with the given content. Deployment tools can compare it with the results of
+// ListUDF to find out which packages need to be registered again.
+func UDFHash(udfBody []byte) string {
+	hash := sha1.Sum(udfBody)
+	return hex.EncodeToString(hash[:])
+}
@@ -82,6 +82,26 @@ var _ = Describe("UDF/Query tests", func() {
 		}
 	})
 
+	It("must Register a UDF from a reader", func() {
+		regTask, err := client.RegisterUDFFromReader(wpolicy, strings.NewReader(udfBody), "udfFromReader.lua", LUA)
+		Expect(err).ToNot(HaveOccurred())
+
+		// wait until UDF is created
+		err = <-regTask.OnComplete()
+		Expect(err).ToNot(HaveOccurred())
+
+		udfList, err := client.ListUDF(nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		found := false
+		for _, udf := range udfList {
+			if udf.Filename == "udfFromReader.lua" {
+				found = true
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+
 	It("must run a UDF on a single record", func() {
 		key, err = NewKey(ns, set, randString(50))
 		Expect(err).ToNot(HaveOccurred())
@@ -104,6 +124,16 @@ var _ = Describe("UDF/Query tests", func() {
 		udfList, err := client.ListUDF(nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(len(udfList)).To(BeNumerically(">", 0))
+
+		found := false
+		for _, udf := range udfList {
+			if udf.Filename == "udf1.lua" {
+				found = true
+				Expect(udf.Hash).To(Equal(UDFHash([]byte(udfBody))))
+				Expect(udf.Language).To(Equal(LUA))
+			}
+		}
+		Expect(found).To(BeTrue())
 	})
 
 	It("must drop a udf on the server", func() {
@@ -22,6 +22,10 @@ import (
 	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
 )
 
+// errSkipHeader is returned when the unpacked object is a msgpack extension
+// header (e.g. the ordering flags of a CDT), which must not be returned to the user.
+var errSkipHeader = NewAerospikeError(SERIALIZE_ERROR, "Skip the unpacker error")
+
 type unpacker struct {
 	buffer []byte
 	offset int
@@ -65,6 +69,9 @@ func (upckr *unpacker) unpackList(count int) ([]interface{}, error) {
 
 	for i := 0; i < count; i++ {
 		obj, err := upckr.unpackObject()
+		if err == errSkipHeader {
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -101,14 +108,17 @@ func (upckr *unpacker) unpackMap(count int) (map[interface{}]interface{}, error)
 
 	for i := 0; i < count; i++ {
 		key, err := upckr.unpackObject()
-		if err != nil {
+		skip := err == errSkipHeader
+		if err != nil && !skip {
 			return nil, err
 		}
 		val, err := upckr.unpackObject()
 		if err != nil {
 			return nil, err
 		}
-		out[key] = val
+		if !skip {
+			out[key] = val
+		}
 	}
 	return out, nil
 }
@@ -127,6 +137,14 @@ func (upckr *unpacker) unpackBlob(count int) (interface{}, error) {
 		b := make([]byte, count)
 		copy(b, upckr.buffer[upckr.offset:upckr.offset+count])
 		val = b
+
+	case ParticleType.GEOJSON:
+		val = NewGeoJSONValue(string(upckr.buffer[upckr.offset : upckr.offset+count]))
+
+	case ParticleType.HLL:
+		b := make([]byte, count)
+		copy(b, upckr.buffer[upckr.offset:upckr.offset+count])
+		val = NewHLLValue(b)
 	default:
 		panic(NewAerospikeError(SERIALIZE_ERROR, fmt.Sprintf("Error while unpacking BLOB. Type-header with code `%d` not recognized.", theType)))
 	}
@@ -143,6 +161,41 @@ func (upckr *unpacker) unpackObject() (interface{}, error) {
 	case 0xc0:
 		return nil, nil
 
+	case 0xc7: // ext 8: skip the header and its data
+		count := int(upckr.buffer[upckr.offset] & 0xff)
+		upckr.offset += 1 + 1 + count
+		return nil, errSkipHeader
+
+	case 0xc8: // ext 16
+		count := int(uint16(Buffer.BytesToInt16(upckr.buffer, upckr.offset)))
+		upckr.offset += 2 + 1 + count
+		return nil, errSkipHeader
+
+	case 0xc9: // ext 32
+		count := int(uint32(Buffer.BytesToInt32(upckr.buffer, upckr.offset)))
+		upckr.offset += 4 + 1 + count
+		return nil, errSkipHeader
+
+	case 0xd4: // fixext 1
+		upckr.offset += 1 + 1
+		return nil, errSkipHeader
+
+	case 0xd5: // fixext 2
+		upckr.offset += 1 + 2
+		return nil, errSkipHeader
+
+	case 0xd6: // fixext 4
+		upckr.offset += 1 + 4
+		return nil, errSkipHeader
+
+	case 0xd7: // fixext 8
+		upckr.offset += 1 + 8
+		return nil, errSkipHeader
+
+	case 0xd8: // fixext 16
+		upckr.offset += 1 + 16
+		return nil, errSkipHeader
+
 	case 0xc3:
 		return true, nil
 
@@ -94,6 +94,16 @@ func LittleBytesToInt32(buf []byte, offset int) int32 {
 	return r
 }
 
+// Covertes a little endian slice into int64; only maximum of 8 bytes will be used
+func LittleBytesToInt64(buf []byte, offset int) int64 {
+	l := len(buf[offset:])
+	if l > uint64sz {
+		l = uint64sz
+	}
+	r := int64(binary.LittleEndian.Uint64(buf[offset : offset+l]))
+	return r
+}
+
 // Covertes a slice into int64; only maximum of 8 bytes will be used
 func BytesToInt64(buf []byte, offset int) int64 {
 	l := len(buf[offset:])
@@ -156,6 +166,28 @@ func Int16ToBytes(num int16, buffer []byte, offset int) []byte {
 	return b
 }
 
+// Converts an int64 into slice of Bytes in little endian order.
+func Int64ToLittleBytes(num int64, buffer []byte, offset int) []byte {
+	if buffer != nil {
+		binary.LittleEndian.PutUint64(buffer[offset:], uint64(num))
+		return nil
+	}
+	b := make([]byte, uint64sz)
+	binary.LittleEndian.PutUint64(b, uint64(num))
+	return b
+}
+
+// Converts an int16 into slice of Bytes in little endian order.
+func Int16ToLittleBytes(num int16, buffer []byte, offset int) []byte {
+	if buffer != nil {
+		binary.LittleEndian.PutUint16(buffer[offset:], uint16(num))
+		return nil
+	}
+	b := make([]byte, uint16sz)
+	binary.LittleEndian.PutUint16(b, uint16(num))
+	return b
+}
+
 func BytesToFloat32(buf []byte, offset int) float32 {
 	bits := binary.BigEndian.Uint32(buf[offset : offset+float32sz])
 	return math.Float32frombits(bits)
@@ -77,6 +77,8 @@ func NewValue(v interface{}) Value {
 		return NewLongValue(val)
 	case string:
 		return NewStringValue(val)
+	case bool:
+		return NewBoolValue(val)
 	case []Value:
 		return NewValueArray(val)
 	case []byte:
@@ -107,6 +109,11 @@ func NewValue(v interface{}) Value {
 		return NewBlobValue(val)
 	}
 
+	// check for registered converters
+	if cv := convertValue(v); cv != nil {
+		return cv
+	}
+
 	// check for array and map
 	rv := reflect.ValueOf(v)
 	switch rv.Kind() {
@@ -132,6 +139,8 @@ func NewValue(v interface{}) Value {
 		return NewLongValue(int64(reflect.ValueOf(v).Uint()))
 	case reflect.String:
 		return NewStringValue(rv.String())
+	case reflect.Bool:
+		return NewBoolValue(rv.Bool())
 	}
 
 	// panic for anything that is not supported.
@@ -184,6 +193,70 @@ func (vl *NullValue) String() string {
 
 ///////////////////////////////////////////////////////////////////////////////
 
+// BoolValue encapsulates a boolean value.
+// Booleans are stored in the server's native boolean particle type,
+// unless WritePolicy.BoolAsInteger is set.
+type BoolValue bool
+
+// NewBoolValue generates a BoolValue instance.
+func NewBoolValue(value bool) BoolValue {
+	return BoolValue(value)
+}
+
+func (vl BoolValue) estimateSize() int {
+	return 1
+}
+
+func (vl BoolValue) write(buffer []byte, offset int) (int, error) {
+	if vl {
+		buffer[offset] = 1
+	} else {
+		buffer[offset] = 0
+	}
+	return 1, nil
+}
+
+func (vl BoolValue) pack(packer *packer) error {
+	packer.PackBool(bool(vl))
+	return nil
+}
+
+// GetType returns wire protocol value type.
+func (vl BoolValue) GetType() int {
+	return ParticleType.BOOL
+}
+
+// GetObject returns original value as an interface{}.
+func (vl BoolValue) GetObject() interface{} {
+	return bool(vl)
+}
+
+func (vl BoolValue) reader() io.Reader {
+	if vl {
+		return bytes.NewReader([]byte{1})
+	}
+	return bytes.NewReader([]byte{0})
+}
+
+// String implements Stringer interface.
+func (vl BoolValue) String() string {
+	if vl {
+		return "true"
+	}
+	return "false"
+}
+
+// asInteger returns the value as an integer, used for servers
+// without native boolean support.
+func (vl BoolValue) asInteger() Value {
+	if vl {
+		return NewLongValue(1)
+	}
+	return NewLongValue(0)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
 // BytesValue encapsulates an array of bytes.
 type BytesValue []byte
 
@@ -243,6 +316,51 @@ func (vl BytesValue) String() string {
 
 ///////////////////////////////////////////////////////////////////////////////
 
+// HLLValue encapsulates a HyperLogLog value.
+type HLLValue []byte
+
+// NewHLLValue generates a HLLValue instance.
+func NewHLLValue(bytes []byte) HLLValue {
+	return HLLValue(bytes)
+}
+
+func (vl HLLValue) estimateSize() int {
+	return len(vl)
+}
+
+func (vl HLLValue) write(buffer []byte, offset int) (int, error) {
+	len := copy(buffer[offset:], vl)
+	return len, nil
+}
+
+func (vl HLLValue) pack(packer *packer) error {
+	packer.PackByteArrayBegin(len(vl) + 1)
+	packer.PackAByte(ParticleType.HLL)
+	packer.PackByteArray(vl, 0, len(vl))
+	return nil
+}
+
+// GetType returns wire protocol value type.
+func (vl HLLValue) GetType() int {
+	return ParticleType.HLL
+}
+
+// GetObject returns original value as an interface{}.
+func (vl HLLValue) GetObject() interface{} {
+	return vl
+}
+
+func (vl HLLValue) reader() io.Reader {
+	return bytes.NewReader(vl)
+}
+
+// String implements Stringer interface.
+func (vl HLLValue) String() string {
+	return Buffer.BytesToHexString(vl)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
 // StringValue encapsulates a string value.
 type StringValue string
 
@@ -289,6 +407,56 @@ func (vl StringValue) String() string {
 
 ///////////////////////////////////////////////////////////////////////////////
 
+// GeoJSONValue encapsulates a GeoJSON value.
+// Geospatial values are stored in a GeoJSON string format, for example:
+//
+//	{"type": "Point", "coordinates": [-122.0, 37.5]}
+type GeoJSONValue string
+
+// NewGeoJSONValue generates a GeoJSONValue instance.
+func NewGeoJSONValue(value string) GeoJSONValue {
+	return GeoJSONValue(value)
+}
+
+func (vl GeoJSONValue) estimateSize() int {
+	// flags + ncells + jsonstr
+	return 1 + 2 + len(vl)
+}
+
+func (vl GeoJSONValue) write(buffer []byte, offset int) (int, error) {
+	buffer[offset] = 0 // flags
+	Buffer.Int16ToBytes(0, buffer, offset+1)
+	return 1 + 2 + copy(buffer[offset+3:], vl), nil
+}
+
+func (vl GeoJSONValue) pack(packer *packer) error {
+	packer.PackByteArrayBegin(len(vl) + 1)
+	packer.PackAByte(ParticleType.GEOJSON)
+	packer.PackByteArray([]byte(vl), 0, len(vl))
+	return nil
+}
+
+// GetType returns wire protocol value type.
+func (vl GeoJSONValue) GetType() int {
+	return ParticleType.GEOJSON
+}
+
+// GetObject returns original value as an interface{}.
+func (vl GeoJSONValue) GetObject() interface{} {
+	return vl
+}
+
+func (vl GeoJSONValue) reader() io.Reader {
+	return strings.NewReader(string(vl))
+}
+
+// String implements Stringer interface.
+func (vl GeoJSONValue) String() string {
+	return string(vl)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
 // IntegerValue encapsulates an integer value.
 type IntegerValue int
 
@@ -516,6 +684,9 @@ func (vl *ListValue) String() string {
 type MapValue struct {
 	vmap  map[interface{}]interface{}
 	bytes []byte
+
+	// error of packing an ordered map, reported when the value is sent
+	err error
 }
 
 // NewMapValue generates a MapValue instance.
@@ -529,16 +700,39 @@ func NewMapValue(vmap map[interface{}]interface{}) *MapValue {
 	return res
 }
 
+// NewOrderedMapValue generates a MapValue instance which is stored by the server
+// with the specified order, e.g. when written with Put. This avoids a separate
+// MapSetPolicyOp call before by-key or by-rank operations.
+// Keys of ordered maps must be integers, strings or byte arrays.
+func NewOrderedMapValue(vmap map[interface{}]interface{}, order MapOrderType) *MapValue {
+	res := &MapValue{
+		vmap: vmap,
+	}
+
+	packer := newPacker()
+	if res.err = packer.PackOrderedMap(vmap, order); res.err == nil {
+		res.bytes = packer.buffer.Bytes()
+	}
+
+	return res
+}
+
 func (vl *MapValue) estimateSize() int {
 	return len(vl.bytes)
 }
 
 func (vl *MapValue) write(buffer []byte, offset int) (int, error) {
+	if vl.err != nil {
+		return 0, vl.err
+	}
 	return copy(buffer[offset:], vl.bytes), nil
 }
 
 func (vl *MapValue) pack(packer *packer) error {
 	// return packer.PackMap(vl.vmap)
+	if vl.err != nil {
+		return vl.err
+	}
 	_, err := packer.buffer.Write(vl.bytes)
 	return err
 }
@@ -577,11 +771,22 @@ func bytesToParticle(ptype int, buf []byte, offset int, length int) (interface{}
 	case ParticleType.STRING:
 		return string(buf[offset : offset+length]), nil
 
+	case ParticleType.BOOL:
+		return length > 0 && buf[offset] != 0, nil
+
 	case ParticleType.BLOB:
 		newObj := make([]byte, length)
 		copy(newObj, buf[offset:offset+length])
 		return newObj, nil
 
+	case ParticleType.GEOJSON:
+		return bytesToGeoJSON(buf, offset, length), nil
+
+	case ParticleType.HLL:
+		newObj := make([]byte, length)
+		copy(newObj, buf[offset:offset+length])
+		return NewHLLValue(newObj), nil
+
 	case ParticleType.LIST:
 		return newUnpacker(buf, offset, length).UnpackList()
 
@@ -592,6 +797,14 @@ func bytesToParticle(ptype int, buf []byte, offset int, length int) (interface{}
 	return nil, nil
 }
 
+// bytesToGeoJSON skips the flags and cells of the server GeoJSON particle
+// and returns the GeoJSON string.
+func bytesToGeoJSON(buf []byte, offset int, length int) GeoJSONValue {
+	ncells := int(Buffer.BytesToInt16(buf, offset+1))
+	headerSize := 1 + 2 + (ncells * 8)
+	return NewGeoJSONValue(string(buf[offset+headerSize : offset+length]))
+}
+
 func bytesToKeyValue(pType int, buf []byte, offset int, len int) (Value, error) {
 
 	switch pType {
@@ -599,7 +812,9 @@ func bytesToKeyValue(pType int, buf []byte, offset int, len int) (Value, error)
 		return NewStringValue(string(buf[offset : offset+len])), nil
 
 	case ParticleType.INTEGER:
-		return NewLongValue(Buffer.VarBytesToInt64(buf, offset, len)), nil
+		// decode like bin values so that keys created via NewKey(ns, set, int)
+		// round trip to the same Go type
+		return NewValue(Buffer.BytesToNumber(buf, offset, len)), nil
 
 	case ParticleType.BLOB:
 		bytes := make([]byte, len, len)
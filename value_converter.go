@@ -0,0 +1,64 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ValueConverter converts a value of a custom Go type to a supported Value.
+type ValueConverter func(v interface{}) Value
+
+var valueConverters = struct {
+	sync.RWMutex
+	converters map[reflect.Type]ValueConverter
+}{converters: map[reflect.Type]ValueConverter{}}
+
+// RegisterValueConverter registers a converter for all values of the same type as sample.
+// Registered converters are used by NewValue, and therefore in bins and BinMaps,
+// as well as for values nested in lists and maps.
+// Converters can not override the conversion of natively supported types.
+//
+// Example:
+//
+//	RegisterValueConverter(net.IP{}, func(v interface{}) Value {
+//		return NewStringValue(v.(net.IP).String())
+//	})
+func RegisterValueConverter(sample interface{}, converter ValueConverter) {
+	valueConverters.Lock()
+	valueConverters.converters[reflect.TypeOf(sample)] = converter
+	valueConverters.Unlock()
+}
+
+// UnregisterValueConverter removes the converter registered for the type of sample.
+func UnregisterValueConverter(sample interface{}) {
+	valueConverters.Lock()
+	delete(valueConverters.converters, reflect.TypeOf(sample))
+	valueConverters.Unlock()
+}
+
+// convertValue converts v using the converter registered for its type.
+// Returns nil if no converter is registered.
+func convertValue(v interface{}) Value {
+	valueConverters.RLock()
+	converter := valueConverters.converters[reflect.TypeOf(v)]
+	valueConverters.RUnlock()
+
+	if converter == nil {
+		return nil
+	}
+	return converter(v)
+}
@@ -16,6 +16,7 @@ package aerospike
 
 import (
 	"math"
+	"net"
 	"reflect"
 
 	. "github.com/onsi/ginkgo"
@@ -84,6 +85,152 @@ var _ = Describe("Value Test", func() {
 		})
 	})
 
+	Context("GeoJSONValues", func() {
+		It("should create a valid GeoJSONValue and read it back", func() {
+			point := `{"type": "Point", "coordinates": [-122.0, 37.5]}`
+			v := NewGeoJSONValue(point)
+
+			Expect(v.GetObject()).To(Equal(GeoJSONValue(point)))
+			Expect(v.estimateSize()).To(Equal(len(point) + 3))
+			Expect(v.GetType()).To(Equal(ParticleType.GEOJSON))
+
+			buf := make([]byte, v.estimateSize())
+			n, err := v.write(buf, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(n).To(Equal(len(buf)))
+
+			res, err := bytesToParticle(ParticleType.GEOJSON, buf, 0, n)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(v))
+		})
+
+		It("should decode geometries with typed accessors", func() {
+			point, err := NewGeoJSONValue(`{"type": "Point", "coordinates": [-122.0, 37.5]}`).Point()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(point).To(Equal(GeoPoint{Lng: -122.0, Lat: 37.5}))
+
+			_, err = NewGeoJSONValue(`{"type": "Point", "coordinates": [-122.0, 37.5]}`).Polygon()
+			Expect(err).To(HaveOccurred())
+
+			circle, err := NewGeoJSONValue(`{"type": "AeroCircle", "coordinates": [[-122.0, 37.5], 3000.0]}`).Circle()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(circle).To(Equal(GeoCircle{Center: GeoPoint{Lng: -122.0, Lat: 37.5}, Radius: 3000}))
+
+			geoType, err := NewGeoJSONValue(`{"type": "Polygon", "coordinates": []}`).GeometryType()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(geoType).To(Equal(GEO_POLYGON))
+		})
+
+		It("should marshal typed geometries back to GeoJSON", func() {
+			polygon := GeoPolygon{{{1, 1}, {1, 2}, {2, 2}, {1, 1}}}
+			v := NewGeoPolygonValue(polygon)
+			Expect(string(v)).To(Equal(`{"type":"Polygon","coordinates":[[[1,1],[1,2],[2,2],[1,1]]]}`))
+
+			res, err := v.Polygon()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(polygon))
+
+			point, err := NewGeoPointValue(-122.5, 37.25).Point()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(point).To(Equal(GeoPoint{Lng: -122.5, Lat: 37.25}))
+
+			circle, err := NewGeoCircleValue(-122.5, 37.25, 100).Circle()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(circle.Radius).To(Equal(100.0))
+
+			polygons, err := NewGeoMultiPolygonValue([]GeoPolygon{polygon, polygon}).MultiPolygon()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(polygons).To(Equal([]GeoPolygon{polygon, polygon}))
+		})
+	})
+
+	Context("BoolValues", func() {
+		It("should create a valid BoolValue", func() {
+			v := NewValue(true)
+
+			Expect(v).To(BeAssignableToTypeOf(BoolValue(true)))
+			Expect(v.GetObject()).To(Equal(true))
+			Expect(v.estimateSize()).To(Equal(1))
+			Expect(v.GetType()).To(Equal(ParticleType.BOOL))
+
+			buf := make([]byte, 1)
+			n, err := v.write(buf, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(n).To(Equal(1))
+			Expect(buf).To(Equal([]byte{1}))
+		})
+	})
+
+	Context("MapValues", func() {
+		It("should pack an ordered map with its order header and sorted keys", func() {
+			v := NewOrderedMapValue(map[interface{}]interface{}{"b": 2, 10: 1, "a": 3}, MapOrderKeyOrdered)
+			Expect(v.GetType()).To(Equal(ParticleType.MAP))
+
+			buf := make([]byte, v.estimateSize())
+			_, err := v.write(buf, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf).To(Equal([]byte{
+				0x84,
+				0xc7, 0, byte(MapOrderKeyOrdered), 0xc0,
+				10, 1,
+				0xa2, ParticleType.STRING, 'a', 3,
+				0xa2, ParticleType.STRING, 'b', 2,
+			}))
+
+			unpacked, err := newUnpacker(buf, 0, len(buf)).UnpackMap()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(unpacked).To(Equal(map[interface{}]interface{}{"b": 2, 10: 1, "a": 3}))
+		})
+
+		It("should report unsupported keys of ordered maps", func() {
+			v := NewOrderedMapValue(map[interface{}]interface{}{1.5: 1}, MapOrderKeyOrdered)
+			_, err := v.write(make([]byte, 16), 0)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Converted Values", func() {
+		type customID int
+
+		BeforeEach(func() {
+			RegisterValueConverter(net.IP{}, func(v interface{}) Value {
+				return NewStringValue(v.(net.IP).String())
+			})
+			RegisterValueConverter(customID(0), func(v interface{}) Value {
+				return NewStringValue("id:" + NewValue(int(v.(customID))).String())
+			})
+		})
+
+		AfterEach(func() {
+			UnregisterValueConverter(net.IP{})
+			UnregisterValueConverter(customID(0))
+		})
+
+		It("should convert registered types", func() {
+			v := NewValue(net.ParseIP("10.0.0.1"))
+			Expect(v.GetObject()).To(Equal("10.0.0.1"))
+			Expect(v.GetType()).To(Equal(ParticleType.STRING))
+
+			v = NewValue(customID(42))
+			Expect(v.GetObject()).To(Equal("id:42"))
+		})
+
+		It("should convert registered types nested in lists", func() {
+			packer := newPacker()
+			Expect(packer.PackObject([]interface{}{customID(42)})).ToNot(HaveOccurred())
+
+			expected := newPacker()
+			Expect(expected.PackObject([]interface{}{"id:42"})).ToNot(HaveOccurred())
+			Expect(packer.buffer.Bytes()).To(Equal(expected.buffer.Bytes()))
+		})
+
+		It("should not convert after the converter is unregistered", func() {
+			UnregisterValueConverter(customID(0))
+			v := NewValue(customID(42))
+			Expect(v.GetObject()).To(Equal(int64(42)))
+		})
+	})
+
 	Context("Blob Values", func() {
 
 		It("should create a BytesValue on valid types, and encode", func() {
@@ -0,0 +1,115 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"sync"
+)
+
+// PendingWrite is a write to a single record applied by Client.ApplyWrites.
+type PendingWrite struct {
+	// Key of the record.
+	Key *Key
+
+	// Operations to apply to the record, as in Client.Operate.
+	Operations []*Operation
+}
+
+// NewPendingWrite creates a write of the operations to the record with the key.
+func NewPendingWrite(key *Key, operations ...*Operation) *PendingWrite {
+	return &PendingWrite{
+		Key:        key,
+		Operations: operations,
+	}
+}
+
+// writeNode holds the writes routed to a node, grouped by key.
+// Each group holds the indexes of the writes of a key, in order.
+type writeNode struct {
+	node   *Node
+	groups [][]int
+}
+
+type writeGroup struct {
+	wn    *writeNode
+	index int
+}
+
+// groupWritesByNode groups the writes by key, and the keys by the master node
+// of their partition. The order of the writes of each key is preserved.
+func groupWritesByNode(cluster *Cluster, writes []*PendingWrite) ([]*writeNode, error) {
+	var res []*writeNode
+	nodes := make(map[*Node]*writeNode)
+	// location of the group of each key
+	groups := make(map[string]writeGroup, len(writes))
+
+	for i, write := range writes {
+		digest := string(write.Key.Digest())
+		if g, exists := groups[digest]; exists {
+			g.wn.groups[g.index] = append(g.wn.groups[g.index], i)
+			continue
+		}
+
+		node, err := cluster.GetNode(NewPartitionByKey(write.Key))
+		if err != nil {
+			return nil, err
+		}
+
+		wn := nodes[node]
+		if wn == nil {
+			wn = &writeNode{node: node}
+			nodes[node] = wn
+			res = append(res, wn)
+		}
+
+		wn.groups = append(wn.groups, []int{i})
+		groups[digest] = writeGroup{wn, len(wn.groups) - 1}
+	}
+
+	return res, nil
+}
+
+// apply applies the groups of writes of the node using up to concurrency goroutines.
+// The writes of each group are applied in order by a single goroutine; once one of
+// them fails, the rest of the group is not applied and reports the same error.
+func (wn *writeNode) apply(writes []*PendingWrite, errs []error, concurrency int, applyFunc func(*PendingWrite) error) {
+	if concurrency < 1 || concurrency > len(wn.groups) {
+		concurrency = len(wn.groups)
+	}
+
+	groups := make(chan []int, len(wn.groups))
+	for _, g := range wn.groups {
+		groups <- g
+	}
+	close(groups)
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for g := range groups {
+				var err error
+				for _, index := range g {
+					if err == nil {
+						err = applyFunc(writes[index])
+					}
+					errs[index] = err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
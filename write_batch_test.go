@@ -0,0 +1,68 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Write Batch Test", func() {
+
+	It("should apply the writes of each key in order and stop at the first failure", func() {
+		writes := make([]*PendingWrite, 6)
+		for i := range writes {
+			writes[i] = &PendingWrite{}
+		}
+
+		// key A: 0, 2, 4 - key B: 1, 3, 5
+		wn := &writeNode{groups: [][]int{{0, 2, 4}, {1, 3, 5}}}
+		errFailed := errors.New("failed")
+
+		var mutex sync.Mutex
+		var applied []int
+		errs := make([]error, len(writes))
+		wn.apply(writes, errs, 2, func(write *PendingWrite) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			for i := range writes {
+				if writes[i] == write {
+					applied = append(applied, i)
+					if i == 3 {
+						return errFailed
+					}
+				}
+			}
+			return nil
+		})
+
+		var keyA, keyB []int
+		for _, i := range applied {
+			if i%2 == 0 {
+				keyA = append(keyA, i)
+			} else {
+				keyB = append(keyB, i)
+			}
+		}
+		Expect(keyA).To(Equal([]int{0, 2, 4}))
+		Expect(keyB).To(Equal([]int{1, 3}))
+		Expect(errs).To(Equal([]error{nil, nil, nil, errFailed, nil, errFailed}))
+	})
+
+})
@@ -14,7 +14,14 @@
 
 package aerospike
 
-import . "github.com/aerospike/aerospike-client-go/types"
+import (
+	"fmt"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+)
 
 // guarantee writeCommand implements command interface
 var _ command = &writeCommand{}
@@ -25,13 +32,30 @@ type writeCommand struct {
 	policy    *WritePolicy
 	bins      []*Bin
 	operation OperationType
+
+	// generation of the record after a successful write
+	generation uint32
 }
 
 func newWriteCommand(cluster *Cluster,
 	policy *WritePolicy,
 	key *Key,
 	bins []*Bin,
-	operation OperationType) *writeCommand {
+	operation OperationType) (*writeCommand, error) {
+
+	if policy.BoolAsInteger {
+		bins = boolBinsAsIntegers(bins)
+	}
+
+	if operation == WRITE {
+		bin, err := keyHashBin(cluster, policy, key)
+		if err != nil {
+			return nil, err
+		}
+		if bin != nil {
+			bins = append(bins[:len(bins):len(bins)], bin)
+		}
+	}
 
 	newWriteCmd := &writeCommand{
 		singleCommand: *newSingleCommand(cluster, key),
@@ -40,7 +64,27 @@ func newWriteCommand(cluster *Cluster,
 		operation:     operation,
 	}
 
-	return newWriteCmd
+	return newWriteCmd, nil
+}
+
+// boolBinsAsIntegers returns a copy of bins with boolean values converted to integers.
+// The original slice is returned if it doesn't contain boolean values.
+func boolBinsAsIntegers(bins []*Bin) []*Bin {
+	var res []*Bin
+	for i, bin := range bins {
+		if bv, ok := bin.Value.(BoolValue); ok {
+			if res == nil {
+				res = make([]*Bin, len(bins))
+				copy(res, bins)
+			}
+			res[i] = NewBin(bin.Name, bv.asInteger())
+		}
+	}
+
+	if res == nil {
+		return bins
+	}
+	return res
 }
 
 func (cmd *writeCommand) getPolicy(ifc command) Policy {
@@ -62,6 +106,7 @@ func (cmd *writeCommand) parseResult(ifc command, conn *Connection) error {
 	if resultCode != 0 {
 		return NewAerospikeError(ResultCode(resultCode))
 	}
+	cmd.generation = uint32(Buffer.BytesToInt32(cmd.dataBuffer, 14))
 	if err := cmd.emptySocket(conn); err != nil {
 		return err
 	}
@@ -69,5 +114,46 @@ func (cmd *writeCommand) parseResult(ifc command, conn *Connection) error {
 }
 
 func (cmd *writeCommand) Execute() error {
-	return cmd.execute(cmd)
+	cmd.policy.ReadYourWrites.Track(cmd.key)
+	if err := cmd.execute(cmd); err != nil {
+		return err
+	}
+
+	if cmd.policy.VerifyRead {
+		return cmd.verifyRead()
+	}
+	return nil
+}
+
+// verifyRead reads the header of the written record from a prole and confirms
+// that it has at least the written generation. Proles which lag behind are
+// read again up to MaxRetries times before the divergence is reported.
+func (cmd *writeCommand) verifyRead() error {
+	// do not modify the caller's policy
+	rp := cmd.policy.BasePolicy
+	rp.ReplicaPolicy = PROLES
+	rp.ReadYourWrites = nil
+	rp.FilterExpression = nil
+	rp.KeyNotFoundAsError = false
+
+	for attempt := 0; ; attempt++ {
+		command := newReadHeaderCommand(cmd.cluster, &rp, cmd.key)
+		if err := command.Execute(); err != nil {
+			return err
+		}
+
+		generation := uint32(0)
+		if rec := command.GetRecord(); rec != nil {
+			generation = uint32(rec.Generation)
+			// compare the 16 bit server generations in serial number arithmetic
+			if int16(uint16(generation)-uint16(cmd.generation)) >= 0 {
+				return nil
+			}
+		}
+
+		if attempt >= rp.MaxRetries {
+			return NewAerospikeError(REPLICA_DIVERGENCE, fmt.Sprintf("Record was written with generation %d, but its replica on node %s has generation %d", cmd.generation, command.node.GetName(), generation))
+		}
+		time.Sleep(rp.SleepBetweenRetries)
+	}
 }
@@ -42,6 +42,8 @@ type WritePolicy struct {
 	// Expiration values:
 	// -1: Never expire for Aerospike 2 server versions >= 2.7.2 and Aerospike 3 server
 	// versions >= 3.1.4.  Do not use -1 for older servers.
+	// -2: Do not change the expiration of an existing record on updates, for
+	// server versions >= 3.10.1.
 	// 0: Default to namespace configuration variable "default-ttl" on the server.
 	// > 0: Actual expiration in seconds.
 	Expiration int32
@@ -49,6 +51,31 @@ type WritePolicy struct {
 	// Send user defined key in addition to hash digest on a record put.
 	// The default is to not send the user defined key.
 	SendKey bool
+
+	// SendKeyHash determines if a secondary hash of the user key should be stored
+	// in the hidden bin KeyHashBinName on Put and Operate commands when SendKey is false.
+	// This allows auditing the records for key collisions without storing the user keys.
+	// The hash function is set by ClientPolicy.KeyHash.
+	SendKeyHash bool
+
+	// BoolAsInteger determines if boolean bins are written as integers 1 and 0
+	// instead of the server's native boolean type. Set it when writing to servers
+	// without native boolean support. Booleans nested in lists and maps are not affected.
+	BoolAsInteger bool //= false
+
+	// DurableDelete leaves a tombstone when the command deletes the record, so that
+	// the record is not revived by a cold restart or a migration of an older copy.
+	// It applies to Delete, and to Operate and Execute when they delete the record.
+	// Requires an Enterprise server; others fail the command with ENTERPRISE_ONLY.
+	DurableDelete bool //= false
+
+	// VerifyRead determines if the record header is read back from a prole after
+	// a successful Put, PutBins, PutObject, Append, Prepend or Add, to confirm that
+	// the write has been replicated. Proles which lag behind, e.g. during migrations
+	// or with COMMIT_MASTER, are read again up to MaxRetries times, and then a
+	// REPLICA_DIVERGENCE error is returned; the write itself is not undone.
+	// Requires ClientPolicy.RequestProleReplicas; otherwise the master is read.
+	VerifyRead bool //= false
 }
 
 // NewWritePolicy initializes a new WritePolicy instance with default parameters.
@@ -61,5 +88,6 @@ func NewWritePolicy(generation, expiration int32) *WritePolicy {
 		Generation:         generation,
 		Expiration:         expiration,
 		SendKey:            false,
+		SendKeyHash:        false,
 	}
 }
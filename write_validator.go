@@ -0,0 +1,98 @@
+// Copyright 2013-2015 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"sync"
+)
+
+// WriteValidator checks a write before it is sent to the server. If it returns
+// an error, the write is rejected with that error and nothing is written.
+// Puts are presented as one WRITE operation per bin; appends, prepends and adds
+// as one operation of their type per bin. Operate commands are presented with
+// all their operations, reads included.
+// Validators are called synchronously from the goroutine issuing the write
+// and must be safe for concurrent use.
+type WriteValidator func(write *PendingWrite) error
+
+type validatedSet struct {
+	namespace, setName string
+}
+
+// writeValidators holds the write validators of a client per set.
+type writeValidators struct {
+	mutex      sync.RWMutex
+	validators map[validatedSet][]WriteValidator
+}
+
+func newWriteValidators() *writeValidators {
+	return &writeValidators{
+		validators: make(map[validatedSet][]WriteValidator),
+	}
+}
+
+// RegisterWriteValidator registers a validator for the writes of Put, Append, Prepend,
+// Add and Operate commands, and the commands built on them, to the records of the set.
+// Validators of a set are called in the order of their registration; the first error
+// rejects the write. Use an empty set name for the records without a set.
+func (clnt *Client) RegisterWriteValidator(namespace string, setName string, validator WriteValidator) {
+	wv := clnt.writeValidators
+	wv.mutex.Lock()
+	defer wv.mutex.Unlock()
+
+	set := validatedSet{namespace, setName}
+	wv.validators[set] = append(wv.validators[set], validator)
+}
+
+// RemoveWriteValidators removes all the validators registered for the set.
+func (clnt *Client) RemoveWriteValidators(namespace string, setName string) {
+	wv := clnt.writeValidators
+	wv.mutex.Lock()
+	defer wv.mutex.Unlock()
+
+	delete(wv.validators, validatedSet{namespace, setName})
+}
+
+// validateWrite runs the validators of the key's set on the write.
+// The operations are only built if the set has validators.
+func (clnt *Client) validateWrite(key *Key, operations func() []*Operation) error {
+	wv := clnt.writeValidators
+	wv.mutex.RLock()
+	validators := wv.validators[validatedSet{key.Namespace(), key.SetName()}]
+	wv.mutex.RUnlock()
+
+	if len(validators) == 0 {
+		return nil
+	}
+
+	write := NewPendingWrite(key, operations()...)
+	for _, validator := range validators {
+		if err := validator(write); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// binOperations returns an operation of the type for each bin.
+func binOperations(opType OperationType, bins []*Bin) func() []*Operation {
+	return func() []*Operation {
+		res := make([]*Operation, len(bins))
+		for i, bin := range bins {
+			res[i] = &Operation{OpType: opType, BinName: bin.Name, BinValue: bin.Value}
+		}
+		return res
+	}
+}